@@ -0,0 +1,50 @@
+package main
+
+/*
+#include "arrow_abi.h"
+*/
+import "C"
+import (
+	"unsafe"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/apache/arrow-go/v18/arrow/cdata"
+)
+
+// ExportArrow exports a frame through the Arrow C Data Interface as a struct
+// array with one child per column, filling the caller-allocated schema and
+// array. The columns are copied once into Arrow buffers, which the consumer
+// then reads in place and frees by calling the release callbacks. It returns
+// 0, or -1 on failure.
+//
+//export ExportArrow
+func ExportArrow(hID C.int64_t, schema *C.struct_ArrowSchema, array *C.struct_ArrowArray) (ret C.int) {
+	defer recoverTo(&ret, -1)
+	h, ok := lookup(hID)
+	if !ok {
+		return -1
+	}
+	df, err := toFrame(h.df)
+	if err != nil {
+		return -1
+	}
+	rec, err := df.ToArrow()
+	if err != nil {
+		return -1
+	}
+	defer rec.Release()
+	cdata.ExportArrowRecordBatch(rec, (*cdata.CArrowArray)(unsafe.Pointer(array)), (*cdata.CArrowSchema)(unsafe.Pointer(schema)))
+	return 0
+}
+
+// toFrame views a bridge frame as a dataframe.DataFrame sharing its columns.
+func toFrame(df *types.DataFrame) (*dataframe.DataFrame, error) {
+	columns := df.Columns()
+	series := make([]*types.Series, len(columns))
+	for i, name := range columns {
+		series[i] = df.Series[name]
+	}
+	return dataframe.FromColumns(series)
+}
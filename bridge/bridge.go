@@ -1,8 +1,12 @@
 package main
 
+//go:generate go run gen_header.go -o ../include/gopolars.h
+
 /*
 #include <stdlib.h>
 #include <stdint.h>
+#include "arrow_abi.h"
+#include "callbacks.h"
 
 // Export these symbols without underscore prefix
 int64_t NewDataFrame(void);
@@ -17,39 +21,153 @@ int64_t Head(int64_t handle, int n);
 void* GetSeries(int64_t handle, char* name, int* length, int* dtype);
 char* GetColumn(int64_t handle, int index);
 int GetColumnCount(int64_t handle);
+char* GetRowJSON(int64_t handle, int64_t row);
+int64_t SliceHandle(int64_t handle, int64_t offset, int64_t length);
+char* GetAggregationTypes(void);
+char* GetDTypeName(int code);
+void GetBridgeVersion(int* major, int* minor, int* patch, uint64_t* features);
+int64_t SubmitSort(int64_t handle, char* column, int ascending);
+int64_t SubmitGroupBy(int64_t handle, char** columns, int num_columns);
+int PollJob(int64_t job, int64_t* result);
+int CancelJob(int64_t job);
+char* ExportSharedMemory(int64_t handle, int64_t* size);
+int ExportArrow(int64_t handle, struct ArrowSchema* schema, struct ArrowArray* array);
+int64_t FilterBatch(int64_t handle, char* column, gopolars_predicate predicate, void* ctx);
+int AddSeries64(int64_t handle, char* name, void* data, int64_t length, int dtype);
+int GetShape64(int64_t handle, int64_t* rows, int64_t* cols);
+void* GetSeries64(int64_t handle, char* name, int64_t* length, int* dtype);
 */
 import "C"
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
 	"unsafe"
 
 	"go-polars/types"
 )
 
+// Column type codes used by AddSeries and GetSeries. They are part of the
+// bridge contract: a code never changes meaning once published.
+const (
+	dtypeInt64 C.int = iota
+	dtypeFloat64
+	dtypeBool
+	dtypeFloat32
+)
+
+var dtypeNames = map[C.int]string{
+	dtypeInt64:   "Int64",
+	dtypeFloat64: "Float64",
+	dtypeBool:    "Boolean",
+	dtypeFloat32: "Float32",
+}
+
+// Semantic version of the exported C API. The major version changes when an
+// export is removed or changes signature or meaning, the minor version when
+// exports are added.
+const (
+	bridgeMajor = 1
+	bridgeMinor = 7
+	bridgePatch = 0
+)
+
+// Feature bits reported by GetBridgeVersion, so bindings can check for the
+// exports they rely on.
+const (
+	featureRowAccess    uint64 = 1 << iota // GetRowJSON, SliceHandle
+	featureTypeQueries                     // GetAggregationTypes, GetDTypeName
+	featureAsyncJobs                       // SubmitSort, SubmitGroupBy, PollJob, CancelJob
+	featureSharedMemory                    // ExportSharedMemory
+	featureArrowExport                     // ExportArrow
+	featureBatchFilter                     // FilterBatch
+	featureLargeSizes                      // AddSeries64, GetShape64, GetSeries64
+)
+
+// Version of the type and aggregation code tables, incremented whenever a
+// code is added to or dropped from either.
+const contractVersion = 2
+
 // Handle represents a DataFrame held by Go but referenced from C/Python.
 type Handle struct {
 	df     *types.DataFrame
 	series map[string]*types.Series // owns its own copy so it never gets stale
+	order  []string                 // column insertion order
+	pins   runtime.Pinner           // Go memory handed out by GetSeries
 }
 
+// handles is guarded by handlesMu since jobs register their results from
+// their own goroutines.
 var (
+	handlesMu  sync.Mutex
 	handles              = make(map[C.int64_t]*Handle)
 	nextHandle C.int64_t = 1
 )
 
+func lookup(hID C.int64_t) (*Handle, bool) {
+	handlesMu.Lock()
+	defer handlesMu.Unlock()
+	h, ok := handles[hID]
+	return h, ok
+}
+
 // newHandleFrom copies df.Series and registers a fresh Handle.
 func newHandleFrom(df *types.DataFrame) C.int64_t {
 	fresh := make(map[string]*types.Series, len(df.Series))
 	for k, v := range df.Series {
 		fresh[k] = v
 	}
+	handlesMu.Lock()
+	defer handlesMu.Unlock()
 	id := nextHandle
 	nextHandle++
-	handles[id] = &Handle{df: df, series: fresh}
+	handles[id] = &Handle{df: df, series: fresh, order: df.Columns()}
 	return id
 }
 
+// snapshot returns the frame of h with its own copy of the columns, for
+// work running while h may change; the Series themselves are never
+// modified in place.
+func (h *Handle) snapshot() (*types.DataFrame, error) {
+	series := make(map[string]*types.Series, len(h.series))
+	for k, v := range h.series {
+		series[k] = v
+	}
+	return types.NewOrdered(series, h.order)
+}
+
+// release drops a handle and unpins the memory handed out from it
+func release(hID C.int64_t) {
+	handlesMu.Lock()
+	defer handlesMu.Unlock()
+	if h, ok := handles[hID]; ok {
+		h.pins.Unpin()
+	}
+	delete(handles, hID)
+}
+
+// A Go panic unwinding into the C caller aborts the whole host process, so
+// every export defers one of these to turn a panic into its failure value:
+// recoverTo stores failure in the export's named result, recoverQuietly is
+// for exports without one.
+func recoverTo[T any](ret *T, failure T) {
+	if r := recover(); r != nil {
+		*ret = failure
+	}
+}
+
+func recoverQuietly() {
+	_ = recover()
+}
+
+// NewDataFrame registers an empty frame and returns its handle, or -1.
+//
 //export NewDataFrame
-func NewDataFrame() C.int64_t {
+func NewDataFrame() (ret C.int64_t) {
+	defer recoverTo(&ret, -1)
 	df, err := types.New(make(map[string]*types.Series))
 	if err != nil {
 		return -1
@@ -57,55 +175,116 @@ func NewDataFrame() C.int64_t {
 	return newHandleFrom(df)
 }
 
+// AddSeries adds, or replaces, column name of a frame with length values of
+// type dtype read from data, which must stay valid while the frame is used.
+// It returns 0, or -1 on failure. AddSeries64 takes columns of any length.
+//
 //export AddSeries
-func AddSeries(hID C.int64_t, name *C.char, data unsafe.Pointer, length C.int, dtype C.int) C.int {
-	h, ok := handles[hID]
-	if !ok {
+func AddSeries(hID C.int64_t, name *C.char, data unsafe.Pointer, length C.int, dtype C.int) (ret C.int) {
+	defer recoverTo(&ret, -1)
+	return addSeries(hID, name, data, int(length), dtype)
+}
+
+// AddSeries64 is AddSeries with a 64-bit length.
+//
+//export AddSeries64
+func AddSeries64(hID C.int64_t, name *C.char, data unsafe.Pointer, length C.int64_t, dtype C.int) (ret C.int) {
+	defer recoverTo(&ret, -1)
+	return addSeries(hID, name, data, int(length), dtype)
+}
+
+func addSeries(hID C.int64_t, name *C.char, data unsafe.Pointer, length int, dtype C.int) C.int {
+	h, ok := lookup(hID)
+	if !ok || length < 0 {
 		return -1
 	}
 
 	goName := C.GoString(name)
-	goLen := int(length)
 
 	var s *types.Series
 	switch dtype {
-	case 0:
-		s = types.NewSeries(goName, unsafe.Slice((*int64)(data), goLen))
-	case 1:
-		s = types.NewSeries(goName, unsafe.Slice((*float64)(data), goLen))
-	case 2:
-		s = types.NewSeries(goName, unsafe.Slice((*bool)(data), goLen))
+	case dtypeInt64:
+		s = types.NewSeries(goName, unsafe.Slice((*int64)(data), length))
+	case dtypeFloat64:
+		s = types.NewSeries(goName, unsafe.Slice((*float64)(data), length))
+	case dtypeBool:
+		s = types.NewSeries(goName, unsafe.Slice((*bool)(data), length))
+	case dtypeFloat32:
+		s = types.NewSeries(goName, unsafe.Slice((*float32)(data), length))
 	default:
 		return -1
 	}
 
-	h.series[goName] = s
-	newDF, err := types.New(h.series)
-	if err != nil {
+	if err := h.put(s); err != nil {
 		return -1
 	}
-	h.df = newDF
 	return 0
 }
 
+// put adds, or replaces, the column s.Name of h
+func (h *Handle) put(s *types.Series) error {
+	if _, exists := h.series[s.Name]; !exists {
+		h.order = append(h.order, s.Name)
+	}
+	h.series[s.Name] = s
+	newDF, err := types.NewOrdered(h.series, h.order)
+	if err != nil {
+		return err
+	}
+	h.df = newDF
+	return nil
+}
+
+// GetShape stores the number of rows and columns of a frame. It returns 0,
+// or -1 for an unknown handle or when a count does not fit in an int, see
+// GetShape64.
+//
 //export GetShape
-func GetShape(hID C.int64_t, rows, cols *C.int) C.int {
-	h, ok := handles[hID]
+func GetShape(hID C.int64_t, rows, cols *C.int) (ret C.int) {
+	defer recoverTo(&ret, -1)
+	h, ok := lookup(hID)
 	if !ok {
 		return -1
 	}
 	r, c := h.df.Shape()
+	if r > math.MaxInt32 || c > math.MaxInt32 {
+		return -1
+	}
 	*rows = C.int(r)
 	*cols = C.int(c)
 	return 0
 }
 
+// GetShape64 is GetShape with 64-bit counts.
+//
+//export GetShape64
+func GetShape64(hID C.int64_t, rows, cols *C.int64_t) (ret C.int) {
+	defer recoverTo(&ret, -1)
+	h, ok := lookup(hID)
+	if !ok {
+		return -1
+	}
+	r, c := h.df.Shape()
+	*rows = C.int64_t(r)
+	*cols = C.int64_t(c)
+	return 0
+}
+
+// DeleteDataFrame releases a handle. Unknown handles are ignored.
+//
 //export DeleteDataFrame
-func DeleteDataFrame(hID C.int64_t) { delete(handles, hID) }
+func DeleteDataFrame(hID C.int64_t) {
+	defer recoverQuietly()
+	release(hID)
+}
 
+// SortByColumn returns the handle of a copy of the frame sorted by column,
+// ascending unless ascending is 0, or -1 on failure.
+//
 //export SortByColumn
-func SortByColumn(hID C.int64_t, column *C.char, asc C.int) C.int64_t {
-	h, ok := handles[hID]
+func SortByColumn(hID C.int64_t, column *C.char, asc C.int) (ret C.int64_t) {
+	defer recoverTo(&ret, -1)
+	h, ok := lookup(hID)
 	if !ok {
 		return -1
 	}
@@ -116,9 +295,13 @@ func SortByColumn(hID C.int64_t, column *C.char, asc C.int) C.int64_t {
 	return newHandleFrom(res)
 }
 
+// SortByIndex returns the handle of a copy of the frame in ascending row
+// order, or reversed when ascending is 0, or -1 on failure.
+//
 //export SortByIndex
-func SortByIndex(hID C.int64_t, asc C.int) C.int64_t {
-	h, ok := handles[hID]
+func SortByIndex(hID C.int64_t, asc C.int) (ret C.int64_t) {
+	defer recoverTo(&ret, -1)
+	h, ok := lookup(hID)
 	if !ok {
 		return -1
 	}
@@ -129,27 +312,40 @@ func SortByIndex(hID C.int64_t, asc C.int) C.int64_t {
 	return newHandleFrom(res)
 }
 
+// GroupBy groups a frame by num_columns columns and returns the handle of
+// the grouped frame for Aggregate, or -1 on failure.
+//
 //export GroupBy
-func GroupBy(hID C.int64_t, cols **C.char, n C.int) C.int64_t {
-	h, ok := handles[hID]
+func GroupBy(hID C.int64_t, cols **C.char, n C.int) (ret C.int64_t) {
+	defer recoverTo(&ret, -1)
+	h, ok := lookup(hID)
 	if !ok {
 		return -1
 	}
-	goCols := make([]string, int(n))
-	cSlice := (*[1 << 30]*C.char)(unsafe.Pointer(cols))[:n:n]
-	for i, c := range cSlice {
-		goCols[i] = C.GoString(c)
-	}
-	res, err := h.df.GroupBy(goCols)
+	res, err := h.df.GroupBy(goStrings(cols, n))
 	if err != nil {
 		return -1
 	}
 	return newHandleFrom(res)
 }
 
+// goStrings copies an array of n C strings.
+func goStrings(strs **C.char, n C.int) []string {
+	out := make([]string, int(n))
+	for i, c := range unsafe.Slice(strs, int(n)) {
+		out[i] = C.GoString(c)
+	}
+	return out
+}
+
+// Aggregate reduces column of a grouped frame with the aggregation code
+// agg_type (see GetAggregationTypes) and returns the handle of the result,
+// or -1 on failure.
+//
 //export Aggregate
-func Aggregate(hID C.int64_t, column *C.char, agg C.int) C.int64_t {
-	h, ok := handles[hID]
+func Aggregate(hID C.int64_t, column *C.char, agg C.int) (ret C.int64_t) {
+	defer recoverTo(&ret, -1)
+	h, ok := lookup(hID)
 	if !ok {
 		return -1
 	}
@@ -160,9 +356,12 @@ func Aggregate(hID C.int64_t, column *C.char, agg C.int) C.int64_t {
 	return newHandleFrom(res)
 }
 
+// Head returns the handle of the first n rows of a frame, or -1.
+//
 //export Head
-func Head(hID C.int64_t, n C.int) C.int64_t {
-	h, ok := handles[hID]
+func Head(hID C.int64_t, n C.int) (ret C.int64_t) {
+	defer recoverTo(&ret, -1)
+	h, ok := lookup(hID)
 	if !ok {
 		return -1
 	}
@@ -173,18 +372,25 @@ func Head(hID C.int64_t, n C.int) C.int64_t {
 	return newHandleFrom(res)
 }
 
+// GetColumnCount returns the number of columns of a frame, or -1.
+//
 //export GetColumnCount
-func GetColumnCount(hID C.int64_t) C.int {
-	h, ok := handles[hID]
+func GetColumnCount(hID C.int64_t) (ret C.int) {
+	defer recoverTo(&ret, -1)
+	h, ok := lookup(hID)
 	if !ok {
 		return -1
 	}
 	return C.int(len(h.df.Columns()))
 }
 
+// GetColumn returns the name of column index, or NULL when out of range.
+// The caller frees the string.
+//
 //export GetColumn
-func GetColumn(hID C.int64_t, idx C.int) *C.char {
-	h, ok := handles[hID]
+func GetColumn(hID C.int64_t, idx C.int) (ret *C.char) {
+	defer recoverTo(&ret, nil)
+	h, ok := lookup(hID)
 	if !ok {
 		return nil
 	}
@@ -195,9 +401,33 @@ func GetColumn(hID C.int64_t, idx C.int) *C.char {
 	return C.CString(cols[idx])
 }
 
+// GetSeries returns a pointer to the values of a numeric or Boolean column
+// and stores their count and type code, or returns NULL. The values belong
+// to the frame and stay valid until it is deleted. Columns of more values
+// than an int holds give NULL, see GetSeries64.
+//
 //export GetSeries
-func GetSeries(hID C.int64_t, name *C.char, length, dtype *C.int) unsafe.Pointer {
-	h, ok := handles[hID]
+func GetSeries(hID C.int64_t, name *C.char, length, dtype *C.int) (ret unsafe.Pointer) {
+	defer recoverTo(&ret, nil)
+	var n C.int64_t
+	p := getSeries(hID, name, &n, dtype)
+	if p == nil || n > math.MaxInt32 {
+		return nil
+	}
+	*length = C.int(n)
+	return p
+}
+
+// GetSeries64 is GetSeries with a 64-bit count.
+//
+//export GetSeries64
+func GetSeries64(hID C.int64_t, name *C.char, length *C.int64_t, dtype *C.int) (ret unsafe.Pointer) {
+	defer recoverTo(&ret, nil)
+	return getSeries(hID, name, length, dtype)
+}
+
+func getSeries(hID C.int64_t, name *C.char, length *C.int64_t, dtype *C.int) unsafe.Pointer {
+	h, ok := lookup(hID)
 	if !ok {
 		return nil
 	}
@@ -205,19 +435,159 @@ func GetSeries(hID C.int64_t, name *C.char, length, dtype *C.int) unsafe.Pointer
 	if !ok {
 		return nil
 	}
+	var p unsafe.Pointer
 	switch data := series.Data.(type) {
 	case []int64:
-		*length, *dtype = C.int(len(data)), 0
-		return unsafe.Pointer(&data[0])
+		*length, *dtype = C.int64_t(len(data)), dtypeInt64
+		p = unsafe.Pointer(&data[0])
 	case []float64:
-		*length, *dtype = C.int(len(data)), 1
-		return unsafe.Pointer(&data[0])
+		*length, *dtype = C.int64_t(len(data)), dtypeFloat64
+		p = unsafe.Pointer(&data[0])
 	case []bool:
-		*length, *dtype = C.int(len(data)), 2
-		return unsafe.Pointer(&data[0])
+		*length, *dtype = C.int64_t(len(data)), dtypeBool
+		p = unsafe.Pointer(&data[0])
+	case []float32:
+		*length, *dtype = C.int64_t(len(data)), dtypeFloat32
+		p = unsafe.Pointer(&data[0])
 	default:
 		return nil
 	}
+	// Columns computed by Go must not move while C holds them. Pinning
+	// memory that came from C is a no-op.
+	handlesMu.Lock()
+	h.pins.Pin(p)
+	handlesMu.Unlock()
+	return p
+}
+
+// GetRowJSON returns row i as a JSON object keyed by column name, in column
+// order, with nulls and non-finite floats as null. The caller frees the
+// string.
+//
+//export GetRowJSON
+func GetRowJSON(hID C.int64_t, row C.int64_t) (ret *C.char) {
+	defer recoverTo(&ret, nil)
+	h, ok := lookup(hID)
+	if !ok {
+		return nil
+	}
+	out, err := rowJSON(h.df, int(row))
+	if err != nil {
+		return nil
+	}
+	return C.CString(string(out))
+}
+
+func rowJSON(df *types.DataFrame, row int) ([]byte, error) {
+	if row < 0 || row >= df.Length {
+		return nil, fmt.Errorf("row %d out of range for %d rows", row, df.Length)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range df.Columns() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		s := df.Series[name]
+		var v interface{}
+		if s.IsValid(row) {
+			switch data := s.Data.(type) {
+			case []int64:
+				v = data[row]
+			case []float64:
+				if f := data[row]; !math.IsNaN(f) && !math.IsInf(f, 0) {
+					v = f
+				}
+			case []float32:
+				if f := float64(data[row]); !math.IsNaN(f) && !math.IsInf(f, 0) {
+					v = f
+				}
+			case []string:
+				v = data[row]
+			case []bool:
+				v = data[row]
+			default:
+				return nil, fmt.Errorf("unsupported data type for column %s", name)
+			}
+		}
+		val, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// SliceHandle registers the rows [offset, offset+length) of a frame as a new
+// handle sharing the column data, so callers can page through large frames.
+//
+//export SliceHandle
+func SliceHandle(hID C.int64_t, offset, length C.int64_t) (ret C.int64_t) {
+	defer recoverTo(&ret, -1)
+	h, ok := lookup(hID)
+	if !ok {
+		return -1
+	}
+	res, err := h.df.Slice(int(offset), int(length))
+	if err != nil {
+		return -1
+	}
+	return newHandleFrom(res)
+}
+
+// GetAggregationTypes returns the aggregation codes accepted by Aggregate as
+// a JSON object {"version": n, "aggregations": {"sum": 0, ...}}. The caller
+// frees the string.
+//
+//export GetAggregationTypes
+func GetAggregationTypes() (ret *C.char) {
+	defer recoverTo(&ret, nil)
+	aggs := make(map[string]int)
+	for a := types.Sum; a <= types.NUnique; a++ {
+		// Aggregate has no parameter for the quantile, so it rejects Quantile.
+		if a == types.Quantile {
+			continue
+		}
+		aggs[a.String()] = int(a)
+	}
+	out, err := json.Marshal(map[string]interface{}{
+		"version":      contractVersion,
+		"aggregations": aggs,
+	})
+	if err != nil {
+		return nil
+	}
+	return C.CString(string(out))
+}
+
+// GetDTypeName returns the name of a column type code, or NULL for an
+// unknown code. The caller frees the string.
+//
+//export GetDTypeName
+func GetDTypeName(code C.int) (ret *C.char) {
+	defer recoverTo(&ret, nil)
+	name, ok := dtypeNames[code]
+	if !ok {
+		return nil
+	}
+	return C.CString(name)
+}
+
+// GetBridgeVersion reports the API version of the library and the feature
+// bits it supports. Bindings call it when loading the library and refuse to
+// run against an incompatible major version.
+//
+//export GetBridgeVersion
+func GetBridgeVersion(major, minor, patch *C.int, features *C.uint64_t) {
+	defer recoverQuietly()
+	*major, *minor, *patch = bridgeMajor, bridgeMinor, bridgePatch
+	*features = C.uint64_t(featureRowAccess | featureTypeQueries | featureAsyncJobs | featureSharedMemory | featureArrowExport | featureBatchFilter | featureLargeSizes)
 }
 
 func main() {}
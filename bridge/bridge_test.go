@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// goString copies a NUL-terminated string returned by an export. Test files
+// cannot use cgo, so the string is read by hand and not freed.
+func goString(p unsafe.Pointer) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	n := 0
+	for *(*byte)(unsafe.Add(p, n)) != 0 {
+		n++
+	}
+	return string(unsafe.Slice((*byte)(p), n)), true
+}
+
+func TestGetAggregationTypes(t *testing.T) {
+	raw, ok := goString(unsafe.Pointer(GetAggregationTypes()))
+	assert.True(t, ok)
+	var contract struct {
+		Version      int            `json:"version"`
+		Aggregations map[string]int `json:"aggregations"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(raw), &contract))
+	assert.Equal(t, contractVersion, contract.Version)
+	assert.Equal(t, int(types.Sum), contract.Aggregations["sum"])
+	assert.NotContains(t, contract.Aggregations, types.Quantile.String())
+
+	// Every advertised code is accepted by Aggregate.
+	df, err := types.New(map[string]*types.Series{
+		"k": types.NewSeries("k", []int64{1, 1, 2}),
+		"v": types.NewSeries("v", []int64{3, 4, 5}),
+	})
+	assert.NoError(t, err)
+	grouped, err := df.GroupBy([]string{"k"})
+	assert.NoError(t, err)
+	for name, code := range contract.Aggregations {
+		assert.Equal(t, name, types.AggregationType(code).String())
+		_, err := grouped.Aggregate("v", types.AggregationType(code))
+		assert.NoError(t, err, name)
+	}
+}
+
+func TestGetDTypeName(t *testing.T) {
+	for code, want := range dtypeNames {
+		name, ok := goString(unsafe.Pointer(GetDTypeName(code)))
+		assert.True(t, ok)
+		assert.Equal(t, want, name)
+	}
+	// Unknown codes, below and above the table, give NULL.
+	for i, p := range []unsafe.Pointer{
+		unsafe.Pointer(GetDTypeName(-1)),
+		unsafe.Pointer(GetDTypeName(dtypeFloat32 + 1)),
+		unsafe.Pointer(GetDTypeName(1000)),
+	} {
+		_, ok := goString(p)
+		assert.False(t, ok, "unknown code %d", i)
+	}
+}
+
+func TestGetBridgeVersion(t *testing.T) {
+	// The out parameters have C types, which test files cannot name.
+	fn := reflect.ValueOf(GetBridgeVersion)
+	args := make([]reflect.Value, fn.Type().NumIn())
+	for i := range args {
+		args[i] = reflect.New(fn.Type().In(i).Elem())
+	}
+	fn.Call(args)
+	assert.EqualValues(t, bridgeMajor, args[0].Elem().Int())
+	assert.EqualValues(t, bridgeMinor, args[1].Elem().Int())
+	assert.EqualValues(t, bridgePatch, args[2].Elem().Int())
+
+	// Every feature bit is defined, distinct and advertised.
+	bits := []uint64{featureRowAccess, featureTypeQueries, featureAsyncJobs, featureSharedMemory,
+		featureArrowExport, featureBatchFilter, featureLargeSizes}
+	var all uint64
+	for _, b := range bits {
+		assert.Zero(t, all&b)
+		all |= b
+	}
+	assert.Equal(t, all, args[3].Elem().Uint())
+	assert.Equal(t, uint64(1)<<len(bits)-1, all)
+}
+
+func TestExportSharedMemory(t *testing.T) {
+	const shm = "/dev/shm"
+	if _, err := os.Stat(shm); err != nil {
+		t.Skip("no /dev/shm to read segments from")
+	}
+	df, err := types.NewOrdered(map[string]*types.Series{
+		"b": types.NewSeries("b", []string{"x", "y", "z"}),
+		"a": types.NewNullableSeries("a", []float64{1.5, 0, 3}, []bool{true, false, true}),
+	}, []string{"b", "a"})
+	assert.NoError(t, err)
+	hID := newHandleFrom(df)
+	defer DeleteDataFrame(hID)
+
+	size := hID
+	name, ok := goString(unsafe.Pointer(ExportSharedMemory(hID, &size)))
+	assert.True(t, ok)
+	path := filepath.Join(shm, name)
+	defer os.Remove(path)
+
+	// The segment holds exactly *size bytes of an Arrow IPC file.
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.EqualValues(t, size, len(data))
+	assert.Equal(t, "ARROW1", string(data[:6]))
+	back, err := dataframe.ReadIPC(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, back.Columns())
+	a, err := back.ToSeries("a")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1.5, 0, 3}, a.Data)
+	assert.False(t, a.IsValid(1))
+
+	// Each export gets a segment of its own; unknown handles give NULL.
+	again, ok := goString(unsafe.Pointer(ExportSharedMemory(hID, &size)))
+	assert.True(t, ok)
+	os.Remove(filepath.Join(shm, again))
+	assert.NotEqual(t, name, again)
+	_, ok = goString(unsafe.Pointer(ExportSharedMemory(-1, &size)))
+	assert.False(t, ok)
+}
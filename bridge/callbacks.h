@@ -0,0 +1,15 @@
+// Callback types of the go-polars C API. The guard lets the header be
+// included by every file of the bridge that needs them.
+
+#ifndef GOPOLARS_CALLBACKS_H
+#define GOPOLARS_CALLBACKS_H
+
+#include <stdint.h>
+
+// gopolars_predicate decides which of n values of type dtype, a
+// gopolars_dtype code, to keep, setting keep[i] to 1 or 0 for each. The
+// values and keep are only valid during the call. ctx is passed through
+// unchanged. A non-zero return aborts the operation.
+typedef int (*gopolars_predicate)(const void* values, int64_t n, int dtype, uint8_t* keep, void* ctx);
+
+#endif  // GOPOLARS_CALLBACKS_H
@@ -0,0 +1,79 @@
+package main
+
+/*
+#include "callbacks.h"
+
+static inline int call_predicate(gopolars_predicate fn, const void* values, int64_t n, int dtype, uint8_t* keep, void* ctx) { return fn(values, n, dtype, keep, ctx); }
+*/
+import "C"
+import (
+	"unsafe"
+
+	"go-polars/types"
+)
+
+// predicateBatchSize is the number of values FilterBatch passes per call,
+// large enough that calling into an interpreter costs little per row.
+const predicateBatchSize = 64 * 1024
+
+// FilterBatch keeps the rows of a frame where predicate, called with ctx on
+// consecutive chunks of the values of a numeric or Boolean column, sets
+// keep, instead of calling back once per row. Rows where the column is null
+// are dropped. It returns the handle of the result, or -1 on failure or
+// when predicate returns non-zero.
+//
+//export FilterBatch
+func FilterBatch(hID C.int64_t, column *C.char, predicate C.gopolars_predicate, ctx unsafe.Pointer) (ret C.int64_t) {
+	defer recoverTo(&ret, -1)
+	h, ok := lookup(hID)
+	if !ok || predicate == nil {
+		return -1
+	}
+	series, ok := h.df.Series[C.GoString(column)]
+	if !ok {
+		return -1
+	}
+	var dtype C.int
+	var base unsafe.Pointer
+	var size uintptr
+	switch data := series.Data.(type) {
+	case []int64:
+		dtype, size = dtypeInt64, unsafe.Sizeof(data[0])
+		base = unsafe.Pointer(unsafe.SliceData(data))
+	case []float64:
+		dtype, size = dtypeFloat64, unsafe.Sizeof(data[0])
+		base = unsafe.Pointer(unsafe.SliceData(data))
+	case []bool:
+		dtype, size = dtypeBool, unsafe.Sizeof(data[0])
+		base = unsafe.Pointer(unsafe.SliceData(data))
+	case []float32:
+		dtype, size = dtypeFloat32, unsafe.Sizeof(data[0])
+		base = unsafe.Pointer(unsafe.SliceData(data))
+	default:
+		return -1
+	}
+
+	keep := make([]C.uint8_t, series.Length)
+	for offset := 0; offset < series.Length; offset += predicateBatchSize {
+		n := min(predicateBatchSize, series.Length-offset)
+		values := unsafe.Add(base, uintptr(offset)*size)
+		if C.call_predicate(predicate, values, C.int64_t(n), dtype, &keep[offset], ctx) != 0 {
+			return -1
+		}
+	}
+	var rows []int
+	for i, k := range keep {
+		if k != 0 && series.IsValid(i) {
+			rows = append(rows, i)
+		}
+	}
+	filtered := make(map[string]*types.Series, len(h.df.Series))
+	for name, s := range h.df.Series {
+		filtered[name] = s.Take(rows)
+	}
+	res, err := types.NewOrdered(filtered, h.df.Columns())
+	if err != nil {
+		return -1
+	}
+	return newHandleFrom(res)
+}
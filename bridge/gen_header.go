@@ -0,0 +1,298 @@
+//go:build ignore
+
+// gen_header writes gopolars.h, the installable C header of the bridge,
+// from the exports, their doc comments and the constants of this package,
+// so bindings in any language can be written against it. Run it with
+// go generate in this directory.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go-polars/types"
+)
+
+var prototype = regexp.MustCompile(`^\s*[A-Za-z_][\w \t\*]*?\b(\w+)\(.*\);\s*$`)
+
+// constGroup is a const block whose names start with prefix, emitted as C
+// constants named macro + the rest of the Go name in upper snake case.
+type constGroup struct {
+	prefix, macro string
+	enum          string // C enum name, or "" for #defines
+	doc           []string
+	names         []string
+	values        map[string]int64
+}
+
+func main() {
+	out := flag.String("o", "../include/gopolars.h", "output file")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	paths, err := filepath.Glob("*.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+	var files []*ast.File
+	for _, path := range paths {
+		if path == "gen_header.go" || strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			log.Fatal(err)
+		}
+		files = append(files, f)
+	}
+
+	// The preamble of bridge.go declares every export with C parameter
+	// names; it fixes the order of the header.
+	var order []string
+	protos := make(map[string]string)
+	docs := make(map[string][]string)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Tok != token.IMPORT || d.Doc == nil || len(d.Specs) != 1 {
+					continue
+				}
+				if d.Specs[0].(*ast.ImportSpec).Path.Value != `"C"` {
+					continue
+				}
+				for _, line := range strings.Split(d.Doc.Text(), "\n") {
+					if m := prototype.FindStringSubmatch(line); m != nil && !strings.HasPrefix(strings.TrimSpace(line), "static") {
+						order = append(order, m[1])
+						protos[m[1]] = strings.TrimSpace(line)
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Doc == nil {
+					continue
+				}
+				var doc []string
+				exported := false
+				for _, c := range d.Doc.List {
+					if c.Text == "//export "+d.Name.Name {
+						exported = true
+						continue
+					}
+					doc = append(doc, c.Text)
+				}
+				if len(doc) > 0 && doc[len(doc)-1] == "//" {
+					doc = doc[:len(doc)-1]
+				}
+				if exported {
+					docs[d.Name.Name] = doc
+				}
+			}
+		}
+	}
+	for name := range docs {
+		if _, ok := protos[name]; !ok {
+			log.Fatalf("export %s is not declared in the preamble of bridge.go", name)
+		}
+	}
+	for _, name := range order {
+		if _, ok := docs[name]; !ok {
+			log.Fatalf("%s is declared in the preamble but not exported", name)
+		}
+	}
+
+	groups := []*constGroup{
+		{prefix: "bridge", macro: "GOPOLARS_VERSION_"},
+		{prefix: "contractVersion", macro: "GOPOLARS_CONTRACT_VERSION"},
+		{prefix: "dtype", macro: "GOPOLARS_DTYPE_", enum: "gopolars_dtype"},
+		{prefix: "job", macro: "GOPOLARS_JOB_", enum: "gopolars_job_state"},
+		{prefix: "feature", macro: "GOPOLARS_FEATURE_"},
+	}
+	for _, f := range files {
+		collectConsts(f, groups)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`/* Code generated by gen_header.go; DO NOT EDIT. */
+
+/* gopolars.h is the C API of the go-polars shared library. Frames live in
+ * the library and are referenced by int64_t handles; every handle returned
+ * by a function must be released with DeleteDataFrame. */
+
+#ifndef GOPOLARS_H
+#define GOPOLARS_H
+
+#include <stdint.h>
+
+`)
+	abi, err := os.ReadFile("arrow_abi.h")
+	if err != nil {
+		log.Fatal(err)
+	}
+	b.Write(abi)
+	callbacks, err := os.ReadFile("callbacks.h")
+	if err != nil {
+		log.Fatal(err)
+	}
+	b.WriteString("\n")
+	b.Write(callbacks)
+	b.WriteString(`
+#ifdef __cplusplus
+extern "C" {
+#endif
+`)
+	for _, g := range groups {
+		if len(g.names) == 0 {
+			log.Fatalf("no constants found with prefix %s", g.prefix)
+		}
+		b.WriteString("\n")
+		for _, line := range g.doc {
+			b.WriteString(line + "\n")
+		}
+		if g.enum != "" {
+			fmt.Fprintf(&b, "enum %s {\n", g.enum)
+			for _, name := range g.names {
+				fmt.Fprintf(&b, "  %s = %d,\n", g.cName(name), g.values[name])
+			}
+			b.WriteString("};\n")
+			continue
+		}
+		for _, name := range g.names {
+			if g.prefix == "feature" {
+				fmt.Fprintf(&b, "#define %s UINT64_C(%#x)\n", g.cName(name), g.values[name])
+			} else {
+				fmt.Fprintf(&b, "#define %s %d\n", g.cName(name), g.values[name])
+			}
+		}
+	}
+
+	b.WriteString("\n// Aggregation codes accepted by Aggregate.\nenum gopolars_agg {\n")
+	for a := types.Sum; a <= types.NUnique; a++ {
+		fmt.Fprintf(&b, "  GOPOLARS_AGG_%s = %d,\n", strings.ToUpper(a.String()), int(a))
+	}
+	b.WriteString("};\n")
+
+	for _, name := range order {
+		b.WriteString("\n")
+		for _, line := range docs[name] {
+			b.WriteString(line + "\n")
+		}
+		b.WriteString(protos[name] + "\n")
+	}
+	b.WriteString(`
+#ifdef __cplusplus
+}
+#endif
+
+#endif  // GOPOLARS_H
+`)
+
+	if err := os.MkdirAll(filepath.Dir(*out), 0o755); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, b.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (g *constGroup) cName(name string) string {
+	rest := strings.TrimPrefix(name, g.prefix)
+	var snake []rune
+	for i, r := range rest {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(rune(rest[i-1])) {
+			snake = append(snake, '_')
+		}
+		snake = append(snake, unicode.ToUpper(r))
+	}
+	return g.macro + string(snake)
+}
+
+// collectConsts evaluates the const blocks of f whose names belong to one
+// of groups. Only the forms used for bridge constants are understood:
+// integer literals, iota, shifts, sums, products, negation and earlier
+// constants.
+func collectConsts(f *ast.File, groups []*constGroup) {
+	for _, decl := range f.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok || d.Tok != token.CONST {
+			continue
+		}
+		known := make(map[string]int64)
+		var last []ast.Expr
+		for iota, spec := range d.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if len(vs.Values) > 0 {
+				last = vs.Values
+			}
+			for i, id := range vs.Names {
+				v := evalConst(last[i], int64(iota), known)
+				known[id.Name] = v
+				for _, g := range groups {
+					if !strings.HasPrefix(id.Name, g.prefix) {
+						continue
+					}
+					if g.values == nil {
+						g.values = make(map[string]int64)
+						if d.Doc != nil {
+							for _, c := range d.Doc.List {
+								g.doc = append(g.doc, c.Text)
+							}
+						}
+					}
+					g.names = append(g.names, id.Name)
+					g.values[id.Name] = v
+				}
+			}
+		}
+	}
+}
+
+func evalConst(e ast.Expr, iota int64, known map[string]int64) int64 {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		v, err := strconv.ParseInt(e.Value, 0, 64)
+		if err != nil {
+			log.Fatalf("constant %s: %v", e.Value, err)
+		}
+		return v
+	case *ast.Ident:
+		if e.Name == "iota" {
+			return iota
+		}
+		v, ok := known[e.Name]
+		if !ok {
+			log.Fatalf("unknown constant %s", e.Name)
+		}
+		return v
+	case *ast.ParenExpr:
+		return evalConst(e.X, iota, known)
+	case *ast.UnaryExpr:
+		if e.Op == token.SUB {
+			return -evalConst(e.X, iota, known)
+		}
+	case *ast.BinaryExpr:
+		x, y := evalConst(e.X, iota, known), evalConst(e.Y, iota, known)
+		switch e.Op {
+		case token.SHL:
+			return x << y
+		case token.OR:
+			return x | y
+		case token.ADD:
+			return x + y
+		case token.MUL:
+			return x * y
+		}
+	}
+	log.Fatalf("cannot evaluate constant expression %T", e)
+	return 0
+}
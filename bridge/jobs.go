@@ -0,0 +1,163 @@
+package main
+
+/*
+#include <stdint.h>
+*/
+import "C"
+import (
+	"context"
+	"sync"
+
+	"go-polars/types"
+)
+
+// Job states returned by PollJob.
+const (
+	jobFailed  C.int = -1 // the operation failed, or the job is unknown
+	jobRunning C.int = 0
+	jobDone    C.int = 1
+)
+
+// job is an operation running in its own goroutine so the caller, typically
+// a Python thread holding the GIL, is not blocked while it runs.
+type job struct {
+	state     C.int
+	result    C.int64_t // handle of the result once done
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// jobs is guarded by jobsMu. A job finishing takes handlesMu while holding
+// jobsMu, never the other way round.
+var (
+	jobsMu  sync.Mutex
+	jobs              = make(map[C.int64_t]*job)
+	nextJob C.int64_t = 1
+)
+
+// submit starts op on a new goroutine and returns the id of its job. op
+// should give up once its context is done, which CancelJob brings about.
+func submit(op func(ctx context.Context) (*types.DataFrame, error)) C.int64_t {
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{state: jobRunning, cancel: cancel}
+	jobsMu.Lock()
+	id := nextJob
+	nextJob++
+	jobs[id] = j
+	jobsMu.Unlock()
+
+	go func() {
+		defer cancel()
+		res := runJob(ctx, op)
+		jobsMu.Lock()
+		defer jobsMu.Unlock()
+		switch {
+		case j.cancelled:
+		case res == nil:
+			j.state = jobFailed
+		default:
+			j.result = newHandleFrom(res)
+			j.state = jobDone
+		}
+	}()
+	return id
+}
+
+// runJob runs op, turning an error or a panic into a nil result; a panic
+// escaping a job's goroutine would abort the host process.
+func runJob(ctx context.Context, op func(ctx context.Context) (*types.DataFrame, error)) (res *types.DataFrame) {
+	defer recoverTo(&res, nil)
+	df, err := op(ctx)
+	if err != nil {
+		return nil
+	}
+	return df
+}
+
+// SubmitSort starts SortByColumn as a job and returns its id, or -1 for an
+// unknown handle. The job sorts the columns the frame has now; the handle
+// may be changed while it runs.
+//
+//export SubmitSort
+func SubmitSort(hID C.int64_t, column *C.char, asc C.int) (ret C.int64_t) {
+	defer recoverTo(&ret, -1)
+	h, ok := lookup(hID)
+	if !ok {
+		return -1
+	}
+	df, err := h.snapshot()
+	if err != nil {
+		return -1
+	}
+	col := C.GoString(column)
+	return submit(func(ctx context.Context) (*types.DataFrame, error) {
+		return df.SortByColumnContext(ctx, col, asc != 0)
+	})
+}
+
+// SubmitGroupBy starts GroupBy as a job and returns its id, or -1 for an
+// unknown handle. Like SubmitSort, it works on the columns the frame has
+// now.
+//
+//export SubmitGroupBy
+func SubmitGroupBy(hID C.int64_t, cols **C.char, n C.int) (ret C.int64_t) {
+	defer recoverTo(&ret, -1)
+	h, ok := lookup(hID)
+	if !ok {
+		return -1
+	}
+	df, err := h.snapshot()
+	if err != nil {
+		return -1
+	}
+	goCols := goStrings(cols, n)
+	return submit(func(ctx context.Context) (*types.DataFrame, error) {
+		return df.GroupByContext(ctx, goCols)
+	})
+}
+
+// PollJob reports the state of a job without blocking. Once it returns done
+// (1), the result handle is stored in *result, is owned by the caller and the
+// job id is released; failed (-1) also releases it. A job still running
+// returns 0.
+//
+//export PollJob
+func PollJob(jobID C.int64_t, result *C.int64_t) (ret C.int) {
+	defer recoverTo(&ret, jobFailed)
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	j, ok := jobs[jobID]
+	if !ok {
+		return jobFailed
+	}
+	switch j.state {
+	case jobRunning:
+		return jobRunning
+	case jobDone:
+		*result = j.result
+	}
+	delete(jobs, jobID)
+	return j.state
+}
+
+// CancelJob releases a job and discards its result. An operation still
+// running is interrupted at its next check of the job's context, which it
+// makes every few thousand steps. It returns 0, or -1 for an unknown job.
+//
+//export CancelJob
+func CancelJob(jobID C.int64_t) (ret C.int) {
+	defer recoverTo(&ret, -1)
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	j, ok := jobs[jobID]
+	if !ok {
+		return -1
+	}
+	delete(jobs, jobID)
+	j.cancelled = true
+	j.cancel()
+	if j.state == jobDone {
+		release(j.result)
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobsSnapshotHandle(t *testing.T) {
+	n := 200000
+	keys := make([]int64, n)
+	for i := range keys {
+		keys[i] = int64((i * 7919) % n)
+	}
+	df, err := types.New(map[string]*types.Series{"k": types.NewSeries("k", keys)})
+	assert.NoError(t, err)
+	hID := newHandleFrom(df)
+	defer DeleteDataFrame(hID)
+	h, _ := lookup(hID)
+
+	frame, err := h.snapshot()
+	assert.NoError(t, err)
+	id := submit(func(ctx context.Context) (*types.DataFrame, error) {
+		return frame.SortByColumnContext(ctx, "k", true)
+	})
+	// Columns added while the job runs neither race with it nor reach it.
+	for i := 0; i < 50; i++ {
+		assert.NoError(t, h.put(types.NewSeries(fmt.Sprintf("c%d", i), keys)))
+	}
+
+	result := hID
+	state := PollJob(id, &result)
+	for state == jobRunning {
+		time.Sleep(time.Millisecond)
+		state = PollJob(id, &result)
+	}
+	assert.Equal(t, jobDone, state)
+	sorted, ok := lookup(result)
+	assert.True(t, ok)
+	rows, cols := sorted.df.Shape()
+	assert.Equal(t, n, rows)
+	assert.Equal(t, 1, cols)
+	DeleteDataFrame(result)
+}
+
+func TestCancelJob(t *testing.T) {
+	started := make(chan struct{})
+	id := submit(func(ctx context.Context) (*types.DataFrame, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	<-started
+	// The job only returns once its context is cancelled.
+	assert.EqualValues(t, 0, CancelJob(id))
+	result := id
+	assert.Equal(t, jobFailed, PollJob(id, &result))
+
+	keys := make([]string, 100000)
+	for i := range keys {
+		keys[i] = fmt.Sprint(len(keys) - i)
+	}
+	df, err := types.New(map[string]*types.Series{"k": types.NewSeries("k", keys)})
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = df.SortByColumnContext(ctx, "k", true)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = df.GroupByContext(ctx, []string{"k"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,91 @@
+package main
+
+/*
+#cgo linux LDFLAGS: -lrt
+#include <errno.h>
+#include <fcntl.h>
+#include <stdint.h>
+#include <stdlib.h>
+#include <string.h>
+#include <sys/mman.h>
+#include <unistd.h>
+
+// write_shm creates the shared-memory segment name holding a copy of the
+// size bytes at data. It returns 0, or -1 with errno set and no segment
+// left behind.
+static int write_shm(const char* name, const void* data, size_t size) {
+	int fd = shm_open(name, O_CREAT | O_EXCL | O_RDWR, 0600);
+	if (fd < 0) {
+		return -1;
+	}
+	void* p = MAP_FAILED;
+	if (ftruncate(fd, (off_t)size) == 0) {
+		p = mmap(NULL, size, PROT_READ | PROT_WRITE, MAP_SHARED, fd, 0);
+	}
+	if (p == MAP_FAILED) {
+		int saved = errno;
+		close(fd);
+		shm_unlink(name);
+		errno = saved;
+		return -1;
+	}
+	memcpy(p, data, size);
+	munmap(p, size);
+	close(fd);
+	return 0;
+}
+*/
+import "C"
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"unsafe"
+
+	"go-polars/types"
+)
+
+var nextSegment atomic.Uint64
+
+// ExportSharedMemory writes a frame in the Arrow IPC file format into a new
+// POSIX shared-memory segment and returns the segment's name, without the
+// leading slash, storing its size in bytes in *size. Other processes map it
+// by name, e.g. with Python's multiprocessing.shared_memory, and read the
+// first *size bytes; the segment may be rounded up to a page. The caller
+// frees the string and unlinks the segment. It returns NULL on failure.
+//
+//export ExportSharedMemory
+func ExportSharedMemory(hID C.int64_t, size *C.int64_t) (ret *C.char) {
+	defer recoverTo(&ret, nil)
+	h, ok := lookup(hID)
+	if !ok {
+		return nil
+	}
+	data, err := ipcBytes(h.df)
+	if err != nil {
+		return nil
+	}
+
+	// macOS limits names to 31 bytes, slash included.
+	name := fmt.Sprintf("gopolars-%d-%d", os.Getpid(), nextSegment.Add(1))
+	cName := C.CString("/" + name)
+	defer C.free(unsafe.Pointer(cName))
+	if rc := C.write_shm(cName, unsafe.Pointer(&data[0]), C.size_t(len(data))); rc != 0 {
+		return nil
+	}
+	*size = C.int64_t(len(data))
+	return C.CString(name)
+}
+
+func ipcBytes(df *types.DataFrame) ([]byte, error) {
+	out, err := toFrame(df)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := out.WriteIPCTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,67 @@
+package dataframe
+
+import (
+	"fmt"
+	"sync"
+
+	"go-polars/types"
+)
+
+// Apply calls fn with every group as a DataFrame of its own, holding all
+// columns of the grouped frame, and concatenates the frames fn returns.
+// Groups are processed by up to types.MaxThreads goroutines, so fn must be safe
+// for concurrent use, but results are concatenated in order of each group's
+// first appearance. Every result must have the columns and types of the
+// first one; fn returning an error stops the whole Apply.
+func (gdf *GroupedDataFrame) Apply(fn func(group *DataFrame) (*DataFrame, error)) (out *DataFrame, err error) {
+	defer gdf.df.track("Apply", map[string]interface{}{"by": gdf.columns})(&out, &err)
+	rows, err := gdf.groupRows()
+	if err != nil {
+		return nil, err
+	}
+	groups := len(rows)
+
+	results := make([]*DataFrame, groups)
+	errs := make([]error, groups)
+	next := make(chan int)
+	var wg sync.WaitGroup
+	workers := types.MaxThreads()
+	if workers > groups {
+		workers = groups
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for g := range next {
+				group, err := gdf.df.take(rows[g])
+				if err != nil {
+					errs[g] = err
+					continue
+				}
+				results[g], errs[g] = fn(group)
+				if errs[g] == nil && results[g] == nil {
+					errs[g] = fmt.Errorf("callback returned no frame")
+				}
+			}
+		}()
+	}
+	for g := range rows {
+		next <- g
+	}
+	close(next)
+	wg.Wait()
+
+	for g, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("group %d: %w", g, err)
+		}
+	}
+	if groups == 0 {
+		return New(nil)
+	}
+	if err := sameSchema(results); err != nil {
+		return nil, fmt.Errorf("apply results differ: %w", err)
+	}
+	return Concat(results, HowVertical)
+}
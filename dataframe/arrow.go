@@ -0,0 +1,408 @@
+package dataframe
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"go-polars/types"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// maxStringBytes is the most text a column in the Arrow String layout can
+// hold, its offsets being 32-bit.
+const maxStringBytes = math.MaxInt32
+
+// ArrowOption configures ToArrow
+type ArrowOption func(*arrowConfig)
+
+type arrowConfig struct {
+	largeStrings bool
+}
+
+// ArrowLargeStrings controls whether String columns are exported in the
+// LargeString layout, whose offsets are 64-bit, even when their text fits
+// the String layout. Columns of more than 2 GiB of text use LargeString
+// regardless, since String offsets would overflow.
+func ArrowLargeStrings(large bool) ArrowOption {
+	return func(c *arrowConfig) { c.largeStrings = large }
+}
+
+// arrowType maps a Series' Go backing slice onto the matching Arrow type.
+func arrowType(data interface{}, cfg arrowConfig) (arrow.DataType, error) {
+	switch data.(type) {
+	case []int64:
+		return arrow.PrimitiveTypes.Int64, nil
+	case []float64:
+		return arrow.PrimitiveTypes.Float64, nil
+	case []float32:
+		return arrow.PrimitiveTypes.Float32, nil
+	case []int8:
+		return arrow.PrimitiveTypes.Int8, nil
+	case []int16:
+		return arrow.PrimitiveTypes.Int16, nil
+	case []int32:
+		return arrow.PrimitiveTypes.Int32, nil
+	case []uint8:
+		return arrow.PrimitiveTypes.Uint8, nil
+	case []uint16:
+		return arrow.PrimitiveTypes.Uint16, nil
+	case []uint32:
+		return arrow.PrimitiveTypes.Uint32, nil
+	case []uint64:
+		return arrow.PrimitiveTypes.Uint64, nil
+	case []string:
+		if cfg.largeStrings || stringBytes(data.([]string)) > maxStringBytes {
+			return arrow.BinaryTypes.LargeString, nil
+		}
+		return arrow.BinaryTypes.String, nil
+	case []bool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case *types.Categorical:
+		// The categories form the dictionary and the codes its indices.
+		if stringBytes(data.(*types.Categorical).Categories) > maxStringBytes {
+			return nil, fmt.Errorf("categories of more than %d bytes for arrow conversion", maxStringBytes)
+		}
+		return &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Uint32, ValueType: arrow.BinaryTypes.String}, nil
+	default:
+		return nil, fmt.Errorf("unsupported data type %T for arrow conversion", data)
+	}
+}
+
+// stringBytes returns the total length of the strings
+func stringBytes(data []string) int {
+	n := 0
+	for _, v := range data {
+		n += len(v)
+	}
+	return n
+}
+
+// ToArrow converts the DataFrame into a single Arrow record batch holding
+// every column. The caller owns the returned record and must Release it.
+func (df *DataFrame) ToArrow(opts ...ArrowOption) (arrow.RecordBatch, error) {
+	var cfg arrowConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return df.toArrow(memory.DefaultAllocator, cfg)
+}
+
+func (df *DataFrame) toArrow(mem memory.Allocator, cfg arrowConfig) (arrow.RecordBatch, error) {
+	columns := df.Columns()
+	fields := make([]arrow.Field, len(columns))
+	for i, name := range columns {
+		dt, err := arrowType(df.series[name].Data, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", name, err)
+		}
+		fields[i] = arrow.Field{Name: name, Type: dt, Nullable: true}
+	}
+	schema := arrow.NewSchema(fields, nil)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+
+	for i, name := range columns {
+		s := df.series[name]
+		var valid []bool
+		if s.HasNulls() {
+			valid = make([]bool, s.Length)
+			for j := range valid {
+				valid[j] = s.IsValid(j)
+			}
+		}
+		switch data := s.Data.(type) {
+		case []int64:
+			b.Field(i).(*array.Int64Builder).AppendValues(data, valid)
+		case []float64:
+			b.Field(i).(*array.Float64Builder).AppendValues(data, valid)
+		case []float32:
+			b.Field(i).(*array.Float32Builder).AppendValues(data, valid)
+		case []string:
+			switch fb := b.Field(i).(type) {
+			case *array.LargeStringBuilder:
+				fb.AppendValues(data, valid)
+			case *array.StringBuilder:
+				fb.AppendValues(data, valid)
+			}
+		case []bool:
+			b.Field(i).(*array.BooleanBuilder).AppendValues(data, valid)
+		case []int8:
+			b.Field(i).(*array.Int8Builder).AppendValues(data, valid)
+		case []int16:
+			b.Field(i).(*array.Int16Builder).AppendValues(data, valid)
+		case []int32:
+			b.Field(i).(*array.Int32Builder).AppendValues(data, valid)
+		case []uint8:
+			b.Field(i).(*array.Uint8Builder).AppendValues(data, valid)
+		case []uint16:
+			b.Field(i).(*array.Uint16Builder).AppendValues(data, valid)
+		case []uint32:
+			b.Field(i).(*array.Uint32Builder).AppendValues(data, valid)
+		case []uint64:
+			b.Field(i).(*array.Uint64Builder).AppendValues(data, valid)
+		case *types.Categorical:
+			if err := appendCategorical(mem, b.Field(i).(*array.BinaryDictionaryBuilder), data, valid); err != nil {
+				return nil, fmt.Errorf("column %s: %w", name, err)
+			}
+		}
+	}
+
+	return b.NewRecordBatch(), nil
+}
+
+// appendCategorical appends the codes of data to db with the categories as
+// the dictionary, so a code keeps its meaning.
+func appendCategorical(mem memory.Allocator, db *array.BinaryDictionaryBuilder, data *types.Categorical, valid []bool) error {
+	sb := array.NewStringBuilder(mem)
+	defer sb.Release()
+	sb.AppendValues(data.Categories, nil)
+	dict := sb.NewStringArray()
+	defer dict.Release()
+	if err := db.InsertStringDictValues(dict); err != nil {
+		return err
+	}
+	codes := make([]int, len(data.Codes))
+	for i, code := range data.Codes {
+		codes[i] = int(code)
+	}
+	db.AppendIndices(codes, valid)
+	return nil
+}
+
+// stringArray is satisfied by Arrow String and LargeString arrays
+type stringArray interface {
+	arrow.Array
+	Value(i int) string
+}
+
+// FromArrow builds a DataFrame by copying the columns of an Arrow record
+// batch. Arrow nulls are preserved in the Series validity.
+func FromArrow(rec arrow.RecordBatch) (*DataFrame, error) {
+	return fromArrowBatches(rec.Schema(), []arrow.RecordBatch{rec})
+}
+
+// fromArrowBatches concatenates record batches sharing one schema.
+func fromArrowBatches(schema *arrow.Schema, batches []arrow.RecordBatch) (*DataFrame, error) {
+	series := make(map[string]*types.Series, schema.NumFields())
+	names := make([]string, 0, schema.NumFields())
+	for col, field := range schema.Fields() {
+		names = append(names, field.Name)
+		var rows int
+		for _, rec := range batches {
+			rows += int(rec.NumRows())
+		}
+		valid := make([]bool, 0, rows)
+		for _, rec := range batches {
+			arr := rec.Column(col)
+			for i := 0; i < arr.Len(); i++ {
+				valid = append(valid, arr.IsValid(i))
+			}
+		}
+
+		switch field.Type.ID() {
+		case arrow.INT64:
+			data := make([]int64, 0, rows)
+			for _, rec := range batches {
+				data = append(data, rec.Column(col).(*array.Int64).Int64Values()...)
+			}
+			series[field.Name] = types.NewNullableSeries(field.Name, zeroNulls(data, valid), valid)
+		case arrow.FLOAT64:
+			data := make([]float64, 0, rows)
+			for _, rec := range batches {
+				data = append(data, rec.Column(col).(*array.Float64).Float64Values()...)
+			}
+			series[field.Name] = types.NewNullableSeries(field.Name, zeroNulls(data, valid), valid)
+		case arrow.INT8:
+			data := make([]int8, 0, rows)
+			for _, rec := range batches {
+				data = append(data, rec.Column(col).(*array.Int8).Int8Values()...)
+			}
+			series[field.Name] = types.NewNullableSeries(field.Name, zeroNulls(data, valid), valid)
+		case arrow.INT16:
+			data := make([]int16, 0, rows)
+			for _, rec := range batches {
+				data = append(data, rec.Column(col).(*array.Int16).Int16Values()...)
+			}
+			series[field.Name] = types.NewNullableSeries(field.Name, zeroNulls(data, valid), valid)
+		case arrow.INT32:
+			data := make([]int32, 0, rows)
+			for _, rec := range batches {
+				data = append(data, rec.Column(col).(*array.Int32).Int32Values()...)
+			}
+			series[field.Name] = types.NewNullableSeries(field.Name, zeroNulls(data, valid), valid)
+		case arrow.UINT8:
+			data := make([]uint8, 0, rows)
+			for _, rec := range batches {
+				data = append(data, rec.Column(col).(*array.Uint8).Uint8Values()...)
+			}
+			series[field.Name] = types.NewNullableSeries(field.Name, zeroNulls(data, valid), valid)
+		case arrow.UINT16:
+			data := make([]uint16, 0, rows)
+			for _, rec := range batches {
+				data = append(data, rec.Column(col).(*array.Uint16).Uint16Values()...)
+			}
+			series[field.Name] = types.NewNullableSeries(field.Name, zeroNulls(data, valid), valid)
+		case arrow.UINT32:
+			data := make([]uint32, 0, rows)
+			for _, rec := range batches {
+				data = append(data, rec.Column(col).(*array.Uint32).Uint32Values()...)
+			}
+			series[field.Name] = types.NewNullableSeries(field.Name, zeroNulls(data, valid), valid)
+		case arrow.UINT64:
+			data := make([]uint64, 0, rows)
+			for _, rec := range batches {
+				data = append(data, rec.Column(col).(*array.Uint64).Uint64Values()...)
+			}
+			series[field.Name] = types.NewNullableSeries(field.Name, zeroNulls(data, valid), valid)
+		case arrow.FLOAT32:
+			data := make([]float32, 0, rows)
+			for _, rec := range batches {
+				data = append(data, rec.Column(col).(*array.Float32).Float32Values()...)
+			}
+			series[field.Name] = types.NewNullableSeries(field.Name, zeroNulls(data, valid), valid)
+		case arrow.STRING, arrow.LARGE_STRING:
+			data := make([]string, 0, rows)
+			for _, rec := range batches {
+				arr := rec.Column(col).(stringArray)
+				for i := 0; i < arr.Len(); i++ {
+					data = append(data, arr.Value(i))
+				}
+			}
+			series[field.Name] = types.NewNullableSeries(field.Name, zeroNulls(data, valid), valid)
+		case arrow.BOOL:
+			data := make([]bool, 0, rows)
+			for _, rec := range batches {
+				arr := rec.Column(col).(*array.Boolean)
+				for i := 0; i < arr.Len(); i++ {
+					data = append(data, arr.Value(i))
+				}
+			}
+			series[field.Name] = types.NewNullableSeries(field.Name, zeroNulls(data, valid), valid)
+		case arrow.DICTIONARY:
+			cat, err := categoricalFromArrow(batches, col, rows)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", field.Name, err)
+			}
+			series[field.Name] = types.NewNullableSeries(field.Name, cat, valid)
+		default:
+			return nil, fmt.Errorf("column %s: unsupported arrow type %s", field.Name, field.Type)
+		}
+	}
+
+	return newOrdered(series, names)
+}
+
+// zeroNulls clears the values under nulls, which Arrow leaves undefined, so
+// null slots hold the zero value as they do in every other Series.
+func zeroNulls[T any](data []T, valid []bool) []T {
+	var zero T
+	for i, ok := range valid {
+		if !ok {
+			data[i] = zero
+		}
+	}
+	return data
+}
+
+// categoricalFromArrow reads a dictionary column of text into a
+// Categorical. Each batch may carry its own dictionary, so categories are
+// numbered in order of first appearance across them.
+func categoricalFromArrow(batches []arrow.RecordBatch, col, rows int) (*types.Categorical, error) {
+	cat := &types.Categorical{Codes: make([]uint32, 0, rows)}
+	lookup := make(map[string]uint32)
+	for _, rec := range batches {
+		arr := rec.Column(col).(*array.Dictionary)
+		dict, ok := arr.Dictionary().(stringArray)
+		if !ok {
+			return nil, fmt.Errorf("unsupported dictionary of %s", arr.Dictionary().DataType())
+		}
+		codes := make([]uint32, dict.Len())
+		for i := range codes {
+			v := dict.Value(i)
+			code, ok := lookup[v]
+			if !ok {
+				code = uint32(len(cat.Categories))
+				lookup[v] = code
+				cat.Categories = append(cat.Categories, v)
+			}
+			codes[i] = code
+		}
+		for i := 0; i < arr.Len(); i++ {
+			var code uint32
+			if arr.IsValid(i) {
+				code = codes[arr.GetValueIndex(i)]
+			}
+			cat.Codes = append(cat.Codes, code)
+		}
+	}
+	return cat, nil
+}
+
+// WriteIPC writes the DataFrame to path in the Arrow IPC file format
+func (df *DataFrame) WriteIPC(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := df.WriteIPCTo(f); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// WriteIPCTo writes the DataFrame to w in the Arrow IPC file format
+func (df *DataFrame) WriteIPCTo(w io.Writer) error {
+	rec, err := df.ToArrow()
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+
+	fw, err := ipc.NewFileWriter(w, ipc.WithSchema(rec.Schema()))
+	if err != nil {
+		return err
+	}
+	if err := fw.Write(rec); err != nil {
+		fw.Close()
+		return err
+	}
+	return fw.Close()
+}
+
+// ReadIPC reads an Arrow IPC file, concatenating all of its record batches
+func ReadIPC(path string) (*DataFrame, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := ipc.NewFileReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	batches := make([]arrow.RecordBatch, 0, r.NumRecords())
+	row := 0
+	for i := 0; i < r.NumRecords(); i++ {
+		rec, err := r.RecordBatchAt(i)
+		if err != nil {
+			return nil, newParseError(row, 0, -1, "", "", fmt.Errorf("record batch %d: %w", i, err))
+		}
+		row += int(rec.NumRows())
+		defer rec.Release()
+		batches = append(batches, rec)
+	}
+
+	return fromArrowBatches(r.Schema(), batches)
+}
@@ -0,0 +1,158 @@
+package dataframe
+
+import (
+	"fmt"
+	"math"
+
+	"go-polars/types"
+)
+
+// Comparison is the row-level difference between two versions of a frame,
+// as computed by Compare
+type Comparison struct {
+	// Added holds the rows of after whose key is not in before.
+	Added *DataFrame
+	// Removed holds the rows of before whose key is not in after.
+	Removed *DataFrame
+	// Changed holds one row per key found in both frames with differing
+	// values: the key columns, then for every compared column c the columns
+	// c_before, c_after and a Boolean c_changed.
+	Changed *DataFrame
+	// Unchanged counts the keys found in both frames with equal values.
+	Unchanged int
+}
+
+// Compare matches the rows of before and after on the key columns, which
+// must be unique within each frame, and reports which rows were added,
+// removed or changed. The non-key columns of before that also exist in
+// after are compared; nulls equal nulls and NaN equals NaN. Columns present
+// on one side only are ignored.
+func Compare(before, after *DataFrame, keys []string) (*Comparison, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("compare needs at least one key column")
+	}
+	cfg := joinConfig{nullsEqual: true}
+	for _, side := range []struct {
+		name string
+		df   *DataFrame
+	}{{"before", before}, {"after", after}} {
+		if _, dup, err := side.df.firstOccurrences(keys, cfg); err != nil {
+			return nil, err
+		} else if dup {
+			return nil, fmt.Errorf("key %v is not unique in the %s frame", keys, side.name)
+		}
+	}
+	matches, matched, err := matchRows(before, after, keys, keys, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	isKey := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		isKey[k] = true
+	}
+	var compared []string
+	var equal []func(i, j int) bool
+	for _, name := range before.order {
+		as, ok := after.series[name]
+		if isKey[name] || !ok {
+			continue
+		}
+		eq, err := valuesEqual(before.series[name], as)
+		if err != nil {
+			return nil, err
+		}
+		compared = append(compared, name)
+		equal = append(equal, eq)
+	}
+
+	var removed, bi, ai []int
+	var flags [][]bool
+	unchanged := 0
+	for i, js := range matches {
+		if len(js) == 0 {
+			removed = append(removed, i)
+			continue
+		}
+		j := js[0]
+		row := make([]bool, len(compared))
+		differs := false
+		for c, eq := range equal {
+			row[c] = !eq(i, j)
+			differs = differs || row[c]
+		}
+		if !differs {
+			unchanged++
+			continue
+		}
+		bi, ai = append(bi, i), append(ai, j)
+		flags = append(flags, row)
+	}
+	var added []int
+	for j, m := range matched {
+		if !m {
+			added = append(added, j)
+		}
+	}
+
+	out := &Comparison{Unchanged: unchanged}
+	if out.Added, err = after.take(added); err != nil {
+		return nil, err
+	}
+	if out.Removed, err = before.take(removed); err != nil {
+		return nil, err
+	}
+
+	series := make(map[string]*types.Series, len(keys)+3*len(compared))
+	order := make([]string, 0, len(keys)+3*len(compared))
+	for _, k := range keys {
+		series[k] = before.series[k].Take(bi)
+		order = append(order, k)
+	}
+	for c, name := range compared {
+		b, a, ch := name+"_before", name+"_after", name+"_changed"
+		changed := make([]bool, len(flags))
+		for r, row := range flags {
+			changed[r] = row[c]
+		}
+		series[b] = before.series[name].Take(bi).Rename(b)
+		series[a] = after.series[name].Take(ai).Rename(a)
+		series[ch] = types.NewSeries(ch, changed)
+		order = append(order, b, a, ch)
+	}
+	if len(series) != len(order) {
+		return nil, fmt.Errorf("compare output columns clash with existing column names")
+	}
+	if out.Changed, err = newOrdered(series, order); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// valuesEqual returns an equality test between row i of b and row j of a
+// that treats two nulls, or two NaNs, as equal.
+func valuesEqual(b, a *types.Series) (func(i, j int) bool, error) {
+	if b.DataType.String() != a.DataType.String() {
+		return nil, fmt.Errorf("column %s has type %s before and %s after", b.Name, b.DataType, a.DataType)
+	}
+	eq, err := keyEqual(b, a)
+	if err != nil {
+		return nil, err
+	}
+	switch bd := b.Data.(type) {
+	case []float64:
+		ad := a.Data.([]float64)
+		inner := eq
+		eq = func(i, j int) bool { return inner(i, j) || (math.IsNaN(bd[i]) && math.IsNaN(ad[j])) }
+	case []float32:
+		ad := a.Data.([]float32)
+		inner := eq
+		eq = func(i, j int) bool {
+			return inner(i, j) || (math.IsNaN(float64(bd[i])) && math.IsNaN(float64(ad[j])))
+		}
+	}
+	return func(i, j int) bool {
+		bv, av := b.IsValid(i), a.IsValid(j)
+		return bv == av && (!bv || eq(i, j))
+	}, nil
+}
@@ -0,0 +1,298 @@
+package dataframe
+
+import (
+	"fmt"
+
+	"go-polars/types"
+)
+
+// ConcatStrategy controls how Concat combines the rows of several frames
+type ConcatStrategy int
+
+const (
+	// HowVertical appends the frames one after another, aligning columns by
+	// name. Every frame must have the same columns with the same types.
+	HowVertical ConcatStrategy = iota
+	// HowInterleave takes rows round-robin from each frame (first row of
+	// every frame, then the second rows, ...), preserving the order within
+	// each source. Frames may have different lengths; exhausted sources are
+	// skipped. The schema rules of HowVertical apply.
+	HowInterleave
+	// HowDiagonal appends the frames like HowVertical but takes the union of
+	// their columns. Rows from frames lacking a column are null there.
+	// Columns present in several frames must still agree on type.
+	HowDiagonal
+)
+
+// Concat combines the rows of dfs into a single DataFrame
+func Concat(dfs []*DataFrame, how ConcatStrategy) (*DataFrame, error) {
+	if len(dfs) == 0 {
+		return New(nil)
+	}
+	if how == HowDiagonal {
+		aligned, err := alignColumns(dfs)
+		if err != nil {
+			return nil, err
+		}
+		return concatColumns(aligned, nil)
+	}
+	if err := sameSchema(dfs); err != nil {
+		return nil, err
+	}
+
+	switch how {
+	case HowVertical:
+		return concatColumns(dfs, nil)
+	case HowInterleave:
+		total, longest := 0, 0
+		for _, df := range dfs {
+			total += df.length
+			if df.length > longest {
+				longest = df.length
+			}
+		}
+		offsets := make([]int, len(dfs))
+		for i := 1; i < len(dfs); i++ {
+			offsets[i] = offsets[i-1] + dfs[i-1].length
+		}
+		order := make([]int, 0, total)
+		for row := 0; row < longest; row++ {
+			for i, df := range dfs {
+				if row < df.length {
+					order = append(order, offsets[i]+row)
+				}
+			}
+		}
+		return concatColumns(dfs, order)
+	default:
+		return nil, fmt.Errorf("unknown concat strategy %d", how)
+	}
+}
+
+// sameSchema verifies that every frame has the columns and types of the first.
+func sameSchema(dfs []*DataFrame) error {
+	first := dfs[0]
+	for i, df := range dfs[1:] {
+		if len(df.series) != len(first.series) {
+			return fmt.Errorf("frame %d has %d columns, expected %d", i+1, len(df.series), len(first.series))
+		}
+		for name, s := range first.series {
+			other, ok := df.series[name]
+			if !ok {
+				return fmt.Errorf("column %s not found in frame %d", name, i+1)
+			}
+			if other.DataType.String() != s.DataType.String() {
+				return fmt.Errorf("column %s has type %s in frame %d, expected %s", name, other.DataType, i+1, s.DataType)
+			}
+		}
+	}
+	return nil
+}
+
+// alignColumns returns copies of dfs that all carry the union of their
+// columns, in order of first appearance, filling the missing ones with nulls.
+func alignColumns(dfs []*DataFrame) ([]*DataFrame, error) {
+	templates := make(map[string]*types.Series)
+	var union []string
+	for i, df := range dfs {
+		for _, name := range df.order {
+			s := df.series[name]
+			t, ok := templates[name]
+			if !ok {
+				templates[name] = s
+				union = append(union, name)
+				continue
+			}
+			if t.DataType.String() != s.DataType.String() {
+				return nil, fmt.Errorf("column %s has type %s in frame %d, expected %s", name, s.DataType, i, t.DataType)
+			}
+		}
+	}
+
+	aligned := make([]*DataFrame, len(dfs))
+	for i, df := range dfs {
+		if len(df.series) == len(templates) {
+			aligned[i] = df
+			continue
+		}
+		// Taking index -1 yields a null of the template's type.
+		missing := make([]int, df.length)
+		for j := range missing {
+			missing[j] = -1
+		}
+		series := make(map[string]*types.Series, len(templates))
+		for name, t := range templates {
+			if s, ok := df.series[name]; ok {
+				series[name] = s
+			} else {
+				series[name] = t.Take(missing).Rename(name)
+			}
+		}
+		aligned[i] = &DataFrame{series: series, order: union, length: df.length}
+	}
+	return aligned, nil
+}
+
+// concatColumns appends each column across dfs and, when order is non-nil,
+// reorders the combined rows by it.
+func concatColumns(dfs []*DataFrame, order []int) (*DataFrame, error) {
+	result := make(map[string]*types.Series, len(dfs[0].series))
+	for _, name := range dfs[0].order {
+		parts := make([]*types.Series, len(dfs))
+		for i, df := range dfs {
+			parts[i] = df.series[name]
+		}
+		combined, err := concatSeries(parts)
+		if err != nil {
+			return nil, err
+		}
+		if order != nil {
+			combined = combined.Take(order)
+		}
+		result[name] = combined
+	}
+	return newOrdered(result, dfs[0].order)
+}
+
+// concatSeries appends the given Series end to end. All parts must share the
+// same element type; the result is named after the first part.
+func concatSeries(parts []*types.Series) (*types.Series, error) {
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("no series to concatenate")
+	}
+	name := parts[0].Name
+	total := 0
+	hasNulls := false
+	for _, p := range parts {
+		if p.DataType.String() != parts[0].DataType.String() {
+			return nil, fmt.Errorf("column %s: cannot concatenate %s with %s", name, parts[0].DataType, p.DataType)
+		}
+		total += p.Length
+		hasNulls = hasNulls || p.HasNulls()
+	}
+
+	var out *types.Series
+	switch parts[0].Data.(type) {
+	case []int64:
+		out = types.NewSeries(name, appendParts[int64](parts, total))
+	case []float64:
+		out = types.NewSeries(name, appendParts[float64](parts, total))
+	case []float32:
+		out = types.NewSeries(name, appendParts[float32](parts, total))
+	case []string:
+		out = types.NewSeries(name, appendParts[string](parts, total))
+	case []bool:
+		out = types.NewSeries(name, appendParts[bool](parts, total))
+	case []int8:
+		out = types.NewSeries(name, appendParts[int8](parts, total))
+	case []int16:
+		out = types.NewSeries(name, appendParts[int16](parts, total))
+	case []int32:
+		out = types.NewSeries(name, appendParts[int32](parts, total))
+	case []uint8:
+		out = types.NewSeries(name, appendParts[uint8](parts, total))
+	case []uint16:
+		out = types.NewSeries(name, appendParts[uint16](parts, total))
+	case []uint32:
+		out = types.NewSeries(name, appendParts[uint32](parts, total))
+	case []uint64:
+		out = types.NewSeries(name, appendParts[uint64](parts, total))
+	case *types.Categorical:
+		// Dictionaries may differ between parts, so re-encode the values.
+		values := make([]string, 0, total)
+		for _, p := range parts {
+			values = append(values, p.Data.(*types.Categorical).Decode()...)
+		}
+		out = types.NewCategoricalSeries(name, values)
+	case [][]int64:
+		out = types.NewSeries(name, appendParts[[]int64](parts, total))
+	case [][]float64:
+		out = types.NewSeries(name, appendParts[[]float64](parts, total))
+	case [][]string:
+		out = types.NewSeries(name, appendParts[[]string](parts, total))
+	case [][]bool:
+		out = types.NewSeries(name, appendParts[[]bool](parts, total))
+	default:
+		return nil, fmt.Errorf("unsupported data type for column %s", name)
+	}
+
+	if hasNulls {
+		valid := types.NewBitmap(total, true)
+		offset := 0
+		for _, p := range parts {
+			for i := 0; i < p.Length; i++ {
+				if !p.IsValid(i) {
+					valid.Set(offset+i, false)
+				}
+			}
+			offset += p.Length
+		}
+		out.Validity = valid
+	}
+	return out, nil
+}
+
+func appendParts[T any](parts []*types.Series, total int) []T {
+	out := make([]T, 0, total)
+	for _, p := range parts {
+		out = append(out, p.Data.([]T)...)
+	}
+	return out
+}
+
+// CollisionPolicy decides what HStack does with a column name present in
+// both frames
+type CollisionPolicy int
+
+const (
+	// CollisionError fails the HStack.
+	CollisionError CollisionPolicy = iota
+	// CollisionSuffix keeps both columns, appending JoinSuffix to the name
+	// of the incoming one.
+	CollisionSuffix
+	// CollisionOverwrite replaces the existing column with the incoming one.
+	CollisionOverwrite
+)
+
+// HStack returns a new DataFrame holding the columns of df followed by
+// those of other; an overwritten column keeps its position. Both frames must
+// have the same number of rows; columns are shared, not copied.
+func (df *DataFrame) HStack(other *DataFrame, onCollision CollisionPolicy) (out *DataFrame, err error) {
+	defer df.track("HStack", map[string]interface{}{"other_columns": other.Columns(), "on_collision": int(onCollision)})(&out, &err)
+	if len(df.series) > 0 && len(other.series) > 0 && df.length != other.length {
+		return nil, fmt.Errorf("cannot hstack frames with %d and %d rows", df.length, other.length)
+	}
+	result := make(map[string]*types.Series, len(df.series)+len(other.series))
+	for name, s := range df.series {
+		result[name] = s
+	}
+	order := append([]string(nil), df.order...)
+	for _, name := range other.order {
+		s := other.series[name]
+		if _, clash := df.series[name]; clash {
+			switch onCollision {
+			case CollisionError:
+				return nil, fmt.Errorf("column %s already exists", name)
+			case CollisionSuffix:
+				suffixed := name + JoinSuffix
+				if _, clash := result[suffixed]; clash {
+					return nil, fmt.Errorf("column %s already exists", suffixed)
+				}
+				if _, clash := other.series[suffixed]; clash {
+					return nil, fmt.Errorf("column %s already exists", suffixed)
+				}
+				result[suffixed] = s.Rename(suffixed)
+				order = append(order, suffixed)
+				continue
+			case CollisionOverwrite:
+				result[name] = s
+				continue
+			default:
+				return nil, fmt.Errorf("unknown collision policy %d", onCollision)
+			}
+		}
+		result[name] = s
+		order = append(order, name)
+	}
+	return newOrdered(result, order)
+}
@@ -0,0 +1,41 @@
+package dataframe
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"go-polars/types"
+)
+
+// Settings read from the environment when the package is initialised,
+// named after their Polars counterparts so deployed binaries can be tuned
+// without code changes:
+//
+//	GOPOLARS_MAX_THREADS           goroutines per parallel operation, see types.MaxThreads
+//	GOPOLARS_STREAMING_CHUNK_SIZE  default StreamOptions.BatchSize
+//	GOPOLARS_VERBOSE               1 to log engine decisions on stderr, see SetVerbose
+//
+// Values that do not parse are reported on stderr and ignored.
+var streamingChunkSize = defaultBatchSize
+
+func init() {
+	if v, ok := os.LookupEnv("GOPOLARS_STREAMING_CHUNK_SIZE"); ok && v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			streamingChunkSize = n
+		} else {
+			fmt.Fprintf(os.Stderr, "gopolars: ignoring GOPOLARS_STREAMING_CHUNK_SIZE=%q, want a positive integer\n", v)
+		}
+	}
+	if v, ok := os.LookupEnv("GOPOLARS_VERBOSE"); ok && v != "" {
+		if on, err := strconv.ParseBool(v); err == nil {
+			SetVerbose(on)
+		} else {
+			fmt.Fprintf(os.Stderr, "gopolars: ignoring GOPOLARS_VERBOSE=%q, want 0 or 1\n", v)
+		}
+	}
+	logDecision("config",
+		"max_threads", types.MaxThreads(),
+		"streaming_chunk_size", streamingChunkSize,
+		"scalar_kernels", !detectFastKernels())
+}
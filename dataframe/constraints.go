@@ -0,0 +1,179 @@
+package dataframe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Constraint is a set of properties a column is declared to have, see
+// WithConstraints. Constraints combine with |.
+type Constraint uint8
+
+const (
+	// NotNull holds when the column has no nulls.
+	NotNull Constraint = 1 << iota
+	// Unique holds when no two valid values of the column are equal; nulls
+	// may repeat, as they never match in joins.
+	Unique
+	// Ascending holds when the valid values never decrease down the rows
+	// and any nulls come last.
+	Ascending
+	// Descending holds when the valid values never increase down the rows
+	// and any nulls come last.
+	Descending
+)
+
+var constraintNames = []string{"not null", "unique", "ascending", "descending"}
+
+func (c Constraint) String() string {
+	if c == 0 {
+		return "none"
+	}
+	var names []string
+	for i, name := range constraintNames {
+		if c&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	if rest := c >> len(constraintNames); rest != 0 {
+		names = append(names, fmt.Sprintf("Constraint(%#x)", uint8(rest<<len(constraintNames))))
+	}
+	return strings.Join(names, "|")
+}
+
+// allConstraints holds every defined constraint
+const allConstraints = NotNull | Unique | Ascending | Descending
+
+// keptBy holds the constraints an operation keeps on the columns it gathers
+// from the input column of the same name: those keeping some of the rows in
+// their order keep all of them, those reordering the rows keep the ones
+// that do not depend on the order. A column passed on untouched keeps its
+// constraints whatever the operation, and any other column loses them.
+var keptBy = map[string]Constraint{
+	"Filter":             allConstraints,
+	"FilterBool":         allConstraints,
+	"FilterBoolBatch":    allConstraints,
+	"FilterColumns":      allConstraints,
+	"FilterExpr":         allConstraints,
+	"FilterMask":         allConstraints,
+	"FilterRows":         allConstraints,
+	"FilterRowsParallel": allConstraints,
+	"FilterString":       allConstraints,
+	"FilterStringBatch":  allConstraints,
+	"DropNulls":          allConstraints,
+	"Head":               allConstraints,
+	"Tail":               allConstraints,
+	"Slice":              allConstraints,
+	"Unique":             allConstraints,
+	"Sort":               NotNull | Unique,
+	"SortByColumn":       NotNull | Unique,
+	"SortByIndex":        NotNull | Unique,
+	"SortColumns":        NotNull | Unique,
+	"TakeOrdered":        NotNull | Unique,
+	"Sample":             NotNull,
+	"TopK":               NotNull | Unique,
+}
+
+// WithConstraints declares that column satisfies c, on top of the
+// constraints it already has, and returns the DataFrame carrying the
+// declaration. The column is checked first, so a violated constraint is an
+// error. Operations pass the constraints on to their result where they
+// still hold, such as through filters and column selections, and drop them
+// otherwise, such as for a column computed by WithColumn. Operations may
+// rely on them: a join whose right key has a unique column keeps the
+// constraints of the left columns, as no left row can match twice, and
+// grouping by a monotonic column skips checking whether it is sorted.
+func (df *DataFrame) WithConstraints(column string, c Constraint) (out *DataFrame, err error) {
+	defer df.track("WithConstraints", map[string]interface{}{"column": column, "constraint": c.String()})(&out, &err)
+	series, ok := df.series[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	if c&^allConstraints != 0 {
+		return nil, fmt.Errorf("unknown constraint %s", c)
+	}
+	if c&NotNull != 0 && series.NullCount() > 0 {
+		return nil, fmt.Errorf("column %s violates %s: %d nulls", column, NotNull, series.NullCount())
+	}
+	if c&(Unique|Ascending|Descending) != 0 {
+		st := series.Stats()
+		valid := series.Length - st.NullCount
+		switch {
+		case valid > 0 && st.Min == nil:
+			return nil, fmt.Errorf("cannot declare %s on %s", c&^NotNull, series.DataType)
+		case c&Unique != 0 && st.NDV != valid:
+			return nil, fmt.Errorf("column %s violates %s: %d distinct of %d values", column, Unique, st.NDV, valid)
+		case c&Ascending != 0 && !st.SortedAscending:
+			return nil, fmt.Errorf("column %s violates %s", column, Ascending)
+		case c&Descending != 0 && !st.SortedDescending:
+			return nil, fmt.Errorf("column %s violates %s", column, Descending)
+		}
+	}
+
+	dup := *df
+	dup.constraints = make(map[string]Constraint, len(df.constraints)+1)
+	for name, have := range df.constraints {
+		dup.constraints[name] = have
+	}
+	dup.constraints[column] |= c
+	return &dup, nil
+}
+
+// Constraints returns the constraints declared on column and kept by the
+// operations since, see WithConstraints
+func (df *DataFrame) Constraints(column string) Constraint {
+	return df.constraints[column]
+}
+
+// ConstrainedColumns returns the names of the columns with constraints, in
+// name order
+func (df *DataFrame) ConstrainedColumns() []string {
+	names := make([]string, 0, len(df.constraints))
+	for name := range df.constraints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// keepConstraints gives out, the result of op on df, the constraints of df
+// that still hold in it, in addition to those op declared itself.
+func (df *DataFrame) keepConstraints(op string, out *DataFrame) {
+	if len(df.constraints) == 0 || out == df {
+		return
+	}
+	kept := make(map[string]Constraint, len(df.constraints)+len(out.constraints))
+	for name, c := range out.constraints {
+		kept[name] = c
+	}
+	for name, c := range df.constraints {
+		s, ok := out.series[name]
+		if !ok {
+			continue
+		}
+		if s != df.series[name] {
+			c &= keptBy[op]
+		}
+		if c != 0 {
+			kept[name] |= c
+		}
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	out.constraints = kept
+}
+
+// uniqueKey reports whether no two rows hold the same key in columns,
+// comparing nulls as a join does, because one of the columns is declared
+// Unique, and NotNull too when nulls compare equal.
+func (df *DataFrame) uniqueKey(columns []string, nullsEqual bool) bool {
+	for _, col := range columns {
+		c := df.constraints[col]
+		if c&Unique != 0 && (!nullsEqual || c&NotNull != 0) {
+			return true
+		}
+	}
+	return false
+}
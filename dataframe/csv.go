@@ -0,0 +1,331 @@
+package dataframe
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"go-polars/types"
+)
+
+// CSVOptions controls how ReadCSV and ParseCSV build a DataFrame
+type CSVOptions struct {
+	// Delimiter separates fields and may be several characters long, such
+	// as "||" or "\t|". Defaults to ",".
+	Delimiter string
+	// Comment marks lines to ignore when it prefixes them, e.g. "#" or "--".
+	// Comments are not recognised inside quoted fields.
+	Comment string
+	// SkipRows discards this many lines before parsing starts, for preambles
+	// that are not valid CSV.
+	SkipRows int
+	// HeaderRow is the index of the record holding the column names,
+	// counted after SkipRows and ignoring comment and empty lines. Earlier
+	// records are discarded. Ignored when NoHeader is set.
+	HeaderRow int
+	// NoHeader treats the first record as data and names the columns
+	// column_0, column_1, ...
+	NoHeader bool
+	// Normalize cleans up the header names.
+	Normalize NormalizeOptions
+	// Schema reconciles the files matched by a glob path.
+	Schema SchemaOptions
+	// Locale reads numbers and dates written in the style of a locale.
+	Locale LocaleOptions
+	// Defaults fills the empty fields of the columns it names, before
+	// types are inferred: values are written as text, with fmt.Sprint or
+	// as RFC 3339 for a time.Time, and parsed like the fields of the file.
+	// A column missing from the file is added, after the others in order
+	// of name, with the defaults as values.
+	Defaults map[string]ColumnDefault
+	ReadLimits
+}
+
+// ReadCSV reads the CSV file at path. Files ending in .gz are decompressed
+// on the fly. The path may be a glob such as "data/2024-*.csv.gz"; matching
+// files are then read in parallel and concatenated in lexical order of
+// their names under opts.Schema, see ConcatFiles. ReadLimits apply to each
+// file on its own.
+func ReadCSV(path string, opts CSVOptions) (*DataFrame, error) {
+	if isGlob(path) {
+		return readGlob(path, opts.Schema, func(p string) (*DataFrame, error) {
+			return ReadCSV(p, opts)
+		})
+	}
+	f, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseCSV(f, opts)
+}
+
+// ParseCSV builds a DataFrame from CSV data. Column types are inferred per
+// column, trying Int64, Float64 and Boolean, and timestamps when
+// opts.Locale asks for dates, before falling back to String; empty fields
+// become nulls. Malformed records fail with a *ParseError.
+func ParseCSV(r io.Reader, opts CSVOptions) (*DataFrame, error) {
+	recs, err := newCSVRecords(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([][]string, len(recs.names))
+	if _, err := recs.read(columns, nil, -1); err != nil && err != io.EOF {
+		return nil, err
+	}
+	series := make(map[string]*types.Series, len(recs.names))
+	for i, name := range recs.names {
+		series[name], _ = inferCSVColumn(name, columns[i], opts.Locale)
+	}
+	return newOrdered(series, recs.names)
+}
+
+// csvRecords reads the data records of CSV input after locating its header.
+type csvRecords struct {
+	cr       *csvReader
+	opts     CSVOptions
+	names    []string
+	width    int              // fields per record; names adds default-only columns
+	defaults []*ColumnDefault // default of each column, nil without Defaults
+	pending  []string         // first data record when there is no header
+	rows     int
+	done     bool
+}
+
+// newCSVRecords skips the preamble and reads the column names. Input
+// without any record has no columns.
+func newCSVRecords(r io.Reader, opts CSVOptions) (*csvRecords, error) {
+	if opts.SkipRows < 0 || opts.HeaderRow < 0 {
+		return nil, fmt.Errorf("csv: skip rows and header row must not be negative")
+	}
+	if err := opts.Locale.validate(opts.Delimiter); err != nil {
+		return nil, err
+	}
+	c := &csvRecords{cr: newCSVReader(opts.ReadLimits.wrap(r), opts.Delimiter, opts.Comment), opts: opts}
+
+	if err := c.cr.skipLines(opts.SkipRows); err != nil && err != io.EOF {
+		if !errors.Is(err, ErrLimitExceeded) {
+			return nil, err
+		}
+		if opts.OnLimit != LimitTruncate {
+			return nil, c.limitErr()
+		}
+	}
+
+	for preamble := 0; ; preamble++ {
+		rec, err := c.record()
+		if err != nil {
+			if err == io.EOF {
+				c.done = true
+				return c, nil
+			}
+			return nil, err
+		}
+		if !opts.NoHeader && preamble < opts.HeaderRow {
+			continue
+		}
+		c.names = make([]string, len(rec))
+		if opts.NoHeader {
+			for i := range c.names {
+				c.names[i] = fmt.Sprintf("column_%d", i)
+			}
+			c.pending = append([]string(nil), rec...)
+		} else {
+			copy(c.names, rec)
+		}
+		break
+	}
+
+	if opts.Normalize.enabled() {
+		var renames []Rename
+		c.names, renames = NormalizeColumnNames(c.names, opts.Normalize)
+		opts.Normalize.report(renames)
+	}
+	seen := make(map[string]bool, len(c.names))
+	for _, name := range c.names {
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate column %s in csv header", name)
+		}
+		seen[name] = true
+	}
+	c.width = len(c.names)
+	if len(opts.Defaults) > 0 {
+		for _, name := range defaultNames(opts.Defaults) {
+			if !seen[name] {
+				c.names = append(c.names, name)
+			}
+		}
+		c.defaults = make([]*ColumnDefault, len(c.names))
+		for i, name := range c.names {
+			if d, ok := opts.Defaults[name]; ok {
+				c.defaults[i] = &d
+			}
+		}
+	}
+	return c, nil
+}
+
+// fill returns rec widened to every column, with the defaults in place of
+// empty fields.
+func (c *csvRecords) fill(rec []string) []string {
+	if len(rec) < len(c.names) {
+		rec = append(append(make([]string, 0, len(c.names)), rec...), make([]string, len(c.names)-len(rec))...)
+	}
+	for i, d := range c.defaults {
+		if d != nil && rec[i] == "" {
+			if v := d.at(c.rows); v != nil {
+				rec[i] = csvDefault(v)
+			}
+		}
+	}
+	return rec
+}
+
+func (c *csvRecords) limitErr() error {
+	return fmt.Errorf("csv: %w: more than %d bytes", ErrLimitExceeded, c.opts.MaxBytes)
+}
+
+// record returns the next raw record. Input truncated by ReadLimits ends
+// with io.EOF.
+func (c *csvRecords) record() ([]string, error) {
+	rec, err := c.cr.Read()
+	if errors.Is(err, ErrLimitExceeded) {
+		if c.opts.OnLimit == LimitTruncate {
+			return nil, io.EOF
+		}
+		return nil, c.limitErr()
+	}
+	if err != nil && err != io.EOF {
+		return nil, newParseError(c.rows, c.cr.startLine, c.cr.startOffset, "", "", err)
+	}
+	return rec, err
+}
+
+// read appends the fields of up to max records, or of all remaining ones
+// when max is negative, to columns: every field when keep is nil, else the
+// fields at the positions in keep. It returns the number of records read,
+// and io.EOF once the input is exhausted.
+func (c *csvRecords) read(columns [][]string, keep []int, max int) (int, error) {
+	n := 0
+	for ; max < 0 || n < max; n++ {
+		if c.done {
+			return n, io.EOF
+		}
+		rec := c.pending
+		c.pending = nil
+		if rec == nil {
+			var err error
+			if rec, err = c.record(); err == io.EOF {
+				c.done = true
+				return n, io.EOF
+			} else if err != nil {
+				return n, err
+			}
+		}
+		if len(rec) != c.width {
+			return n, newParseError(c.rows, c.cr.startLine, c.cr.startOffset, "", "", csv.ErrFieldCount)
+		}
+		if c.defaults != nil {
+			rec = c.fill(rec)
+		}
+		if c.opts.ReadLimits.rowsExceeded(c.rows + 1) {
+			if c.opts.OnLimit == LimitTruncate {
+				c.done = true
+				return n, io.EOF
+			}
+			return n, fmt.Errorf("csv: %w: more than %d rows", ErrLimitExceeded, c.opts.MaxRows)
+		}
+		if keep == nil {
+			for i, field := range rec {
+				columns[i] = append(columns[i], field)
+			}
+		} else {
+			for i, pos := range keep {
+				columns[i] = append(columns[i], rec[pos])
+			}
+		}
+		c.rows++
+	}
+	return n, nil
+}
+
+// csvParser converts the fields of a column, empty ones being null, into a
+// Series, or reports the position of the first field it cannot parse.
+type csvParser func(name string, fields []string) (*types.Series, int, bool)
+
+// inferCSVColumn converts raw fields to the narrowest type that parses every
+// non-empty field, returning the parser that did so for later batches.
+func inferCSVColumn(name string, fields []string, locale LocaleOptions) (*types.Series, csvParser) {
+	for _, parse := range locale.parsers() {
+		if s, _, ok := parse(name, fields); ok {
+			return s, parse
+		}
+	}
+	s, _, _ := parseCSVStrings(name, fields)
+	return s, parseCSVStrings
+}
+
+// parsers returns the typed column parsers in the order inference tries
+// them: Int64, Float64, Boolean, then timestamps when dates are parsed.
+func (l LocaleOptions) parsers() []csvParser {
+	parsers := []csvParser{
+		csvParserOf(func(f string) (int64, error) {
+			n, ok := l.number(f)
+			if !ok {
+				return 0, strconv.ErrSyntax
+			}
+			return strconv.ParseInt(n, 10, 64)
+		}),
+		csvParserOf(func(f string) (float64, error) {
+			n, ok := l.number(f)
+			if !ok {
+				return 0, strconv.ErrSyntax
+			}
+			return strconv.ParseFloat(n, 64)
+		}),
+		csvParserOf(parseCSVBool),
+	}
+	if l.ParseDates {
+		parsers = append(parsers, csvParserOf(l.date))
+	}
+	return parsers
+}
+
+func csvParserOf[T any](parse func(string) (T, error)) csvParser {
+	return func(name string, fields []string) (*types.Series, int, bool) {
+		out := make([]T, len(fields))
+		valid := make([]bool, len(fields))
+		for i, f := range fields {
+			if f == "" {
+				continue
+			}
+			v, err := parse(f)
+			if err != nil {
+				return nil, i, false
+			}
+			out[i], valid[i] = v, true
+		}
+		return types.NewNullableSeries(name, out, valid), 0, true
+	}
+}
+
+func parseCSVStrings(name string, fields []string) (*types.Series, int, bool) {
+	valid := make([]bool, len(fields))
+	for i, f := range fields {
+		valid[i] = f != ""
+	}
+	return types.NewNullableSeries(name, append([]string(nil), fields...), valid), 0, true
+}
+
+func parseCSVBool(f string) (bool, error) {
+	switch strings.ToLower(f) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, strconv.ErrSyntax
+}
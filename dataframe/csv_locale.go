@@ -0,0 +1,117 @@
+package dataframe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LocaleOptions describes how numbers and dates are written in CSV input
+// exported in the style of a locale, such as "1.234,5" and "31/12/2024" in
+// much of Europe. The zero value reads the style of Go and Polars.
+type LocaleOptions struct {
+	// DecimalComma reads "," as the decimal mark of floats; the delimiter
+	// must then be something else, typically ";".
+	DecimalComma bool
+	// Thousands separates digit groups in numbers, e.g. ".", ",", " " or
+	// "'". Groups must have three digits, so "1,5" is not a number with
+	// Thousands ",". It must differ from the decimal mark and the delimiter.
+	Thousands string
+	// ParseDates makes columns whose every field is a date or date and time
+	// Int64 timestamps, nanoseconds since the Unix epoch as used by
+	// Series.Dt, rather than String. Dates are ISO 8601, with an optional
+	// time and offset, or numeric ones like 12/31/2024, 31.12.2024 and
+	// 31-12-2024 optionally followed by a time such as 14:30 or 14:30:05.
+	// Times without an offset are read as UTC.
+	ParseDates bool
+	// DayFirst reads numeric dates as day/month/year, so 02/01/2024 is the
+	// 2nd of January rather than the 1st of February.
+	DayFirst bool
+}
+
+// isoLayouts are tried on every date field; numericDates holds the
+// month-first and day-first layouts of numeric dates, each also tried
+// followed by a time.
+var (
+	isoLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	}
+	numericDates = map[bool][]string{
+		false: {"1/2/2006", "1.2.2006", "1-2-2006"},
+		true:  {"2/1/2006", "2.1.2006", "2-1-2006"},
+	}
+)
+
+func (l LocaleOptions) validate(delimiter string) error {
+	if delimiter == "" {
+		delimiter = ","
+	}
+	decimal := l.decimal()
+	switch {
+	case l.DecimalComma && delimiter == ",":
+		return fmt.Errorf("csv: a decimal comma needs a delimiter other than \",\"")
+	case l.Thousands != "" && (l.Thousands == decimal || l.Thousands == delimiter):
+		return fmt.Errorf("csv: thousands separator %q clashes with the decimal mark or delimiter", l.Thousands)
+	}
+	return nil
+}
+
+func (l LocaleOptions) decimal() string {
+	if l.DecimalComma {
+		return ","
+	}
+	return "."
+}
+
+// number rewrites a numeric field in the syntax of package strconv,
+// reporting false when its digit groups or decimal mark are malformed.
+func (l LocaleOptions) number(f string) (string, bool) {
+	if l.Thousands == "" && !l.DecimalComma {
+		return f, true
+	}
+	whole, frac := f, ""
+	if i := strings.Index(f, l.decimal()); i >= 0 {
+		whole, frac = f[:i], "."+f[i+1:]
+	}
+	if l.DecimalComma && strings.Contains(whole, ".") && l.Thousands != "." {
+		return "", false
+	}
+	if l.Thousands != "" && strings.Contains(whole, l.Thousands) {
+		sign := ""
+		if whole != "" && (whole[0] == '-' || whole[0] == '+') {
+			sign, whole = whole[:1], whole[1:]
+		}
+		groups := strings.Split(whole, l.Thousands)
+		if len(groups[0]) == 0 || len(groups[0]) > 3 {
+			return "", false
+		}
+		for _, g := range groups[1:] {
+			if len(g) != 3 {
+				return "", false
+			}
+		}
+		whole = sign + strings.Join(groups, "")
+	}
+	return whole + frac, true
+}
+
+// date parses a date field into Unix nanoseconds, see ParseDates
+func (l LocaleOptions) date(f string) (int64, error) {
+	for _, layout := range isoLayouts {
+		if t, err := time.ParseInLocation(layout, f, time.UTC); err == nil {
+			return t.UnixNano(), nil
+		}
+	}
+	for _, layout := range numericDates[l.DayFirst] {
+		for _, suffix := range []string{"", " 15:04", " 15:04:05"} {
+			if t, err := time.ParseInLocation(layout+suffix, f, time.UTC); err == nil {
+				return t.UnixNano(), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%q is not a date", f)
+}
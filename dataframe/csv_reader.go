@@ -0,0 +1,142 @@
+package dataframe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+// csvReader splits CSV input into records. Unlike encoding/csv it accepts
+// delimiters and comment prefixes of any length. Quoting follows RFC 4180:
+// quoted fields may contain delimiters, doubled quotes and line breaks.
+type csvReader struct {
+	r       *bufio.Reader
+	delim   []byte
+	comment []byte
+
+	line   int   // physical lines consumed
+	offset int64 // bytes consumed
+
+	// Position of the first line of the record returned by the last Read.
+	startLine   int
+	startOffset int64
+
+	field  []byte
+	record []string
+}
+
+func newCSVReader(r io.Reader, delim, comment string) *csvReader {
+	if delim == "" {
+		delim = ","
+	}
+	return &csvReader{r: bufio.NewReader(r), delim: []byte(delim), comment: []byte(comment)}
+}
+
+// readLine returns the next physical line without its line terminator.
+// A final line without a trailing newline is returned with a nil error;
+// io.EOF is only reported once the input is exhausted.
+func (c *csvReader) readLine() ([]byte, error) {
+	line, err := c.r.ReadBytes('\n')
+	if err == io.EOF && len(line) > 0 {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.line++
+	c.offset += int64(len(line))
+	line = bytes.TrimSuffix(line, []byte{'\n'})
+	return bytes.TrimSuffix(line, []byte{'\r'}), nil
+}
+
+// skipLines discards n physical lines, regardless of their content.
+func (c *csvReader) skipLines(n int) error {
+	for i := 0; i < n; i++ {
+		if _, err := c.readLine(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read returns the next record, skipping empty and comment lines. The
+// returned slice is reused by the next call.
+func (c *csvReader) Read() ([]string, error) {
+	var line []byte
+	for {
+		c.startLine, c.startOffset = c.line+1, c.offset
+		var err error
+		if line, err = c.readLine(); err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || (len(c.comment) > 0 && bytes.HasPrefix(line, c.comment)) {
+			continue
+		}
+		break
+	}
+
+	c.record = c.record[:0]
+	for {
+		if len(line) > 0 && line[0] == '"' {
+			var err error
+			if line, err = c.readQuoted(line[1:]); err != nil {
+				return nil, err
+			}
+			c.record = append(c.record, string(c.field))
+			if len(line) == 0 {
+				return c.record, nil
+			}
+			if !bytes.HasPrefix(line, c.delim) {
+				return nil, csv.ErrQuote
+			}
+			line = line[len(c.delim):]
+			continue
+		}
+
+		i := bytes.Index(line, c.delim)
+		field := line
+		if i >= 0 {
+			field = line[:i]
+		}
+		if bytes.IndexByte(field, '"') >= 0 {
+			return nil, csv.ErrBareQuote
+		}
+		c.record = append(c.record, string(field))
+		if i < 0 {
+			return c.record, nil
+		}
+		line = line[i+len(c.delim):]
+	}
+}
+
+// readQuoted reads a quoted field whose opening quote has been consumed,
+// pulling further lines while the quote stays open. The field lands in
+// c.field and the rest of the line after the closing quote is returned.
+func (c *csvReader) readQuoted(line []byte) ([]byte, error) {
+	c.field = c.field[:0]
+	for {
+		i := bytes.IndexByte(line, '"')
+		if i < 0 {
+			c.field = append(c.field, line...)
+			c.field = append(c.field, '\n')
+			next, err := c.readLine()
+			if err == io.EOF {
+				return nil, csv.ErrQuote
+			}
+			if err != nil {
+				return nil, err
+			}
+			line = next
+			continue
+		}
+		c.field = append(c.field, line[:i]...)
+		line = line[i+1:]
+		if len(line) > 0 && line[0] == '"' {
+			c.field = append(c.field, '"')
+			line = line[1:]
+			continue
+		}
+		return line, nil
+	}
+}
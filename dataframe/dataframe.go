@@ -1,56 +1,88 @@
 package dataframe
 
 import (
-	"encoding/binary"
+	"bytes"
 	"fmt"
 	"math"
-	"math/bits"
-	"runtime"
+	"math/rand"
 	"sort"
 	"sync"
+	"sync/atomic"
 
 	"go-polars/types"
-
-	xxhash "github.com/cespare/xxhash/v2"
 )
 
 // DataFrame represents a collection of Series with the same length
 type DataFrame struct {
 	series map[string]*types.Series
+	order  []string // column names in display order
 	length int
+
+	lineage     *Lineage              // optional recorder of applied operations
+	history     *history              // versions committed with Commit
+	constraints map[string]Constraint // declared with WithConstraints
 }
 
-// New creates a new DataFrame from a map of Series
+// New creates a new DataFrame from a map of Series. Since maps are
+// unordered, the columns are ordered by name; use FromColumns or
+// SelectOrdered to choose the order.
 func New(series map[string]*types.Series) (*DataFrame, error) {
-	if len(series) == 0 {
-		return &DataFrame{
-			series: make(map[string]*types.Series),
-			length: 0,
-		}, nil
-	}
+	return newOrdered(series, nil)
+}
 
-	// Get length from first series
-	var length int
-	for _, s := range series {
-		length = s.Length
-		break
+// newOrdered creates a DataFrame whose columns follow order. Names in order
+// that are not in series are skipped, and columns missing from order are
+// appended by name, so callers can pass the order of the frame they derive
+// from after adding or dropping columns.
+func newOrdered(series map[string]*types.Series, order []string) (*DataFrame, error) {
+	if series == nil {
+		series = make(map[string]*types.Series)
+	}
+	cols := make([]string, 0, len(series))
+	seen := make(map[string]bool, len(series))
+	for _, name := range order {
+		if _, ok := series[name]; ok && !seen[name] {
+			seen[name] = true
+			cols = append(cols, name)
+		}
+	}
+	if len(cols) < len(series) {
+		var rest []string
+		for name := range series {
+			if !seen[name] {
+				rest = append(rest, name)
+			}
+		}
+		sort.Strings(rest)
+		cols = append(cols, rest...)
 	}
 
-	// Verify all series have the same length
-	for name, s := range series {
-		if s.Length != length {
+	length := 0
+	if len(cols) > 0 {
+		length = series[cols[0]].Length
+	}
+	for _, name := range cols {
+		if s := series[name]; s.Length != length {
 			return nil, fmt.Errorf("series %s has length %d, expected %d", name, s.Length, length)
 		}
 	}
 
 	return &DataFrame{
 		series: series,
+		order:  cols,
 		length: length,
 	}, nil
 }
 
-// Select returns a new DataFrame with only the specified columns
-func (df *DataFrame) Select(columns []string) (*DataFrame, error) {
+// Select returns a new DataFrame with only the specified columns, in the
+// given order
+func (df *DataFrame) Select(columns []string) (out *DataFrame, err error) {
+	defer df.track("Select", map[string]interface{}{"columns": columns})(&out, &err)
+	return df.project(columns)
+}
+
+// project returns the given columns, in that order, sharing their data
+func (df *DataFrame) project(columns []string) (*DataFrame, error) {
 	selected := make(map[string]*types.Series)
 	for _, col := range columns {
 		series, ok := df.series[col]
@@ -59,11 +91,129 @@ func (df *DataFrame) Select(columns []string) (*DataFrame, error) {
 		}
 		selected[col] = series
 	}
-	return New(selected)
+	return newOrdered(selected, columns)
+}
+
+// WithColumn returns a new DataFrame with s added under name, replacing any
+// existing column of that name. s must have as many rows as the frame.
+func (df *DataFrame) WithColumn(name string, s *types.Series) (out *DataFrame, err error) {
+	defer df.track("WithColumn", map[string]interface{}{"name": name})(&out, &err)
+	if s.Name != name {
+		s = s.Rename(name)
+	}
+	return df.withColumns(s)
+}
+
+// WithColumns returns a new DataFrame with each Series added under its own
+// name, replacing existing columns of the same name. Every Series must have
+// as many rows as the frame.
+func (df *DataFrame) WithColumns(series ...*types.Series) (out *DataFrame, err error) {
+	names := make([]string, len(series))
+	for i, s := range series {
+		if s != nil {
+			names[i] = s.Name
+		}
+	}
+	defer df.track("WithColumns", map[string]interface{}{"columns": names})(&out, &err)
+	return df.withColumns(series...)
+}
+
+func (df *DataFrame) withColumns(series ...*types.Series) (*DataFrame, error) {
+	result := make(map[string]*types.Series, len(df.series)+len(series))
+	for name, s := range df.series {
+		result[name] = s
+	}
+	order := append([]string(nil), df.order...)
+	for _, s := range series {
+		if s == nil {
+			return nil, fmt.Errorf("series must not be nil")
+		}
+		if len(df.series) > 0 && s.Length != df.length {
+			return nil, fmt.Errorf("series %s has length %d, expected %d", s.Name, s.Length, df.length)
+		}
+		if _, replaced := result[s.Name]; !replaced {
+			order = append(order, s.Name)
+		}
+		result[s.Name] = s
+	}
+	return newOrdered(result, order)
+}
+
+// Drop returns a new DataFrame without the given columns
+func (df *DataFrame) Drop(columns ...string) (out *DataFrame, err error) {
+	defer df.track("Drop", map[string]interface{}{"columns": columns})(&out, &err)
+	drop := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		if _, ok := df.series[col]; !ok {
+			return nil, fmt.Errorf("column %s not found", col)
+		}
+		drop[col] = true
+	}
+	result := make(map[string]*types.Series, len(df.series))
+	for name, s := range df.series {
+		if !drop[name] {
+			result[name] = s
+		}
+	}
+	return newOrdered(result, df.order)
+}
+
+// Rename returns a new DataFrame with columns renamed according to mapping
+// (old name to new name). Renamed columns keep their position. Renaming
+// onto a name that remains in use is an error, but names may be swapped.
+func (df *DataFrame) Rename(mapping map[string]string) (out *DataFrame, err error) {
+	defer df.track("Rename", map[string]interface{}{"mapping": mapping})(&out, &err)
+	for from := range mapping {
+		if _, ok := df.series[from]; !ok {
+			return nil, fmt.Errorf("column %s not found", from)
+		}
+	}
+	result := make(map[string]*types.Series, len(df.series))
+	order := make([]string, len(df.order))
+	for i, name := range df.order {
+		to, ok := mapping[name]
+		if !ok {
+			to = name
+		}
+		if _, dup := result[to]; dup {
+			return nil, fmt.Errorf("column %s already exists", to)
+		}
+		s := df.series[name]
+		if to != name {
+			s = s.Rename(to)
+		}
+		result[to] = s
+		order[i] = to
+	}
+	return newOrdered(result, order)
+}
+
+// SelectOrdered returns a new DataFrame with the given columns moved to the
+// front in the given order; the remaining columns follow in their current
+// order.
+func (df *DataFrame) SelectOrdered(columns []string) (out *DataFrame, err error) {
+	defer df.track("SelectOrdered", map[string]interface{}{"columns": columns})(&out, &err)
+	seen := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		if _, ok := df.series[col]; !ok {
+			return nil, fmt.Errorf("column %s not found", col)
+		}
+		if seen[col] {
+			return nil, fmt.Errorf("duplicate column %s", col)
+		}
+		seen[col] = true
+	}
+	order := append(append([]string(nil), columns...), df.order...)
+	return newOrdered(df.series, order)
 }
 
-// Filter returns a new DataFrame with only the rows that satisfy the predicate
-func (df *DataFrame) Filter(column string, predicate func(interface{}) bool) (*DataFrame, error) {
+// Filter returns a new DataFrame with only the rows that satisfy the predicate.
+// Rows where the column is null never match. The predicate is called with
+// every value boxed in an interface; FilterInt64, FilterFloat64,
+// FilterString and FilterBool compare against a constant much faster, and
+// FilterInt64Batch and its siblings call a predicate over whole chunks.
+func (df *DataFrame) Filter(column string, predicate func(interface{}) bool) (out *DataFrame, err error) {
+	defer df.track("Filter", map[string]interface{}{"column": column})(&out, &err)
 	series, ok := df.series[column]
 	if !ok {
 		return nil, fmt.Errorf("column %s not found", column)
@@ -73,65 +223,109 @@ func (df *DataFrame) Filter(column string, predicate func(interface{}) bool) (*D
 	mask := make([]bool, df.length)
 	switch data := series.Data.(type) {
 	case []int64:
-		for i, val := range data {
-			mask[i] = predicate(val)
-		}
+		applyPredicate(data, predicate, mask)
 	case []float64:
-		for i, val := range data {
-			mask[i] = predicate(val)
-		}
+		applyPredicate(data, predicate, mask)
+	case []float32:
+		applyPredicate(data, predicate, mask)
 	case []string:
-		for i, val := range data {
-			mask[i] = predicate(val)
-		}
+		applyPredicate(data, predicate, mask)
 	case []bool:
-		for i, val := range data {
-			mask[i] = predicate(val)
+		applyPredicate(data, predicate, mask)
+	case []int8:
+		applyPredicate(data, predicate, mask)
+	case []int16:
+		applyPredicate(data, predicate, mask)
+	case []int32:
+		applyPredicate(data, predicate, mask)
+	case []uint8:
+		applyPredicate(data, predicate, mask)
+	case []uint16:
+		applyPredicate(data, predicate, mask)
+	case []uint32:
+		applyPredicate(data, predicate, mask)
+	case []uint64:
+		applyPredicate(data, predicate, mask)
+	case *types.Categorical:
+		for i := range mask {
+			mask[i] = predicate(data.Value(i))
 		}
 	default:
 		return nil, fmt.Errorf("unsupported data type for column %s", column)
 	}
 
-	// Apply mask to all series
-	filtered := make(map[string]*types.Series)
-	for name, s := range df.series {
-		switch data := s.Data.(type) {
-		case []int64:
-			newData := make([]int64, 0)
-			for i, keep := range mask {
-				if keep {
-					newData = append(newData, data[i])
-				}
-			}
-			filtered[name] = types.NewSeries(name, newData)
-		case []float64:
-			newData := make([]float64, 0)
-			for i, keep := range mask {
-				if keep {
-					newData = append(newData, data[i])
-				}
-			}
-			filtered[name] = types.NewSeries(name, newData)
-		case []string:
-			newData := make([]string, 0)
-			for i, keep := range mask {
-				if keep {
-					newData = append(newData, data[i])
-				}
-			}
-			filtered[name] = types.NewSeries(name, newData)
-		case []bool:
-			newData := make([]bool, 0)
-			for i, keep := range mask {
-				if keep {
-					newData = append(newData, data[i])
-				}
-			}
-			filtered[name] = types.NewSeries(name, newData)
+	return df.filterMask(maskSeries(series, mask))
+}
+
+// applyPredicate evaluates predicate on every element, passing values with
+// the column's own element type.
+func applyPredicate[T any](data []T, predicate func(interface{}) bool, mask []bool) {
+	for i, val := range data {
+		mask[i] = predicate(val)
+	}
+}
+
+// take gathers the given rows of every column into a new DataFrame.
+func (df *DataFrame) take(indices []int) (*DataFrame, error) {
+	return df.gatherRows(len(indices), func(s *types.Series) *types.Series { return s.Take(indices) })
+}
+
+// filter keeps the rows of a selection, in either of its forms
+func (df *DataFrame) filter(sel *types.Selection) (*DataFrame, error) {
+	return df.gatherRows(sel.Count(), func(s *types.Series) *types.Series { return s.Filter(sel) })
+}
+
+// gatherRows builds the n rows that fn gathers from every column
+func (df *DataFrame) gatherRows(n int, fn func(*types.Series) *types.Series) (*DataFrame, error) {
+	taken := make(map[string]*types.Series, len(df.series))
+	workers := types.MaxThreads()
+	if n < 50000 || len(df.series) < 2 || workers < 2 {
+		for name, s := range df.series {
+			taken[name] = fn(s)
 		}
+		return newOrdered(taken, df.order)
 	}
 
-	return New(filtered)
+	// Gather large frames one column per goroutine.
+	columns := make([]*types.Series, len(df.order))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, name := range df.order {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, s *types.Series) {
+			defer wg.Done()
+			columns[i] = fn(s)
+			<-sem
+		}(i, df.series[name])
+	}
+	wg.Wait()
+	for i, name := range df.order {
+		taken[name] = columns[i]
+	}
+	return newOrdered(taken, df.order)
+}
+
+// Take returns the rows at the given positions, in that order; positions
+// may repeat. A negative position produces a row of nulls. Sorting,
+// filtering and joins gather their output rows the same way.
+func (df *DataFrame) Take(indices []int) (out *DataFrame, err error) {
+	defer df.track("Take", map[string]interface{}{"rows": len(indices)})(&out, &err)
+	for _, i := range indices {
+		if i >= df.length {
+			return nil, fmt.Errorf("row %d out of range for %d rows", i, df.length)
+		}
+	}
+	return df.take(indices)
+}
+
+// slice returns the rows [offset, offset+length) sharing the data of df.
+func (df *DataFrame) slice(offset, length int) *DataFrame {
+	sliced := make(map[string]*types.Series, len(df.series))
+	for name, s := range df.series {
+		sliced[name] = s.Slice(offset, length)
+	}
+	return &DataFrame{series: sliced, order: df.order, length: length}
 }
 
 // Shape returns the dimensions of the DataFrame (rows, columns)
@@ -139,124 +333,471 @@ func (df *DataFrame) Shape() (int, int) {
 	return df.length, len(df.series)
 }
 
-// Columns returns the column names of the DataFrame
+// Columns returns the column names of the DataFrame in order
 func (df *DataFrame) Columns() []string {
-	cols := make([]string, 0, len(df.series))
-	for name := range df.series {
-		cols = append(cols, name)
-	}
-	return cols
+	return append([]string(nil), df.order...)
 }
 
 // Head returns a new DataFrame with the first n rows
-func (df *DataFrame) Head(n int) (*DataFrame, error) {
+func (df *DataFrame) Head(n int) (out *DataFrame, err error) {
+	defer df.track("Head", map[string]interface{}{"n": n})(&out, &err)
 	if n > df.length {
 		n = df.length
 	}
 
 	head := make(map[string]*types.Series)
 	for name, s := range df.series {
-		switch data := s.Data.(type) {
-		case []int64:
-			head[name] = types.NewSeries(name, data[:n])
-		case []float64:
-			head[name] = types.NewSeries(name, data[:n])
-		case []string:
-			head[name] = types.NewSeries(name, data[:n])
-		case []bool:
-			head[name] = types.NewSeries(name, data[:n])
+		head[name] = s.Slice(0, n)
+	}
+
+	return newOrdered(head, df.order)
+}
+
+// Tail returns a new DataFrame with the last n rows
+func (df *DataFrame) Tail(n int) (out *DataFrame, err error) {
+	defer df.track("Tail", map[string]interface{}{"n": n})(&out, &err)
+	if n < 0 {
+		return nil, fmt.Errorf("cannot take the last %d rows", n)
+	}
+	n = min(n, df.length)
+	return df.slice(df.length-n, n), nil
+}
+
+// Slice returns the rows [offset, offset+length) as a new DataFrame sharing
+// the column data. A range reaching past the last row is truncated.
+func (df *DataFrame) Slice(offset, length int) (out *DataFrame, err error) {
+	defer df.track("Slice", map[string]interface{}{"offset": offset, "length": length})(&out, &err)
+	if offset < 0 || length < 0 || offset > df.length {
+		return nil, fmt.Errorf("slice [%d, %d+%d) out of range for %d rows", offset, offset, length, df.length)
+	}
+	return df.slice(offset, min(length, df.length-offset)), nil
+}
+
+// Sample returns n rows drawn at random, in the order drawn. Without
+// replacement every row is drawn at most once, so n may not exceed the
+// number of rows. The same seed always draws the same rows.
+func (df *DataFrame) Sample(n int, withReplacement bool, seed int64) (out *DataFrame, err error) {
+	defer df.track("Sample", map[string]interface{}{"n": n, "replace": withReplacement, "seed": seed})(&out, &err)
+	if n < 0 {
+		return nil, fmt.Errorf("cannot sample %d rows", n)
+	}
+	rng := rand.New(rand.NewSource(seed))
+	rows := make([]int, n)
+	if withReplacement {
+		if n > 0 && df.length == 0 {
+			return nil, fmt.Errorf("cannot sample %d rows from an empty DataFrame", n)
+		}
+		for i := range rows {
+			rows[i] = rng.Intn(df.length)
 		}
+		return df.take(rows)
+	}
+	if n > df.length {
+		return nil, fmt.Errorf("cannot sample %d of %d rows without replacement", n, df.length)
+	}
+	// A partial Fisher-Yates shuffle, drawing each row from those left.
+	perm := make([]int, df.length)
+	for i := range perm {
+		perm[i] = i
+	}
+	for i := range rows {
+		j := i + rng.Intn(df.length-i)
+		perm[i], perm[j] = perm[j], perm[i]
+		rows[i] = perm[i]
+	}
+	return df.take(rows)
+}
+
+// SortOption configures Sort, ArgSort, SortByColumn and SortColumns
+type SortOption func(*sortConfig)
+
+type sortConfig struct {
+	SortOptions
+	collation *types.Collation
+}
+
+// SortKind selects the algorithm ordering the values of a sort key
+type SortKind int
+
+const (
+	// SortAuto radix sorts numeric, Boolean and Categorical keys and
+	// compares text.
+	SortAuto SortKind = iota
+	// SortRadix radix sorts every key, text by the rank of its distinct
+	// values, which pays off when they are few.
+	SortRadix
+	// SortComparison compares keys, with a pattern-defeating quicksort or,
+	// when stable, a merge-based insertion sort.
+	SortComparison
+)
+
+func (k SortKind) String() string {
+	switch k {
+	case SortAuto:
+		return "auto"
+	case SortRadix:
+		return "radix"
+	case SortComparison:
+		return "comparison"
+	default:
+		return fmt.Sprintf("SortKind(%d)", int(k))
 	}
+}
+
+// SortOptions chooses how a sort orders its rows. The zero value picks the
+// algorithm by type and lets rows with equal keys come out in any order.
+type SortOptions struct {
+	// Stable keeps rows with equal keys in their order. Radix sorts are
+	// stable anyway; comparison sorts, used for text by default, pay for it
+	// with a slower algorithm.
+	Stable bool
+	// Algorithm selects the sort algorithm.
+	Algorithm SortKind
+}
 
-	return New(head)
+// WithSortOptions sets the stability and algorithm of a sort
+func WithSortOptions(o SortOptions) SortOption {
+	return func(cfg *sortConfig) { cfg.SortOptions = o }
 }
 
-// SortByColumn sorts the DataFrame by the specified column
-func (df *DataFrame) SortByColumn(column string, ascending bool) (*DataFrame, error) {
+// SortCollation orders String and Categorical columns by the linguistic
+// rules of c, e.g. types.NewCollation("de"), instead of byte-wise. The
+// byte-wise order is the default and much faster; pass nil to keep it.
+func SortCollation(c *types.Collation) SortOption {
+	return func(cfg *sortConfig) { cfg.collation = c }
+}
+
+func sortParams(params map[string]interface{}, opts []SortOption) (map[string]interface{}, sortConfig) {
+	var cfg sortConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.collation != nil {
+		params["collation"] = cfg.collation.String()
+	}
+	if cfg.Stable {
+		params["stable"] = true
+	}
+	if cfg.Algorithm != SortAuto {
+		params["algorithm"] = cfg.Algorithm.String()
+	}
+	return params, cfg
+}
+
+// SortByColumn sorts the DataFrame by the specified column. Nulls are placed
+// last regardless of the sort direction. See Sort for several columns and
+// leading nulls.
+func (df *DataFrame) SortByColumn(column string, ascending bool, opts ...SortOption) (out *DataFrame, err error) {
+	params, cfg := sortParams(map[string]interface{}{"column": column, "ascending": ascending}, opts)
+	defer df.track("SortByColumn", params)(&out, &err)
 	series, ok := df.series[column]
 	if !ok {
 		return nil, fmt.Errorf("column %s not found", column)
 	}
+	indices, err := sortedRows(series, ascending, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return df.take(indices)
+}
 
-	// Create index slice to track original positions
-	indices := make([]int, df.length)
-	for i := range indices {
-		indices[i] = i
+// SortColumns is SortByColumn keeping only the given columns, in that
+// order, so that columns about to be dropped are never gathered. column
+// need not be one of them. Head and Slice share the column data and need
+// no such variant.
+func (df *DataFrame) SortColumns(columns []string, column string, ascending bool, opts ...SortOption) (out *DataFrame, err error) {
+	params, cfg := sortParams(map[string]interface{}{"columns": columns, "column": column, "ascending": ascending}, opts)
+	defer df.track("SortColumns", params)(&out, &err)
+	projected, err := df.project(columns)
+	if err != nil {
+		return nil, err
+	}
+	series, ok := df.series[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	indices, err := sortedRows(series, ascending, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return projected.take(indices)
+}
+
+// NullOrder places the nulls of a sort key before or after its values,
+// whatever the direction of the sort.
+type NullOrder int
+
+const (
+	NullsLast NullOrder = iota
+	NullsFirst
+)
+
+// SortField is one key of Sort: a column, its direction and the place of
+// its nulls.
+type SortField struct {
+	Column    string
+	Ascending bool
+	Nulls     NullOrder
+}
+
+// Sort sorts the DataFrame by the fields of by: the first field decides the
+// order and every later one breaks the ties left by those before it. Rows
+// equal on every field keep their order when the sort is stable, see
+// SortOptions. It takes one sort of the rows per field, from the last field
+// to the first, all but that first one stable.
+func (df *DataFrame) Sort(by []SortField, opts ...SortOption) (out *DataFrame, err error) {
+	params, cfg := sortParams(map[string]interface{}{"by": by}, opts)
+	defer df.track("Sort", params)(&out, &err)
+	rows, err := df.sortRows(by, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return df.take(rows)
+}
+
+// ArgSort returns the permutation Sort would apply, the positions of the
+// rows in sorted order, without gathering the rows. TakeOrdered applies it
+// to this DataFrame or to any other of as many rows, so several frames can
+// be sorted alike by the keys of one.
+func (df *DataFrame) ArgSort(by []SortField, opts ...SortOption) ([]int, error) {
+	_, cfg := sortParams(map[string]interface{}{}, opts)
+	return df.sortRows(by, cfg)
+}
+
+// TakeOrdered returns the rows in the order of perm, a permutation of the
+// row positions such as ArgSort returns. Unlike Take, every row must appear
+// exactly once.
+func (df *DataFrame) TakeOrdered(perm []int) (out *DataFrame, err error) {
+	defer df.track("TakeOrdered", map[string]interface{}{"rows": len(perm)})(&out, &err)
+	if len(perm) != df.length {
+		return nil, fmt.Errorf("permutation of %d rows applied to %d rows", len(perm), df.length)
+	}
+	seen := make([]bool, df.length)
+	for _, i := range perm {
+		if i < 0 || i >= df.length {
+			return nil, fmt.Errorf("row %d out of range for %d rows", i, df.length)
+		}
+		if seen[i] {
+			return nil, fmt.Errorf("row %d appears twice in the permutation", i)
+		}
+		seen[i] = true
+	}
+	return df.take(perm)
+}
+
+// sortRows returns the row positions of df sorted by the fields of by
+func (df *DataFrame) sortRows(by []SortField, cfg sortConfig) ([]int, error) {
+	if len(by) == 0 {
+		return nil, fmt.Errorf("sort needs at least one field")
+	}
+	for _, f := range by {
+		if _, ok := df.series[f.Column]; !ok {
+			return nil, fmt.Errorf("column %s not found", f.Column)
+		}
+	}
+	rows := make([]int, df.length)
+	for i := range rows {
+		rows[i] = i
+	}
+	for i := len(by) - 1; i >= 0; i-- {
+		f, pass := by[i], cfg
+		pass.Stable = pass.Stable || i < len(by)-1
+		var err error
+		if rows, err = orderRows(df.series[f.Column], rows, f.Ascending, f.Nulls == NullsFirst, pass); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// sortedRows returns the row positions of series in sorted order, with null
+// rows last, sorted as cfg says.
+func sortedRows(series *types.Series, ascending bool, cfg sortConfig) ([]int, error) {
+	rows := make([]int, series.Length)
+	for i := range rows {
+		rows[i] = i
 	}
+	return orderRows(series, rows, ascending, false, cfg)
+}
 
-	// Sort indices based on the column values
+// orderRows sorts rows, positions in series, by their values as cfg says
+// and places the null rows, in their given order, first or last.
+func orderRows(series *types.Series, rows []int, ascending, nullsFirst bool, cfg sortConfig) ([]int, error) {
+	// Only valid rows take part in the sort; null rows are added after.
+	valid := make([]int, 0, len(rows))
+	var nulls []int
+	for _, row := range rows {
+		if series.IsValid(row) {
+			valid = append(valid, row)
+		} else {
+			nulls = append(nulls, row)
+		}
+	}
+	indices, err := validOrder(series, valid, ascending, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if nullsFirst {
+		return append(nulls, indices...), nil
+	}
+	return append(indices, nulls...), nil
+}
+
+// validOrder sorts rows, valid positions in series, by their values as cfg
+// says.
+func validOrder(series *types.Series, rows []int, ascending bool, cfg sortConfig) ([]int, error) {
+	if cfg.Algorithm < SortAuto || cfg.Algorithm > SortComparison {
+		return nil, fmt.Errorf("unknown sort algorithm %s", cfg.Algorithm)
+	}
+	// Narrow integers and Float32 are sorted through their 64-bit widening.
+	if _, unsigned := series.Data.([]uint64); !unsigned {
+		var err error
+		if series, err = series.Widen(); err != nil {
+			return nil, err
+		}
+	}
+	indices := make([]int, len(rows))
+	byOrder := func(order []int) []int {
+		for i, p := range order {
+			indices[i] = rows[p]
+		}
+		return indices
+	}
+	if cfg.collation != nil {
+		if keys, ok := collationKeys(series, rows, cfg.collation); ok {
+			return byOrder(comparisonOrder(len(rows), cfg.Stable, func(i, j int) bool {
+				c := bytes.Compare(keys[i], keys[j])
+				return (ascending && c < 0) || (!ascending && c > 0)
+			})), nil
+		}
+	}
+
+	// Every other key is mapped to integers of the same order, unless text
+	// is compared directly.
+	keys := make([]uint64, len(rows))
 	switch data := series.Data.(type) {
+	case []uint64:
+		for i, row := range rows {
+			keys[i] = data[row]
+		}
 	case []int64:
-		keys := make([]uint64, len(data))
-		for i, v := range data {
-			keys[i] = uint64(v) ^ 0x8000000000000000
+		for i, row := range rows {
+			keys[i] = uint64(data[row]) ^ 0x8000000000000000
 		}
-		indices = ParallelRadixSortUint64(keys, ascending)
 	case []float64:
-		keys := make([]uint64, len(data))
-		for i, v := range data {
-			bits := math.Float64bits(v)
-			if bits>>63 == 0 {
-				keys[i] = bits ^ 0x8000000000000000
-			} else {
-				keys[i] = ^bits
-			}
+		for i, row := range rows {
+			keys[i] = floatSortKey(data[row])
 		}
-		indices = ParallelRadixSortUint64(keys, ascending)
-	case []string:
-		sort.Slice(indices, func(i, j int) bool {
-			if ascending {
-				return data[indices[i]] < data[indices[j]]
-			}
-			return data[indices[i]] > data[indices[j]]
-		})
 	case []bool:
-		sort.Slice(indices, func(i, j int) bool {
-			if ascending {
-				return !data[indices[i]] && data[indices[j]]
+		for i, row := range rows {
+			if data[row] {
+				keys[i] = 1
 			}
-			return data[indices[i]] && !data[indices[j]]
-		})
+		}
+	case *types.Categorical:
+		// Rank the categories once, then sort rows by rank.
+		rank := textRanks(data.Categories)
+		for i, row := range rows {
+			keys[i] = rank[data.Codes[row]]
+		}
+	case []string:
+		if cfg.Algorithm != SortRadix {
+			return byOrder(comparisonOrder(len(rows), cfg.Stable, func(i, j int) bool {
+				if ascending {
+					return data[rows[i]] < data[rows[j]]
+				}
+				return data[rows[i]] > data[rows[j]]
+			})), nil
+		}
+		values := make([]string, len(rows))
+		for i, row := range rows {
+			values[i] = data[row]
+		}
+		keys = textRanks(values)
 	default:
-		return nil, fmt.Errorf("unsupported data type for column %s", column)
+		return nil, fmt.Errorf("unsupported data type for column %s", series.Name)
 	}
+	if cfg.Algorithm == SortComparison {
+		return byOrder(comparisonOrder(len(rows), cfg.Stable, func(i, j int) bool {
+			return (ascending && keys[i] < keys[j]) || (!ascending && keys[i] > keys[j])
+		})), nil
+	}
+	return byOrder(ParallelRadixSortUint64(keys, ascending)), nil
+}
 
-	// Create new sorted series
-	sorted := make(map[string]*types.Series)
-	for name, s := range df.series {
-		switch data := s.Data.(type) {
-		case []int64:
-			newData := make([]int64, df.length)
-			for newIdx, oldIdx := range indices {
-				newData[newIdx] = data[oldIdx]
-			}
-			sorted[name] = types.NewSeries(name, newData)
-		case []float64:
-			newData := make([]float64, df.length)
-			for newIdx, oldIdx := range indices {
-				newData[newIdx] = data[oldIdx]
-			}
-			sorted[name] = types.NewSeries(name, newData)
-		case []string:
-			newData := make([]string, df.length)
-			for newIdx, oldIdx := range indices {
-				newData[newIdx] = data[oldIdx]
-			}
-			sorted[name] = types.NewSeries(name, newData)
-		case []bool:
-			newData := make([]bool, df.length)
-			for newIdx, oldIdx := range indices {
-				newData[newIdx] = data[oldIdx]
-			}
-			sorted[name] = types.NewSeries(name, newData)
+// comparisonOrder returns the positions 0 to n-1 sorted by less, keeping
+// equal positions in order when stable is set.
+func comparisonOrder(n int, stable bool, less func(i, j int) bool) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	if stable {
+		sort.SliceStable(order, func(i, j int) bool { return less(order[i], order[j]) })
+	} else {
+		sort.Slice(order, func(i, j int) bool { return less(order[i], order[j]) })
+	}
+	return order
+}
+
+// textRanks returns the rank of every value among the distinct values, in
+// byte-wise order.
+func textRanks(values []string) []uint64 {
+	rank := make(map[string]uint64, len(values))
+	for _, v := range values {
+		rank[v] = 0
+	}
+	distinct := make([]string, 0, len(rank))
+	for v := range rank {
+		distinct = append(distinct, v)
+	}
+	sort.Strings(distinct)
+	for r, v := range distinct {
+		rank[v] = uint64(r)
+	}
+	keys := make([]uint64, len(values))
+	for i, v := range values {
+		keys[i] = rank[v]
+	}
+	return keys
+}
+
+// collationKeys returns the collation keys of the valid rows of a String or
+// Categorical series, reporting false for other types.
+func collationKeys(series *types.Series, rows []int, collation *types.Collation) ([][]byte, bool) {
+	switch data := series.Data.(type) {
+	case []string:
+		values := make([]string, len(rows))
+		for i, row := range rows {
+			values[i] = data[row]
 		}
+		return collation.Keys(values), true
+	case *types.Categorical:
+		// Key the categories once and share them between rows.
+		byCode := collation.Keys(data.Categories)
+		keys := make([][]byte, len(rows))
+		for i, row := range rows {
+			keys[i] = byCode[data.Codes[row]]
+		}
+		return keys, true
+	default:
+		return nil, false
 	}
+}
 
-	return New(sorted)
+// floatSortKey maps a float to an unsigned integer with the same order,
+// placing NaNs with the sign bit clear after +Inf.
+func floatSortKey(f float64) uint64 {
+	bits := math.Float64bits(f)
+	if bits>>63 == 0 {
+		return bits ^ 0x8000000000000000
+	}
+	return ^bits
 }
 
 // SortByIndex sorts the DataFrame by the row index
-func (df *DataFrame) SortByIndex(ascending bool) (*DataFrame, error) {
+func (df *DataFrame) SortByIndex(ascending bool) (out *DataFrame, err error) {
+	defer df.track("SortByIndex", map[string]interface{}{"ascending": ascending})(&out, &err)
 	// Create index slice
 	indices := make([]int, df.length)
 	for i := range indices {
@@ -270,38 +811,7 @@ func (df *DataFrame) SortByIndex(ascending bool) (*DataFrame, error) {
 		sort.Sort(sort.Reverse(sort.IntSlice(indices)))
 	}
 
-	// Create new sorted series
-	sorted := make(map[string]*types.Series)
-	for name, s := range df.series {
-		switch data := s.Data.(type) {
-		case []int64:
-			newData := make([]int64, df.length)
-			for newIdx, oldIdx := range indices {
-				newData[newIdx] = data[oldIdx]
-			}
-			sorted[name] = types.NewSeries(name, newData)
-		case []float64:
-			newData := make([]float64, df.length)
-			for newIdx, oldIdx := range indices {
-				newData[newIdx] = data[oldIdx]
-			}
-			sorted[name] = types.NewSeries(name, newData)
-		case []string:
-			newData := make([]string, df.length)
-			for newIdx, oldIdx := range indices {
-				newData[newIdx] = data[oldIdx]
-			}
-			sorted[name] = types.NewSeries(name, newData)
-		case []bool:
-			newData := make([]bool, df.length)
-			for newIdx, oldIdx := range indices {
-				newData[newIdx] = data[oldIdx]
-			}
-			sorted[name] = types.NewSeries(name, newData)
-		}
-	}
-
-	return New(sorted)
+	return df.take(indices)
 }
 
 // AggregationType represents the type of aggregation to perform
@@ -313,10 +823,85 @@ const (
 	Count
 	Min
 	Max
+	NullCount
+	Var
+	Std
+	Median
+	Quantile
+	First
+	Last
+	NUnique
+	Any
+	All
+	Product
+	GeoMean
+	BitAnd
+	BitOr
+	BitXor
 )
 
+func (a AggregationType) String() string {
+	switch a {
+	case Sum:
+		return "sum"
+	case Mean:
+		return "mean"
+	case Count:
+		return "count"
+	case Min:
+		return "min"
+	case Max:
+		return "max"
+	case NullCount:
+		return "null_count"
+	case Var:
+		return "var"
+	case Std:
+		return "std"
+	case Median:
+		return "median"
+	case Quantile:
+		return "quantile"
+	case First:
+		return "first"
+	case Last:
+		return "last"
+	case NUnique:
+		return "n_unique"
+	case Any:
+		return "any"
+	case All:
+		return "all"
+	case Product:
+		return "product"
+	case GeoMean:
+		return "geo_mean"
+	case BitAnd:
+		return "bit_and"
+	case BitOr:
+		return "bit_or"
+	case BitXor:
+		return "bit_xor"
+	default:
+		return fmt.Sprintf("AggregationType(%d)", int(a))
+	}
+}
+
+// GroupByOption configures GroupBy
+type GroupByOption func(*GroupedDataFrame)
+
+// GroupVerifyKeys controls whether grouping checks that rows sharing a
+// 128-bit key hash really hold equal keys, so colliding keys form separate
+// groups instead of being merged and every row of a group holds the key
+// reported for it. It is on by default; turning it off saves comparing the
+// key columns of every row with those of its group's first row, at the risk
+// of merging the rare keys whose hashes collide.
+func GroupVerifyKeys(verify bool) GroupByOption {
+	return func(gdf *GroupedDataFrame) { gdf.verify = verify }
+}
+
 // GroupBy groups the DataFrame by one or more columns
-func (df *DataFrame) GroupBy(columns []string) (*GroupedDataFrame, error) {
+func (df *DataFrame) GroupBy(columns []string, opts ...GroupByOption) (*GroupedDataFrame, error) {
 	// Verify columns exist
 	for _, col := range columns {
 		if _, ok := df.series[col]; !ok {
@@ -327,11 +912,15 @@ func (df *DataFrame) GroupBy(columns []string) (*GroupedDataFrame, error) {
 	// Defer actual grouping work until Aggregate to enable a single-pass
 	// streaming aggregation. This minimises memory usage by avoiding the
 	// per-group []int slice that previously stored row indices.
-	return &GroupedDataFrame{
+	gdf := &GroupedDataFrame{
 		df:      df,
-		groups:  nil, // will be filled lazily if needed
 		columns: columns,
-	}, nil
+		verify:  true,
+	}
+	for _, opt := range opts {
+		opt(gdf)
+	}
+	return gdf, nil
 }
 
 // key128 is a simple 128-bit hash key used for grouping. It is comparable, so
@@ -344,568 +933,634 @@ type key128 struct {
 // GroupedDataFrame represents a grouped DataFrame
 type GroupedDataFrame struct {
 	df      *DataFrame
-	groups  map[key128][]int
 	columns []string
+	verify  bool
+
+	// grouping is built by the first call to Groups and reused afterwards.
+	// groupingDone is set once it has been, for readers that must not build
+	// it themselves.
+	groupingOnce sync.Once
+	groupingDone atomic.Bool
+	grouping     *types.Groups
+	groupingErr  error
 }
 
-// Aggregate performs the specified aggregation on the grouped DataFrame
-func (gdf *GroupedDataFrame) Aggregate(column string, aggType AggregationType) (*DataFrame, error) {
+// resultOrder is the column order of an aggregation result: the group
+// columns followed by the aggregated column.
+func (gdf *GroupedDataFrame) resultOrder(column string) []string {
+	return append(append([]string(nil), gdf.columns...), column)
+}
+
+// Aggregate performs the specified aggregation on the grouped DataFrame.
+// Null values are skipped: Sum of an all-null group is 0, Mean/Min/Max are
+// null, Count counts valid values and NullCount counts the nulls. Var and Std
+// are sample statistics, null for groups with fewer than two values; Median
+// interpolates between the middle values. First and Last take the group's
+// first and last row, null included, and NUnique counts distinct non-null
+// values. Any and All reduce a Boolean column with three-valued logic: Any
+// is true when some value is true and All false when some value is false;
+// otherwise a null in the group makes them null. Product multiplies the
+// values, giving 1 for a group without values and wrapping around on
+// Int64 overflow; GeoMean is the Float64 geometric mean, null for a group
+// without values and NaN when a value is negative. BitAnd, BitOr and BitXor
+// combine the bits of an integer column, null for a group without values.
+// Narrow and unsigned integer columns are upcast to Int64, and
+// Float32 to Float64, before aggregating, except that Sum, Min and Max of a
+// UInt64 column stay UInt64; such a Sum fails rather than overflow. An empty frame has no groups and
+// aggregates to an empty result of the same column types, unless it is
+// grouped by no columns: the whole frame is then one group, which yields a
+// single row even without rows to aggregate. Use AggregateQuantile for
+// Quantile.
+func (gdf *GroupedDataFrame) Aggregate(column string, aggType AggregationType) (out *DataFrame, err error) {
+	defer gdf.df.track("Aggregate", map[string]interface{}{"by": gdf.columns, "column": column, "agg": aggType.String()})(&out, &err)
+	if aggType == Quantile {
+		return nil, fmt.Errorf("quantile aggregation needs a quantile, use AggregateQuantile")
+	}
+	return gdf.aggregate(column, aggSpec{kind: aggType, q: 0.5})
+}
+
+// AggregateQuantile computes the q-th quantile, 0 <= q <= 1, of column in
+// every group, interpolating linearly between the closest values. Nulls are
+// skipped and groups without values yield null.
+func (gdf *GroupedDataFrame) AggregateQuantile(column string, q float64) (out *DataFrame, err error) {
+	return gdf.AggregateQuantileWith(column, q, types.QuantileLinear)
+}
+
+// AggregateQuantileWith is AggregateQuantile with the value between the
+// closest values chosen by method, e.g. types.QuantileLower to always get
+// a value of the group. The values are selected in linear time per group
+// rather than sorted.
+func (gdf *GroupedDataFrame) AggregateQuantileWith(column string, q float64, method types.QuantileInterpolation) (out *DataFrame, err error) {
+	defer gdf.df.track("AggregateQuantile", map[string]interface{}{"by": gdf.columns, "column": column, "q": q, "method": method.String()})(&out, &err)
+	if !(q >= 0 && q <= 1) {
+		return nil, fmt.Errorf("quantile %v out of range [0, 1]", q)
+	}
+	if !method.Valid() {
+		return nil, fmt.Errorf("unknown quantile interpolation %d", int(method))
+	}
+	return gdf.aggregate(column, aggSpec{kind: Quantile, q: q, method: method})
+}
+
+func (gdf *GroupedDataFrame) aggregate(column string, spec aggSpec) (*DataFrame, error) {
+	aggType := spec.kind
 	series, ok := gdf.df.series[column]
 	if !ok {
 		return nil, fmt.Errorf("column %s not found", column)
 	}
-
-	// Fast streaming path: if groups map is nil or empty, build aggregation in
-	// a single pass without allocating per-group index slices.
-	if gdf.groups == nil || len(gdf.groups) == 0 {
-		return gdf.aggregateStreaming(column, series, aggType)
+	if data, ok := series.Data.([]uint64); ok && (aggType == Sum || aggType == Min || aggType == Max) {
+		return gdf.aggregateUint64(column, series, data, aggType)
+	}
+	series, err := aggInput(series, aggType)
+	if err != nil {
+		return nil, err
 	}
 
-	// === Legacy path (uses precomputed []int indices) ======================
-
-	// Create result series for group columns and aggregated column
-	resultSeries := make(map[string]*types.Series)
-	length := len(gdf.groups)
-
-	// Initialize series for group columns
-	for _, col := range gdf.columns {
-		switch gdf.df.series[col].Data.(type) {
+	// Without group columns the whole frame is one group, so even an empty
+	// frame aggregates to a single row.
+	if len(gdf.columns) == 0 && gdf.df.length == 0 {
+		switch series.Data.(type) {
 		case []int64:
-			resultSeries[col] = types.NewSeries(col, make([]int64, length))
+			return newOrdered(map[string]*types.Series{column: aggColumn(column, []*aggState[int64]{{}}, spec)}, []string{column})
 		case []float64:
-			resultSeries[col] = types.NewSeries(col, make([]float64, length))
-		case []string:
-			resultSeries[col] = types.NewSeries(col, make([]string, length))
-		case []bool:
-			resultSeries[col] = types.NewSeries(col, make([]bool, length))
+			return newOrdered(map[string]*types.Series{column: aggColumn(column, []*aggState[float64]{{}}, spec)}, []string{column})
+		default:
+			return nil, fmt.Errorf("unsupported data type for aggregation")
 		}
 	}
 
-	// Initialize aggregated column
-	var aggData interface{}
-	switch series.Data.(type) {
-	case []int64:
-		aggData = make([]int64, length)
-	case []float64:
-		aggData = make([]float64, length)
-	default:
-		return nil, fmt.Errorf("unsupported data type for aggregation")
+	// Once Groups has been computed, fold over its rows instead of hashing
+	// the keys again.
+	if groups := gdf.cachedGroups(); groups != nil {
+		logDecision("aggregate", "path", "indexed", "column", column, "groups", groups.Len())
+		return gdf.aggregateGroups(column, series, spec)
 	}
-	resultSeries[column] = types.NewSeries(column, aggData)
 
-	// Perform aggregation for each group
-	i := 0
-	for _, indices := range gdf.groups {
-		// Set group column values
-		for _, col := range gdf.columns {
-			series := gdf.df.series[col]
-			switch data := series.Data.(type) {
-			case []int64:
-				resultSeries[col].Data.([]int64)[i] = data[indices[0]]
-			case []float64:
-				resultSeries[col].Data.([]float64)[i] = data[indices[0]]
-			case []string:
-				resultSeries[col].Data.([]string)[i] = data[indices[0]]
-			case []bool:
-				resultSeries[col].Data.([]bool)[i] = data[indices[0]]
-			}
+	return gdf.aggregateStreaming(column, series, spec)
+}
+
+// aggInput prepares a column for the aggregation engines, which work on
+// Int64 and Float64: Any and All take a Boolean column as 0 and 1, the
+// bitwise aggregations an integer column widened to Int64, and every other
+// aggregation a widened numeric one.
+func aggInput(s *types.Series, aggType AggregationType) (*types.Series, error) {
+	switch aggType {
+	case BitAnd, BitOr, BitXor:
+		if !types.IsInteger(s.DataType) {
+			return nil, fmt.Errorf("%s aggregation needs an integer column, %s is %s", aggType, s.Name, s.DataType)
 		}
+		return s.Widen()
+	case Any, All:
+	default:
+		return s.Widen()
+	}
+	data, ok := s.Data.([]bool)
+	if !ok {
+		return nil, fmt.Errorf("%s aggregation needs a Boolean column, %s is %s", aggType, s.Name, s.DataType)
+	}
+	out := types.NewSeries(s.Name, boolsAsInts(data))
+	out.Validity = s.Validity
+	return out, nil
+}
 
-		// Perform aggregation
-		switch data := series.Data.(type) {
-		case []int64:
-			var result int64
-			switch aggType {
-			case Sum:
-				result = sumInt64Indexed(data, indices)
-			case Mean:
-				result = sumInt64Indexed(data, indices) / int64(len(indices))
-			case Count:
-				result = int64(len(indices))
-			case Min:
-				result = minInt64Indexed(data, indices)
-			case Max:
-				result = maxInt64Indexed(data, indices)
-			}
-			resultSeries[column].Data.([]int64)[i] = result
-		case []float64:
-			var result float64
-			switch aggType {
-			case Sum:
-				result = sumFloat64Indexed(data, indices)
-			case Mean:
-				result = sumFloat64Indexed(data, indices) / float64(len(indices))
-			case Count:
-				result = float64(len(indices))
-			case Min:
-				result = minFloat64Indexed(data, indices)
-			case Max:
-				result = maxFloat64Indexed(data, indices)
+// aggSpec is an aggregation together with its parameters: q is the
+// quantile for Quantile and Median, and method how it interpolates.
+type aggSpec struct {
+	kind   AggregationType
+	q      float64
+	method types.QuantileInterpolation
+}
+
+// aggState accumulates one group's running aggregate in the streaming path.
+// Only the fields needed by the aggregation being computed are maintained.
+type aggState[T int64 | float64] struct {
+	sum   T
+	min   T
+	max   T
+	count int64 // number of valid values
+	nulls int64
+	rep   int          // row holding the group's key, the first one seen
+	next  *aggState[T] // another group whose key hashes the same, see GroupVerifyKeys
+
+	// Var and Std: Welford's running mean and sum of squared deviations.
+	mean float64
+	m2   float64
+
+	// Product multiplies the values; GeoMean sums their logarithms.
+	prod   T
+	logSum float64
+
+	// BitAnd, BitOr and BitXor fold the bits of the integer values.
+	bitAnd, bitOr, bitXor int64
+
+	// Median and Quantile keep the group's valid values.
+	values []T
+
+	// NUnique collects the distinct valid values; NaN is tracked apart since
+	// it never equals itself.
+	distinct map[T]struct{}
+	nan      bool
+
+	// First and Last keep the values at the lowest and highest row seen so
+	// that shards merge regardless of the order they are combined in.
+	first, last           T
+	firstRow, lastRow     int
+	firstValid, lastValid bool
+}
+
+func (st *aggState[T]) add(row int, v T, aggType AggregationType) {
+	if st.count == 0 {
+		st.min, st.max = v, v
+	}
+	switch aggType {
+	case Sum, Mean:
+		st.sum += v
+	case Var, Std:
+		x := float64(v)
+		delta := x - st.mean
+		st.mean += delta / float64(st.count+1)
+		st.m2 += delta * (x - st.mean)
+	case Median, Quantile:
+		st.values = append(st.values, v)
+	case Product:
+		if st.count == 0 {
+			st.prod = v
+		} else {
+			st.prod *= v
+		}
+	case GeoMean:
+		st.logSum += math.Log(float64(v))
+	case BitAnd:
+		if st.count == 0 {
+			st.bitAnd = int64(v)
+		} else {
+			st.bitAnd &= int64(v)
+		}
+	case BitOr:
+		st.bitOr |= int64(v)
+	case BitXor:
+		st.bitXor ^= int64(v)
+	case NUnique:
+		if v != v {
+			st.nan = true
+		} else {
+			if st.distinct == nil {
+				st.distinct = make(map[T]struct{})
 			}
-			resultSeries[column].Data.([]float64)[i] = result
+			st.distinct[v] = struct{}{}
 		}
-		i++
+	case First, Last:
+		st.position(row, v, true)
+	}
+	if v < st.min {
+		st.min = v
+	}
+	if v > st.max {
+		st.max = v
 	}
+	st.count++
+}
 
-	return New(resultSeries)
+func (st *aggState[T]) addNull(row int, aggType AggregationType) {
+	if aggType == First || aggType == Last {
+		var zero T
+		st.position(row, zero, false)
+	}
+	st.nulls++
 }
 
-// aggregateStreaming performs a single-pass aggregation without allocating
-// per-group index slices. It is called when GroupBy deferred building the map.
-func (gdf *GroupedDataFrame) aggregateStreaming(column string, series *types.Series, aggType AggregationType) (*DataFrame, error) {
-	if gdf.groups == nil {
-		gdf.groups = make(map[key128][]int)
-	}
-	// Define aggregation state containers.
-	type int64State struct {
-		sum   int64
-		min   int64
-		max   int64
-		count int64
-		rep   int // representative row index for group column extraction
-	}
-	type float64State struct {
-		sum   float64
-		min   float64
-		max   float64
-		count int64
-		rep   int
-	}
-
-	// Containers per key.
-	intStates := make(map[key128]*int64State)
-	floatStates := make(map[key128]*float64State)
-
-	// Convenience for value series data switch.
-	switch data := series.Data.(type) {
-	case []int64:
-		rows := len(data)
-		workers := runtime.GOMAXPROCS(0)
-		if workers < 1 {
-			workers = 1
-		}
-
-		// Use parallel path for larger datasets (> 50k) and multiple CPUs.
-		if rows >= 50000 && workers > 1 {
-			shard := (rows + workers - 1) / workers
-			local := make([]map[key128]*int64State, workers)
-			var wg sync.WaitGroup
-			wg.Add(workers)
-
-			for w := 0; w < workers; w++ {
-				start := w * shard
-				end := start + shard
-				if end > rows {
-					end = rows
-				}
-				go func(slot, s, e int) {
-					defer wg.Done()
-					m := make(map[key128]*int64State)
-					for i := s; i < e; i++ {
-						v := data[i]
-						var hi, lo uint64
-						for colIdx, col := range gdf.columns {
-							s := gdf.df.series[col]
-							var hv uint64
-							switch colData := s.Data.(type) {
-							case []int64:
-								var buf [8]byte
-								binary.LittleEndian.PutUint64(buf[:], uint64(colData[i]))
-								hv = xxhash.Sum64(buf[:])
-							case []float64:
-								var buf [8]byte
-								binary.LittleEndian.PutUint64(buf[:], math.Float64bits(colData[i]))
-								hv = xxhash.Sum64(buf[:])
-							case []string:
-								hv = xxhash.Sum64String(colData[i])
-							case []bool:
-								var buf [8]byte
-								var b uint64
-								if colData[i] {
-									b = 1
-								}
-								binary.LittleEndian.PutUint64(buf[:], b)
-								hv = xxhash.Sum64(buf[:])
-							}
-
-							shift := uint(colIdx*11) & 63
-							if colIdx%2 == 0 {
-								hi ^= bits.RotateLeft64(hv, int(shift))
-							} else {
-								lo ^= bits.RotateLeft64(hv, int(shift))
-							}
-						}
-
-						k := key128{hi: hi, lo: lo}
-						st, ok := m[k]
-						if !ok {
-							st = &int64State{min: v, max: v, rep: i}
-							m[k] = st
-						}
-						if aggType == Sum || aggType == Mean {
-							st.sum += v
-						}
-						if v < st.min {
-							st.min = v
-						}
-						if v > st.max {
-							st.max = v
-						}
-						st.count++
-					}
-					local[slot] = m
-				}(w, start, end)
-			}
-			wg.Wait()
-
-			// Merge local maps into intStates
-			for _, m := range local {
-				for k, st := range m {
-					dst, ok := intStates[k]
-					if !ok {
-						intStates[k] = st
-						continue
-					}
-					dst.sum += st.sum
-					dst.count += st.count
-					if st.min < dst.min {
-						dst.min = st.min
-					}
-					if st.max > dst.max {
-						dst.max = st.max
-					}
-				}
-			}
+// position records v as the first and/or last value if row lies before or
+// after every row seen so far.
+func (st *aggState[T]) position(row int, v T, valid bool) {
+	seen := st.count+st.nulls > 0
+	if !seen || row < st.firstRow {
+		st.first, st.firstRow, st.firstValid = v, row, valid
+	}
+	if !seen || row > st.lastRow {
+		st.last, st.lastRow, st.lastValid = v, row, valid
+	}
+}
 
+func (st *aggState[T]) merge(other *aggState[T]) {
+	if other.count+other.nulls > 0 {
+		if st.count+st.nulls == 0 || other.firstRow < st.firstRow {
+			st.first, st.firstRow, st.firstValid = other.first, other.firstRow, other.firstValid
+		}
+		if st.count+st.nulls == 0 || other.lastRow > st.lastRow {
+			st.last, st.lastRow, st.lastValid = other.last, other.lastRow, other.lastValid
+		}
+	}
+	if other.count > 0 {
+		if st.count == 0 || other.min < st.min {
+			st.min = other.min
+		}
+		if st.count == 0 || other.max > st.max {
+			st.max = other.max
+		}
+		// Chan et al.'s pairwise update of the Welford moments.
+		n := float64(st.count + other.count)
+		delta := other.mean - st.mean
+		st.mean += delta * float64(other.count) / n
+		st.m2 += other.m2 + delta*delta*float64(st.count)*float64(other.count)/n
+		if st.count == 0 {
+			st.prod, st.bitAnd = other.prod, other.bitAnd
 		} else {
-			// Single-threaded fall-back (previous logic)
-			for i, v := range data {
-				var hi, lo uint64
-				for colIdx, col := range gdf.columns {
-					s := gdf.df.series[col]
-					var hv uint64
-					switch colData := s.Data.(type) {
-					case []int64:
-						var buf [8]byte
-						binary.LittleEndian.PutUint64(buf[:], uint64(colData[i]))
-						hv = xxhash.Sum64(buf[:])
-					case []float64:
-						var buf [8]byte
-						binary.LittleEndian.PutUint64(buf[:], math.Float64bits(colData[i]))
-						hv = xxhash.Sum64(buf[:])
-					case []string:
-						hv = xxhash.Sum64String(colData[i])
-					case []bool:
-						var buf [8]byte
-						var b uint64
-						if colData[i] {
-							b = 1
-						}
-						binary.LittleEndian.PutUint64(buf[:], b)
-						hv = xxhash.Sum64(buf[:])
-					}
-
-					shift := uint(colIdx*11) & 63
-					if colIdx%2 == 0 {
-						hi ^= bits.RotateLeft64(hv, int(shift))
-					} else {
-						lo ^= bits.RotateLeft64(hv, int(shift))
-					}
-				}
+			st.prod *= other.prod
+			st.bitAnd &= other.bitAnd
+		}
+	}
+	st.values = append(st.values, other.values...)
+	if other.distinct != nil {
+		if st.distinct == nil {
+			st.distinct = make(map[T]struct{}, len(other.distinct))
+		}
+		for v := range other.distinct {
+			st.distinct[v] = struct{}{}
+		}
+	}
+	st.nan = st.nan || other.nan
+	st.sum += other.sum
+	st.logSum += other.logSum
+	st.bitOr |= other.bitOr
+	st.bitXor ^= other.bitXor
+	st.count += other.count
+	st.nulls += other.nulls
+}
 
-				k := key128{hi: hi, lo: lo}
-				st, ok := intStates[k]
-				if !ok {
-					st = &int64State{min: v, max: v, rep: i}
-					intStates[k] = st
-				}
+// result finalises an aggregate that keeps the column's type. The boolean is
+// false when the result is null, which happens for Mean/Min/Max over a group
+// without valid values and for First/Last when that row is null.
+func (st *aggState[T]) result(aggType AggregationType) (T, bool) {
+	switch aggType {
+	case Sum:
+		return st.sum, true
+	case Mean:
+		if st.count == 0 {
+			return 0, false
+		}
+		return st.sum / T(st.count), true
+	case Count:
+		return T(st.count), true
+	case Min:
+		return st.min, st.count > 0
+	case Max:
+		return st.max, st.count > 0
+	case NullCount:
+		return T(st.nulls), true
+	case Product:
+		if st.count == 0 {
+			return 1, true
+		}
+		return st.prod, true
+	case BitAnd:
+		return T(st.bitAnd), st.count > 0
+	case BitOr:
+		return T(st.bitOr), st.count > 0
+	case BitXor:
+		return T(st.bitXor), st.count > 0
+	case First:
+		return st.first, st.firstValid
+	case Last:
+		return st.last, st.lastValid
+	default:
+		return 0, false
+	}
+}
 
-				if aggType == Sum || aggType == Mean {
-					st.sum += v
-				}
-				if v < st.min {
-					st.min = v
-				}
-				if v > st.max {
-					st.max = v
-				}
-				st.count++
-			}
+// boolResult finalises Any and All over values of 0 and 1 with
+// three-valued logic: a value that decides the result wins, otherwise a
+// null makes the result null. Without any values Any is false and All true.
+func (st *aggState[T]) boolResult(aggType AggregationType) (bool, bool) {
+	if aggType == Any {
+		if st.count > 0 && st.max == 1 {
+			return true, true
 		}
+		return false, st.nulls == 0
+	}
+	if st.count > 0 && st.min == 0 {
+		return false, true
+	}
+	return true, st.nulls == 0
+}
 
-		// Build result series.
-		length := len(intStates)
-		resultSeries := make(map[string]*types.Series)
+// floatResult finalises the Float64-valued aggregates. Var and Std use the
+// sample estimator and are null for groups with fewer than two valid values;
+// quantiles pick or interpolate between the closest ranks.
+func (st *aggState[T]) floatResult(spec aggSpec) (float64, bool) {
+	switch spec.kind {
+	case Var, Std:
+		if st.count < 2 {
+			return 0, false
+		}
+		v := st.m2 / float64(st.count-1)
+		if spec.kind == Std {
+			v = math.Sqrt(v)
+		}
+		return v, true
+	case Median, Quantile:
+		return types.QuantileOf(st.values, spec.q, spec.method)
+	case GeoMean:
+		if st.count == 0 {
+			return 0, false
+		}
+		return math.Exp(st.logSum / float64(st.count)), true
+	default:
+		return 0, false
+	}
+}
 
-		// Init group column series
-		for _, col := range gdf.columns {
-			switch gdf.df.series[col].Data.(type) {
+// aggColumn builds the aggregated column from the groups' states in output
+// order. Var, Std, Median, Quantile and GeoMean produce Float64, NUnique
+// Int64, Any and All Boolean, and every other aggregation the type of the
+// input.
+func aggColumn[T int64 | float64](name string, states []*aggState[T], spec aggSpec) *types.Series {
+	valid := make([]bool, len(states))
+	switch spec.kind {
+	case Any, All:
+		data := make([]bool, len(states))
+		for i, st := range states {
+			data[i], valid[i] = st.boolResult(spec.kind)
+		}
+		return types.NewNullableSeries(name, data, valid)
+	case Var, Std, Median, Quantile, GeoMean:
+		data := make([]float64, len(states))
+		for i, st := range states {
+			data[i], valid[i] = st.floatResult(spec)
+		}
+		return types.NewNullableSeries(name, data, valid)
+	case NUnique:
+		data := make([]int64, len(states))
+		for i, st := range states {
+			data[i], valid[i] = int64(len(st.distinct)), true
+			if st.nan {
+				data[i]++
+			}
+		}
+		return types.NewNullableSeries(name, data, valid)
+	default:
+		data := make([]T, len(states))
+		for i, st := range states {
+			data[i], valid[i] = st.result(spec.kind)
+		}
+		return types.NewNullableSeries(name, data, valid)
+	}
+}
+
+// aggregateStreaming performs a single-pass aggregation without allocating
+// per-group index slices. It is called when GroupBy deferred building the map.
+func (gdf *GroupedDataFrame) aggregateStreaming(column string, series *types.Series, spec aggSpec) (*DataFrame, error) {
+	// Grouping by a single categorical column indexes states by code.
+	if len(gdf.columns) == 1 {
+		key := gdf.df.series[gdf.columns[0]]
+		if cat, ok := key.Data.(*types.Categorical); ok {
+			logDecision("aggregate", "path", "streaming", "column", column, "strategy", "categorical codes")
+			switch data := series.Data.(type) {
 			case []int64:
-				resultSeries[col] = types.NewSeries(col, make([]int64, length))
+				return categoricalAggregate(gdf, key, cat, column, data, series.Validity, spec)
 			case []float64:
-				resultSeries[col] = types.NewSeries(col, make([]float64, length))
-			case []string:
-				resultSeries[col] = types.NewSeries(col, make([]string, length))
-			case []bool:
-				resultSeries[col] = types.NewSeries(col, make([]bool, length))
+				return categoricalAggregate(gdf, key, cat, column, data, series.Validity, spec)
 			}
 		}
+	}
 
-		aggData := make([]int64, length)
-		resultSeries[column] = types.NewSeries(column, aggData)
-
-		idx := 0
-		for k, st := range intStates {
-			// Set group column values from representative row
-			rep := st.rep
-			for _, col := range gdf.columns {
-				s := gdf.df.series[col]
-				switch colData := s.Data.(type) {
-				case []int64:
-					resultSeries[col].Data.([]int64)[idx] = colData[rep]
-				case []float64:
-					resultSeries[col].Data.([]float64)[idx] = colData[rep]
-				case []string:
-					resultSeries[col].Data.([]string)[idx] = colData[rep]
-				case []bool:
-					resultSeries[col].Data.([]bool)[idx] = colData[rep]
-				}
-			}
+	// Sorted or high-cardinality numeric keys aggregate over contiguous runs.
+	if key, order, ok := gdf.sortStrategy(); ok {
+		logDecision("aggregate", "path", "streaming", "column", column, "strategy", "sorted runs", "sorted_first", order != nil)
+		switch data := series.Data.(type) {
+		case []int64:
+			return sortAggregateInt64(gdf, key, order, column, data, series.Validity, spec)
+		case []float64:
+			return sortAggregateFloat64(gdf, key, order, column, data, series.Validity, spec)
+		}
+	}
 
-			// Finalise aggregation value
-			var out int64
-			switch aggType {
-			case Sum:
-				out = st.sum
-			case Mean:
-				out = st.sum / st.count
-			case Count:
-				out = st.count
-			case Min:
-				out = st.min
-			case Max:
-				out = st.max
-			}
-			aggData[idx] = out
+	logDecision("aggregate", "path", "streaming", "column", column, "strategy", "hash", "verify_keys", gdf.verify)
+	var same func(i, j int) bool
+	if gdf.verify {
+		var err error
+		if same, err = gdf.df.rowKeysEqual(gdf.columns); err != nil {
+			return nil, err
+		}
+	}
+	switch data := series.Data.(type) {
+	case []int64:
+		states := streamAggregate(gdf, data, series.Validity, spec.kind, same)
+		return streamingResult(gdf, column, states, spec)
+	case []float64:
+		states := streamAggregate(gdf, data, series.Validity, spec.kind, same)
+		return streamingResult(gdf, column, states, spec)
+	default:
+		return nil, fmt.Errorf("unsupported data type for aggregation")
+	}
+}
+
+// categoricalAggregate groups directly on the dictionary codes of key: states
+// live in an array sized by the number of categories (plus one slot for null
+// keys), so no hashing is needed. Groups are emitted in category order.
+func categoricalAggregate[T int64 | float64](gdf *GroupedDataFrame, key *types.Series, cat *types.Categorical, column string, data []T, valid *types.Bitmap, spec aggSpec) (*DataFrame, error) {
+	nullSlot := len(cat.Categories)
+	states := make([]aggState[T], nullSlot+1)
+	for i := range states {
+		states[i].rep = -1
+	}
 
-			// Optionally store back into groups map for later reuse.
-			gdf.groups[k] = []int{rep} // minimal placeholder
-			idx++
+	for i, v := range data {
+		slot := nullSlot
+		if key.IsValid(i) {
+			slot = int(cat.Codes[i])
 		}
+		st := &states[slot]
+		if st.rep < 0 {
+			st.rep = i
+		}
+		if valid != nil && !valid.Get(i) {
+			st.addNull(i, spec.kind)
+			continue
+		}
+		st.add(i, v, spec.kind)
+	}
 
-		return New(resultSeries)
+	reps := make([]int, 0, len(states))
+	groups := make([]*aggState[T], 0, len(states))
+	for i := range states {
+		st := &states[i]
+		if st.rep < 0 {
+			continue
+		}
+		reps = append(reps, st.rep)
+		groups = append(groups, st)
+	}
 
-	case []float64:
-		rows := len(data)
-		workers := runtime.GOMAXPROCS(0)
-		if workers < 1 {
-			workers = 1
-		}
-
-		if rows >= 50000 && workers > 1 {
-			shard := (rows + workers - 1) / workers
-			local := make([]map[key128]*float64State, workers)
-			var wg sync.WaitGroup
-			wg.Add(workers)
-
-			for w := 0; w < workers; w++ {
-				start := w * shard
-				end := start + shard
-				if end > rows {
-					end = rows
-				}
-				go func(slot, s, e int) {
-					defer wg.Done()
-					m := make(map[key128]*float64State)
-					for i := s; i < e; i++ {
-						v := data[i]
-						var hi, lo uint64
-						for colIdx, col := range gdf.columns {
-							s := gdf.df.series[col]
-							var hv uint64
-							switch colData := s.Data.(type) {
-							case []int64:
-								var buf [8]byte
-								binary.LittleEndian.PutUint64(buf[:], uint64(colData[i]))
-								hv = xxhash.Sum64(buf[:])
-							case []float64:
-								var buf [8]byte
-								binary.LittleEndian.PutUint64(buf[:], math.Float64bits(colData[i]))
-								hv = xxhash.Sum64(buf[:])
-							case []string:
-								hv = xxhash.Sum64String(colData[i])
-							case []bool:
-								var buf [8]byte
-								var b uint64
-								if colData[i] {
-									b = 1
-								}
-								binary.LittleEndian.PutUint64(buf[:], b)
-								hv = xxhash.Sum64(buf[:])
-							}
-
-							shift := uint(colIdx*11) & 63
-							if colIdx%2 == 0 {
-								hi ^= bits.RotateLeft64(hv, int(shift))
-							} else {
-								lo ^= bits.RotateLeft64(hv, int(shift))
-							}
-						}
-
-						k := key128{hi: hi, lo: lo}
-						st, ok := m[k]
-						if !ok {
-							st = &float64State{min: v, max: v, rep: i}
-							m[k] = st
-						}
-						if aggType == Sum || aggType == Mean {
-							st.sum += v
-						}
-						if v < st.min {
-							st.min = v
-						}
-						if v > st.max {
-							st.max = v
-						}
-						st.count++
-					}
-					local[slot] = m
-				}(w, start, end)
-			}
+	return newOrdered(map[string]*types.Series{
+		gdf.columns[0]: key.Take(reps),
+		column:         aggColumn(column, groups, spec),
+	}, gdf.resultOrder(column))
+}
 
-			wg.Wait()
-
-			// Merge local maps
-			for _, m := range local {
-				for k, st := range m {
-					dst, ok := floatStates[k]
-					if !ok {
-						floatStates[k] = st
-						continue
-					}
-					dst.sum += st.sum
-					dst.count += st.count
-					if st.min < dst.min {
-						dst.min = st.min
-					}
-					if st.max > dst.max {
-						dst.max = st.max
-					}
-				}
+// streamAggregate hashes every row's group key and folds the value into the
+// group's state, sharding the work across CPUs for larger inputs. When same
+// is set, states sharing a hash are chained and a row joins the one whose
+// key row holds an equal key.
+func streamAggregate[T int64 | float64](gdf *GroupedDataFrame, data []T, valid *types.Bitmap, aggType AggregationType, same func(i, j int) bool) map[key128]*aggState[T] {
+	accumulate := func(m map[key128]*aggState[T], s, e int) {
+		for i := s; i < e; i++ {
+			k := groupHash(gdf.df, gdf.columns, i)
+			head := m[k]
+			st := head
+			for same != nil && st != nil && !same(st.rep, i) {
+				st = st.next
+			}
+			if st == nil {
+				st = &aggState[T]{rep: i, next: head}
+				m[k] = st
+			}
+			if valid != nil && !valid.Get(i) {
+				st.addNull(i, aggType)
+				continue
 			}
+			st.add(i, data[i], aggType)
+		}
+	}
 
-		} else {
-			for i, v := range data {
-				var hi, lo uint64
-				for colIdx, col := range gdf.columns {
-					s := gdf.df.series[col]
-					var hv uint64
-					switch colData := s.Data.(type) {
-					case []int64:
-						var buf [8]byte
-						binary.LittleEndian.PutUint64(buf[:], uint64(colData[i]))
-						hv = xxhash.Sum64(buf[:])
-					case []float64:
-						var buf [8]byte
-						binary.LittleEndian.PutUint64(buf[:], math.Float64bits(colData[i]))
-						hv = xxhash.Sum64(buf[:])
-					case []string:
-						hv = xxhash.Sum64String(colData[i])
-					case []bool:
-						var buf [8]byte
-						var b uint64
-						if colData[i] {
-							b = 1
-						}
-						binary.LittleEndian.PutUint64(buf[:], b)
-						hv = xxhash.Sum64(buf[:])
-					}
-
-					shift := uint(colIdx*11) & 63
-					if colIdx%2 == 0 {
-						hi ^= bits.RotateLeft64(hv, int(shift))
-					} else {
-						lo ^= bits.RotateLeft64(hv, int(shift))
-					}
-				}
+	rows := len(data)
+	workers := types.MaxThreads()
+	if workers < 1 {
+		workers = 1
+	}
 
-				k := key128{hi: hi, lo: lo}
-				st, ok := floatStates[k]
-				if !ok {
-					st = &float64State{min: v, max: v, rep: i}
-					floatStates[k] = st
-				}
+	// Use parallel path for larger datasets (> 50k) and multiple CPUs.
+	if rows < 50000 || workers == 1 {
+		states := make(map[key128]*aggState[T])
+		accumulate(states, 0, rows)
+		return states
+	}
 
-				if aggType == Sum || aggType == Mean {
-					st.sum += v
-				}
-				if v < st.min {
-					st.min = v
+	shard := (rows + workers - 1) / workers
+	local := make([]map[key128]*aggState[T], workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	for w := 0; w < workers; w++ {
+		start := w * shard
+		end := start + shard
+		if end > rows {
+			end = rows
+		}
+		if start > end {
+			start = end
+		}
+		go func(slot, s, e int) {
+			defer wg.Done()
+			m := make(map[key128]*aggState[T])
+			accumulate(m, s, e)
+			local[slot] = m
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	// Merge local maps; earlier shards win the key row.
+	states := make(map[key128]*aggState[T])
+	for _, m := range local {
+		for k, chain := range m {
+			for st := chain; st != nil; {
+				next := st.next
+				dst := states[k]
+				for same != nil && dst != nil && !same(dst.rep, st.rep) {
+					dst = dst.next
 				}
-				if v > st.max {
-					st.max = v
+				if dst == nil {
+					st.next = states[k]
+					states[k] = st
+				} else {
+					dst.merge(st)
 				}
-				st.count++
+				st = next
 			}
 		}
+	}
+	return states
+}
 
-		// Build result series as before
-		length := len(floatStates)
-		resultSeries := make(map[string]*types.Series)
-		for _, col := range gdf.columns {
-			switch gdf.df.series[col].Data.(type) {
-			case []int64:
-				resultSeries[col] = types.NewSeries(col, make([]int64, length))
-			case []float64:
-				resultSeries[col] = types.NewSeries(col, make([]float64, length))
-			case []string:
-				resultSeries[col] = types.NewSeries(col, make([]string, length))
-			case []bool:
-				resultSeries[col] = types.NewSeries(col, make([]bool, length))
-			}
+// rowKeysEqual returns a test of whether rows i and j hold the same values in
+// columns, treating two nulls, or two NaNs, as equal.
+func (df *DataFrame) rowKeysEqual(columns []string) (func(i, j int) bool, error) {
+	eqs := make([]func(i, j int) bool, len(columns))
+	for c, col := range columns {
+		s := df.series[col]
+		eq, err := valuesEqual(s, s)
+		if err != nil {
+			return nil, fmt.Errorf("cannot verify group keys: %w", err)
 		}
-
-		aggData := make([]float64, length)
-		resultSeries[column] = types.NewSeries(column, aggData)
-
-		idx := 0
-		for k, st := range floatStates {
-			rep := st.rep
-			for _, col := range gdf.columns {
-				s := gdf.df.series[col]
-				switch colData := s.Data.(type) {
-				case []int64:
-					resultSeries[col].Data.([]int64)[idx] = colData[rep]
-				case []float64:
-					resultSeries[col].Data.([]float64)[idx] = colData[rep]
-				case []string:
-					resultSeries[col].Data.([]string)[idx] = colData[rep]
-				case []bool:
-					resultSeries[col].Data.([]bool)[idx] = colData[rep]
-				}
-			}
-
-			var out float64
-			switch aggType {
-			case Sum:
-				out = st.sum
-			case Mean:
-				out = st.sum / float64(st.count)
-			case Count:
-				out = float64(st.count)
-			case Min:
-				out = st.min
-			case Max:
-				out = st.max
+		eqs[c] = eq
+	}
+	return func(i, j int) bool {
+		for _, eq := range eqs {
+			if !eq(i, j) {
+				return false
 			}
-			aggData[idx] = out
+		}
+		return true
+	}, nil
+}
 
-			gdf.groups[k] = []int{rep}
-			idx++
+// streamingResult builds the output frame of the streaming path: one row per
+// group holding the group keys and the finalised aggregate.
+func streamingResult[T int64 | float64](gdf *GroupedDataFrame, column string, states map[key128]*aggState[T], spec aggSpec) (*DataFrame, error) {
+	reps := make([]int, 0, len(states))
+	groups := make([]*aggState[T], 0, len(states))
+	for _, chain := range states {
+		for st := chain; st != nil; st = st.next {
+			reps = append(reps, st.rep)
+			groups = append(groups, st)
 		}
+	}
 
-		return New(resultSeries)
-	default:
-		return nil, fmt.Errorf("unsupported data type for aggregation")
+	// Group column values come from each group's key row, whose key every
+	// row of the group shares when keys are verified
+	resultSeries := make(map[string]*types.Series)
+	for _, col := range gdf.columns {
+		resultSeries[col] = gdf.df.series[col].Take(reps)
 	}
+	resultSeries[column] = aggColumn(column, groups, spec)
+
+	return newOrdered(resultSeries, gdf.resultOrder(column))
 }
@@ -0,0 +1,69 @@
+package dataframe
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ColumnDefault supplies the value of a column where the input has none:
+// a key missing from a record, a nil pointer field or struct, or an empty
+// CSV field. Func, when set, is called with the row's index and its result
+// used instead of Value, e.g. to stamp rows or draw identifiers; it must be
+// safe to call from the goroutine of the constructor. A nil result leaves
+// the element null.
+type ColumnDefault struct {
+	Value any
+	Func  func(row int) any
+}
+
+func (d ColumnDefault) at(row int) any {
+	if d.Func != nil {
+		return d.Func(row)
+	}
+	return d.Value
+}
+
+type buildConfig struct {
+	defaults map[string]ColumnDefault
+}
+
+// BuildOption configures FromStructs and FromRecords
+type BuildOption func(*buildConfig)
+
+// WithDefaults sets the defaults of the columns named in defaults. Values
+// are converted as the values of the input would be.
+func WithDefaults(defaults map[string]ColumnDefault) BuildOption {
+	return func(c *buildConfig) { c.defaults = defaults }
+}
+
+func buildOptions(opts []BuildOption) buildConfig {
+	var cfg buildConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// defaultNames returns the names defaults gives a value for, sorted
+func defaultNames(defaults map[string]ColumnDefault) []string {
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// csvDefault writes a default value as a CSV field, to be parsed with the
+// fields of the file.
+func csvDefault(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,35 @@
+package dataframe
+
+import (
+	"fmt"
+
+	"go-polars/types"
+)
+
+// GroupByDynamic groups the rows into time windows over timeColumn, whose
+// Int64 values are nanoseconds since the Unix epoch, for downsampling
+// such as the mean per 5 minutes:
+//
+//	gdf, err := df.GroupByDynamic("ts", types.Fixed(5*time.Minute), types.Duration{}, types.Duration{})
+//	means, err := gdf.Aggregate("value", Mean)
+//
+// Windows start every every and last period, tumbling when period is zero
+// or equal to every and sliding when longer; offset shifts their starts.
+// See types.DynamicGroups for how windows are laid out. The groups are
+// keyed by window start, in column timeColumn, and feed Aggregate, Apply,
+// Collect and Partitions like those of GroupBy; sliding windows share rows,
+// which the per-group transforms such as Cumulative refuse.
+func (df *DataFrame) GroupByDynamic(timeColumn string, every, period, offset types.Duration) (*GroupedDataFrame, error) {
+	times, ok := df.series[timeColumn]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", timeColumn)
+	}
+	groups, err := types.DynamicGroups(times, every, period, offset)
+	if err != nil {
+		return nil, err
+	}
+	gdf := &GroupedDataFrame{df: df, columns: []string{timeColumn}}
+	gdf.groupingOnce.Do(func() { gdf.grouping = groups })
+	gdf.groupingDone.Store(true)
+	return gdf, nil
+}
@@ -0,0 +1,53 @@
+package dataframe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxErrorValue caps how much of an offending record a ParseError keeps.
+const maxErrorValue = 256
+
+// ParseError describes a record a reader failed to parse. Readers return it
+// (possibly wrapped) so a failure in a large input can be traced to the exact
+// record; use errors.As to retrieve it.
+type ParseError struct {
+	Row    int    // zero-based index of the record
+	Line   int    // one-based line number, 0 when the format has no lines
+	Offset int64  // byte offset of the record in the input, -1 when unknown
+	Column string // column being parsed, empty when not specific to one
+	Value  string // offending text, truncated to a few hundred bytes
+	Err    error
+}
+
+func newParseError(row, line int, offset int64, column, value string, err error) *ParseError {
+	return &ParseError{Row: row, Line: line, Offset: offset, Column: column, Value: truncateValue(value), Err: err}
+}
+
+func truncateValue(value string) string {
+	if len(value) > maxErrorValue {
+		return value[:maxErrorValue] + "..."
+	}
+	return value
+}
+
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "row %d", e.Row)
+	if e.Line > 0 {
+		fmt.Fprintf(&b, " (line %d)", e.Line)
+	}
+	if e.Offset >= 0 {
+		fmt.Fprintf(&b, " at byte %d", e.Offset)
+	}
+	if e.Column != "" {
+		fmt.Fprintf(&b, ", column %s", e.Column)
+	}
+	if e.Value != "" {
+		fmt.Fprintf(&b, ", value %q", e.Value)
+	}
+	fmt.Fprintf(&b, ": %v", e.Err)
+	return b.String()
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
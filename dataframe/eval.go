@@ -0,0 +1,290 @@
+package dataframe
+
+import (
+	"cmp"
+	"fmt"
+
+	"go-polars/expr"
+	"go-polars/types"
+)
+
+// WithColumnExpr evaluates e against the DataFrame and stores the result in
+// column name, replacing any column of that name.
+func (df *DataFrame) WithColumnExpr(name string, e expr.Expr) (out *DataFrame, err error) {
+	defer df.track("WithColumnExpr", map[string]interface{}{"name": name, "expr": e.String()})(&out, &err)
+	s, err := df.Eval(e)
+	if err != nil {
+		return nil, err
+	}
+	return df.withColumns(s.Rename(name))
+}
+
+// literalSeries broadcasts a constant to n rows.
+func literalSeries(v interface{}, n int) (*types.Series, error) {
+	const name = "literal"
+	switch x := v.(type) {
+	case int:
+		return types.NewSeries(name, fill(int64(x), n)), nil
+	case int64:
+		return types.NewSeries(name, fill(x, n)), nil
+	case float64:
+		return types.NewSeries(name, fill(x, n)), nil
+	case string:
+		return types.NewSeries(name, fill(x, n)), nil
+	case bool:
+		return types.NewSeries(name, fill(x, n)), nil
+	case nil:
+		return nil, fmt.Errorf("cannot infer the type of a null literal on its own")
+	default:
+		return nil, fmt.Errorf("unsupported literal %v (%T)", v, v)
+	}
+}
+
+func fill[T any](v T, n int) []T {
+	out := make([]T, n)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
+
+func isNullLiteral(e expr.Expr) bool {
+	return e.Kind == expr.KindLiteral && e.Value == nil
+}
+
+// evalOperand evaluates e with integers widened to Int64, floats to Float64
+// and categoricals decoded to strings, the forms the operators work on.
+func (df *DataFrame) evalOperand(e expr.Expr) (*types.Series, error) {
+	s, err := df.Eval(e)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := s.Data.(*types.Categorical); ok {
+		out := types.NewSeries(s.Name, c.Decode())
+		out.Validity = s.Validity
+		return out, nil
+	}
+	return s.Widen()
+}
+
+// evalPair evaluates two operands. A null literal on one side becomes a
+// column of nulls of the other side's type.
+func (df *DataFrame) evalPair(a, b expr.Expr) (l, r *types.Series, err error) {
+	aNull, bNull := isNullLiteral(a), isNullLiteral(b)
+	if aNull && bNull {
+		return nil, nil, fmt.Errorf("cannot infer the type of %s and %s", a, b)
+	}
+	if !aNull {
+		if l, err = df.evalOperand(a); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !bNull {
+		if r, err = df.evalOperand(b); err != nil {
+			return nil, nil, err
+		}
+	}
+	if aNull {
+		l = nullLike(r)
+	}
+	if bNull {
+		r = nullLike(l)
+	}
+	return l, r, nil
+}
+
+func nullLike(s *types.Series) *types.Series {
+	idx := make([]int, s.Length)
+	for i := range idx {
+		idx[i] = -1
+	}
+	return s.Take(idx)
+}
+
+// asFloats returns the values of a widened numeric Series as float64.
+func asFloats(s *types.Series) ([]float64, bool) {
+	switch d := s.Data.(type) {
+	case []float64:
+		return d, true
+	case []int64:
+		out := make([]float64, len(d))
+		for i, v := range d {
+			out[i] = float64(v)
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+func (df *DataFrame) evalBinary(e expr.Expr) (*types.Series, error) {
+	l, r, err := df.evalPair(e.Inputs[0], e.Inputs[1])
+	if err != nil {
+		return nil, err
+	}
+	valid := make([]bool, l.Length)
+	for i := range valid {
+		valid[i] = l.IsValid(i) && r.IsValid(i)
+	}
+	mismatch := fmt.Errorf("cannot apply %s to %s and %s", e.Op, l.DataType, r.DataType)
+
+	switch {
+	case e.Op.IsArithmetic():
+		if li, ok := l.Data.([]int64); ok && e.Op != expr.OpDiv {
+			if ri, ok := r.Data.([]int64); ok {
+				return types.NewNullableSeries(l.Name, arithmetic(e.Op, li, ri), valid), nil
+			}
+		}
+		lf, lok := asFloats(l)
+		rf, rok := asFloats(r)
+		if !lok || !rok {
+			return nil, mismatch
+		}
+		return types.NewNullableSeries(l.Name, arithmetic(e.Op, lf, rf), valid), nil
+	case e.Op.IsComparison():
+		var out []bool
+		switch ld := l.Data.(type) {
+		case []int64:
+			if rd, ok := r.Data.([]int64); ok {
+				out = comparison(e.Op, ld, rd)
+			}
+		case []string:
+			if rd, ok := r.Data.([]string); ok {
+				out = comparison(e.Op, ld, rd)
+			}
+		case []bool:
+			if rd, ok := r.Data.([]bool); ok {
+				out = comparison(e.Op, boolsAsInts(ld), boolsAsInts(rd))
+			}
+		}
+		if out == nil {
+			lf, lok := asFloats(l)
+			rf, rok := asFloats(r)
+			if !lok || !rok {
+				return nil, mismatch
+			}
+			out = comparison(e.Op, lf, rf)
+		}
+		return types.NewNullableSeries(l.Name, out, valid), nil
+	case e.Op == expr.OpOr:
+		return l.Or(r)
+	default:
+		return l.And(r)
+	}
+}
+
+func arithmetic[T int64 | float64](op expr.Op, l, r []T) []T {
+	out := make([]T, len(l))
+	switch op {
+	case expr.OpAdd:
+		for i := range out {
+			out[i] = l[i] + r[i]
+		}
+	case expr.OpSub:
+		for i := range out {
+			out[i] = l[i] - r[i]
+		}
+	case expr.OpMul:
+		for i := range out {
+			out[i] = l[i] * r[i]
+		}
+	case expr.OpDiv:
+		for i := range out {
+			out[i] = l[i] / r[i]
+		}
+	}
+	return out
+}
+
+func comparison[T cmp.Ordered](op expr.Op, l, r []T) []bool {
+	out := make([]bool, len(l))
+	switch op {
+	case expr.OpEq:
+		for i := range out {
+			out[i] = l[i] == r[i]
+		}
+	case expr.OpNe:
+		for i := range out {
+			out[i] = l[i] != r[i]
+		}
+	case expr.OpLt:
+		for i := range out {
+			out[i] = l[i] < r[i]
+		}
+	case expr.OpLe:
+		for i := range out {
+			out[i] = l[i] <= r[i]
+		}
+	case expr.OpGt:
+		for i := range out {
+			out[i] = l[i] > r[i]
+		}
+	case expr.OpGe:
+		for i := range out {
+			out[i] = l[i] >= r[i]
+		}
+	}
+	return out
+}
+
+func boolsAsInts(data []bool) []int64 {
+	out := make([]int64, len(data))
+	for i, v := range data {
+		if v {
+			out[i] = 1
+		}
+	}
+	return out
+}
+
+func (df *DataFrame) evalNot(e expr.Expr) (*types.Series, error) {
+	s, err := df.Eval(e.Inputs[0])
+	if err != nil {
+		return nil, err
+	}
+	return s.Not()
+}
+
+// evalCond picks every row from the then or the otherwise branch. The
+// branches must have the same type, except that integers and floats mix as
+// Float64.
+func (df *DataFrame) evalCond(e expr.Expr) (*types.Series, error) {
+	cond, err := df.Eval(e.Inputs[0])
+	if err != nil {
+		return nil, err
+	}
+	mask, ok := cond.Data.([]bool)
+	if !ok {
+		return nil, fmt.Errorf("when condition %s is %s, not Boolean", e.Inputs[0], cond.DataType)
+	}
+	then, otherwise, err := df.evalPair(e.Inputs[1], e.Inputs[2])
+	if err != nil {
+		return nil, err
+	}
+	if then.DataType.String() != otherwise.DataType.String() {
+		tf, tok := asFloats(then)
+		of, ook := asFloats(otherwise)
+		if !tok || !ook {
+			return nil, fmt.Errorf("when branches have different types %s and %s", then.DataType, otherwise.DataType)
+		}
+		then = withValidity(types.NewSeries(then.Name, tf), then)
+		otherwise = withValidity(types.NewSeries(then.Name, of), otherwise)
+	}
+	both, err := concatSeries([]*types.Series{then, otherwise.Rename(then.Name)})
+	if err != nil {
+		return nil, err
+	}
+	idx := make([]int, len(mask))
+	for i, m := range mask {
+		idx[i] = i
+		if !m || !cond.IsValid(i) {
+			idx[i] += len(mask)
+		}
+	}
+	return both.Take(idx), nil
+}
+
+func withValidity(s, from *types.Series) *types.Series {
+	s.Validity = from.Validity
+	return s
+}
@@ -0,0 +1,122 @@
+package dataframe
+
+import (
+	"fmt"
+
+	"go-polars/types"
+)
+
+// Explode turns every element of the given list columns into its own row,
+// repeating the values of the remaining columns. Several columns are exploded
+// in lockstep, so paired lists such as (timestamps, values) stay aligned; the
+// lists of a row must then all have the same length. A null or empty list
+// yields a single row holding null.
+func (df *DataFrame) Explode(columns ...string) (out *DataFrame, err error) {
+	defer df.track("Explode", map[string]interface{}{"columns": columns})(&out, &err)
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns to explode")
+	}
+
+	var counts []int64
+	exploded := make(map[string]*types.Series, len(columns))
+	for _, col := range columns {
+		s, ok := df.series[col]
+		if !ok {
+			return nil, fmt.Errorf("column %s not found", col)
+		}
+		lens, err := s.ListLengths()
+		if err != nil {
+			return nil, err
+		}
+		// Null lists count as empty when comparing lengths.
+		n := lens.Data.([]int64)
+		for i := range n {
+			if !lens.IsValid(i) {
+				n[i] = 0
+			}
+		}
+		if counts == nil {
+			counts = n
+		} else {
+			for i := range n {
+				if n[i] != counts[i] {
+					return nil, fmt.Errorf("row %d: column %s has %d elements, expected %d", i, col, n[i], counts[i])
+				}
+			}
+		}
+		if exploded[col], err = s.Explode(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Each input row repeats max(n, 1) times.
+	var rows []int
+	for i, n := range counts {
+		if n == 0 {
+			n = 1
+		}
+		for j := int64(0); j < n; j++ {
+			rows = append(rows, i)
+		}
+	}
+
+	result := make(map[string]*types.Series, len(df.series))
+	for name, s := range df.series {
+		if e, ok := exploded[name]; ok {
+			result[name] = e
+		} else {
+			result[name] = s.Take(rows)
+		}
+	}
+	return newOrdered(result, df.order)
+}
+
+// Collect gathers the values of column within each group into a list column,
+// the inverse of Explode. Groups appear in order of first appearance and
+// null values are left out of the lists.
+func (gdf *GroupedDataFrame) Collect(column string) (out *DataFrame, err error) {
+	defer gdf.df.track("Collect", map[string]interface{}{"by": gdf.columns, "column": column})(&out, &err)
+	s, ok := gdf.df.series[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	rows, err := gdf.groupRows()
+	if err != nil {
+		return nil, err
+	}
+
+	var lists *types.Series
+	switch data := s.Data.(type) {
+	case []int64:
+		lists = types.NewSeries(column, collectLists(s, data, rows))
+	case []float64:
+		lists = types.NewSeries(column, collectLists(s, data, rows))
+	case []string:
+		lists = types.NewSeries(column, collectLists(s, data, rows))
+	case []bool:
+		lists = types.NewSeries(column, collectLists(s, data, rows))
+	default:
+		return nil, fmt.Errorf("unsupported data type for collect: %s", s.DataType)
+	}
+
+	result := make(map[string]*types.Series, len(gdf.columns)+1)
+	for i, col := range gdf.columns {
+		result[col] = gdf.grouping.Keys[i]
+	}
+	result[column] = lists
+	return newOrdered(result, gdf.resultOrder(column))
+}
+
+func collectLists[T any](s *types.Series, data []T, rows [][]int) [][]T {
+	lists := make([][]T, len(rows))
+	for g, r := range rows {
+		l := make([]T, 0, len(r))
+		for _, i := range r {
+			if s.IsValid(i) {
+				l = append(l, data[i])
+			}
+		}
+		lists[g] = l
+	}
+	return lists
+}
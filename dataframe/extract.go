@@ -0,0 +1,24 @@
+package dataframe
+
+import (
+	"fmt"
+)
+
+// ExtractInto matches the regular expression pattern against the String or
+// Categorical column and adds one String column per named capture group,
+// named after the group, holding what it captured in the first match of
+// every row. Rows that do not match, and null rows, give nulls. Columns of
+// the same name as a group are replaced; the source column is kept. For
+// example, `(?P<level>\w+) (?P<msg>.*)` splits log lines into level and msg.
+func (df *DataFrame) ExtractInto(column, pattern string) (out *DataFrame, err error) {
+	defer df.track("ExtractInto", map[string]interface{}{"column": column, "pattern": pattern})(&out, &err)
+	s, ok := df.series[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	groups, err := s.Str().ExtractGroups(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return df.withColumns(groups...)
+}
@@ -0,0 +1,470 @@
+package dataframe
+
+import (
+	"cmp"
+	"fmt"
+
+	"go-polars/expr"
+	"go-polars/types"
+)
+
+// The typed comparisons below evaluate a whole column against a constant
+// without boxing every value, unlike Filter's callback. Their masks can be
+// combined with Series.And, Or and Not and applied with FilterMask.
+
+// CompareInt64 returns a Boolean mask that is true where column compares to
+// value under op, which must be one of the comparison operators of expr.
+// Integer columns of any width are accepted. The mask is null where the
+// column is.
+func (df *DataFrame) CompareInt64(column string, op expr.Op, value int64) (*types.Series, error) {
+	s, err := df.compareColumn(column, op)
+	if err != nil {
+		return nil, err
+	}
+	var out []bool
+	switch data := s.Data.(type) {
+	case []int64:
+		out = compareScalar(op, data, value)
+	case []int8:
+		out = compareInts(op, data, value)
+	case []int16:
+		out = compareInts(op, data, value)
+	case []int32:
+		out = compareInts(op, data, value)
+	case []uint8:
+		out = compareInts(op, data, value)
+	case []uint16:
+		out = compareInts(op, data, value)
+	case []uint32:
+		out = compareInts(op, data, value)
+	case []uint64:
+		if value < 0 {
+			// Every element is greater than a negative value.
+			out = compareScalar(op, make([]int64, len(data)), -1)
+		} else {
+			out = compareScalar(op, data, uint64(value))
+		}
+	default:
+		return nil, fmt.Errorf("cannot compare %s column %s to an integer", s.DataType, column)
+	}
+	return maskSeries(s, out), nil
+}
+
+// CompareFloat64 returns a Boolean mask that is true where column compares
+// to value under op. Numeric columns of any type are compared as Float64;
+// comparisons with NaN are false except for OpNe. The mask is null where the
+// column is.
+func (df *DataFrame) CompareFloat64(column string, op expr.Op, value float64) (*types.Series, error) {
+	s, err := df.compareColumn(column, op)
+	if err != nil {
+		return nil, err
+	}
+	if !types.IsNumeric(s.DataType) {
+		return nil, fmt.Errorf("cannot compare %s column %s to a float", s.DataType, column)
+	}
+	var out []bool
+	switch data := s.Data.(type) {
+	case []float64:
+		out = compareScalar(op, data, value)
+	default:
+		w, err := s.Widen()
+		if err != nil {
+			return nil, err
+		}
+		floats, _ := asFloats(w)
+		out = compareScalar(op, floats, value)
+	}
+	return maskSeries(s, out), nil
+}
+
+// CompareString returns a Boolean mask that is true where column compares
+// to value under op, ordering strings bytewise. Categorical columns compare
+// their values, evaluating each category once. The mask is null where the
+// column is.
+func (df *DataFrame) CompareString(column string, op expr.Op, value string) (*types.Series, error) {
+	s, err := df.compareColumn(column, op)
+	if err != nil {
+		return nil, err
+	}
+	var out []bool
+	switch data := s.Data.(type) {
+	case []string:
+		out = compareScalar(op, data, value)
+	case *types.Categorical:
+		match := compareScalar(op, data.Categories, value)
+		out = make([]bool, len(data.Codes))
+		for i, code := range data.Codes {
+			out[i] = match[code]
+		}
+	default:
+		return nil, fmt.Errorf("cannot compare %s column %s to a string", s.DataType, column)
+	}
+	return maskSeries(s, out), nil
+}
+
+// CompareBool returns a Boolean mask that is true where column compares to
+// value under op, ordering false before true. The mask is null where the
+// column is.
+func (df *DataFrame) CompareBool(column string, op expr.Op, value bool) (*types.Series, error) {
+	s, err := df.compareColumn(column, op)
+	if err != nil {
+		return nil, err
+	}
+	data, ok := s.Data.([]bool)
+	if !ok {
+		return nil, fmt.Errorf("cannot compare %s column %s to a bool", s.DataType, column)
+	}
+	out := make([]bool, len(data))
+	switch op {
+	case expr.OpEq:
+		for i, v := range data {
+			out[i] = v == value
+		}
+	case expr.OpNe:
+		for i, v := range data {
+			out[i] = v != value
+		}
+	default:
+		v := int64(0)
+		if value {
+			v = 1
+		}
+		out = compareScalar(op, boolsAsInts(data), v)
+	}
+	return maskSeries(s, out), nil
+}
+
+// FilterInt64 keeps the rows where column compares to value under op, see
+// CompareInt64. Rows where the column is null never match.
+func (df *DataFrame) FilterInt64(column string, op expr.Op, value int64) (out *DataFrame, err error) {
+	defer df.track("FilterInt64", map[string]interface{}{"column": column, "op": op.String(), "value": value})(&out, &err)
+	mask, err := df.CompareInt64(column, op, value)
+	if err != nil {
+		return nil, err
+	}
+	return df.filterMask(mask)
+}
+
+// FilterFloat64 keeps the rows where column compares to value under op, see
+// CompareFloat64. Rows where the column is null never match.
+func (df *DataFrame) FilterFloat64(column string, op expr.Op, value float64) (out *DataFrame, err error) {
+	defer df.track("FilterFloat64", map[string]interface{}{"column": column, "op": op.String(), "value": value})(&out, &err)
+	mask, err := df.CompareFloat64(column, op, value)
+	if err != nil {
+		return nil, err
+	}
+	return df.filterMask(mask)
+}
+
+// FilterString keeps the rows where column compares to value under op, see
+// CompareString. Rows where the column is null never match.
+func (df *DataFrame) FilterString(column string, op expr.Op, value string) (out *DataFrame, err error) {
+	defer df.track("FilterString", map[string]interface{}{"column": column, "op": op.String(), "value": value})(&out, &err)
+	mask, err := df.CompareString(column, op, value)
+	if err != nil {
+		return nil, err
+	}
+	return df.filterMask(mask)
+}
+
+// FilterBool keeps the rows where column compares to value under op, see
+// CompareBool. Rows where the column is null never match.
+func (df *DataFrame) FilterBool(column string, op expr.Op, value bool) (out *DataFrame, err error) {
+	defer df.track("FilterBool", map[string]interface{}{"column": column, "op": op.String(), "value": value})(&out, &err)
+	mask, err := df.CompareBool(column, op, value)
+	if err != nil {
+		return nil, err
+	}
+	return df.filterMask(mask)
+}
+
+// predicateBatchSize is the number of values a batched predicate sees per
+// call.
+const predicateBatchSize = 4096
+
+// FilterInt64Batch keeps the rows where predicate sets keep. Instead of
+// one boxed value per call like Filter, predicate is called with
+// consecutive chunks of the column, integers of any width widened to
+// Int64, and the part of the mask for those rows to fill in. Neither slice
+// may be kept after the call. Rows where the column is null never match,
+// whatever predicate decides for them.
+func (df *DataFrame) FilterInt64Batch(column string, predicate func(values []int64, keep []bool)) (out *DataFrame, err error) {
+	defer df.track("FilterInt64Batch", map[string]interface{}{"column": column})(&out, &err)
+	s, ok := df.series[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	if !types.IsInteger(s.DataType) {
+		return nil, fmt.Errorf("cannot filter %s column %s as integers", s.DataType, column)
+	}
+	w, err := s.Widen()
+	if err != nil {
+		return nil, err
+	}
+	return df.filterMask(maskSeries(s, filterBatches(w.Data.([]int64), predicate)))
+}
+
+// FilterFloat64Batch keeps the rows where predicate sets keep, see
+// FilterInt64Batch. Numeric columns of any type are passed as Float64.
+func (df *DataFrame) FilterFloat64Batch(column string, predicate func(values []float64, keep []bool)) (out *DataFrame, err error) {
+	defer df.track("FilterFloat64Batch", map[string]interface{}{"column": column})(&out, &err)
+	s, ok := df.series[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	if !types.IsNumeric(s.DataType) {
+		return nil, fmt.Errorf("cannot filter %s column %s as floats", s.DataType, column)
+	}
+	w, err := s.Widen()
+	if err != nil {
+		return nil, err
+	}
+	floats, _ := asFloats(w)
+	return df.filterMask(maskSeries(s, filterBatches(floats, predicate)))
+}
+
+// FilterStringBatch keeps the rows where predicate sets keep, see
+// FilterInt64Batch. Categorical columns are passed as their values.
+func (df *DataFrame) FilterStringBatch(column string, predicate func(values []string, keep []bool)) (out *DataFrame, err error) {
+	defer df.track("FilterStringBatch", map[string]interface{}{"column": column})(&out, &err)
+	s, ok := df.series[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	var values []string
+	switch data := s.Data.(type) {
+	case []string:
+		values = data
+	case *types.Categorical:
+		values = data.Decode()
+	default:
+		return nil, fmt.Errorf("cannot filter %s column %s as strings", s.DataType, column)
+	}
+	return df.filterMask(maskSeries(s, filterBatches(values, predicate)))
+}
+
+// FilterBoolBatch keeps the rows where predicate sets keep, see
+// FilterInt64Batch.
+func (df *DataFrame) FilterBoolBatch(column string, predicate func(values []bool, keep []bool)) (out *DataFrame, err error) {
+	defer df.track("FilterBoolBatch", map[string]interface{}{"column": column})(&out, &err)
+	s, ok := df.series[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	values, ok := s.Data.([]bool)
+	if !ok {
+		return nil, fmt.Errorf("cannot filter %s column %s as bools", s.DataType, column)
+	}
+	return df.filterMask(maskSeries(s, filterBatches(values, predicate)))
+}
+
+// filterBatches runs predicate over values a batch at a time, letting it
+// write straight into the returned mask.
+func filterBatches[T any](values []T, predicate func(values []T, keep []bool)) []bool {
+	mask := make([]bool, len(values))
+	for offset := 0; offset < len(values); offset += predicateBatchSize {
+		end := min(offset+predicateBatchSize, len(values))
+		predicate(values[offset:end:end], mask[offset:end:end])
+	}
+	return mask
+}
+
+// FilterMask keeps the rows where mask, a Boolean Series as long as the
+// DataFrame, is true. Rows where the mask is null are dropped.
+func (df *DataFrame) FilterMask(mask *types.Series) (out *DataFrame, err error) {
+	defer df.track("FilterMask", nil)(&out, &err)
+	return df.filterMask(mask)
+}
+
+// filterMask keeps the rows where mask is true. Selective masks become a
+// list of row positions before the columns are gathered, the others a
+// packed mask, see types.Selection.
+func (df *DataFrame) filterMask(mask *types.Series) (*DataFrame, error) {
+	if _, ok := mask.Data.([]bool); !ok {
+		return nil, fmt.Errorf("filter mask is %s, not Boolean", mask.DataType)
+	}
+	if mask.Length != df.length {
+		return nil, fmt.Errorf("filter mask has length %d, expected %d", mask.Length, df.length)
+	}
+	sel, err := types.NewSelection(mask)
+	if err != nil {
+		return nil, err
+	}
+	return df.filter(sel)
+}
+
+// filterChunkSize is the number of rows FilterExpr evaluates at a time
+const filterChunkSize = 64 * 1024
+
+// FilterExpr keeps the rows where the Boolean expression pred is true;
+// rows where it is null are dropped. The terms of a top-level And chain
+// run one after the other on chunks of rows: a term only sees the rows of
+// its chunk that passed the earlier terms, on the columns it reads, and
+// once a chunk has no rows left the remaining terms are skipped. The rows
+// that pass are copied once at the end. Predicates holding aggregations,
+// windows or shifts see the whole frame instead.
+func (df *DataFrame) FilterExpr(pred expr.Expr) (out *DataFrame, err error) {
+	defer df.track("FilterExpr", map[string]interface{}{"predicate": pred.String()})(&out, &err)
+	sel, err := df.exprSelection(pred)
+	if err != nil {
+		return nil, err
+	}
+	return df.filter(sel)
+}
+
+// FilterColumns is FilterExpr keeping only the given columns, in that
+// order, so that columns about to be dropped are never copied. pred may
+// read any column of the DataFrame.
+func (df *DataFrame) FilterColumns(columns []string, pred expr.Expr) (out *DataFrame, err error) {
+	defer df.track("FilterColumns", map[string]interface{}{"columns": columns, "predicate": pred.String()})(&out, &err)
+	projected, err := df.project(columns)
+	if err != nil {
+		return nil, err
+	}
+	sel, err := df.exprSelection(pred)
+	if err != nil {
+		return nil, err
+	}
+	return projected.filter(sel)
+}
+
+// exprSelection evaluates a filter predicate, see FilterExpr
+func (df *DataFrame) exprSelection(pred expr.Expr) (*types.Selection, error) {
+	if !rowWise(pred) {
+		mask, err := df.predicateMask(pred)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewSelection(mask)
+	}
+	// Check the whole predicate up front so that errors do not depend on
+	// which terms the data lets run.
+	if _, err := df.slice(0, 0).predicateMask(pred); err != nil {
+		return nil, err
+	}
+
+	terms := conjuncts(pred)
+	keep := make([]int, 0)
+	for offset := 0; offset < df.length; offset += filterChunkSize {
+		n := min(filterChunkSize, df.length-offset)
+		var rows []int // positions in the chunk still passing, nil for all
+		for _, term := range terms {
+			mask, err := df.gather(exprColumns(term), offset, n, rows).predicateMask(term)
+			if err != nil {
+				return nil, err
+			}
+			data := mask.Data.([]bool)
+			passed := make([]int, 0, len(data))
+			for i, v := range data {
+				if !v || !mask.IsValid(i) {
+					continue
+				}
+				if rows == nil {
+					passed = append(passed, i)
+				} else {
+					passed = append(passed, rows[i])
+				}
+			}
+			if rows = passed; len(rows) == 0 {
+				break
+			}
+		}
+		for _, row := range rows {
+			keep = append(keep, offset+row)
+		}
+	}
+	return types.SelectRows(keep, df.length), nil
+}
+
+// predicateMask evaluates a filter predicate, which must be Boolean
+func (df *DataFrame) predicateMask(pred expr.Expr) (*types.Series, error) {
+	mask, err := df.Eval(pred)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := mask.Data.([]bool); !ok {
+		return nil, fmt.Errorf("filter predicate %s is %s, not Boolean", pred, mask.DataType)
+	}
+	return mask, nil
+}
+
+// gather copies the given columns of rows [offset, offset+n), or only the
+// rows at the positions in rows of that range when rows is not nil.
+func (df *DataFrame) gather(columns []string, offset, n int, rows []int) *DataFrame {
+	out := &DataFrame{series: make(map[string]*types.Series, len(columns)), length: n}
+	if rows != nil {
+		out.length = len(rows)
+	}
+	for _, name := range columns {
+		if _, ok := out.series[name]; ok {
+			continue
+		}
+		s := df.series[name].Slice(offset, n)
+		if rows != nil {
+			s = s.Take(rows)
+		}
+		out.series[name] = s
+		out.order = append(out.order, name)
+	}
+	return out
+}
+
+// compareColumn looks up the column of a typed comparison
+func (df *DataFrame) compareColumn(column string, op expr.Op) (*types.Series, error) {
+	if !op.IsComparison() {
+		return nil, fmt.Errorf("%s is not a comparison operator", op)
+	}
+	s, ok := df.series[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	return s, nil
+}
+
+// maskSeries wraps the result of comparing s, keeping its nulls
+func maskSeries(s *types.Series, out []bool) *types.Series {
+	mask := types.NewSeries(s.Name, out)
+	mask.Validity = s.Validity
+	return mask
+}
+
+func compareScalar[T cmp.Ordered](op expr.Op, data []T, v T) []bool {
+	out := make([]bool, len(data))
+	switch op {
+	case expr.OpEq:
+		for i, x := range data {
+			out[i] = x == v
+		}
+	case expr.OpNe:
+		for i, x := range data {
+			out[i] = x != v
+		}
+	case expr.OpLt:
+		for i, x := range data {
+			out[i] = x < v
+		}
+	case expr.OpLe:
+		for i, x := range data {
+			out[i] = x <= v
+		}
+	case expr.OpGt:
+		for i, x := range data {
+			out[i] = x > v
+		}
+	case expr.OpGe:
+		for i, x := range data {
+			out[i] = x >= v
+		}
+	}
+	return out
+}
+
+// compareInts compares integers narrower than 64 bits, which all fit in
+// an Int64.
+func compareInts[T int8 | int16 | int32 | uint8 | uint16 | uint32](op expr.Op, data []T, v int64) []bool {
+	wide := make([]int64, len(data))
+	for i, x := range data {
+		wide[i] = int64(x)
+	}
+	return compareScalar(op, wide, v)
+}
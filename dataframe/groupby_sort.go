@@ -2,47 +2,38 @@ package dataframe
 
 import (
 	"encoding/binary"
-	"errors"
 	"math"
 	"math/bits"
 
+	"go-polars/types"
+
 	xxhash "github.com/cespare/xxhash/v2"
 )
 
+// nullKeyHash stands in for the hash of a null key element so that all nulls
+// of a column fall into the same group.
+const nullKeyHash uint64 = 0x9e3779b97f4a7c15
+
 // buildKey128 constructs a deterministic 128-bit hash key for the given row
-// using the supplied grouping columns. The algorithm mirrors the one used in
-// aggregateStreaming so that both the streaming and sort-based paths can share
-// the same key space.
+// using the supplied grouping columns. It is used by aggregateStreaming and the
+// sort-based path alike so that both share the same key space.
 func buildKey128(df *DataFrame, columns []string, row int) key128 {
+	return mixKey128(df, columns, row, hashValue)
+}
+
+// mixKey128 combines the hash of each key column, as given by hash, into a
+// 128-bit key.
+func mixKey128(df *DataFrame, columns []string, row int, hash func(interface{}, int) uint64) key128 {
 	var hi, lo uint64
 
 	for colIdx, col := range columns {
 		s := df.series[col]
 		var hv uint64
 
-		switch colData := s.Data.(type) {
-		case []int64:
-			var buf [8]byte
-			binary.LittleEndian.PutUint64(buf[:], uint64(colData[row]))
-			hv = xxhash.Sum64(buf[:])
-		case []float64:
-			var buf [8]byte
-			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(colData[row]))
-			hv = xxhash.Sum64(buf[:])
-		case []string:
-			hv = xxhash.Sum64String(colData[row])
-		case []bool:
-			var buf [8]byte
-			var b uint64
-			if colData[row] {
-				b = 1
-			}
-			binary.LittleEndian.PutUint64(buf[:], b)
-			hv = xxhash.Sum64(buf[:])
-		default:
-			// Unsupported types fall back to zero hash – this still provides
-			// determinism but may lead to collisions for exotic column types.
-			hv = 0
+		if !s.IsValid(row) {
+			hv = nullKeyHash
+		} else {
+			hv = hash(s.Data, row)
 		}
 
 		shift := uint(colIdx*11) & 63
@@ -56,24 +47,234 @@ func buildKey128(df *DataFrame, columns []string, row int) key128 {
 	return key128{hi: hi, lo: lo}
 }
 
-// sortAggregateInt64 is the planned sort-based aggregation path for int64
-// value columns. It is currently a stub – functionality will be implemented in
-// a follow-up patch.
-func sortAggregateInt64(df *DataFrame, columns []string, values []int64, aggType AggregationType) (*DataFrame, error) {
-	_ = df
-	_ = columns
-	_ = values
-	_ = aggType
-	return nil, errors.New("sortAggregateInt64: not implemented")
+// hashValue hashes a single element of a column's backing slice.
+func hashValue(data interface{}, row int) uint64 {
+	var hv uint64
+	switch colData := data.(type) {
+	case []int64:
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], uint64(colData[row]))
+		hv = xxhash.Sum64(buf[:])
+	case []float64:
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(colData[row]))
+		hv = xxhash.Sum64(buf[:])
+	case []int8:
+		hv = hashInt(colData[row])
+	case []int16:
+		hv = hashInt(colData[row])
+	case []int32:
+		hv = hashInt(colData[row])
+	case []uint8:
+		hv = hashInt(colData[row])
+	case []uint16:
+		hv = hashInt(colData[row])
+	case []uint32:
+		hv = hashInt(colData[row])
+	case []uint64:
+		hv = hashInt(colData[row])
+	case []float32:
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(float64(colData[row])))
+		hv = xxhash.Sum64(buf[:])
+	case []string:
+		hv = xxhash.Sum64String(colData[row])
+	case *types.Categorical:
+		hv = xxhash.Sum64String(colData.Value(row))
+	case []bool:
+		var buf [8]byte
+		var b uint64
+		if colData[row] {
+			b = 1
+		}
+		binary.LittleEndian.PutUint64(buf[:], b)
+		hv = xxhash.Sum64(buf[:])
+	default:
+		// Unsupported types fall back to zero hash – this still provides
+		// determinism but may lead to collisions for exotic column types.
+		hv = 0
+	}
+	return hv
+}
+
+// hashInt hashes an integer of any width by its value, so equal values of
+// different integer types produce the same hash as the int64 path.
+func hashInt[T types.Integer](v T) uint64 {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(int64(v)))
+	return xxhash.Sum64(buf[:])
+}
+
+// sortStrategy decides whether a grouped aggregation should scan contiguous
+// runs of a sorted key instead of hashing. It applies to a single int64,
+// float64 or string key (narrow types are widened) and returns the row order
+// to scan: nil when the key is already sorted, or a sort permutation when the
+// key is numeric and its estimated cardinality is high enough that filling a
+// hash map would cost more than a radix sort.
+func (gdf *GroupedDataFrame) sortStrategy() (*types.Series, []int, bool) {
+	if len(gdf.columns) != 1 || gdf.df.length == 0 {
+		return nil, nil, false
+	}
+	orig := gdf.df.series[gdf.columns[0]]
+	key, err := orig.Widen()
+	if err != nil {
+		return nil, nil, false
+	}
+	switch key.Data.(type) {
+	case []int64, []float64, []string:
+	default:
+		return nil, nil, false
+	}
+
+	// Declared constraints and statistics computed earlier spare the
+	// sortedness and cardinality scans. They compare floats by value while
+	// runs hold keys of identical bits, as hashing does, so float keys are
+	// checked again: -0 and 0 are in order but distinct.
+	st, cached := orig.CachedStats()
+	declared := gdf.df.constraints[gdf.columns[0]]&(Ascending|Descending) != 0 ||
+		(cached && (st.SortedAscending || st.SortedDescending))
+	_, float := key.Data.([]float64)
+	if (declared && (!float || isSortedKey(key))) || (!declared && !cached && isSortedKey(key)) {
+		return key, nil, true
+	}
+	if _, ok := key.Data.([]string); ok || gdf.df.length < sortGroupMinRows {
+		return nil, nil, false
+	}
+	ndv := 0
+	if cached {
+		ndv = st.NDV
+	} else {
+		ndv = estimateCardinality(key)
+	}
+	if ndv*2 < gdf.df.length {
+		return nil, nil, false
+	}
+	order, err := sortedRows(key, true, sortConfig{})
+	if err != nil {
+		return nil, nil, false
+	}
+	return key, order, true
+}
+
+// sortGroupMinRows is the smallest input for which sorting an unsorted key is
+// considered; below it hashing is always cheap.
+const sortGroupMinRows = 4096
+
+// cardinalitySample is the number of rows inspected by estimateCardinality.
+const cardinalitySample = 1024
+
+// estimateCardinality extrapolates the number of distinct keys from an evenly
+// spaced sample of rows.
+func estimateCardinality(key *types.Series) int {
+	n := key.Length
+	step := n / cardinalitySample
+	if step < 1 {
+		step = 1
+	}
+	seen := make(map[uint64]struct{}, cardinalitySample)
+	sampled := 0
+	for i := 0; i < n; i += step {
+		seen[hashValue(key.Data, i)] = struct{}{}
+		sampled++
+	}
+	return len(seen) * n / sampled
 }
 
-// sortAggregateFloat64 is the planned sort-based aggregation path for float64
-// value columns. It is currently a stub – functionality will be implemented in
-// a follow-up patch.
-func sortAggregateFloat64(df *DataFrame, columns []string, values []float64, aggType AggregationType) (*DataFrame, error) {
-	_ = df
-	_ = columns
-	_ = values
-	_ = aggType
-	return nil, errors.New("sortAggregateFloat64: not implemented")
+// isSortedKey reports whether equal keys are contiguous because the valid
+// values are monotonic (ascending or descending) with nulls only at the end.
+func isSortedKey(key *types.Series) bool {
+	valid := key.Length - key.NullCount()
+	for i := valid; i < key.Length; i++ {
+		if key.IsValid(i) {
+			return false
+		}
+	}
+	switch data := key.Data.(type) {
+	case []int64:
+		return monotonic(data[:valid])
+	case []float64:
+		keys := make([]uint64, valid)
+		for i, v := range data[:valid] {
+			keys[i] = floatSortKey(v)
+		}
+		return monotonic(keys)
+	case []string:
+		return monotonic(data[:valid])
+	default:
+		return false
+	}
+}
+
+func monotonic[T int64 | uint64 | string](data []T) bool {
+	asc, desc := true, true
+	for i := 1; i < len(data) && (asc || desc); i++ {
+		asc = asc && data[i-1] <= data[i]
+		desc = desc && data[i-1] >= data[i]
+	}
+	return asc || desc
+}
+
+// sortAggregateInt64 aggregates int64 values by scanning runs of equal keys
+// in the given row order (nil for the natural order).
+func sortAggregateInt64(gdf *GroupedDataFrame, key *types.Series, order []int, column string, values []int64, valid *types.Bitmap, spec aggSpec) (*DataFrame, error) {
+	return sortAggregate(gdf, key, order, column, values, valid, spec)
+}
+
+// sortAggregateFloat64 aggregates float64 values by scanning runs of equal
+// keys in the given row order (nil for the natural order).
+func sortAggregateFloat64(gdf *GroupedDataFrame, key *types.Series, order []int, column string, values []float64, valid *types.Bitmap, spec aggSpec) (*DataFrame, error) {
+	return sortAggregate(gdf, key, order, column, values, valid, spec)
+}
+
+func sortAggregate[T int64 | float64](gdf *GroupedDataFrame, key *types.Series, order []int, column string, values []T, valid *types.Bitmap, spec aggSpec) (*DataFrame, error) {
+	var same func(a, b int) bool
+	switch data := key.Data.(type) {
+	case []int64:
+		same = func(a, b int) bool { return data[a] == data[b] }
+	case []float64:
+		same = func(a, b int) bool { return math.Float64bits(data[a]) == math.Float64bits(data[b]) }
+	case []string:
+		same = func(a, b int) bool { return data[a] == data[b] }
+	}
+
+	var reps []int
+	var groups []*aggState[T]
+	st := &aggState[T]{}
+	flush := func() {
+		reps = append(reps, st.rep)
+		groups = append(groups, st)
+	}
+
+	prev := -1
+	for p := 0; p < len(values); p++ {
+		row := p
+		if order != nil {
+			row = order[p]
+		}
+		if prev >= 0 {
+			pv, rv := key.IsValid(prev), key.IsValid(row)
+			if pv != rv || (pv && !same(prev, row)) {
+				flush()
+				st = &aggState[T]{}
+			}
+		}
+		if st.count == 0 && st.nulls == 0 {
+			st.rep = row
+		}
+		if valid != nil && !valid.Get(row) {
+			st.addNull(row, spec.kind)
+		} else {
+			st.add(row, values[row], spec.kind)
+		}
+		prev = row
+	}
+	if prev >= 0 {
+		flush()
+	}
+
+	col := gdf.columns[0]
+	return newOrdered(map[string]*types.Series{
+		col:    gdf.df.series[col].Take(reps),
+		column: aggColumn(column, groups, spec),
+	}, gdf.resultOrder(column))
 }
@@ -0,0 +1,75 @@
+package dataframe
+
+import (
+	"testing"
+
+	"go-polars/expr"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupVerifyKeysCollisions(t *testing.T) {
+	// Every key hashes the same, as if all of them collided.
+	defer func(h func(*DataFrame, []string, int) key128) { groupHash = h }(groupHash)
+	groupHash = func(*DataFrame, []string, int) key128 { return key128{} }
+
+	df, err := FromColumns([]*types.Series{
+		types.NewSeries("a", []string{"x", "y", "x", "z"}),
+		types.NewSeries("b", []int64{1, 1, 1, 2}),
+		types.NewSeries("v", []int64{1, 2, 3, 4}),
+	})
+	assert.NoError(t, err)
+
+	for _, verify := range []bool{true, false} {
+		want := 3
+		if !verify {
+			want = 1
+		}
+		gdf, err := df.GroupBy([]string{"a", "b"}, GroupVerifyKeys(verify))
+		assert.NoError(t, err)
+		out, err := gdf.Aggregate("v", Sum)
+		assert.NoError(t, err)
+		assert.Equal(t, want, out.length, "streaming, verify=%v", verify)
+
+		gdf, err = df.GroupBy([]string{"a", "b"}, GroupVerifyKeys(verify))
+		assert.NoError(t, err)
+		groups, err := gdf.Groups()
+		assert.NoError(t, err)
+		assert.Equal(t, want, groups.Len(), "Groups, verify=%v", verify)
+		if verify {
+			assert.Equal(t, []string{"x", "y", "z"}, groups.Keys[0].Data)
+			out, err := gdf.Aggregate("v", Sum)
+			assert.NoError(t, err)
+			assert.Equal(t, []int64{4, 2, 4}, out.series["v"].Data)
+		}
+	}
+
+	// Partitions for windows and PartitionBy always verify.
+	totals, err := df.Eval(expr.Col("v").Sum().Over("a", "b"))
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4, 2, 4, 4}, totals.Data)
+	parts, err := df.PartitionBy([]string{"a"})
+	assert.NoError(t, err)
+	assert.Len(t, parts, 3)
+}
+
+func TestJoinAsofCollisions(t *testing.T) {
+	defer func(h func(*DataFrame, []string, int) key128) { groupHash = h }(groupHash)
+	groupHash = func(*DataFrame, []string, int) key128 { return key128{} }
+
+	left, err := FromColumns([]*types.Series{
+		types.NewSeries("ts", []int64{5, 5}),
+		types.NewSeries("sym", []string{"a", "b"}),
+	})
+	assert.NoError(t, err)
+	right, err := FromColumns([]*types.Series{
+		types.NewSeries("ts", []int64{1, 4, 2}),
+		types.NewSeries("sym", []string{"b", "a", "b"}),
+		types.NewSeries("price", []int64{10, 20, 30}),
+	})
+	assert.NoError(t, err)
+	out, err := left.JoinAsof(right, "ts", []string{"sym"}, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{20, 30}, out.series["price"].Data)
+}
@@ -0,0 +1,228 @@
+package dataframe
+
+import (
+	"fmt"
+	"math/bits"
+
+	"go-polars/types"
+)
+
+// Groups returns the groups of the grouped frame, numbered in order of each
+// key's first appearance, with the rows of every group in their original
+// order. The grouping is computed on the first call and kept: later calls,
+// and the Aggregate, Apply, Partitions and per-group window calls on the
+// same GroupedDataFrame, reuse it instead of hashing the key columns again.
+// Aggregations then emit their groups in this order too. The result is
+// shared and must not be modified.
+func (gdf *GroupedDataFrame) Groups() (*types.Groups, error) {
+	gdf.groupingOnce.Do(func() {
+		ids, n, err := gdf.df.partition(gdf.columns, gdf.verify)
+		if err != nil {
+			gdf.groupingErr = err
+			return
+		}
+		groups, err := types.NewGroups(ids, n)
+		if err != nil {
+			gdf.groupingErr = err
+			return
+		}
+		reps := make([]int, n)
+		for g := range reps {
+			reps[g] = groups.Rows(g)[0]
+		}
+		groups.Keys = make([]*types.Series, len(gdf.columns))
+		for i, col := range gdf.columns {
+			groups.Keys[i] = gdf.df.series[col].Take(reps)
+		}
+		gdf.grouping = groups
+	})
+	gdf.groupingDone.Store(true)
+	return gdf.grouping, gdf.groupingErr
+}
+
+// cachedGroups returns the grouping if Groups has already built it, and nil
+// otherwise, without building it. It is safe to call while another goroutine
+// is inside Groups.
+func (gdf *GroupedDataFrame) cachedGroups() *types.Groups {
+	if !gdf.groupingDone.Load() {
+		return nil
+	}
+	return gdf.grouping
+}
+
+// groupRows returns the rows of every group, see Groups
+func (gdf *GroupedDataFrame) groupRows() ([][]int, error) {
+	groups, err := gdf.Groups()
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]int, groups.Len())
+	for g := range rows {
+		rows[g] = groups.Rows(g)
+	}
+	return rows, nil
+}
+
+// Partitions splits the grouped frame into one DataFrame per group, in the
+// order of Groups, each holding all columns and the group's rows in their
+// original order.
+func (gdf *GroupedDataFrame) Partitions() ([]*DataFrame, error) {
+	groups, err := gdf.Groups()
+	if err != nil {
+		return nil, err
+	}
+	parts := make([]*DataFrame, groups.Len())
+	for g := range parts {
+		if parts[g], err = gdf.df.take(groups.Rows(g)); err != nil {
+			return nil, err
+		}
+	}
+	return parts, nil
+}
+
+// PartitionBy splits the DataFrame into one DataFrame per distinct key of
+// columns, in order of each key's first appearance. Use GroupBy and
+// Partitions to also aggregate over the same grouping.
+func (df *DataFrame) PartitionBy(columns []string) ([]*DataFrame, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("PartitionBy needs at least one column")
+	}
+	gdf, err := df.GroupBy(columns)
+	if err != nil {
+		return nil, err
+	}
+	return gdf.Partitions()
+}
+
+// aggregateGroups folds column over the rows of every group of the cached
+// grouping, emitting the groups in its order. Each group's rows are one run
+// of the permutation, so Sum, Min and Max of a column without nulls reduce
+// them with the indexed reduction kernels.
+func (gdf *GroupedDataFrame) aggregateGroups(column string, series *types.Series, spec aggSpec) (*DataFrame, error) {
+	groups := gdf.grouping
+	switch data := series.Data.(type) {
+	case []int64:
+		if kernel := int64Kernel(spec.kind); kernel != nil && !series.HasNulls() {
+			out := make([]int64, groups.Len())
+			for g := range out {
+				out[g] = kernel(data, groups.Rows(g))
+			}
+			return groupsResult(gdf, column, types.NewSeries(column, out))
+		}
+		return groupsResult(gdf, column, aggColumn(column, foldGroups(groups, data, series.Validity, spec.kind), spec))
+	case []float64:
+		if kernel := float64Kernel(spec.kind); kernel != nil && !series.HasNulls() {
+			out := make([]float64, groups.Len())
+			for g := range out {
+				out[g] = kernel(data, groups.Rows(g))
+			}
+			return groupsResult(gdf, column, types.NewSeries(column, out))
+		}
+		return groupsResult(gdf, column, aggColumn(column, foldGroups(groups, data, series.Validity, spec.kind), spec))
+	default:
+		return nil, fmt.Errorf("unsupported data type for aggregation")
+	}
+}
+
+func int64Kernel(aggType AggregationType) func([]int64, []int) int64 {
+	switch aggType {
+	case Sum:
+		return sumInt64Indexed
+	case Min:
+		return minInt64Indexed
+	case Max:
+		return maxInt64Indexed
+	default:
+		return nil
+	}
+}
+
+func float64Kernel(aggType AggregationType) func([]float64, []int) float64 {
+	switch aggType {
+	case Sum:
+		return sumFloat64Indexed
+	case Min:
+		return minFloat64Indexed
+	case Max:
+		return maxFloat64Indexed
+	default:
+		return nil
+	}
+}
+
+func foldGroups[T int64 | float64](groups *types.Groups, data []T, valid *types.Bitmap, aggType AggregationType) []*aggState[T] {
+	states := make([]*aggState[T], groups.Len())
+	for g := range states {
+		st := &aggState[T]{}
+		for _, i := range groups.Rows(g) {
+			if valid != nil && !valid.Get(i) {
+				st.addNull(i, aggType)
+				continue
+			}
+			st.add(i, data[i], aggType)
+		}
+		states[g] = st
+	}
+	return states
+}
+
+// aggregateUint64 computes Sum, Min and Max of a UInt64 column over the
+// grouping with uint64 accumulators, which aggInput would widen to Int64 and
+// so reject values above math.MaxInt64. A Sum beyond math.MaxUint64 is an
+// error rather than wrapping.
+func (gdf *GroupedDataFrame) aggregateUint64(column string, series *types.Series, data []uint64, aggType AggregationType) (*DataFrame, error) {
+	groups, err := gdf.Groups()
+	if err != nil {
+		return nil, err
+	}
+	n := groups.Len()
+	// Without group columns the whole frame is one group, even when empty.
+	if len(gdf.columns) == 0 {
+		n = 1
+	}
+	logDecision("aggregate", "path", "indexed", "column", column, "groups", n)
+
+	out := make([]uint64, n)
+	valid := make([]bool, n)
+	for g := range out {
+		var rows []int
+		if g < groups.Len() {
+			rows = groups.Rows(g)
+		}
+		var acc uint64
+		count := 0
+		for _, i := range rows {
+			if !series.IsValid(i) {
+				continue
+			}
+			v := data[i]
+			switch aggType {
+			case Sum:
+				var carry uint64
+				if acc, carry = bits.Add64(acc, v, 0); carry != 0 {
+					return nil, fmt.Errorf("sum of column %s overflows UInt64", column)
+				}
+			case Min:
+				if count == 0 || v < acc {
+					acc = v
+				}
+			case Max:
+				if count == 0 || v > acc {
+					acc = v
+				}
+			}
+			count++
+		}
+		out[g], valid[g] = acc, aggType == Sum || count > 0
+	}
+	return groupsResult(gdf, column, types.NewNullableSeries(column, out, valid))
+}
+
+func groupsResult(gdf *GroupedDataFrame, column string, agg *types.Series) (*DataFrame, error) {
+	resultSeries := make(map[string]*types.Series, len(gdf.columns)+1)
+	for i, col := range gdf.columns {
+		resultSeries[col] = gdf.grouping.Keys[i]
+	}
+	resultSeries[column] = agg
+	return newOrdered(resultSeries, gdf.resultOrder(column))
+}
@@ -0,0 +1,67 @@
+package dataframe
+
+import (
+	"fmt"
+	"sync"
+)
+
+// history holds the labelled versions committed from a chain of frames.
+// Frames never modify their columns in place, so a version is just a
+// reference to the frame: committing copies nothing.
+type history struct {
+	mu       sync.Mutex
+	versions map[string]*DataFrame
+	labels   []string
+}
+
+// Commit records df under label in the history shared by df and the frames
+// derived from it, and returns df with that history attached. Committing an
+// existing label replaces it.
+func (df *DataFrame) Commit(label string) *DataFrame {
+	h := df.history
+	if h == nil {
+		h = &history{versions: make(map[string]*DataFrame)}
+	}
+	out := *df
+	out.history = h
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.versions[label]; ok {
+		for i, l := range h.labels {
+			if l == label {
+				h.labels = append(h.labels[:i], h.labels[i+1:]...)
+				break
+			}
+		}
+	}
+	h.versions[label] = &out
+	h.labels = append(h.labels, label)
+	return &out
+}
+
+// At returns the version committed under label from df's history
+func (df *DataFrame) At(label string) (*DataFrame, error) {
+	h := df.history
+	if h == nil {
+		return nil, fmt.Errorf("no version %s: nothing committed", label)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	v, ok := h.versions[label]
+	if !ok {
+		return nil, fmt.Errorf("no version %s", label)
+	}
+	return v, nil
+}
+
+// Versions returns the committed labels, oldest first
+func (df *DataFrame) Versions() []string {
+	h := df.history
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.labels...)
+}
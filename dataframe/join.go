@@ -0,0 +1,314 @@
+package dataframe
+
+import (
+	"fmt"
+
+	"go-polars/types"
+)
+
+type joinConfig struct {
+	nullsEqual bool
+}
+
+// JoinOption configures joins and key uniqueness checks
+type JoinOption func(*joinConfig)
+
+// JoinNullsEqual controls whether null keys compare equal to each other. By
+// default nulls follow SQL semantics and never match, so a null key neither
+// joins with another null nor counts as a duplicate of one.
+func JoinNullsEqual(equal bool) JoinOption {
+	return func(c *joinConfig) { c.nullsEqual = equal }
+}
+
+func newJoinConfig(opts []JoinOption) joinConfig {
+	var cfg joinConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// hasNullKey reports whether any key column is null at row.
+func (df *DataFrame) hasNullKey(columns []string, row int) bool {
+	for _, col := range columns {
+		if !df.series[col].IsValid(row) {
+			return true
+		}
+	}
+	return false
+}
+
+// JoinType selects which unmatched rows a join keeps
+type JoinType int
+
+const (
+	// JoinInner keeps only rows whose key appears on both sides.
+	JoinInner JoinType = iota
+	// JoinLeft keeps every left row, with nulls where no right row matches.
+	JoinLeft
+	// JoinRight keeps every right row, with nulls where no left row matches.
+	JoinRight
+	// JoinOuter keeps every row of both sides.
+	JoinOuter
+)
+
+func (j JoinType) String() string {
+	switch j {
+	case JoinInner:
+		return "inner"
+	case JoinLeft:
+		return "left"
+	case JoinRight:
+		return "right"
+	case JoinOuter:
+		return "outer"
+	default:
+		return fmt.Sprintf("JoinType(%d)", int(j))
+	}
+}
+
+// JoinSuffix is appended to right column names that clash with left columns
+const JoinSuffix = "_right"
+
+// Join combines df with other by matching the leftOn key columns against the
+// rightOn key columns with a hash join. The output holds the left columns,
+// whose key columns are filled from the right side for right-only rows, then
+// the non-key right columns (suffixed with JoinSuffix on a name clash). Rows
+// follow the left order; right-only rows of an outer join come last, and a
+// right join follows the right order. Null keys never match unless
+// JoinNullsEqual(true) is given.
+func (df *DataFrame) Join(other *DataFrame, leftOn, rightOn []string, how JoinType, opts ...JoinOption) (out *DataFrame, err error) {
+	defer df.track("Join", map[string]interface{}{"left_on": leftOn, "right_on": rightOn, "how": how.String()})(&out, &err)
+	cfg := newJoinConfig(opts)
+	if how < JoinInner || how > JoinOuter {
+		return nil, fmt.Errorf("unknown join type %d", how)
+	}
+	if len(leftOn) == 0 || len(leftOn) != len(rightOn) {
+		return nil, fmt.Errorf("join needs the same non-zero number of left and right keys, got %d and %d", len(leftOn), len(rightOn))
+	}
+	leftMatches, rightMatched, err := matchRows(df, other, leftOn, rightOn, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var li, ri []int
+	if how == JoinRight {
+		// Regroup the matches by right row to follow the right order.
+		rightMatches := make([][]int, other.length)
+		for i, js := range leftMatches {
+			for _, j := range js {
+				rightMatches[j] = append(rightMatches[j], i)
+			}
+		}
+		for j, is := range rightMatches {
+			if len(is) == 0 {
+				li, ri = append(li, -1), append(ri, j)
+			}
+			for _, i := range is {
+				li, ri = append(li, i), append(ri, j)
+			}
+		}
+	} else {
+		keepLeft := how == JoinLeft || how == JoinOuter
+		for i, js := range leftMatches {
+			if len(js) == 0 && keepLeft {
+				li, ri = append(li, i), append(ri, -1)
+			}
+			for _, j := range js {
+				li, ri = append(li, i), append(ri, j)
+			}
+		}
+		if how == JoinOuter {
+			for j, matched := range rightMatched {
+				if !matched {
+					li, ri = append(li, -1), append(ri, j)
+				}
+			}
+		}
+	}
+
+	if out, err = joinResult(df, other, leftOn, rightOn, li, ri); err != nil {
+		return nil, err
+	}
+	if (how == JoinInner || how == JoinLeft) && len(df.constraints) > 0 && other.uniqueKey(rightOn, cfg.nullsEqual) {
+		// No left row matched twice, so the left columns hold left rows
+		// in order and keep their constraints.
+		out.constraints = make(map[string]Constraint, len(df.constraints))
+		for name, c := range df.constraints {
+			out.constraints[name] = c
+		}
+	}
+	return out, nil
+}
+
+// matchRows finds, for every row of left, the rows of right holding the same
+// key, and marks the right rows that matched at least once.
+func matchRows(left, right *DataFrame, leftOn, rightOn []string, cfg joinConfig) ([][]int, []bool, error) {
+	equal := make([]func(i, j int) bool, len(leftOn))
+	for k := range leftOn {
+		ls, ok := left.series[leftOn[k]]
+		if !ok {
+			return nil, nil, fmt.Errorf("column %s not found", leftOn[k])
+		}
+		rs, ok := right.series[rightOn[k]]
+		if !ok {
+			return nil, nil, fmt.Errorf("column %s not found in right frame", rightOn[k])
+		}
+		eq, err := keyEqual(ls, rs)
+		if err != nil {
+			return nil, nil, err
+		}
+		equal[k] = eq
+	}
+
+	// matches reports whether left row i and right row j hold the same key;
+	// it guards against hash collisions.
+	matches := func(i, j int) bool {
+		for k, eq := range equal {
+			lv := left.series[leftOn[k]].IsValid(i)
+			rv := right.series[rightOn[k]].IsValid(j)
+			if lv != rv || (lv && !eq(i, j)) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Build on the right side, probe with the left.
+	table := make(map[key128][]int, right.length)
+	for j := 0; j < right.length; j++ {
+		if !cfg.nullsEqual && right.hasNullKey(rightOn, j) {
+			continue
+		}
+		k := joinKey128(right, rightOn, j)
+		table[k] = append(table[k], j)
+	}
+
+	// A unique right key matches every left row at most once.
+	unique := right.uniqueKey(rightOn, cfg.nullsEqual)
+	rightMatched := make([]bool, right.length)
+	leftMatches := make([][]int, left.length)
+	for i := 0; i < left.length; i++ {
+		if !cfg.nullsEqual && left.hasNullKey(leftOn, i) {
+			continue
+		}
+		for _, j := range table[joinKey128(left, leftOn, i)] {
+			if matches(i, j) {
+				leftMatches[i] = append(leftMatches[i], j)
+				rightMatched[j] = true
+				if unique {
+					break
+				}
+			}
+		}
+	}
+
+	return leftMatches, rightMatched, nil
+}
+
+// joinResult gathers the output columns for the matched row pairs; -1 marks
+// a missing side.
+func joinResult(left, right *DataFrame, leftOn, rightOn []string, li, ri []int) (*DataFrame, error) {
+	result := make(map[string]*types.Series, len(left.series)+len(right.series))
+	rightKey := make(map[string]string, len(rightOn))
+	for k, name := range leftOn {
+		rightKey[name] = rightOn[k]
+	}
+
+	// Key columns coalesce: the left value if present, else the right one.
+	coalesce := make([]int, len(li))
+	for p := range li {
+		if li[p] >= 0 {
+			coalesce[p] = li[p]
+		} else {
+			coalesce[p] = left.length + ri[p]
+		}
+	}
+
+	names, fromRight, err := joinSchema(left.order, right.order, rightOn)
+	if err != nil {
+		return nil, err
+	}
+	for name, s := range left.series {
+		rk, isKey := rightKey[name]
+		if !isKey {
+			result[name] = s.Take(li)
+			continue
+		}
+		both, err := concatSeries([]*types.Series{s, right.series[rk]})
+		if err != nil {
+			return nil, err
+		}
+		result[name] = both.Take(coalesce)
+	}
+	for _, out := range names[len(left.order):] {
+		taken := right.series[fromRight[out]].Take(ri)
+		taken.Name = out
+		result[out] = taken
+	}
+	return newOrdered(result, names)
+}
+
+// zeroHash is the hash of a float zero of either sign.
+var zeroHash = hashValue([]float64{0}, 0)
+
+// joinKey128 is buildKey128 for join keys. Group keys match by bits, but join
+// keys match with ==, so -0 must hash like +0.
+func joinKey128(df *DataFrame, columns []string, row int) key128 {
+	return mixKey128(df, columns, row, func(data interface{}, row int) uint64 {
+		switch d := data.(type) {
+		case []float64:
+			if d[row] == 0 {
+				return zeroHash
+			}
+		case []float32:
+			if d[row] == 0 {
+				return zeroHash
+			}
+		}
+		return hashValue(data, row)
+	})
+}
+
+// keyEqual returns an equality test between row i of l and row j of r. Both
+// key columns must have the same type.
+func keyEqual(l, r *types.Series) (func(i, j int) bool, error) {
+	if l.DataType.String() != r.DataType.String() {
+		return nil, fmt.Errorf("cannot join %s column %s with %s column %s", l.DataType, l.Name, r.DataType, r.Name)
+	}
+	switch ld := l.Data.(type) {
+	case []int64:
+		return equalAt(ld, r.Data.([]int64)), nil
+	case []int32:
+		return equalAt(ld, r.Data.([]int32)), nil
+	case []int16:
+		return equalAt(ld, r.Data.([]int16)), nil
+	case []int8:
+		return equalAt(ld, r.Data.([]int8)), nil
+	case []uint64:
+		return equalAt(ld, r.Data.([]uint64)), nil
+	case []uint32:
+		return equalAt(ld, r.Data.([]uint32)), nil
+	case []uint16:
+		return equalAt(ld, r.Data.([]uint16)), nil
+	case []uint8:
+		return equalAt(ld, r.Data.([]uint8)), nil
+	case []float64:
+		return equalAt(ld, r.Data.([]float64)), nil
+	case []float32:
+		return equalAt(ld, r.Data.([]float32)), nil
+	case []string:
+		return equalAt(ld, r.Data.([]string)), nil
+	case []bool:
+		return equalAt(ld, r.Data.([]bool)), nil
+	case *types.Categorical:
+		rd := r.Data.(*types.Categorical)
+		return func(i, j int) bool { return ld.Value(i) == rd.Value(j) }, nil
+	default:
+		return nil, fmt.Errorf("unsupported join key type %s for column %s", l.DataType, l.Name)
+	}
+}
+
+func equalAt[T comparable](l, r []T) func(i, j int) bool {
+	return func(i, j int) bool { return l[i] == r[j] }
+}
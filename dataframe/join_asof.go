@@ -0,0 +1,132 @@
+package dataframe
+
+import (
+	"fmt"
+	"sort"
+)
+
+// JoinAsof matches every left row with the most recent right row: the one
+// with the largest on value not greater than the left row's, taken from the
+// right rows sharing the left row's by keys. A match further back than
+// tolerance (in the units of on) is rejected; a negative tolerance disables
+// the check. Neither frame needs to be sorted. The output keeps the left rows
+// in order followed by the non-key right columns, null where nothing matched.
+func (df *DataFrame) JoinAsof(other *DataFrame, on string, by []string, tolerance int64, opts ...JoinOption) (out *DataFrame, err error) {
+	defer df.track("JoinAsof", map[string]interface{}{"on": on, "by": by, "tolerance": tolerance})(&out, &err)
+	cfg := newJoinConfig(opts)
+	lt, ok := df.series[on]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", on)
+	}
+	rt, ok := other.series[on]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found in right frame", on)
+	}
+	// The on columns coalesce into one output column, so their types must
+	// match before widening does.
+	if lt.DataType.String() != rt.DataType.String() {
+		return nil, fmt.Errorf("cannot asof join %s column %s with %s", lt.DataType, on, rt.DataType)
+	}
+	if lt, err = lt.Widen(); err != nil {
+		return nil, err
+	}
+	if rt, err = rt.Widen(); err != nil {
+		return nil, err
+	}
+
+	// less orders right rows; before reports whether right row r is at or
+	// before left row l; within checks the tolerance between them.
+	var less func(a, b int) bool
+	var before, within func(r, l int) bool
+	switch ld := lt.Data.(type) {
+	case []int64:
+		rd := rt.Data.([]int64)
+		less = func(a, b int) bool { return rd[a] < rd[b] }
+		before = func(r, l int) bool { return rd[r] <= ld[l] }
+		within = func(r, l int) bool { return ld[l]-rd[r] <= tolerance }
+	case []float64:
+		rd := rt.Data.([]float64)
+		less = func(a, b int) bool { return rd[a] < rd[b] }
+		before = func(r, l int) bool { return rd[r] <= ld[l] }
+		within = func(r, l int) bool { return ld[l]-rd[r] <= float64(tolerance) }
+	default:
+		return nil, fmt.Errorf("unsupported data type for asof column %s", on)
+	}
+
+	equal := make([]func(i, j int) bool, len(by))
+	for k, col := range by {
+		ls, ok := df.series[col]
+		if !ok {
+			return nil, fmt.Errorf("column %s not found", col)
+		}
+		rs, ok := other.series[col]
+		if !ok {
+			return nil, fmt.Errorf("column %s not found in right frame", col)
+		}
+		if equal[k], err = keyEqual(ls, rs); err != nil {
+			return nil, err
+		}
+	}
+
+	// Right rows per by key, ordered by on. Keys whose hashes collide keep
+	// groups of their own, told apart by the group's first row.
+	ids, n, err := other.partition(by, true)
+	if err != nil {
+		return nil, err
+	}
+	groups := partitionRows(ids, n)
+	first := make([]int, n)
+	buckets := make(map[key128][]int)
+	for g, rows := range groups {
+		first[g] = rows[0]
+		k := groupHash(other, by, rows[0])
+		buckets[k] = append(buckets[k], g)
+		kept := rows[:0]
+		for _, j := range rows {
+			if rt.IsValid(j) && (cfg.nullsEqual || !other.hasNullKey(by, j)) {
+				kept = append(kept, j)
+			}
+		}
+		sort.SliceStable(kept, func(a, b int) bool { return less(kept[a], kept[b]) })
+		groups[g] = kept
+	}
+
+	li := make([]int, df.length)
+	ri := make([]int, df.length)
+	for i := range li {
+		li[i], ri[i] = i, -1
+		if !lt.IsValid(i) || (!cfg.nullsEqual && df.hasNullKey(by, i)) {
+			continue
+		}
+		var rows []int
+		for _, g := range buckets[groupHash(df, by, i)] {
+			if sameByKey(df, other, by, equal, i, first[g]) {
+				rows = groups[g]
+				break
+			}
+		}
+		// Last right row whose on value does not exceed the left one.
+		p := sort.Search(len(rows), func(p int) bool { return !before(rows[p], i) }) - 1
+		if p < 0 {
+			continue
+		}
+		if tolerance >= 0 && !within(rows[p], i) {
+			continue
+		}
+		ri[i] = rows[p]
+	}
+
+	keys := append(append([]string(nil), by...), on)
+	return joinResult(df, other, keys, keys, li, ri)
+}
+
+// sameByKey verifies that left row i and right row j share their by keys.
+func sameByKey(left, right *DataFrame, by []string, equal []func(i, j int) bool, i, j int) bool {
+	for k, col := range by {
+		lv, rv := left.series[col].IsValid(i), right.series[col].IsValid(j)
+		if lv != rv || (lv && !equal[k](i, j)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,356 @@
+package dataframe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"go-polars/types"
+)
+
+// JSONOptions controls how ReadJSON and ReadNDJSON build a DataFrame
+type JSONOptions struct {
+	// Flatten expands one level of nested objects into "parent.child"
+	// columns. Deeper values are kept as their JSON text.
+	Flatten bool
+	// Normalize cleans up the column names built from object keys.
+	Normalize NormalizeOptions
+	ReadLimits
+}
+
+// ReadJSON builds a DataFrame from a JSON array of objects. Arrays of
+// scalars become list columns; arrays holding nulls or nested values are kept
+// as JSON text.
+//
+// Records are decoded one at a time; a malformed record fails with a
+// *ParseError carrying its index and byte offset.
+func ReadJSON(r io.Reader, opts JSONOptions) (*DataFrame, error) {
+	dec := json.NewDecoder(opts.ReadLimits.wrap(r))
+	dec.UseNumber()
+
+	if tok, err := dec.Token(); err != nil {
+		return nil, newParseError(0, 0, dec.InputOffset(), "", "", fmt.Errorf("decode json: %w", err))
+	} else if tok != json.Delim('[') {
+		return nil, newParseError(0, 0, 0, "", fmt.Sprint(tok), fmt.Errorf("decode json: expected an array of objects"))
+	}
+
+	var records []map[string]interface{}
+	for dec.More() {
+		if opts.ReadLimits.rowsExceeded(len(records) + 1) {
+			if opts.OnLimit == LimitTruncate {
+				return fromJSONRecords(records, opts)
+			}
+			return nil, fmt.Errorf("json: %w: more than %d rows", ErrLimitExceeded, opts.MaxRows)
+		}
+		offset := dec.InputOffset()
+		var rec map[string]interface{}
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, ErrLimitExceeded) {
+				return jsonLimitHit(records, opts)
+			}
+			return nil, jsonParseError(len(records), 0, offset, 0, err)
+		}
+		records = append(records, rec)
+	}
+	if _, err := dec.Token(); errors.Is(err, ErrLimitExceeded) {
+		return jsonLimitHit(records, opts)
+	} else if err != nil {
+		return nil, newParseError(len(records), 0, dec.InputOffset(), "", "", fmt.Errorf("decode json: %w", err))
+	}
+	return fromJSONRecords(records, opts)
+}
+
+// jsonLimitHit applies the limit policy once the byte limit interrupted
+// decoding.
+func jsonLimitHit(records []map[string]interface{}, opts JSONOptions) (*DataFrame, error) {
+	if opts.OnLimit == LimitTruncate {
+		return fromJSONRecords(records, opts)
+	}
+	return nil, fmt.Errorf("json: %w: more than %d bytes", ErrLimitExceeded, opts.MaxBytes)
+}
+
+// jsonParseError locates a decoding failure of the record starting at offset.
+// Syntax errors pinpoint the failing byte instead; their offset is relative
+// to base, where the decoder's input started.
+func jsonParseError(row, line int, offset, base int64, err error) *ParseError {
+	var value string
+	var syntax *json.SyntaxError
+	var typ *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntax):
+		offset = base + syntax.Offset
+	case errors.As(err, &typ):
+		value = typ.Value
+	}
+	return newParseError(row, line, offset, "", value, fmt.Errorf("decode json: %w", err))
+}
+
+// ReadNDJSON builds a DataFrame from newline-delimited JSON objects. A
+// malformed line fails with a *ParseError carrying its record index, line
+// number, byte offset and text.
+func ReadNDJSON(r io.Reader, opts JSONOptions) (*DataFrame, error) {
+	br := bufio.NewReaderSize(opts.ReadLimits.wrap(r), 64*1024)
+
+	var records []map[string]interface{}
+	var offset int64
+	line := 0
+	for {
+		raw, readErr := br.ReadBytes('\n')
+		if errors.Is(readErr, ErrLimitExceeded) {
+			return jsonLimitHit(records, opts)
+		}
+		if readErr != nil && readErr != io.EOF {
+			return nil, newParseError(len(records), line+1, offset, "", "", readErr)
+		}
+		start := offset
+		offset += int64(len(raw))
+		line++
+
+		trimmed := bytes.TrimSpace(raw)
+		if len(trimmed) > 0 && opts.ReadLimits.rowsExceeded(len(records)+1) {
+			if opts.OnLimit == LimitTruncate {
+				break
+			}
+			return nil, fmt.Errorf("ndjson: %w: more than %d rows", ErrLimitExceeded, opts.MaxRows)
+		}
+		if len(trimmed) > 0 {
+			dec := json.NewDecoder(bytes.NewReader(trimmed))
+			dec.UseNumber()
+			var rec map[string]interface{}
+			if err := dec.Decode(&rec); err != nil {
+				lead := int64(len(raw) - len(bytes.TrimLeft(raw, " \t\r\n")))
+				pe := jsonParseError(len(records), line, start+lead, start+lead, err)
+				pe.Value = truncateValue(string(trimmed))
+				return nil, pe
+			}
+			records = append(records, rec)
+		}
+		if readErr == io.EOF {
+			break
+		}
+	}
+	return fromJSONRecords(records, opts)
+}
+
+// jsonKind tracks the narrowest column type that can hold every value seen.
+type jsonKind int
+
+const (
+	jsonUnknown jsonKind = iota
+	jsonBool
+	jsonInt
+	jsonFloat
+	jsonString
+	jsonList
+)
+
+func kindOf(v interface{}) jsonKind {
+	switch val := v.(type) {
+	case bool:
+		return jsonBool
+	case json.Number:
+		if _, err := strconv.ParseInt(string(val), 10, 64); err == nil {
+			return jsonInt
+		}
+		return jsonFloat
+	case []interface{}:
+		return jsonList
+	default:
+		return jsonString
+	}
+}
+
+func widenKind(a, b jsonKind) jsonKind {
+	switch {
+	case a == jsonUnknown:
+		return b
+	case b == jsonUnknown || a == b:
+		return a
+	case (a == jsonInt && b == jsonFloat) || (a == jsonFloat && b == jsonInt):
+		return jsonFloat
+	default:
+		return jsonString
+	}
+}
+
+// elemKind widens kind over the elements of a JSON array. It reports false
+// when an element is null or nested, which a list column cannot hold.
+func elemKind(kind jsonKind, arr []interface{}) (jsonKind, bool) {
+	for _, v := range arr {
+		switch v.(type) {
+		case nil, []interface{}, map[string]interface{}:
+			return kind, false
+		}
+		kind = widenKind(kind, kindOf(v))
+	}
+	return kind, true
+}
+
+// jsonLists converts the array held by each valid row into a typed list.
+func jsonLists[T any](rows []map[string]interface{}, name string, valid []bool, conv func(interface{}) T) [][]T {
+	lists := make([][]T, len(rows))
+	for i, row := range rows {
+		if !valid[i] {
+			continue
+		}
+		arr := row[name].([]interface{})
+		l := make([]T, len(arr))
+		for j, v := range arr {
+			l[j] = conv(v)
+		}
+		lists[i] = l
+	}
+	return lists
+}
+
+// flattenJSON copies rec into a flat column->value map, expanding one level of
+// nested objects when requested. It fails when a flattened name is also a key
+// of rec, as in {"a.b": 1, "a": {"b": 2}}, rather than keep either value.
+func flattenJSON(rec map[string]interface{}, flatten bool) (map[string]interface{}, error) {
+	if !flatten {
+		return rec, nil
+	}
+	out := make(map[string]interface{}, len(rec))
+	set := func(k string, v interface{}) error {
+		if _, ok := out[k]; ok {
+			return fmt.Errorf("flatten json: more than one key flattens to %s", k)
+		}
+		out[k] = v
+		return nil
+	}
+	for k, v := range rec {
+		if nested, ok := v.(map[string]interface{}); ok {
+			for nk, nv := range nested {
+				if err := set(k+"."+nk, nv); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if err := set(k, v); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func jsonText(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		b, _ := json.Marshal(val)
+		return string(b)
+	}
+}
+
+// fromJSONRecords infers one Series per key. Keys missing from a record, or
+// holding JSON null, become nulls.
+func fromJSONRecords(records []map[string]interface{}, opts JSONOptions) (*DataFrame, error) {
+	rows := make([]map[string]interface{}, len(records))
+	kinds := make(map[string]jsonKind)
+	elems := make(map[string]jsonKind)
+	for i, rec := range records {
+		row, err := flattenJSON(rec, opts.Flatten)
+		if err != nil {
+			return nil, newParseError(i, 0, -1, "", "", err)
+		}
+		rows[i] = row
+		for k, v := range row {
+			if v == nil {
+				if _, ok := kinds[k]; !ok {
+					kinds[k] = jsonUnknown
+				}
+				continue
+			}
+			if arr, ok := v.([]interface{}); ok {
+				ek, ok := elemKind(elems[k], arr)
+				if !ok {
+					kinds[k] = jsonString
+					continue
+				}
+				elems[k] = ek
+			}
+			kinds[k] = widenKind(kinds[k], kindOf(v))
+		}
+	}
+
+	series := make(map[string]*types.Series, len(kinds))
+	for name, kind := range kinds {
+		valid := make([]bool, len(rows))
+		for i, row := range rows {
+			valid[i] = row[name] != nil
+		}
+
+		switch kind {
+		case jsonBool:
+			data := make([]bool, len(rows))
+			for i, row := range rows {
+				if v, ok := row[name].(bool); ok {
+					data[i] = v
+				}
+			}
+			series[name] = types.NewNullableSeries(name, data, valid)
+		case jsonInt:
+			data := make([]int64, len(rows))
+			for i, row := range rows {
+				if v, ok := row[name].(json.Number); ok {
+					data[i], _ = v.Int64()
+				}
+			}
+			series[name] = types.NewNullableSeries(name, data, valid)
+		case jsonFloat:
+			data := make([]float64, len(rows))
+			for i, row := range rows {
+				if v, ok := row[name].(json.Number); ok {
+					data[i], _ = v.Float64()
+				}
+			}
+			series[name] = types.NewNullableSeries(name, data, valid)
+		case jsonList:
+			switch elems[name] {
+			case jsonBool:
+				data := jsonLists(rows, name, valid, func(v interface{}) bool { return v.(bool) })
+				series[name] = types.NewNullableSeries(name, data, valid)
+			case jsonInt:
+				data := jsonLists(rows, name, valid, func(v interface{}) int64 {
+					n, _ := v.(json.Number).Int64()
+					return n
+				})
+				series[name] = types.NewNullableSeries(name, data, valid)
+			case jsonFloat:
+				data := jsonLists(rows, name, valid, func(v interface{}) float64 {
+					f, _ := v.(json.Number).Float64()
+					return f
+				})
+				series[name] = types.NewNullableSeries(name, data, valid)
+			default:
+				data := jsonLists(rows, name, valid, jsonText)
+				series[name] = types.NewNullableSeries(name, data, valid)
+			}
+		default:
+			data := make([]string, len(rows))
+			for i, row := range rows {
+				if valid[i] {
+					data[i] = jsonText(row[name])
+				}
+			}
+			series[name] = types.NewNullableSeries(name, data, valid)
+		}
+	}
+
+	df, err := New(series)
+	if err != nil || !opts.Normalize.enabled() {
+		return df, err
+	}
+	df, _, err = df.NormalizeColumns(opts.Normalize)
+	return df, err
+}
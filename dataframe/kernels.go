@@ -0,0 +1,91 @@
+package dataframe
+
+import (
+	"runtime"
+	"sync/atomic"
+
+	"golang.org/x/sys/cpu"
+)
+
+// fastKernels selects the unrolled reductions and the parallel radix sort
+// over their plain scalar counterparts. It is decided once from the CPU the
+// process runs on, so a single binary takes the fast paths where they pay
+// off, and can be overridden with ForceScalarKernels.
+var fastKernels atomic.Bool
+
+func init() {
+	fastKernels.Store(detectFastKernels())
+}
+
+// detectFastKernels reports whether the CPU has wide vector units (AVX2 on
+// amd64, ASIMD on arm64), taken as a sign of cores that keep several
+// independent accumulators busy at once.
+func detectFastKernels() bool {
+	switch runtime.GOARCH {
+	case "amd64":
+		return cpu.X86.HasAVX2
+	case "arm64":
+		return cpu.ARM64.HasASIMD
+	default:
+		return false
+	}
+}
+
+// ForceScalarKernels makes every later operation use the scalar kernels
+// when force is set, whatever the CPU supports, and restores the detected
+// choice otherwise. Results are the same either way, except for the last
+// bits of floating point sums, which the unrolled kernels add in a
+// different order. It is meant for debugging and benchmarking and is safe
+// to call at any time.
+func ForceScalarKernels(force bool) {
+	fastKernels.Store(!force && detectFastKernels())
+}
+
+// ScalarKernels reports whether the scalar kernels are in use, because the
+// CPU lacks the features of the fast ones or ForceScalarKernels asked for
+// them.
+func ScalarKernels() bool {
+	return !fastKernels.Load()
+}
+
+func sumInt64Indexed(data []int64, idx []int) int64 {
+	if fastKernels.Load() {
+		return sumInt64IndexedUnrolled(data, idx)
+	}
+	return sumInt64IndexedScalar(data, idx)
+}
+
+func minInt64Indexed(data []int64, idx []int) int64 {
+	if fastKernels.Load() {
+		return minInt64IndexedUnrolled(data, idx)
+	}
+	return minInt64IndexedScalar(data, idx)
+}
+
+func maxInt64Indexed(data []int64, idx []int) int64 {
+	if fastKernels.Load() {
+		return maxInt64IndexedUnrolled(data, idx)
+	}
+	return maxInt64IndexedScalar(data, idx)
+}
+
+func sumFloat64Indexed(data []float64, idx []int) float64 {
+	if fastKernels.Load() {
+		return sumFloat64IndexedUnrolled(data, idx)
+	}
+	return sumFloat64IndexedScalar(data, idx)
+}
+
+func minFloat64Indexed(data []float64, idx []int) float64 {
+	if fastKernels.Load() {
+		return minFloat64IndexedUnrolled(data, idx)
+	}
+	return minFloat64IndexedScalar(data, idx)
+}
+
+func maxFloat64Indexed(data []float64, idx []int) float64 {
+	if fastKernels.Load() {
+		return maxFloat64IndexedUnrolled(data, idx)
+	}
+	return maxFloat64IndexedScalar(data, idx)
+}
@@ -0,0 +1,579 @@
+package dataframe
+
+import (
+	"fmt"
+	"strings"
+
+	"go-polars/expr"
+)
+
+// LazyFrame is a query over one or more DataFrames or files (see ScanCSV
+// and ScanParquet) that is only planned as it is built. Collect optimizes
+// the plan and executes it: filters are fused and pushed towards the scans,
+// and columns that no later step reads are dropped at the scan. Building a
+// LazyFrame never fails; invalid column references are reported by Collect.
+type LazyFrame struct {
+	plan *plan
+}
+
+type planKind int
+
+const (
+	planScan planKind = iota
+	planSelect
+	planFilter
+	planWithColumn
+	planAggregate
+	planJoin
+	planSort
+)
+
+// plan is a node of a logical plan. Nodes are never modified once built,
+// so LazyFrames derived from the same frame can share them; the optimizer
+// copies the nodes it changes.
+type plan struct {
+	kind  planKind
+	input *plan
+	// right is the right input of planJoin.
+	right *plan
+	// df is the frame read by planScan, unless it reads the file src.
+	df  *DataFrame
+	src source
+	// columns is the projection of planScan (nil for all columns), the
+	// selection of planSelect, the group columns of planAggregate or the
+	// left keys of planJoin.
+	columns []string
+	// rightOn holds the right keys of planJoin.
+	rightOn []string
+	how     JoinType
+	opts    []JoinOption
+	// name is the column set by planWithColumn, aggregated by
+	// planAggregate or sorted by planSort.
+	name string
+	// expr is the predicate of planFilter or the value of planWithColumn.
+	expr      expr.Expr
+	agg       AggregationType
+	ascending bool
+}
+
+// Lazy starts a lazy query reading df
+func (df *DataFrame) Lazy() *LazyFrame {
+	return &LazyFrame{plan: &plan{kind: planScan, df: df}}
+}
+
+func (lf *LazyFrame) then(p plan) *LazyFrame {
+	p.input = lf.plan
+	return &LazyFrame{plan: &p}
+}
+
+// Select keeps only the given columns, in the given order
+func (lf *LazyFrame) Select(columns ...string) *LazyFrame {
+	return lf.then(plan{kind: planSelect, columns: columns})
+}
+
+// Filter keeps the rows for which the boolean predicate is true; rows where
+// it is false or null are dropped.
+func (lf *LazyFrame) Filter(predicate expr.Expr) *LazyFrame {
+	return lf.then(plan{kind: planFilter, expr: predicate})
+}
+
+// WithColumn adds the value of e under name, replacing any existing column
+// of that name
+func (lf *LazyFrame) WithColumn(name string, e expr.Expr) *LazyFrame {
+	return lf.then(plan{kind: planWithColumn, name: name, expr: e})
+}
+
+// Sort orders the rows by column, with nulls last
+func (lf *LazyFrame) Sort(column string, ascending bool) *LazyFrame {
+	return lf.then(plan{kind: planSort, name: column, ascending: ascending})
+}
+
+// Join joins the query with other as DataFrame.Join does
+func (lf *LazyFrame) Join(other *LazyFrame, leftOn, rightOn []string, how JoinType, opts ...JoinOption) *LazyFrame {
+	return lf.then(plan{kind: planJoin, right: other.plan, columns: leftOn, rightOn: rightOn, how: how, opts: opts})
+}
+
+// LazyGroupBy is a grouping of a LazyFrame waiting for its aggregation
+type LazyGroupBy struct {
+	lf      *LazyFrame
+	columns []string
+}
+
+// GroupBy groups the query by one or more columns
+func (lf *LazyFrame) GroupBy(columns ...string) *LazyGroupBy {
+	return &LazyGroupBy{lf: lf, columns: columns}
+}
+
+// Agg aggregates column within every group as GroupedDataFrame.Aggregate
+// does
+func (g *LazyGroupBy) Agg(column string, aggType AggregationType) *LazyFrame {
+	return g.lf.then(plan{kind: planAggregate, columns: g.columns, name: column, agg: aggType})
+}
+
+// Collect optimizes the query and executes it
+func (lf *LazyFrame) Collect() (*DataFrame, error) {
+	if _, err := lf.plan.schema(); err != nil {
+		return nil, err
+	}
+	return optimize(lf.plan).execute()
+}
+
+// Explain describes the plan, one step per line with inputs indented below
+// the step reading them. With optimized set it shows the plan Collect would
+// execute.
+func (lf *LazyFrame) Explain(optimized bool) (string, error) {
+	p := lf.plan
+	if _, err := p.schema(); err != nil {
+		return "", err
+	}
+	if optimized {
+		p = optimize(p)
+	}
+	var b strings.Builder
+	p.describe(&b, 0)
+	return b.String(), nil
+}
+
+func (p *plan) describe(b *strings.Builder, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	switch p.kind {
+	case planScan:
+		all, _ := p.scanColumns()
+		what := ""
+		if p.src != nil {
+			what = " " + p.src.String()
+		}
+		if p.columns == nil {
+			fmt.Fprintf(b, "SCAN%s %d columns\n", what, len(all))
+		} else {
+			fmt.Fprintf(b, "SCAN%s [%s] of %d columns\n", what, strings.Join(p.columns, ", "), len(all))
+		}
+	case planSelect:
+		fmt.Fprintf(b, "SELECT [%s]\n", strings.Join(p.columns, ", "))
+	case planFilter:
+		fmt.Fprintf(b, "FILTER %s\n", p.expr)
+	case planWithColumn:
+		fmt.Fprintf(b, "WITH COLUMN %s = %s\n", p.name, p.expr)
+	case planAggregate:
+		fmt.Fprintf(b, "AGGREGATE %s(%s) BY [%s]\n", p.agg, p.name, strings.Join(p.columns, ", "))
+	case planJoin:
+		fmt.Fprintf(b, "%s JOIN ON [%s] = [%s]\n", strings.ToUpper(p.how.String()), strings.Join(p.columns, ", "), strings.Join(p.rightOn, ", "))
+	case planSort:
+		dir := "ascending"
+		if !p.ascending {
+			dir = "descending"
+		}
+		fmt.Fprintf(b, "SORT BY %s %s\n", p.name, dir)
+	}
+	if p.input != nil {
+		p.input.describe(b, depth+1)
+	}
+	if p.right != nil {
+		p.right.describe(b, depth+1)
+	}
+}
+
+// schema returns the output columns of p in order, checking every column
+// referenced along the way.
+func (p *plan) schema() ([]string, error) {
+	if p.kind == planScan {
+		if p.columns == nil {
+			return p.scanColumns()
+		}
+		return p.columns, nil
+	}
+	in, err := p.input.schema()
+	if err != nil {
+		return nil, err
+	}
+	has := make(map[string]bool, len(in))
+	for _, name := range in {
+		has[name] = true
+	}
+	need := func(names ...string) error {
+		for _, name := range names {
+			if !has[name] {
+				return fmt.Errorf("column %s not found", name)
+			}
+		}
+		return nil
+	}
+
+	switch p.kind {
+	case planSelect:
+		return p.columns, need(p.columns...)
+	case planFilter:
+		return in, need(exprColumns(p.expr)...)
+	case planSort:
+		return in, need(p.name)
+	case planWithColumn:
+		if err := need(exprColumns(p.expr)...); err != nil {
+			return nil, err
+		}
+		if has[p.name] {
+			return in, nil
+		}
+		return append(append([]string(nil), in...), p.name), nil
+	case planAggregate:
+		if err := need(p.columns...); err != nil {
+			return nil, err
+		}
+		return append(append([]string(nil), p.columns...), p.name), need(p.name)
+	case planJoin:
+		right, err := p.right.schema()
+		if err != nil {
+			return nil, err
+		}
+		if err := need(p.columns...); err != nil {
+			return nil, err
+		}
+		for _, key := range p.rightOn {
+			if !contains(right, key) {
+				return nil, fmt.Errorf("column %s not found", key)
+			}
+		}
+		out, _, err := joinSchema(in, right, p.rightOn)
+		return out, err
+	default:
+		return nil, fmt.Errorf("unknown plan step %d", p.kind)
+	}
+}
+
+// scanColumns returns every column of the frame or file read by a scan.
+func (p *plan) scanColumns() ([]string, error) {
+	if p.src != nil {
+		return p.src.columns()
+	}
+	return p.df.order, nil
+}
+
+// joinSchema returns the output columns of a join of frames with the given
+// columns, and maps every output column coming from the right side to its
+// right name. A right column clashing with a left one takes JoinSuffix,
+// which is an error when the suffixed name is taken too.
+func joinSchema(left, right, rightOn []string) ([]string, map[string]string, error) {
+	out := append([]string(nil), left...)
+	taken := make(map[string]bool, len(left)+len(right))
+	for _, name := range left {
+		taken[name] = true
+	}
+	key := make(map[string]bool, len(rightOn))
+	for _, name := range rightOn {
+		key[name] = true
+	}
+	kept := make(map[string]bool, len(right))
+	for _, name := range right {
+		kept[name] = !key[name]
+	}
+	fromRight := make(map[string]string)
+	for _, name := range right {
+		if key[name] {
+			continue
+		}
+		o := name
+		if taken[o] {
+			o = name + JoinSuffix
+			if taken[o] || kept[o] {
+				return nil, nil, fmt.Errorf("column %s already exists", o)
+			}
+		}
+		taken[o] = true
+		out = append(out, o)
+		fromRight[o] = name
+	}
+	return out, fromRight, nil
+}
+
+// exprColumns lists the columns e reads.
+func exprColumns(e expr.Expr) []string {
+	var cols []string
+	if e.Kind == expr.KindColumn {
+		cols = append(cols, e.Name)
+	}
+	cols = append(cols, e.Partition...)
+	for _, in := range e.Inputs {
+		cols = append(cols, exprColumns(in)...)
+	}
+	return cols
+}
+
+// rowWise reports whether every row of e depends only on the same row of
+// its input. Only such expressions give the same result whether rows are
+// filtered before or after them.
+func rowWise(e expr.Expr) bool {
+	switch e.Kind {
+	case expr.KindAgg, expr.KindWindow, expr.KindShift:
+		return false
+	}
+	for _, in := range e.Inputs {
+		if !rowWise(in) {
+			return false
+		}
+	}
+	return true
+}
+
+// conjuncts splits a predicate into the terms of its top-level And chain.
+// A row passes the predicate exactly when it passes every term, nulls
+// included, so the terms may be applied separately.
+func conjuncts(e expr.Expr) []expr.Expr {
+	if e.Kind == expr.KindBinary && e.Op == expr.OpAnd {
+		return append(conjuncts(e.Inputs[0]), conjuncts(e.Inputs[1])...)
+	}
+	return []expr.Expr{e}
+}
+
+func optimize(p *plan) *plan {
+	return prune(pushDown(p, nil), nil)
+}
+
+// pushDown moves the row-wise predicates preds, which apply to the output
+// of p, as close to the scans as they stay valid, fusing the predicates
+// that meet into a single filter.
+func pushDown(p *plan, preds []expr.Expr) *plan {
+	q := *p
+	switch p.kind {
+	case planScan:
+		if len(preds) > 0 {
+			logDecision("predicate pushdown", "scan", p.scanName(), "predicates", len(preds))
+		}
+		return withFilter(&q, preds)
+	case planFilter:
+		if !rowWise(p.expr) {
+			// The predicate sees every row of its input, so nothing moves
+			// past it.
+			q.input = pushDown(p.input, nil)
+			return withFilter(&q, preds)
+		}
+		return pushDown(p.input, append(preds, conjuncts(p.expr)...))
+	case planSelect, planSort:
+		q.input = pushDown(p.input, preds)
+		return &q
+	case planWithColumn:
+		if !rowWise(p.expr) {
+			q.input = pushDown(p.input, nil)
+			return withFilter(&q, preds)
+		}
+		below, above := splitPredicates(preds, func(col string) bool { return col != p.name })
+		q.input = pushDown(p.input, below)
+		return withFilter(&q, above)
+	case planAggregate:
+		// Filtering on group keys drops whole groups.
+		keys := make(map[string]bool, len(p.columns))
+		for _, col := range p.columns {
+			keys[col] = true
+		}
+		below, above := splitPredicates(preds, func(col string) bool { return keys[col] })
+		q.input = pushDown(p.input, below)
+		return withFilter(&q, above)
+	case planJoin:
+		left, _ := p.input.schema()
+		right, _ := p.right.schema()
+		_, fromRight, _ := joinSchema(left, right, p.rightOn)
+		isLeft := make(map[string]bool, len(left))
+		for _, col := range left {
+			isLeft[col] = true
+		}
+		// A side may only be filtered early when its rows are never padded
+		// with nulls, as a predicate can hold for a null.
+		rest := preds
+		var leftPreds, rightPreds []expr.Expr
+		if p.how == JoinInner || p.how == JoinLeft {
+			leftPreds, rest = splitPredicates(rest, func(col string) bool { return isLeft[col] })
+		}
+		if p.how == JoinInner || p.how == JoinRight {
+			var renamed []expr.Expr
+			renamed, rest = splitPredicates(rest, func(col string) bool { _, ok := fromRight[col]; return ok })
+			for _, pred := range renamed {
+				rightPreds = append(rightPreds, renameColumns(pred, fromRight))
+			}
+		}
+		q.input = pushDown(p.input, leftPreds)
+		q.right = pushDown(p.right, rightPreds)
+		return withFilter(&q, rest)
+	}
+	return withFilter(&q, preds)
+}
+
+// splitPredicates separates the predicates reading only columns accepted
+// by movable from the others.
+func splitPredicates(preds []expr.Expr, movable func(col string) bool) (moved, kept []expr.Expr) {
+	for _, pred := range preds {
+		ok := true
+		for _, col := range exprColumns(pred) {
+			if !movable(col) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			moved = append(moved, pred)
+		} else {
+			kept = append(kept, pred)
+		}
+	}
+	return moved, kept
+}
+
+func renameColumns(e expr.Expr, names map[string]string) expr.Expr {
+	if e.Kind == expr.KindColumn {
+		e.Name = names[e.Name]
+		return e
+	}
+	inputs := make([]expr.Expr, len(e.Inputs))
+	for i, in := range e.Inputs {
+		inputs[i] = renameColumns(in, names)
+	}
+	e.Inputs = inputs
+	return e
+}
+
+// withFilter puts the conjunction of preds on top of p.
+func withFilter(p *plan, preds []expr.Expr) *plan {
+	if len(preds) == 0 {
+		return p
+	}
+	pred := preds[0]
+	for _, next := range preds[1:] {
+		pred = pred.And(next)
+	}
+	return &plan{kind: planFilter, input: p, expr: pred}
+}
+
+// prune removes the columns that are not needed from the scans below p
+// and drops WithColumn steps whose column is never read. need lists the
+// columns read from the output of p, or is nil when all of them are; p may
+// still produce more.
+func prune(p *plan, need []string) *plan {
+	q := *p
+	switch p.kind {
+	case planScan:
+		if need == nil {
+			return &q
+		}
+		wanted := make(map[string]bool, len(need))
+		for _, col := range need {
+			wanted[col] = true
+		}
+		all, _ := p.scanColumns()
+		q.columns = []string{}
+		for _, col := range all {
+			if wanted[col] {
+				q.columns = append(q.columns, col)
+			}
+		}
+		logDecision("projection pushdown", "scan", p.scanName(), "columns", q.columns, "of", len(all))
+		return &q
+	case planSelect:
+		q.input = prune(p.input, p.columns)
+	case planFilter:
+		q.input = prune(p.input, addColumns(need, exprColumns(p.expr)...))
+	case planSort:
+		q.input = prune(p.input, addColumns(need, p.name))
+	case planWithColumn:
+		if need != nil && !contains(need, p.name) {
+			return prune(p.input, need)
+		}
+		var rest []string
+		if need != nil {
+			rest = []string{}
+			for _, col := range need {
+				if col != p.name {
+					rest = append(rest, col)
+				}
+			}
+		}
+		q.input = prune(p.input, addColumns(rest, exprColumns(p.expr)...))
+	case planAggregate:
+		q.input = prune(p.input, append(append([]string{}, p.columns...), p.name))
+	case planJoin:
+		if need == nil {
+			q.input, q.right = prune(p.input, nil), prune(p.right, nil)
+			break
+		}
+		left, _ := p.input.schema()
+		right, _ := p.right.schema()
+		_, fromRight, _ := joinSchema(left, right, p.rightOn)
+		leftNeed := append([]string{}, p.columns...)
+		rightNeed := append([]string{}, p.rightOn...)
+		for _, col := range need {
+			if name, ok := fromRight[col]; ok {
+				rightNeed = append(rightNeed, name)
+				if name != col {
+					// Keep the clashing left column so the suffix stays.
+					leftNeed = append(leftNeed, name)
+				}
+			} else {
+				leftNeed = append(leftNeed, col)
+			}
+		}
+		q.input, q.right = prune(p.input, leftNeed), prune(p.right, rightNeed)
+	}
+	return &q
+}
+
+// scanName names the input of planScan in the decision log
+func (p *plan) scanName() string {
+	if p.src != nil {
+		return p.src.String()
+	}
+	return "frame"
+}
+
+// addColumns adds cols to need, unless need is nil and so already means
+// every column.
+func addColumns(need []string, cols ...string) []string {
+	if need == nil {
+		return nil
+	}
+	return append(append([]string{}, need...), cols...)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *plan) execute() (*DataFrame, error) {
+	if p.kind == planScan {
+		if p.src != nil {
+			return p.src.read(p.columns)
+		}
+		if p.columns == nil {
+			return p.df, nil
+		}
+		return p.df.Select(p.columns)
+	}
+	in, err := p.input.execute()
+	if err != nil {
+		return nil, err
+	}
+	switch p.kind {
+	case planSelect:
+		return in.Select(p.columns)
+	case planFilter:
+		return in.FilterExpr(p.expr)
+	case planWithColumn:
+		return in.WithColumnExpr(p.name, p.expr)
+	case planSort:
+		return in.SortByColumn(p.name, p.ascending)
+	case planAggregate:
+		gdf, err := in.GroupBy(p.columns)
+		if err != nil {
+			return nil, err
+		}
+		return gdf.Aggregate(p.name, p.agg)
+	case planJoin:
+		right, err := p.right.execute()
+		if err != nil {
+			return nil, err
+		}
+		return in.Join(right, p.columns, p.rightOn, p.how, p.opts...)
+	default:
+		return nil, fmt.Errorf("unknown plan step %d", p.kind)
+	}
+}
@@ -0,0 +1,67 @@
+package dataframe
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrLimitExceeded is returned (wrapped) by readers whose input exceeds the
+// configured ReadLimits under the LimitError policy.
+var ErrLimitExceeded = errors.New("reader limit exceeded")
+
+// LimitPolicy decides what a reader does when its input exceeds ReadLimits
+type LimitPolicy int
+
+const (
+	// LimitError fails the read with ErrLimitExceeded.
+	LimitError LimitPolicy = iota
+	// LimitTruncate stops at the limit and returns the complete records read
+	// so far.
+	LimitTruncate
+)
+
+// ReadLimits bounds how much a reader will load, so that untrusted input
+// cannot allocate without bound. Zero values mean no limit.
+type ReadLimits struct {
+	MaxRows  int   // maximum number of data records
+	MaxBytes int64 // maximum number of input bytes consumed
+	OnLimit  LimitPolicy
+}
+
+// wrap applies MaxBytes to r.
+func (l ReadLimits) wrap(r io.Reader) io.Reader {
+	if l.MaxBytes <= 0 {
+		return r
+	}
+	return &limitReader{r: r, remaining: l.MaxBytes}
+}
+
+// rowsExceeded reports whether n records pass MaxRows.
+func (l ReadLimits) rowsExceeded(n int) bool {
+	return l.MaxRows > 0 && n > l.MaxRows
+}
+
+// limitReader yields at most remaining bytes and then fails with
+// ErrLimitExceeded, unless the underlying reader is exhausted exactly at the
+// limit.
+type limitReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		var probe [1]byte
+		n, err := l.r.Read(probe[:])
+		if n == 0 && err != nil {
+			return 0, err
+		}
+		return 0, ErrLimitExceeded
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,108 @@
+package dataframe
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// LineageEntry records one operation applied to a DataFrame
+type LineageEntry struct {
+	Op         string                 `json:"op"`
+	Params     map[string]interface{} `json:"params,omitempty"`
+	InputRows  int                    `json:"input_rows"`
+	InputCols  int                    `json:"input_cols"`
+	OutputRows int                    `json:"output_rows"`
+	OutputCols int                    `json:"output_cols"`
+	Duration   time.Duration          `json:"duration_ns"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// Lineage collects the operations applied to a DataFrame and every frame
+// derived from it. It is safe for concurrent use.
+type Lineage struct {
+	mu      sync.Mutex
+	entries []LineageEntry
+}
+
+// NewLineage creates an empty Lineage
+func NewLineage() *Lineage {
+	return &Lineage{}
+}
+
+// Entries returns a copy of the recorded operations in the order they
+// finished
+func (l *Lineage) Entries() []LineageEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]LineageEntry(nil), l.entries...)
+}
+
+// WriteJSON writes the recorded operations to w as a JSON array
+func (l *Lineage) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	entries := l.Entries()
+	if entries == nil {
+		entries = []LineageEntry{}
+	}
+	return enc.Encode(entries)
+}
+
+func (l *Lineage) add(e LineageEntry) {
+	l.mu.Lock()
+	l.entries = append(l.entries, e)
+	l.mu.Unlock()
+}
+
+// WithLineage returns a shallow copy of the DataFrame that records its
+// operations, and those of frames derived from it, into l. Passing nil
+// stops recording.
+func (df *DataFrame) WithLineage(l *Lineage) *DataFrame {
+	out := *df
+	out.lineage = l
+	return &out
+}
+
+// Lineage returns the recorder attached to the DataFrame, or nil
+func (df *DataFrame) Lineage() *Lineage {
+	return df.lineage
+}
+
+// track starts recording op on df. Deferred with the method's named
+// results, the returned function logs the operation and carries the
+// recorder, version history and the constraints still holding over to the
+// output frame. It costs nothing on frames without any of them.
+func (df *DataFrame) track(op string, params map[string]interface{}) func(out **DataFrame, err *error) {
+	if df.lineage == nil && df.history == nil && df.constraints == nil {
+		return func(**DataFrame, *error) {}
+	}
+	if df.lineage == nil {
+		return func(out **DataFrame, err *error) {
+			if *err == nil && *out != nil {
+				(*out).history = df.history
+				df.keepConstraints(op, *out)
+			}
+		}
+	}
+	start := time.Now()
+	return func(out **DataFrame, err *error) {
+		e := LineageEntry{
+			Op:        op,
+			Params:    params,
+			InputRows: df.length,
+			InputCols: len(df.order),
+			Duration:  time.Since(start),
+		}
+		if *err != nil {
+			e.Error = (*err).Error()
+		} else if *out != nil {
+			e.OutputRows, e.OutputCols = (*out).Shape()
+			(*out).lineage = df.lineage
+			(*out).history = df.history
+			df.keepConstraints(op, *out)
+		}
+		df.lineage.add(e)
+	}
+}
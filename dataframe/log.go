@@ -0,0 +1,47 @@
+package dataframe
+
+import (
+	"log/slog"
+	"os"
+	"sync/atomic"
+)
+
+// logger records the decisions of the engine, nil when nobody listens.
+var logger atomic.Pointer[slog.Logger]
+
+// SetLogger records the decisions of the engine on l at debug level: which
+// code path an operation took, such as a parallel or serial radix sort or
+// a streaming or indexed aggregation, the filters and projections the lazy
+// optimizer pushed into scans, and the spills of CollectStreaming. Every
+// record has a message naming the decision and attributes describing it,
+// and is meant to explain performance that differs between environments,
+// not to be parsed. A nil l stops the records. It is safe to call at any
+// time.
+func SetLogger(l *slog.Logger) {
+	logger.Store(l)
+}
+
+// SetVerbose logs the decisions of the engine as text on stderr when on is
+// set, see SetLogger, and stops logging them otherwise, replacing any
+// logger. GOPOLARS_VERBOSE=1 turns it on at startup.
+func SetVerbose(on bool) {
+	if !on {
+		SetLogger(nil)
+		return
+	}
+	h := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+	SetLogger(slog.New(h).With("lib", "gopolars"))
+}
+
+// Verbose reports whether the decisions of the engine are logged
+func Verbose() bool {
+	return logger.Load() != nil
+}
+
+// logDecision records a decision of the engine, with alternating keys and
+// values as for slog.Logger.Debug.
+func logDecision(msg string, args ...any) {
+	if l := logger.Load(); l != nil {
+		l.Debug(msg, args...)
+	}
+}
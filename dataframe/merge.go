@@ -0,0 +1,112 @@
+package dataframe
+
+import (
+	"cmp"
+	"fmt"
+
+	"go-polars/types"
+)
+
+// MergeSorted combines two frames that are both sorted ascending on column on
+// into a single frame sorted on the same column. It runs in O(n+m) without
+// hashing or re-sorting. Both frames must have the same columns and types.
+// Ties keep left rows before right rows, and nulls (placed last by
+// SortByColumn) stay at the end. A frame that is not sorted this way is
+// rejected.
+func MergeSorted(left, right *DataFrame, on string) (*DataFrame, error) {
+	if err := sameSchema([]*DataFrame{left, right}); err != nil {
+		return nil, err
+	}
+	lk, ok := left.series[on]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", on)
+	}
+	lk, err := lk.Widen()
+	if err != nil {
+		return nil, err
+	}
+	rk, err := right.series[on].Widen()
+	if err != nil {
+		return nil, err
+	}
+
+	var less func(i, j int) bool
+	var sorted bool
+	switch ld := lk.Data.(type) {
+	case []int64:
+		rd := rk.Data.([]int64)
+		less = func(i, j int) bool { return rd[j] < ld[i] }
+		sorted = ascending(ld, lk) && ascending(rd, rk)
+	case []float64:
+		// Floats compare by their sort keys, which SortByColumn orders by.
+		lf, rf := floatSortKeys(ld), floatSortKeys(rk.Data.([]float64))
+		less = func(i, j int) bool { return rf[j] < lf[i] }
+		sorted = ascending(lf, lk) && ascending(rf, rk)
+	case []string:
+		rd := rk.Data.([]string)
+		less = func(i, j int) bool { return rd[j] < ld[i] }
+		sorted = ascending(ld, lk) && ascending(rd, rk)
+	default:
+		return nil, fmt.Errorf("unsupported data type for merge column %s", on)
+	}
+	if !sorted {
+		return nil, fmt.Errorf("frames must be sorted ascending on column %s", on)
+	}
+
+	// takeRight reports whether right row j must come before left row i.
+	takeRight := func(i, j int) bool {
+		switch {
+		case !rk.IsValid(j):
+			return false
+		case !lk.IsValid(i):
+			return true
+		default:
+			return less(i, j)
+		}
+	}
+
+	// Positions index into the concatenation left ++ right.
+	order := make([]int, 0, left.length+right.length)
+	i, j := 0, 0
+	for i < left.length && j < right.length {
+		if takeRight(i, j) {
+			order = append(order, left.length+j)
+			j++
+		} else {
+			order = append(order, i)
+			i++
+		}
+	}
+	for ; i < left.length; i++ {
+		order = append(order, i)
+	}
+	for ; j < right.length; j++ {
+		order = append(order, left.length+j)
+	}
+
+	return concatColumns([]*DataFrame{left, right}, order)
+}
+
+// ascending reports whether the valid values of key never decrease and all
+// nulls come after them.
+func ascending[T cmp.Ordered](data []T, key *types.Series) bool {
+	last := -1
+	for i := range data {
+		if !key.IsValid(i) {
+			continue
+		}
+		if i != last+1 || (last >= 0 && data[i] < data[last]) {
+			return false
+		}
+		last = i
+	}
+	return true
+}
+
+func floatSortKeys(data []float64) []uint64 {
+	keys := make([]uint64, len(data))
+	for i, v := range data {
+		keys[i] = floatSortKey(v)
+	}
+	return keys
+}
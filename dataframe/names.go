@@ -0,0 +1,138 @@
+package dataframe
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"go-polars/types"
+)
+
+// NormalizeOptions selects the clean-ups applied to column names
+type NormalizeOptions struct {
+	Trim      bool // strip surrounding whitespace
+	Lowercase bool // lower-case every letter
+	SnakeCase bool // split words and camelCase humps with '_' (implies Lowercase)
+	Dedupe    bool // suffix repeated names with _1, _2, ...
+	// OnRename, when set, is called by readers for every column whose name
+	// changed.
+	OnRename func(Rename)
+}
+
+// Rename records a column name changed by normalization
+type Rename struct {
+	From string
+	To   string
+}
+
+func (o NormalizeOptions) enabled() bool {
+	return o.Trim || o.Lowercase || o.SnakeCase || o.Dedupe
+}
+
+// NormalizeColumnNames applies opts to names and returns the new names in the
+// same order together with the renames performed. Names left empty become
+// column_<position>. Without Dedupe the result may contain duplicates.
+func NormalizeColumnNames(names []string, opts NormalizeOptions) ([]string, []Rename) {
+	out := make([]string, len(names))
+	for i, name := range names {
+		n := name
+		if opts.Trim {
+			n = strings.TrimSpace(n)
+		}
+		if opts.SnakeCase {
+			n = snakeCase(n)
+		} else if opts.Lowercase {
+			n = strings.ToLower(n)
+		}
+		if n == "" && opts.enabled() {
+			n = fmt.Sprintf("column_%d", i)
+		}
+		out[i] = n
+	}
+
+	if opts.Dedupe {
+		taken := make(map[string]bool, len(out))
+		for _, n := range out {
+			taken[n] = false
+		}
+		for i, n := range out {
+			if !taken[n] {
+				taken[n] = true
+				continue
+			}
+			for k := 1; ; k++ {
+				candidate := fmt.Sprintf("%s_%d", n, k)
+				if _, used := taken[candidate]; !used {
+					out[i] = candidate
+					taken[candidate] = true
+					break
+				}
+			}
+		}
+	}
+
+	var renames []Rename
+	for i, name := range names {
+		if out[i] != name {
+			renames = append(renames, Rename{From: name, To: out[i]})
+		}
+	}
+	return out, renames
+}
+
+// snakeCase lower-cases s, turning runs of non-alphanumeric characters and
+// camelCase boundaries into single underscores.
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	pending := false // an underscore is due before the next word character
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pending = b.Len() > 0
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 && b.Len() > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				pending = true
+			}
+		}
+		if pending {
+			b.WriteByte('_')
+			pending = false
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// NormalizeColumns returns a copy of the DataFrame with normalized column
+// names and the renames performed. Names are processed in column order, so
+// dedupe suffixes go to the later columns.
+func (df *DataFrame) NormalizeColumns(opts NormalizeOptions) (out *DataFrame, renames []Rename, err error) {
+	defer df.track("NormalizeColumns", nil)(&out, &err)
+	normalized, renames := NormalizeColumnNames(df.order, opts)
+	result := make(map[string]*types.Series, len(df.order))
+	for i, name := range df.order {
+		to := normalized[i]
+		if _, dup := result[to]; dup {
+			return nil, nil, fmt.Errorf("normalized column name %s is not unique", to)
+		}
+		result[to] = df.series[name].Rename(to)
+	}
+	if out, err = newOrdered(result, normalized); err != nil {
+		return nil, nil, err
+	}
+	opts.report(renames)
+	return out, renames, nil
+}
+
+func (o NormalizeOptions) report(renames []Rename) {
+	if o.OnRename == nil {
+		return
+	}
+	for _, r := range renames {
+		o.OnRename(r)
+	}
+}
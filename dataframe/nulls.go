@@ -0,0 +1,117 @@
+package dataframe
+
+import (
+	"fmt"
+
+	"go-polars/types"
+)
+
+// DropNulls returns a new DataFrame without the rows holding a null in any of
+// the given columns. With no columns every column is checked.
+func (df *DataFrame) DropNulls(subset ...string) (out *DataFrame, err error) {
+	defer df.track("DropNulls", map[string]interface{}{"subset": subset})(&out, &err)
+	if len(subset) == 0 {
+		subset = df.Columns()
+	}
+	checked := make([]*types.Series, 0, len(subset))
+	for _, col := range subset {
+		s, ok := df.series[col]
+		if !ok {
+			return nil, fmt.Errorf("column %s not found", col)
+		}
+		if s.HasNulls() {
+			checked = append(checked, s)
+		}
+	}
+	if len(checked) == 0 {
+		return df, nil
+	}
+
+	keep := make([]int, 0, df.length)
+	for i := 0; i < df.length; i++ {
+		valid := true
+		for _, s := range checked {
+			if !s.IsValid(i) {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			keep = append(keep, i)
+		}
+	}
+	return df.take(keep)
+}
+
+// FillNull returns a new DataFrame where the nulls of column are replaced by
+// value, which must match the column's element type.
+func (df *DataFrame) FillNull(column string, value interface{}) (out *DataFrame, err error) {
+	defer df.track("FillNull", map[string]interface{}{"column": column, "value": value})(&out, &err)
+	s, ok := df.series[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	filled, err := s.FillNull(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return df.withColumns(filled)
+}
+
+// FillNullForward returns a new DataFrame where the nulls of the given
+// columns, or of every column when none are given, take the last valid
+// value before them; see Series.FillNullForward for limit.
+func (df *DataFrame) FillNullForward(limit int, columns ...string) (out *DataFrame, err error) {
+	defer df.track("FillNullForward", map[string]interface{}{"limit": limit, "columns": columns})(&out, &err)
+	return df.fillColumns(columns, false, func(s *types.Series) (*types.Series, error) {
+		return s.FillNullForward(limit)
+	})
+}
+
+// FillNullBackward returns a new DataFrame where the nulls of the given
+// columns, or of every column, take the first valid value after them; see
+// Series.FillNullBackward.
+func (df *DataFrame) FillNullBackward(limit int, columns ...string) (out *DataFrame, err error) {
+	defer df.track("FillNullBackward", map[string]interface{}{"limit": limit, "columns": columns})(&out, &err)
+	return df.fillColumns(columns, false, func(s *types.Series) (*types.Series, error) {
+		return s.FillNullBackward(limit)
+	})
+}
+
+// Interpolate returns a new DataFrame where the inner nulls of the given
+// numeric columns, or of every numeric column when none are given, are
+// interpolated by method; see Series.Interpolate. Rows are taken in their
+// current order, so sort by time first.
+func (df *DataFrame) Interpolate(method types.InterpolateMethod, columns ...string) (out *DataFrame, err error) {
+	defer df.track("Interpolate", map[string]interface{}{"method": method.String(), "columns": columns})(&out, &err)
+	return df.fillColumns(columns, true, func(s *types.Series) (*types.Series, error) {
+		return s.Interpolate(method)
+	})
+}
+
+// fillColumns replaces the given columns, or every column when none are
+// given, by fn applied to them. numeric restricts the default to numeric
+// columns.
+func (df *DataFrame) fillColumns(columns []string, numeric bool, fn func(*types.Series) (*types.Series, error)) (*DataFrame, error) {
+	if len(columns) == 0 {
+		for _, col := range df.order {
+			if !numeric || types.IsNumeric(df.series[col].DataType) {
+				columns = append(columns, col)
+			}
+		}
+	}
+	filled := make([]*types.Series, 0, len(columns))
+	for _, col := range columns {
+		s, ok := df.series[col]
+		if !ok {
+			return nil, fmt.Errorf("column %s not found", col)
+		}
+		f, err := fn(s)
+		if err != nil {
+			return nil, err
+		}
+		filled = append(filled, f)
+	}
+	return df.withColumns(filled...)
+}
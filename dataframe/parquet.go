@@ -0,0 +1,112 @@
+package dataframe
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// ParquetCompression selects the codec used for Parquet column chunks
+type ParquetCompression int
+
+const (
+	CompressionSnappy ParquetCompression = iota
+	CompressionZstd
+	CompressionGzip
+	CompressionNone
+)
+
+// WriteParquetOptions controls the layout of files produced by WriteParquet
+type WriteParquetOptions struct {
+	// Compression is the codec applied to every column. Defaults to Snappy.
+	Compression ParquetCompression
+	// RowGroupSize is the maximum number of rows per row group. Zero keeps the
+	// writer's default.
+	RowGroupSize int
+	// DictionaryStrings enables dictionary encoding for string columns.
+	// Numeric and boolean columns are always written plain.
+	DictionaryStrings bool
+}
+
+func (c ParquetCompression) codec() (compress.Compression, error) {
+	switch c {
+	case CompressionSnappy:
+		return compress.Codecs.Snappy, nil
+	case CompressionZstd:
+		return compress.Codecs.Zstd, nil
+	case CompressionGzip:
+		return compress.Codecs.Gzip, nil
+	case CompressionNone:
+		return compress.Codecs.Uncompressed, nil
+	default:
+		return 0, fmt.Errorf("unknown parquet compression %d", c)
+	}
+}
+
+// WriteParquet writes the DataFrame to a Parquet file at path, replacing
+// any file already there only once the write has succeeded.
+func (df *DataFrame) WriteParquet(path string, opts WriteParquetOptions) error {
+	codec, err := opts.Compression.codec()
+	if err != nil {
+		return err
+	}
+	if opts.RowGroupSize < 0 {
+		return fmt.Errorf("row group size must be non-negative, got %d", opts.RowGroupSize)
+	}
+
+	props := []parquet.WriterProperty{
+		parquet.WithCompression(codec),
+		parquet.WithDictionaryDefault(false),
+	}
+	if opts.RowGroupSize > 0 {
+		props = append(props, parquet.WithMaxRowGroupLength(int64(opts.RowGroupSize)))
+	}
+	if opts.DictionaryStrings {
+		for name, s := range df.series {
+			if _, ok := s.Data.([]string); ok {
+				props = append(props, parquet.WithDictionaryFor(name, true))
+			}
+		}
+	}
+
+	rec, err := df.ToArrow()
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+
+	// The file is written next to path and renamed into place, so a failed
+	// write never leaves a truncated file behind.
+	tmp := path + ".tmp"
+	err = writeParquetFile(tmp, rec, parquet.NewWriterProperties(props...))
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+func writeParquetFile(path string, rec arrow.RecordBatch, props *parquet.WriterProperties) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w, err := pqarrow.NewFileWriter(rec.Schema(), f, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Write(rec); err != nil {
+		w.Close()
+		return err
+	}
+	// Closing the parquet writer also closes the underlying file.
+	return w.Close()
+}
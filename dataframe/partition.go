@@ -0,0 +1,65 @@
+package dataframe
+
+import "fmt"
+
+// groupHash hashes the key of a row for grouping. Tests replace it to make
+// keys collide.
+var groupHash = buildKey128
+
+// partition assigns every row a dense group ID, numbered in order of the first
+// appearance of each key, and returns the IDs together with the number of
+// groups. With no columns the whole frame forms a single group. When verify
+// is set, a row only joins a group whose first row holds an equal key, so
+// keys whose hashes collide still form groups of their own.
+func (df *DataFrame) partition(columns []string, verify bool) ([]int, int, error) {
+	for _, col := range columns {
+		if _, ok := df.series[col]; !ok {
+			return nil, 0, fmt.Errorf("column %s not found", col)
+		}
+	}
+
+	ids := make([]int, df.length)
+	if len(columns) == 0 {
+		if df.length == 0 {
+			return ids, 0, nil
+		}
+		return ids, 1, nil
+	}
+
+	var same func(i, j int) bool
+	if verify {
+		var err error
+		if same, err = df.rowKeysEqual(columns); err != nil {
+			return nil, 0, err
+		}
+	}
+	seen := make(map[key128][]int) // the groups whose keys share a hash
+	var first []int                // first row of every group
+	for i := 0; i < df.length; i++ {
+		k := groupHash(df, columns, i)
+		id := -1
+		for _, g := range seen[k] {
+			if same == nil || same(first[g], i) {
+				id = g
+				break
+			}
+		}
+		if id < 0 {
+			id = len(first)
+			first = append(first, i)
+			seen[k] = append(seen[k], id)
+		}
+		ids[i] = id
+	}
+	return ids, len(first), nil
+}
+
+// partitionRows inverts partition IDs into per-group row lists, each kept in
+// original row order.
+func partitionRows(ids []int, groups int) [][]int {
+	rows := make([][]int, groups)
+	for i, id := range ids {
+		rows[id] = append(rows[id], i)
+	}
+	return rows
+}
@@ -0,0 +1,137 @@
+package dataframe
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	xxhash "github.com/cespare/xxhash/v2"
+)
+
+// Stage is one step of a Pipeline. Key identifies the step's logic and
+// parameters for checkpointing, e.g. "filter:amount>100"; it must change
+// whenever the step would produce a different result.
+type Stage struct {
+	Key string
+	Fn  func(*DataFrame) (*DataFrame, error)
+}
+
+// Pipeline runs a sequence of stages over a DataFrame, optionally persisting
+// each intermediate frame so that a rerun resumes after the last completed
+// stage.
+type Pipeline struct {
+	stages []Stage
+	dir    string
+}
+
+// NewPipeline creates a Pipeline running stages in order
+func NewPipeline(stages ...Stage) *Pipeline {
+	return &Pipeline{stages: stages}
+}
+
+// Then appends a stage to the pipeline and returns it for chaining
+func (p *Pipeline) Then(key string, fn func(*DataFrame) (*DataFrame, error)) *Pipeline {
+	p.stages = append(p.stages, Stage{Key: key, Fn: fn})
+	return p
+}
+
+// Checkpoint makes Run store the output of every stage in dir as an Arrow
+// IPC file. Files are named by a hash of the input frame's content and the
+// keys of all stages up to that point, so a changed input or plan never
+// reuses stale results. Checkpointed frames must hold Arrow-compatible
+// columns.
+func (p *Pipeline) Checkpoint(dir string) *Pipeline {
+	p.dir = dir
+	return p
+}
+
+// Run executes the pipeline on input. With checkpointing enabled it starts
+// from the latest stage whose checkpoint can be read, skipping unreadable
+// ones, and writes a checkpoint after each stage it runs.
+func (p *Pipeline) Run(input *DataFrame) (*DataFrame, error) {
+	if p.dir == "" {
+		df := input
+		for i, st := range p.stages {
+			var err error
+			if df, err = st.Fn(df); err != nil {
+				return nil, fmt.Errorf("stage %d (%s): %w", i, st.Key, err)
+			}
+		}
+		return df, nil
+	}
+
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return nil, err
+	}
+	h, err := contentHash(input)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: %w", err)
+	}
+	paths := make([]string, len(p.stages))
+	for i, st := range p.stages {
+		h = chainHash(h, st.Key)
+		paths[i] = filepath.Join(p.dir, fmt.Sprintf("%016x.arrow", h))
+	}
+
+	df, start := input, 0
+	for i := len(paths) - 1; i >= 0; i-- {
+		if saved, err := ReadIPC(paths[i]); err == nil {
+			df, start = saved, i+1
+			break
+		}
+	}
+
+	for i := start; i < len(p.stages); i++ {
+		st := p.stages[i]
+		if df, err = st.Fn(df); err != nil {
+			return nil, fmt.Errorf("stage %d (%s): %w", i, st.Key, err)
+		}
+		if err := writeCheckpoint(df, paths[i]); err != nil {
+			return nil, fmt.Errorf("stage %d (%s): checkpoint: %w", i, st.Key, err)
+		}
+	}
+	return df, nil
+}
+
+// writeCheckpoint writes df next to path and renames it into place, so an
+// interrupted run never leaves a truncated checkpoint behind.
+func writeCheckpoint(df *DataFrame, path string) error {
+	tmp := path + ".tmp"
+	if err := df.WriteIPC(tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// contentHash hashes the Arrow IPC stream encoding of df, which covers
+// column names, order, types, values and validity.
+func contentHash(df *DataFrame) (uint64, error) {
+	rec, err := df.ToArrow()
+	if err != nil {
+		return 0, err
+	}
+	defer rec.Release()
+
+	d := xxhash.New()
+	w := ipc.NewWriter(d, ipc.WithSchema(rec.Schema()))
+	if err := w.Write(rec); err != nil {
+		w.Close()
+		return 0, err
+	}
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	return d.Sum64(), nil
+}
+
+func chainHash(prev uint64, key string) uint64 {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], prev)
+	d := xxhash.New()
+	d.Write(buf[:])
+	d.WriteString(key)
+	return d.Sum64()
+}
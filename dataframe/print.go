@@ -0,0 +1,189 @@
+package dataframe
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go-polars/types"
+)
+
+// PrintOptions controls how Format lays out a DataFrame. Zero values select
+// the defaults.
+type PrintOptions struct {
+	// MaxRows is the number of rows shown; when the frame is longer, its
+	// first and last rows are shown around a row of ellipses. Defaults to
+	// 10; negative shows every row.
+	MaxRows int
+	// MaxCols is the number of columns shown, likewise. Defaults to 8;
+	// negative shows every column.
+	MaxCols int
+	// FloatPrecision is the number of decimals shown for floats. Defaults to
+	// the shortest representation that reads back as the same value.
+	FloatPrecision int
+	// MaxWidth is the number of characters a cell is cut to. Defaults to 32.
+	MaxWidth int
+}
+
+const ellipsis = "…"
+
+// String formats the DataFrame with the default PrintOptions
+func (df *DataFrame) String() string {
+	return df.Format(PrintOptions{})
+}
+
+// Print writes the DataFrame to standard output, see Format
+func (df *DataFrame) Print(opts PrintOptions) {
+	fmt.Fprintln(os.Stdout, df.Format(opts))
+}
+
+// Format renders the DataFrame as a table headed by its shape, with the
+// name and data type of every column above its values. Numbers are aligned
+// right and everything else left; nulls read "null".
+func (df *DataFrame) Format(opts PrintOptions) string {
+	if opts.MaxRows == 0 {
+		opts.MaxRows = 10
+	}
+	if opts.MaxCols == 0 {
+		opts.MaxCols = 8
+	}
+	if opts.MaxWidth <= 0 {
+		opts.MaxWidth = 32
+	}
+	rows := shownPositions(df.length, opts.MaxRows)
+	cols := shownPositions(len(df.order), opts.MaxCols)
+
+	// Every shown column becomes a list of cells: name, type, then values.
+	// A gap (-1) becomes a column or row of ellipses.
+	var table [][]string
+	var right []bool
+	for _, c := range cols {
+		if c < 0 {
+			column := []string{ellipsis, ""}
+			for range rows {
+				column = append(column, ellipsis)
+			}
+			table, right = append(table, column), append(right, false)
+			continue
+		}
+		s := df.series[df.order[c]]
+		column := []string{truncateCell(s.Name, opts.MaxWidth), s.DataType.String()}
+		for _, r := range rows {
+			if r < 0 {
+				column = append(column, ellipsis)
+				continue
+			}
+			column = append(column, truncateCell(formatCell(s, r, opts.FloatPrecision), opts.MaxWidth))
+		}
+		table, right = append(table, column), append(right, types.IsNumeric(s.DataType))
+	}
+
+	widths := make([]int, len(table))
+	for c, column := range table {
+		for _, cell := range column {
+			widths[c] = max(widths[c], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "shape: (%d, %d)\n", df.length, len(df.order))
+	rule := func(left, fill, sep, end string) {
+		b.WriteString(left)
+		for c, w := range widths {
+			if c > 0 {
+				b.WriteString(sep)
+			}
+			b.WriteString(strings.Repeat(fill, w+2))
+		}
+		b.WriteString(end + "\n")
+	}
+	line := func(i int, alignRight bool) {
+		b.WriteString("│")
+		for c, column := range table {
+			if c > 0 {
+				b.WriteString("┆")
+			}
+			pad := strings.Repeat(" ", widths[c]-utf8.RuneCountInString(column[i]))
+			if alignRight && right[c] {
+				b.WriteString(" " + pad + column[i] + " ")
+			} else {
+				b.WriteString(" " + column[i] + pad + " ")
+			}
+		}
+		b.WriteString("│\n")
+	}
+	rule("┌", "─", "┬", "┐")
+	line(0, false)
+	line(1, false)
+	rule("╞", "═", "╪", "╡")
+	for i := range rows {
+		line(i+2, true)
+	}
+	rule("└", "─", "┴", "┘")
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// shownPositions returns the positions of n items to display when at most
+// limit fit, with -1 standing for the items left out. Negative limits show
+// everything.
+func shownPositions(n, limit int) []int {
+	if limit < 0 || n <= limit {
+		out := make([]int, n)
+		for i := range out {
+			out[i] = i
+		}
+		return out
+	}
+	head := (limit + 1) / 2
+	out := make([]int, 0, limit+1)
+	for i := 0; i < head; i++ {
+		out = append(out, i)
+	}
+	out = append(out, -1)
+	for i := n - (limit - head); i < n; i++ {
+		out = append(out, i)
+	}
+	return out
+}
+
+// formatCell renders element row of s for display
+func formatCell(s *types.Series, row, precision int) string {
+	if !s.IsValid(row) {
+		return "null"
+	}
+	switch d := s.Data.(type) {
+	case []float64:
+		return formatFloat(d[row], precision, 64)
+	case []float32:
+		return formatFloat(float64(d[row]), precision, 32)
+	case []string:
+		return d[row]
+	default:
+		return fmt.Sprint(rowValue(s, row))
+	}
+}
+
+func formatFloat(v float64, precision, bitSize int) string {
+	if precision > 0 {
+		return strconv.FormatFloat(v, 'f', precision, bitSize)
+	}
+	out := strconv.FormatFloat(v, 'g', -1, bitSize)
+	if !strings.ContainsAny(out, ".eIN") {
+		out += ".0" // keep floats recognisable next to integers
+	}
+	return out
+}
+
+// truncateCell cuts text to width characters, marking the cut
+func truncateCell(text string, width int) string {
+	if i := strings.IndexAny(text, "\n\r"); i >= 0 {
+		text = text[:i] + ellipsis
+	}
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:width-1]) + ellipsis
+}
@@ -1,15 +1,13 @@
-//go:build !purego
-// +build !purego
-
 package dataframe
 
-import "math"
-
 // radixSortInt64 performs an unsigned radix sort on int64 data and returns a slice
 // of indices that represent the order of the sorted data. The sort is performed
 // in O(64/b) passes where b=8 (one byte per pass) and is stable.
 // Negative numbers are handled by XOR biased conversion to uint64.
 func radixSortInt64(data []int64, ascending bool) []int {
+	if !fastKernels.Load() {
+		return sortInt64Scalar(data, ascending)
+	}
 	n := len(data)
 	if n == 0 {
 		return nil
@@ -30,6 +28,9 @@ func radixSortInt64(data []int64, ascending bool) []int {
 // representation is transformed so that the lexicographic order of the keys
 // matches the numeric order of the floats.
 func radixSortFloat64(data []float64, ascending bool) []int {
+	if !fastKernels.Load() {
+		return sortFloat64Scalar(data, ascending)
+	}
 	n := len(data)
 	if n == 0 {
 		return nil
@@ -37,15 +38,7 @@ func radixSortFloat64(data []float64, ascending bool) []int {
 
 	keys := make([]uint64, n)
 	for i, v := range data {
-		bits := math.Float64bits(v)
-		// For positive floats, flip the sign bit; for negative floats, invert all
-		// bits so that the ordering of the resulting unsigned integers matches
-		// the ordering of the original floats.
-		if bits>>63 == 0 {
-			keys[i] = bits ^ 0x8000000000000000
-		} else {
-			keys[i] = ^bits
-		}
+		keys[i] = floatSortKey(v)
 	}
 
 	return radixSortUint64Keys(keys, ascending)
@@ -1,11 +1,6 @@
-//go:build !purego
-// +build !purego
-
 package dataframe
 
-import (
-	"runtime"
-)
+import "go-polars/types"
 
 // helper types for k-way merge
 type radixNode struct {
@@ -51,10 +46,12 @@ func ParallelRadixSortUint64(keys []uint64, ascending bool) []int {
 		return idx
 	}
 
-	workers := runtime.GOMAXPROCS(0)
-	if workers < 2 || n < 1<<15 { // fall back to serial for small workloads
+	workers := types.MaxThreads()
+	if workers < 2 || n < 1<<15 || !fastKernels.Load() { // fall back to serial for small workloads and scalar kernels
+		logDecision("radix sort", "path", "serial", "rows", n, "workers", workers, "scalar_kernels", !fastKernels.Load())
 		return radixSortUint64Keys(keys, ascending)
 	}
+	logDecision("radix sort", "path", "parallel", "rows", n, "workers", workers)
 
 	// Use new in-place parallel radix implementation
 	return radixSortUint64KeysParallel(keys, ascending)
@@ -0,0 +1,42 @@
+package dataframe
+
+import "sort"
+
+// sortInt64Scalar is the comparison sort radixSortInt64 falls back to with
+// scalar kernels. It is stable too, so both give the same order.
+func sortInt64Scalar(data []int64, ascending bool) []int {
+	indices := make([]int, len(data))
+	for i := range indices {
+		indices[i] = i
+	}
+
+	sort.SliceStable(indices, func(i, j int) bool {
+		if ascending {
+			return data[indices[i]] < data[indices[j]]
+		}
+		return data[indices[i]] > data[indices[j]]
+	})
+
+	return indices
+}
+
+// sortFloat64Scalar is the float64 counterpart of sortInt64Scalar. It
+// compares the keys of floatSortKey, as the radix sort does, so NaNs and
+// signed zeros land in the same places.
+func sortFloat64Scalar(data []float64, ascending bool) []int {
+	indices := make([]int, len(data))
+	keys := make([]uint64, len(data))
+	for i, v := range data {
+		indices[i] = i
+		keys[i] = floatSortKey(v)
+	}
+
+	sort.SliceStable(indices, func(i, j int) bool {
+		if ascending {
+			return keys[indices[i]] < keys[indices[j]]
+		}
+		return keys[indices[i]] > keys[indices[j]]
+	})
+
+	return indices
+}
@@ -0,0 +1,61 @@
+package dataframe
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRadixSortFloat64Kernels(t *testing.T) {
+	// fastKernels is set directly so both paths run without AVX2.
+	defer func(fast bool) { fastKernels.Store(fast) }(fastKernels.Load())
+
+	nan := math.NaN()
+	small := []float64{nan, 1, math.Copysign(0, -1), math.Inf(1), 0, nan, -2,
+		math.Inf(-1), 1, -math.NaN(), 0.5, nan}
+	large := make([]float64, 1<<16)
+	r := rand.New(rand.NewSource(1))
+	for i := range large {
+		switch r.Intn(8) {
+		case 0:
+			large[i] = nan
+		case 1:
+			large[i] = math.Copysign(0, -1)
+		default:
+			large[i] = float64(r.Intn(100) - 50)
+		}
+	}
+
+	for _, data := range [][]float64{small, large} {
+		df, err := FromColumns([]*types.Series{
+			types.NewSeries("v", data),
+			types.NewSeries("i", intRange(len(data))),
+		})
+		assert.NoError(t, err)
+		for _, ascending := range []bool{true, false} {
+			perms := make([][]int, 2)
+			rows := make([]interface{}, 2)
+			for k, fast := range []bool{false, true} {
+				fastKernels.Store(fast)
+				perms[k] = radixSortFloat64(data, ascending)
+				sorted, err := df.SortByColumn("v", ascending)
+				assert.NoError(t, err)
+				rows[k] = sorted.series["i"].Data
+			}
+			assert.Equal(t, perms[0], perms[1], "n=%d ascending=%v", len(data), ascending)
+			assert.Equal(t, rows[0], rows[1], "n=%d ascending=%v", len(data), ascending)
+		}
+	}
+}
+
+func intRange(n int) []int64 {
+	out := make([]int64, n)
+	for i := range out {
+		out[i] = int64(i)
+	}
+	return out
+}
@@ -1,11 +1,9 @@
-//go:build !purego
-// +build !purego
-
 package dataframe
 
 import (
-	"runtime"
 	"sync"
+
+	"go-polars/types"
 )
 
 // radixSortUint64KeysParallel performs an in-place, stable LSD radix sort on keys and
@@ -27,10 +25,19 @@ func radixSortUint64KeysParallel(keys []uint64, ascending bool) []int {
 		passes      = 64 / bitsPerPass
 	)
 
-	workers := runtime.GOMAXPROCS(0)
+	workers := types.MaxThreads()
 	if workers < 2 || n < 1<<15 {
 		return radixSortUint64Keys(keys, ascending)
 	}
+	if !ascending {
+		// Sort the complemented keys ascending; reversing an ascending sort
+		// would also reverse the order of equal keys.
+		flipped := make([]uint64, n)
+		for i, k := range keys {
+			flipped[i] = ^k
+		}
+		keys = flipped
+	}
 
 	// Initial index slice 0..n-1
 	indices := make([]int, n)
@@ -143,12 +150,5 @@ func radixSortUint64KeysParallel(keys []uint64, ascending bool) []int {
 		indices, tmp = tmp, indices
 	}
 
-	if !ascending {
-		// reverse indices
-		for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
-			indices[i], indices[j] = indices[j], indices[i]
-		}
-	}
-
 	return indices
 }
@@ -0,0 +1,129 @@
+package dataframe
+
+import (
+	"fmt"
+	"time"
+
+	"go-polars/types"
+)
+
+// FromRecords builds a DataFrame from rows given as maps from column name to
+// value, with one column per key found in any row; a key missing from a
+// row gives a null, like a nil value, unless WithDefaults gives the column
+// a default, which also adds columns no row has. See FromValues for how
+// values and schema are handled.
+func FromRecords(records []map[string]any, schema map[string]types.DataType, opts ...BuildOption) (*DataFrame, error) {
+	cfg := buildOptions(opts)
+	columns := make(map[string][]any)
+	for i, record := range records {
+		for name, v := range record {
+			col, ok := columns[name]
+			if !ok {
+				col = make([]any, len(records))
+				columns[name] = col
+			}
+			col[i] = v
+		}
+	}
+	for name, d := range cfg.defaults {
+		col, ok := columns[name]
+		if !ok {
+			col = make([]any, len(records))
+			columns[name] = col
+		}
+		for i, record := range records {
+			if _, present := record[name]; !present {
+				col[i] = d.at(i)
+			}
+		}
+	}
+	return fromValues(columns, schema)
+}
+
+// FromValues builds a DataFrame from columns of Go values, which must all
+// have the same length. Columns are ordered by name, as with New.
+//
+// Without a schema entry a column's type follows its values: integers of
+// any Go type give Int64, and Float64 when floats of either size are mixed
+// in; strings give String, bools Boolean and time.Time values their
+// RFC 3339 text. A nil value is null, and a column of nils becomes a String
+// column of nulls. schema, which may be nil, overrides the type of the
+// columns it names by casting them strictly; time.Time values become Unix
+// nanoseconds when cast to an integer type.
+func FromValues(columns map[string][]any, schema map[string]types.DataType) (*DataFrame, error) {
+	length := -1
+	for name, values := range columns {
+		if length >= 0 && len(values) != length {
+			return nil, fmt.Errorf("column %s has length %d, expected %d", name, len(values), length)
+		}
+		length = len(values)
+	}
+	return fromValues(columns, schema)
+}
+
+func fromValues(columns map[string][]any, schema map[string]types.DataType) (*DataFrame, error) {
+	for name := range schema {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("schema names column %s, which is not in the data", name)
+		}
+	}
+	series := make(map[string]*types.Series, len(columns))
+	for name, values := range columns {
+		s, err := seriesFromNative(name, values, schema[name])
+		if err != nil {
+			return nil, err
+		}
+		series[name] = s
+	}
+	return New(series)
+}
+
+// seriesFromNative builds a column from Go values of any numeric type,
+// strings, bools and times, cast to dtype unless it is nil.
+func seriesFromNative(name string, values []any, dtype types.DataType) (*types.Series, error) {
+	timesAsInts := dtype != nil && types.IsInteger(dtype)
+	normalized := make([]interface{}, len(values))
+	for i, v := range values {
+		switch x := v.(type) {
+		case int:
+			normalized[i] = int64(x)
+		case int8:
+			normalized[i] = int64(x)
+		case int16:
+			normalized[i] = int64(x)
+		case int32:
+			normalized[i] = int64(x)
+		case uint8:
+			normalized[i] = int64(x)
+		case uint16:
+			normalized[i] = int64(x)
+		case uint32:
+			normalized[i] = int64(x)
+		case uint:
+			if uint64(x) > 1<<63-1 {
+				return nil, fmt.Errorf("column %s: value %d at row %d overflows Int64", name, x, i)
+			}
+			normalized[i] = int64(x)
+		case uint64:
+			if x > 1<<63-1 {
+				return nil, fmt.Errorf("column %s: value %d at row %d overflows Int64", name, x, i)
+			}
+			normalized[i] = int64(x)
+		case float32:
+			normalized[i] = float64(x)
+		case time.Time:
+			if timesAsInts {
+				normalized[i] = x.UnixNano()
+			} else {
+				normalized[i] = x.Format(time.RFC3339Nano)
+			}
+		default:
+			normalized[i] = v
+		}
+	}
+	s, err := seriesFromValues(name, normalized)
+	if err != nil || dtype == nil {
+		return s, err
+	}
+	return s.Cast(dtype, true)
+}
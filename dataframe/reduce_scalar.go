@@ -1,10 +1,7 @@
-//go:build !simd
-// +build !simd
-
 package dataframe
 
-// sumInt64Indexed returns the sum of values at given indices.
-func sumInt64Indexed(data []int64, idx []int) int64 {
+// sumInt64IndexedScalar returns the sum of values at given indices.
+func sumInt64IndexedScalar(data []int64, idx []int) int64 {
 	var sum int64
 	for _, i := range idx {
 		sum += data[i]
@@ -12,8 +9,8 @@ func sumInt64Indexed(data []int64, idx []int) int64 {
 	return sum
 }
 
-// minInt64Indexed returns minimum value across indices.
-func minInt64Indexed(data []int64, idx []int) int64 {
+// minInt64IndexedScalar returns minimum value across indices.
+func minInt64IndexedScalar(data []int64, idx []int) int64 {
 	if len(idx) == 0 {
 		return 0
 	}
@@ -26,8 +23,8 @@ func minInt64Indexed(data []int64, idx []int) int64 {
 	return min
 }
 
-// maxInt64Indexed returns maximum value across indices.
-func maxInt64Indexed(data []int64, idx []int) int64 {
+// maxInt64IndexedScalar returns maximum value across indices.
+func maxInt64IndexedScalar(data []int64, idx []int) int64 {
 	if len(idx) == 0 {
 		return 0
 	}
@@ -40,8 +37,8 @@ func maxInt64Indexed(data []int64, idx []int) int64 {
 	return max
 }
 
-// sumFloat64Indexed returns the sum of float64 values.
-func sumFloat64Indexed(data []float64, idx []int) float64 {
+// sumFloat64IndexedScalar returns the sum of float64 values.
+func sumFloat64IndexedScalar(data []float64, idx []int) float64 {
 	var sum float64
 	for _, i := range idx {
 		sum += data[i]
@@ -49,8 +46,8 @@ func sumFloat64Indexed(data []float64, idx []int) float64 {
 	return sum
 }
 
-// minFloat64Indexed returns minimum float.
-func minFloat64Indexed(data []float64, idx []int) float64 {
+// minFloat64IndexedScalar returns minimum float.
+func minFloat64IndexedScalar(data []float64, idx []int) float64 {
 	if len(idx) == 0 {
 		return 0
 	}
@@ -63,8 +60,8 @@ func minFloat64Indexed(data []float64, idx []int) float64 {
 	return min
 }
 
-// maxFloat64Indexed returns maximum float.
-func maxFloat64Indexed(data []float64, idx []int) float64 {
+// maxFloat64IndexedScalar returns maximum float.
+func maxFloat64IndexedScalar(data []float64, idx []int) float64 {
 	if len(idx) == 0 {
 		return 0
 	}
@@ -1,12 +1,9 @@
-//go:build simd
-// +build simd
-
 package dataframe
 
 import "unsafe"
 
-// sumInt64Indexed processes four indices per iteration to leverage ILP/SIMD
-func sumInt64Indexed(data []int64, idx []int) int64 {
+// sumInt64IndexedUnrolled processes four indices per iteration to leverage ILP/SIMD
+func sumInt64IndexedUnrolled(data []int64, idx []int) int64 {
 	var s0, s1, s2, s3 int64
 	n := len(idx)
 	i := 0
@@ -24,7 +21,7 @@ func sumInt64Indexed(data []int64, idx []int) int64 {
 	return (s0 + s1) + (s2 + s3) + tail
 }
 
-func minInt64Indexed(data []int64, idx []int) int64 {
+func minInt64IndexedUnrolled(data []int64, idx []int) int64 {
 	if len(idx) == 0 {
 		return 0
 	}
@@ -69,7 +66,7 @@ func minInt64Indexed(data []int64, idx []int) int64 {
 	return min0
 }
 
-func maxInt64Indexed(data []int64, idx []int) int64 {
+func maxInt64IndexedUnrolled(data []int64, idx []int) int64 {
 	if len(idx) == 0 {
 		return 0
 	}
@@ -115,7 +112,7 @@ func maxInt64Indexed(data []int64, idx []int) int64 {
 }
 
 // Float64 helpers.
-func sumFloat64Indexed(data []float64, idx []int) float64 {
+func sumFloat64IndexedUnrolled(data []float64, idx []int) float64 {
 	var s0, s1, s2, s3 float64
 	n := len(idx)
 	i := 0
@@ -133,7 +130,7 @@ func sumFloat64Indexed(data []float64, idx []int) float64 {
 	return (s0 + s1) + (s2 + s3) + tail
 }
 
-func minFloat64Indexed(data []float64, idx []int) float64 {
+func minFloat64IndexedUnrolled(data []float64, idx []int) float64 {
 	if len(idx) == 0 {
 		return 0
 	}
@@ -178,7 +175,7 @@ func minFloat64Indexed(data []float64, idx []int) float64 {
 	return min0
 }
 
-func maxFloat64Indexed(data []float64, idx []int) float64 {
+func maxFloat64IndexedUnrolled(data []float64, idx []int) float64 {
 	if len(idx) == 0 {
 		return 0
 	}
@@ -0,0 +1,169 @@
+package dataframe
+
+import (
+	"fmt"
+
+	"go-polars/types"
+)
+
+// FromColumns builds a DataFrame from Series keyed by their names, keeping
+// the order of columns, e.g. as returned by Series.Reshape.
+func FromColumns(columns []*types.Series) (*DataFrame, error) {
+	series := make(map[string]*types.Series, len(columns))
+	order := make([]string, len(columns))
+	for i, s := range columns {
+		if _, dup := series[s.Name]; dup {
+			return nil, fmt.Errorf("duplicate column %s", s.Name)
+		}
+		series[s.Name] = s
+		order[i] = s.Name
+	}
+	return newOrdered(series, order)
+}
+
+// ToSeries flattens the DataFrame row-major into a single Series named
+// "values", the inverse of Series.Reshape. Columns are read in the given
+// order, or in the frame's column order when none are given. All columns
+// must share one type.
+func (df *DataFrame) ToSeries(columns ...string) (*types.Series, error) {
+	if len(columns) == 0 {
+		columns = df.Columns()
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("cannot flatten a DataFrame without columns")
+	}
+
+	parts := make([]*types.Series, len(columns))
+	for i, col := range columns {
+		s, ok := df.series[col]
+		if !ok {
+			return nil, fmt.Errorf("column %s not found", col)
+		}
+		parts[i] = s
+	}
+	flat, err := concatSeries(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Element (r, c) sits at c*length+r in the concatenation.
+	order := make([]int, 0, flat.Length)
+	for r := 0; r < df.length; r++ {
+		for c := range parts {
+			order = append(order, c*df.length+r)
+		}
+	}
+	out := flat.Take(order)
+	out.Name = "values"
+	return out, nil
+}
+
+// Pivot reshapes the DataFrame from long to wide: it keeps one row per
+// distinct value of index and adds one column per distinct value of columns,
+// named after that value ("null" for nulls), holding agg of values over the
+// rows sharing both. Rows and new columns appear in order of first
+// appearance; cells without rows are null. Quantile is not supported.
+func (df *DataFrame) Pivot(index, columns, values string, agg AggregationType) (out *DataFrame, err error) {
+	defer df.track("Pivot", map[string]interface{}{"index": index, "columns": columns, "values": values, "agg": agg.String()})(&out, &err)
+	if agg == Quantile {
+		return nil, fmt.Errorf("pivot does not support quantile aggregation")
+	}
+	vs, ok := df.series[values]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", values)
+	}
+	vs, err = aggInput(vs, agg)
+	if err != nil {
+		return nil, err
+	}
+	rowIDs, nRows, err := df.partition([]string{index}, true)
+	if err != nil {
+		return nil, err
+	}
+	colIDs, nCols, err := df.partition([]string{columns}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	reps := make([]int, nRows)
+	for i := len(rowIDs) - 1; i >= 0; i-- {
+		reps[rowIDs[i]] = i
+	}
+	names := make([]string, nCols)
+	seen := make([]bool, nCols)
+	cs := df.series[columns]
+	for i, id := range colIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if v := rowValue(cs, i); v != nil {
+			names[id] = fmt.Sprint(v)
+		} else {
+			names[id] = "null"
+		}
+	}
+
+	series := map[string]*types.Series{index: df.series[index].Take(reps)}
+	order := []string{index}
+	for _, name := range names {
+		if _, dup := series[name]; dup {
+			return nil, fmt.Errorf("pivot column %s clashes with another column", name)
+		}
+		series[name] = nil
+		order = append(order, name)
+	}
+	spec := aggSpec{kind: agg, q: 0.5}
+	var cells []*types.Series
+	switch data := vs.Data.(type) {
+	case []int64:
+		cells = pivotCells(data, vs.Validity, rowIDs, colIDs, names, nRows, spec)
+	case []float64:
+		cells = pivotCells(data, vs.Validity, rowIDs, colIDs, names, nRows, spec)
+	default:
+		return nil, fmt.Errorf("unsupported data type for aggregation")
+	}
+	for _, s := range cells {
+		series[s.Name] = s
+	}
+	return newOrdered(series, order)
+}
+
+// pivotCells aggregates data per (row, column) cell and returns one Series
+// per pivot column, null where a cell received no rows.
+func pivotCells[T int64 | float64](data []T, valid *types.Bitmap, rowIDs, colIDs []int, names []string, nRows int, spec aggSpec) []*types.Series {
+	states := make([][]*aggState[T], len(names))
+	for c := range states {
+		states[c] = make([]*aggState[T], nRows)
+	}
+	for i, v := range data {
+		st := states[colIDs[i]][rowIDs[i]]
+		if st == nil {
+			st = &aggState[T]{rep: i}
+			states[colIDs[i]][rowIDs[i]] = st
+		}
+		if valid != nil && !valid.Get(i) {
+			st.addNull(i, spec.kind)
+			continue
+		}
+		st.add(i, v, spec.kind)
+	}
+
+	out := make([]*types.Series, len(names))
+	for c, name := range names {
+		present := make([]bool, nRows)
+		for r, st := range states[c] {
+			if st == nil {
+				states[c][r] = &aggState[T]{}
+				continue
+			}
+			present[r] = true
+		}
+		s := aggColumn(name, states[c], spec)
+		for r := range present {
+			present[r] = present[r] && s.IsValid(r)
+		}
+		out[c] = types.NewNullableSeries(name, s.Data, present)
+	}
+	return out
+}
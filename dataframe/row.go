@@ -0,0 +1,343 @@
+package dataframe
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+	"sync"
+
+	"go-polars/types"
+)
+
+// Row gives read access to every column of one row of a DataFrame without
+// copying it. Rows passed to callbacks, returned by DataFrame.Row or
+// yielded by a RowIterator stay valid as long as their DataFrame.
+type Row struct {
+	df  *DataFrame
+	idx int
+}
+
+// Row returns row i of the DataFrame
+func (df *DataFrame) Row(i int) (Row, error) {
+	if i < 0 || i >= df.length {
+		return Row{}, fmt.Errorf("row %d out of range for %d rows", i, df.length)
+	}
+	return Row{df: df, idx: i}, nil
+}
+
+// RowIterator steps through the rows of a DataFrame in order:
+//
+//	it := df.Iterator()
+//	for it.Next() {
+//		row := it.Row()
+//	}
+type RowIterator struct {
+	df   *DataFrame
+	next int
+}
+
+// Iterator returns a RowIterator positioned before the first row
+func (df *DataFrame) Iterator() *RowIterator {
+	return &RowIterator{df: df}
+}
+
+// Next advances to the next row, reporting false once past the last one
+func (it *RowIterator) Next() bool {
+	if it.next >= it.df.length {
+		return false
+	}
+	it.next++
+	return true
+}
+
+// Row returns the current row
+func (it *RowIterator) Row() Row {
+	return Row{df: it.df, idx: it.next - 1}
+}
+
+// Rows iterates over the positions and rows of the DataFrame in order, for
+// use with range.
+func (df *DataFrame) Rows() iter.Seq2[int, Row] {
+	return func(yield func(int, Row) bool) {
+		for i := 0; i < df.length; i++ {
+			if !yield(i, Row{df: df, idx: i}) {
+				return
+			}
+		}
+	}
+}
+
+// Map returns the values of the row keyed by column name, nil for nulls,
+// with the element types of Get.
+func (r Row) Map() map[string]interface{} {
+	out := make(map[string]interface{}, len(r.df.order))
+	for _, name := range r.df.order {
+		out[name] = rowValue(r.df.series[name], r.idx)
+	}
+	return out
+}
+
+// Index returns the position of the row in its DataFrame
+func (r Row) Index() int {
+	return r.idx
+}
+
+// Get returns the value of column in the row with the column's element type
+// (string for Categorical columns), or nil when the value is null or the
+// column does not exist.
+func (r Row) Get(column string) interface{} {
+	s, ok := r.df.series[column]
+	if !ok {
+		return nil
+	}
+	return rowValue(s, r.idx)
+}
+
+// IsNull reports whether column is null, or missing, in the row
+func (r Row) IsNull(column string) bool {
+	s, ok := r.df.series[column]
+	return !ok || !s.IsValid(r.idx)
+}
+
+// Int64 returns the value of an integer column as int64. The boolean is
+// false when the value is null or the column is missing or not an integer
+// column.
+func (r Row) Int64(column string) (int64, bool) {
+	switch v := r.Get(column).(type) {
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= 1<<63-1
+	default:
+		return 0, false
+	}
+}
+
+// Float64 returns the value of a numeric column as float64. The boolean is
+// false when the value is null or the column is missing or not numeric.
+func (r Row) Float64(column string) (float64, bool) {
+	switch v := r.Get(column).(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		i, ok := r.Int64(column)
+		return float64(i), ok
+	}
+}
+
+// String returns the value of a String or Categorical column. The boolean
+// is false when the value is null or the column is missing or of another
+// type.
+func (r Row) String(column string) (string, bool) {
+	v, ok := r.Get(column).(string)
+	return v, ok
+}
+
+// Bool returns the value of a Boolean column. The boolean is false when the
+// value is null or the column is missing or of another type.
+func (r Row) Bool(column string) (value, ok bool) {
+	value, ok = r.Get(column).(bool)
+	return value, ok
+}
+
+// rowValue returns element row of s, or nil when it is null.
+func rowValue(s *types.Series, row int) interface{} {
+	if !s.IsValid(row) {
+		return nil
+	}
+	switch d := s.Data.(type) {
+	case *types.Categorical:
+		return d.Value(row)
+	case [][]int64:
+		return d[row]
+	case [][]float64:
+		return d[row]
+	case [][]string:
+		return d[row]
+	case [][]bool:
+		return d[row]
+	default:
+		return sqlValue(s, row)
+	}
+}
+
+// FilterRows keeps the rows for which fn returns true. Unlike Filter, fn
+// sees every column of the row, nulls included, so predicates can combine
+// several columns.
+func (df *DataFrame) FilterRows(fn func(Row) bool) (out *DataFrame, err error) {
+	defer df.track("FilterRows", nil)(&out, &err)
+	keep := make([]int, 0)
+	for i := 0; i < df.length; i++ {
+		if fn(Row{df: df, idx: i}) {
+			keep = append(keep, i)
+		}
+	}
+	return df.take(keep)
+}
+
+// FilterRowsParallel is FilterRows evaluating fn on chunks of chunkSize rows
+// with up to types.MaxThreads goroutines. fn must be safe for concurrent use; the
+// kept rows stay in their original order.
+func (df *DataFrame) FilterRowsParallel(fn func(Row) bool, chunkSize int) (out *DataFrame, err error) {
+	defer df.track("FilterRowsParallel", map[string]interface{}{"chunk_size": chunkSize})(&out, &err)
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", chunkSize)
+	}
+	mask := make([]bool, df.length)
+	chunks := (df.length + chunkSize - 1) / chunkSize
+	next := make(chan int)
+	var wg sync.WaitGroup
+	workers := types.MaxThreads()
+	if workers > chunks {
+		workers = chunks
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range next {
+				end := min((c+1)*chunkSize, df.length)
+				for i := c * chunkSize; i < end; i++ {
+					mask[i] = fn(Row{df: df, idx: i})
+				}
+			}
+		}()
+	}
+	for c := 0; c < chunks; c++ {
+		next <- c
+	}
+	close(next)
+	wg.Wait()
+
+	keep := make([]int, 0)
+	for i, m := range mask {
+		if m {
+			keep = append(keep, i)
+		}
+	}
+	return df.take(keep)
+}
+
+// WithColumnsFn calls fn once per row and adds every key of the returned
+// maps as a column, replacing existing columns of that name; new columns are
+// appended in name order. Values may be int, int64, float64, string or bool,
+// with ints widened to Float64 when a column also holds floats. A nil value,
+// or a key missing from a row's map, gives a null.
+func (df *DataFrame) WithColumnsFn(fn func(Row) map[string]interface{}) (out *DataFrame, err error) {
+	defer df.track("WithColumnsFn", nil)(&out, &err)
+	values := make(map[string][]interface{})
+	for i := 0; i < df.length; i++ {
+		for name, v := range fn(Row{df: df, idx: i}) {
+			col, ok := values[name]
+			if !ok {
+				col = make([]interface{}, df.length)
+				values[name] = col
+			}
+			col[i] = v
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	series := make([]*types.Series, len(names))
+	for i, name := range names {
+		if series[i], err = seriesFromValues(name, values[name]); err != nil {
+			return nil, err
+		}
+	}
+	return df.withColumns(series...)
+}
+
+// seriesFromValues builds a Series from Go values of a single kind, nil
+// standing for null. A column whose values are all nil becomes a String
+// column of nulls.
+func seriesFromValues(name string, values []interface{}) (*types.Series, error) {
+	var kind types.DataType = types.StringType{}
+	seen := false
+	valid := make([]bool, len(values))
+	for i, v := range values {
+		var k types.DataType
+		switch v.(type) {
+		case nil:
+			continue
+		case int, int64:
+			k = types.Int64Type{}
+		case float64:
+			k = types.Float64Type{}
+		case string:
+			k = types.StringType{}
+		case bool:
+			k = types.BooleanType{}
+		default:
+			return nil, fmt.Errorf("column %s: unsupported value type %T", name, v)
+		}
+		valid[i] = true
+		switch {
+		case !seen:
+			kind, seen = k, true
+		case k == kind:
+		case k == types.Float64Type{} && kind == types.Int64Type{}, k == types.Int64Type{} && kind == types.Float64Type{}:
+			kind = types.Float64Type{}
+		default:
+			return nil, fmt.Errorf("column %s mixes %s and %s values", name, kind, k)
+		}
+	}
+
+	switch kind.(type) {
+	case types.Int64Type:
+		data := make([]int64, len(values))
+		for i, v := range values {
+			switch x := v.(type) {
+			case int:
+				data[i] = int64(x)
+			case int64:
+				data[i] = x
+			}
+		}
+		return types.NewNullableSeries(name, data, valid), nil
+	case types.Float64Type:
+		data := make([]float64, len(values))
+		for i, v := range values {
+			switch x := v.(type) {
+			case int:
+				data[i] = float64(x)
+			case int64:
+				data[i] = float64(x)
+			case float64:
+				data[i] = x
+			}
+		}
+		return types.NewNullableSeries(name, data, valid), nil
+	case types.BooleanType:
+		data := make([]bool, len(values))
+		for i, v := range values {
+			data[i], _ = v.(bool)
+		}
+		return types.NewNullableSeries(name, data, valid), nil
+	default:
+		data := make([]string, len(values))
+		for i, v := range values {
+			data[i], _ = v.(string)
+		}
+		return types.NewNullableSeries(name, data, valid), nil
+	}
+}
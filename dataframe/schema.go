@@ -0,0 +1,149 @@
+package dataframe
+
+import (
+	"fmt"
+
+	"go-polars/types"
+)
+
+// SchemaPolicy decides how ConcatFiles treats files whose schema differs
+// from the first file's
+type SchemaPolicy int
+
+const (
+	// SchemaPromote accepts every difference: missing columns become nulls
+	// and differing types are cast to their supertype, falling back to
+	// String when the types have none.
+	SchemaPromote SchemaPolicy = iota
+	// SchemaAddMissing fills missing columns with nulls but rejects type
+	// changes.
+	SchemaAddMissing
+	// SchemaStrict rejects any difference.
+	SchemaStrict
+)
+
+// DeviationKind classifies a SchemaDeviation
+type DeviationKind int
+
+const (
+	// DeviationMissing is a column of the first file absent from this one.
+	DeviationMissing DeviationKind = iota
+	// DeviationExtra is a column absent from the first file.
+	DeviationExtra
+	// DeviationType is a column whose type differs from the first file.
+	DeviationType
+)
+
+// SchemaDeviation describes one way a file's schema differs from the schema
+// of the first file in a scan
+type SchemaDeviation struct {
+	Source   string // file path, or "frame <i>" when no paths are known
+	Column   string
+	Kind     DeviationKind
+	Expected types.DataType // type in the first file; nil for DeviationExtra
+	Got      types.DataType // type in this file; nil for DeviationMissing
+}
+
+func (d SchemaDeviation) String() string {
+	switch d.Kind {
+	case DeviationMissing:
+		return fmt.Sprintf("%s: column %s is missing", d.Source, d.Column)
+	case DeviationExtra:
+		return fmt.Sprintf("%s: unexpected column %s", d.Source, d.Column)
+	default:
+		return fmt.Sprintf("%s: column %s has type %s, expected %s", d.Source, d.Column, d.Got, d.Expected)
+	}
+}
+
+// SchemaOptions controls how multi-file scans reconcile schemas
+type SchemaOptions struct {
+	Policy SchemaPolicy
+	// OnDeviation, when set, is called for every deviation found, including
+	// those that make the scan fail.
+	OnDeviation func(SchemaDeviation)
+}
+
+// ConcatFiles appends frames read from separate files of the same dataset,
+// reconciling their schemas under opts.Policy. Each frame is compared with
+// the first; all deviations are returned, in file order, even when the
+// policy rejects them. paths names the sources in the report and may be nil.
+func ConcatFiles(dfs []*DataFrame, paths []string, opts SchemaOptions) (*DataFrame, []SchemaDeviation, error) {
+	if len(dfs) == 0 {
+		df, err := New(nil)
+		return df, nil, err
+	}
+	if paths != nil && len(paths) != len(dfs) {
+		return nil, nil, fmt.Errorf("got %d paths for %d frames", len(paths), len(dfs))
+	}
+
+	deviations := schemaDeviations(dfs, paths)
+	if opts.OnDeviation != nil {
+		for _, d := range deviations {
+			opts.OnDeviation(d)
+		}
+	}
+	for _, d := range deviations {
+		if opts.Policy == SchemaStrict || (opts.Policy == SchemaAddMissing && d.Kind == DeviationType) {
+			return nil, deviations, fmt.Errorf("schema mismatch: %s (%d deviations)", d, len(deviations))
+		}
+	}
+
+	target := make(map[string]types.DataType)
+	for _, df := range dfs {
+		for name, s := range df.series {
+			t, ok := target[name]
+			if !ok {
+				target[name] = s.DataType
+				continue
+			}
+			super, err := types.Supertype(t, s.DataType)
+			if err != nil {
+				super = types.StringType{}
+			}
+			target[name] = super
+		}
+	}
+
+	reconciled := make([]*DataFrame, len(dfs))
+	for i, df := range dfs {
+		series := make(map[string]*types.Series, len(df.series))
+		for name, s := range df.series {
+			cast, err := s.Cast(target[name], true)
+			if err != nil {
+				return nil, deviations, err
+			}
+			series[name] = cast
+		}
+		reconciled[i] = &DataFrame{series: series, order: df.order, length: df.length}
+	}
+	out, err := Concat(reconciled, HowDiagonal)
+	return out, deviations, err
+}
+
+func schemaDeviations(dfs []*DataFrame, paths []string) []SchemaDeviation {
+	first := dfs[0]
+	firstNames := first.Columns()
+	var deviations []SchemaDeviation
+	for i, df := range dfs[1:] {
+		source := fmt.Sprintf("frame %d", i+1)
+		if paths != nil {
+			source = paths[i+1]
+		}
+		for _, name := range firstNames {
+			want := first.series[name].DataType
+			s, ok := df.series[name]
+			switch {
+			case !ok:
+				deviations = append(deviations, SchemaDeviation{Source: source, Column: name, Kind: DeviationMissing, Expected: want})
+			case s.DataType.String() != want.String():
+				deviations = append(deviations, SchemaDeviation{Source: source, Column: name, Kind: DeviationType, Expected: want, Got: s.DataType})
+			}
+		}
+		for _, name := range df.order {
+			if _, ok := first.series[name]; !ok {
+				deviations = append(deviations, SchemaDeviation{Source: source, Column: name, Kind: DeviationExtra, Got: df.series[name].DataType})
+			}
+		}
+	}
+	return deviations
+}
@@ -0,0 +1,77 @@
+package dataframe
+
+import (
+	"fmt"
+	"sort"
+
+	"go-polars/types"
+)
+
+// SessionColumn is the name of the column added by Sessionize
+const SessionColumn = "session_id"
+
+// Sessionize assigns a session ID to every row. Rows sharing the same byCols
+// key are ordered by timeCol, and consecutive events no more than gap apart
+// (in the units of timeCol) belong to the same session. Session IDs are unique
+// across the whole frame and the original row order is preserved. Rows with
+// a null time get a null session ID.
+func (df *DataFrame) Sessionize(byCols []string, timeCol string, gap int64) (out *DataFrame, err error) {
+	defer df.track("Sessionize", map[string]interface{}{"by": byCols, "time": timeCol, "gap": gap})(&out, &err)
+	ts, ok := df.series[timeCol]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", timeCol)
+	}
+	ts, err = ts.Widen()
+	if err != nil {
+		return nil, err
+	}
+	if gap < 0 {
+		return nil, fmt.Errorf("session gap must be non-negative, got %d", gap)
+	}
+
+	// Partition IDs follow the first appearance of each key, which keeps
+	// session numbering deterministic.
+	ids, groups, err := df.partition(byCols, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var less func(a, b int) bool
+	var within func(prev, cur int) bool
+	switch data := ts.Data.(type) {
+	case []int64:
+		less = func(a, b int) bool { return data[a] < data[b] }
+		within = func(prev, cur int) bool { return data[cur]-data[prev] <= gap }
+	case []float64:
+		less = func(a, b int) bool { return data[a] < data[b] }
+		within = func(prev, cur int) bool { return data[cur]-data[prev] <= float64(gap) }
+	default:
+		return nil, fmt.Errorf("unsupported data type for time column %s", timeCol)
+	}
+
+	// Rows without a time belong to no session.
+	sessions := make([]int64, df.length)
+	valid := make([]bool, df.length)
+	var next int64
+	for _, rows := range partitionRows(ids, groups) {
+		timed := rows[:0]
+		for _, row := range rows {
+			if ts.IsValid(row) {
+				timed = append(timed, row)
+			}
+		}
+		if len(timed) == 0 {
+			continue
+		}
+		sort.SliceStable(timed, func(i, j int) bool { return less(timed[i], timed[j]) })
+		for i, row := range timed {
+			if i > 0 && !within(timed[i-1], row) {
+				next++
+			}
+			sessions[row], valid[row] = next, true
+		}
+		next++
+	}
+
+	return df.withColumns(types.NewNullableSeries(SessionColumn, sessions, valid))
+}
@@ -0,0 +1,201 @@
+package dataframe
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go-polars/types"
+)
+
+// sqlColumn accumulates the values of one result column. The column starts
+// with the narrowest type implied by the first non-NULL value and is widened
+// in place (int -> float -> string) when a later value does not fit.
+type sqlColumn struct {
+	name    string
+	kind    jsonKind
+	rows    int
+	valid   []bool
+	ints    []int64
+	floats  []float64
+	bools   []bool
+	strings []string
+}
+
+func sqlKind(v interface{}) jsonKind {
+	switch v.(type) {
+	case int64:
+		return jsonInt
+	case float64:
+		return jsonFloat
+	case bool:
+		return jsonBool
+	default:
+		return jsonString
+	}
+}
+
+func sqlText(v interface{}) string {
+	switch val := v.(type) {
+	case []byte:
+		return string(val)
+	case string:
+		return val
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// widen converts the values collected so far into the storage of kind.
+func (c *sqlColumn) widen(kind jsonKind) {
+	switch {
+	case c.kind == jsonUnknown:
+		// Only NULLs so far: materialise them as zero values.
+		switch kind {
+		case jsonInt:
+			c.ints = make([]int64, c.rows)
+		case jsonFloat:
+			c.floats = make([]float64, c.rows)
+		case jsonBool:
+			c.bools = make([]bool, c.rows)
+		default:
+			c.strings = make([]string, c.rows)
+		}
+	case kind == jsonFloat && c.kind == jsonInt:
+		c.floats = make([]float64, len(c.ints))
+		for i, v := range c.ints {
+			c.floats[i] = float64(v)
+		}
+		c.ints = nil
+	default:
+		c.strings = make([]string, 0, c.rows)
+		switch c.kind {
+		case jsonInt:
+			for _, v := range c.ints {
+				c.strings = append(c.strings, sqlText(v))
+			}
+		case jsonFloat:
+			for _, v := range c.floats {
+				c.strings = append(c.strings, sqlText(v))
+			}
+		case jsonBool:
+			for _, v := range c.bools {
+				c.strings = append(c.strings, sqlText(v))
+			}
+		}
+		c.ints, c.floats, c.bools = nil, nil, nil
+	}
+	c.kind = kind
+}
+
+func (c *sqlColumn) append(v interface{}) {
+	if v != nil {
+		if kind := widenKind(c.kind, sqlKind(v)); kind != c.kind {
+			c.widen(kind)
+		}
+	}
+	c.rows++
+	c.valid = append(c.valid, v != nil)
+
+	switch c.kind {
+	case jsonInt:
+		n, _ := v.(int64)
+		c.ints = append(c.ints, n)
+	case jsonFloat:
+		var f float64
+		switch val := v.(type) {
+		case float64:
+			f = val
+		case int64:
+			f = float64(val)
+		}
+		c.floats = append(c.floats, f)
+	case jsonBool:
+		b, _ := v.(bool)
+		c.bools = append(c.bools, b)
+	case jsonString:
+		var s string
+		if v != nil {
+			s = sqlText(v)
+		}
+		c.strings = append(c.strings, s)
+	}
+}
+
+func (c *sqlColumn) series() *types.Series {
+	switch c.kind {
+	case jsonInt:
+		return types.NewNullableSeries(c.name, c.ints, c.valid)
+	case jsonFloat:
+		return types.NewNullableSeries(c.name, c.floats, c.valid)
+	case jsonBool:
+		return types.NewNullableSeries(c.name, c.bools, c.valid)
+	case jsonString:
+		return types.NewNullableSeries(c.name, c.strings, c.valid)
+	default:
+		// Every value was NULL; there is nothing to infer a type from.
+		return types.NewNullableSeries(c.name, make([]string, c.rows), c.valid)
+	}
+}
+
+// ReadSQL executes query on db and materialises the result set as a
+// DataFrame. Rows are streamed straight into typed column buffers, so only
+// one row of driver values is held at a time. Integer, float and boolean
+// columns keep their type; everything else (text, bytes, timestamps) becomes
+// a string column. SQL NULLs become nulls in the resulting Series.
+func ReadSQL(db *sql.DB, query string, args ...interface{}) (*DataFrame, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]*sqlColumn, len(names))
+	for i, name := range names {
+		columns[i] = &sqlColumn{name: name}
+	}
+
+	values := make([]interface{}, len(names))
+	dest := make([]interface{}, len(names))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	row := 0
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, newParseError(row, 0, -1, "", "", err)
+		}
+		row++
+		for i, v := range values {
+			columns[i].append(v)
+			values[i] = nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newParseError(row, 0, -1, "", "", err)
+	}
+
+	series := make(map[string]*types.Series, len(columns))
+	for _, c := range columns {
+		if _, dup := series[c.name]; dup {
+			return nil, fmt.Errorf("duplicate column %s in result set", c.name)
+		}
+		series[c.name] = c.series()
+	}
+	return newOrdered(series, names)
+}
@@ -0,0 +1,210 @@
+package dataframe
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-polars/types"
+)
+
+// SQLDialect selects identifier quoting and placeholder syntax for WriteSQL
+type SQLDialect int
+
+const (
+	// DialectGeneric quotes identifiers with double quotes and uses ?
+	// placeholders (SQLite and most ANSI databases).
+	DialectGeneric SQLDialect = iota
+	// DialectPostgres uses $1, $2, ... placeholders.
+	DialectPostgres
+	// DialectMySQL quotes identifiers with backticks.
+	DialectMySQL
+)
+
+// maxSQLParams keeps a single INSERT under the bind-parameter limit shared by
+// PostgreSQL and recent SQLite builds.
+const maxSQLParams = 65535
+
+type sqlWriteConfig struct {
+	dialect   SQLDialect
+	batchSize int
+	create    bool
+}
+
+// SQLOption configures WriteSQL
+type SQLOption func(*sqlWriteConfig)
+
+// WithDialect selects the SQL dialect. Defaults to DialectGeneric.
+func WithDialect(d SQLDialect) SQLOption {
+	return func(c *sqlWriteConfig) { c.dialect = d }
+}
+
+// WithBatchSize sets the number of rows sent per multi-row INSERT. Defaults
+// to 500 and is capped so a statement never exceeds the parameter limit.
+func WithBatchSize(n int) SQLOption {
+	return func(c *sqlWriteConfig) { c.batchSize = n }
+}
+
+// WithCreateTable controls whether WriteSQL issues CREATE TABLE IF NOT EXISTS
+// before inserting. Enabled by default.
+func WithCreateTable(create bool) SQLOption {
+	return func(c *sqlWriteConfig) { c.create = create }
+}
+
+func (d SQLDialect) quote(ident string) string {
+	if d == DialectMySQL {
+		return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+func (d SQLDialect) placeholder(n int) string {
+	if d == DialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// sqlType maps a Series' backing slice onto a portable column type.
+func sqlType(data interface{}) (string, error) {
+	switch data.(type) {
+	case []int8, []int16, []uint8:
+		return "SMALLINT", nil
+	case []int32, []uint16:
+		return "INTEGER", nil
+	case []int64, []uint32:
+		return "BIGINT", nil
+	case []uint64:
+		return "NUMERIC(20)", nil
+	case []float64:
+		return "DOUBLE PRECISION", nil
+	case []float32:
+		return "REAL", nil
+	case []string, *types.Categorical:
+		return "TEXT", nil
+	case []bool:
+		return "BOOLEAN", nil
+	default:
+		return "", fmt.Errorf("unsupported data type %T for sql", data)
+	}
+}
+
+// WriteSQL bulk-inserts the DataFrame into table, creating the table first
+// if it does not exist. All rows are written inside a single transaction
+// using multi-row INSERT statements.
+func (df *DataFrame) WriteSQL(db *sql.DB, table string, opts ...SQLOption) error {
+	cfg := sqlWriteConfig{batchSize: 500, create: true}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.batchSize <= 0 {
+		return fmt.Errorf("batch size must be positive, got %d", cfg.batchSize)
+	}
+
+	columns := df.Columns()
+	if len(columns) == 0 {
+		return fmt.Errorf("cannot write a DataFrame without columns")
+	}
+	// Every column is checked even when the table exists, as sqlValue has
+	// no value for the others.
+	quoted := make([]string, len(columns))
+	defs := make([]string, len(columns))
+	for i, name := range columns {
+		quoted[i] = cfg.dialect.quote(name)
+		typ, err := sqlType(df.series[name].Data)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", name, err)
+		}
+		defs[i] = quoted[i] + " " + typ
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if cfg.create {
+		ddl := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", cfg.dialect.quote(table), strings.Join(defs, ", "))
+		if _, err := tx.Exec(ddl); err != nil {
+			return err
+		}
+	}
+
+	batch := cfg.batchSize
+	if limit := maxSQLParams / len(columns); batch > limit {
+		batch = limit
+	}
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", cfg.dialect.quote(table), strings.Join(quoted, ", "))
+
+	var sb strings.Builder
+	args := make([]interface{}, 0, batch*len(columns))
+	for start := 0; start < df.length; start += batch {
+		end := start + batch
+		if end > df.length {
+			end = df.length
+		}
+
+		sb.Reset()
+		sb.WriteString(prefix)
+		args = args[:0]
+		for row := start; row < end; row++ {
+			if row > start {
+				sb.WriteString(", ")
+			}
+			sb.WriteByte('(')
+			for i, name := range columns {
+				if i > 0 {
+					sb.WriteString(", ")
+				}
+				args = append(args, sqlValue(df.series[name], row))
+				sb.WriteString(cfg.dialect.placeholder(len(args)))
+			}
+			sb.WriteByte(')')
+		}
+
+		if _, err := tx.Exec(sb.String(), args...); err != nil {
+			return fmt.Errorf("insert rows %d-%d: %w", start, end-1, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// sqlValue returns the driver argument for one element; nulls map to NULL.
+func sqlValue(s *types.Series, row int) interface{} {
+	if !s.IsValid(row) {
+		return nil
+	}
+	switch d := s.Data.(type) {
+	case []int64:
+		return d[row]
+	case []float64:
+		return d[row]
+	case []float32:
+		return d[row]
+	case []string:
+		return d[row]
+	case *types.Categorical:
+		return d.Value(row)
+	case []bool:
+		return d[row]
+	case []int8:
+		return d[row]
+	case []int16:
+		return d[row]
+	case []int32:
+		return d[row]
+	case []uint8:
+		return d[row]
+	case []uint16:
+		return d[row]
+	case []uint32:
+		return d[row]
+	case []uint64:
+		return d[row]
+	default:
+		return nil
+	}
+}
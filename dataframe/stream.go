@@ -0,0 +1,972 @@
+package dataframe
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go-polars/types"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// StreamOptions controls LazyFrame.CollectStreaming
+type StreamOptions struct {
+	// BatchSize is the number of rows read from a source at a time.
+	// Defaults to GOPOLARS_STREAMING_CHUNK_SIZE, or 65536 when unset.
+	BatchSize int
+	// MemoryBudget is the number of bytes of rows a sort or aggregation
+	// gathers before it spills them to disk. Zero means no limit.
+	MemoryBudget int64
+	// SpillDir is the directory holding spill files, which are removed
+	// before CollectStreaming returns. Defaults to os.TempDir().
+	SpillDir string
+}
+
+const defaultBatchSize = 65536
+
+// spillPartitions is the number of hash partitions an aggregation spills
+// its input into.
+const spillPartitions = 16
+
+// ScanCSV starts a lazy query reading the CSV file at path, which may be
+// gzip-compressed but not a glob. Collect reads it like ReadCSV; see
+// CollectStreaming for reading it in batches.
+func ScanCSV(path string, opts CSVOptions) *LazyFrame {
+	return &LazyFrame{plan: &plan{kind: planScan, src: &csvSource{path: path, opts: opts}}}
+}
+
+// ScanParquet starts a lazy query reading the Parquet file at path. Only
+// the columns the query reads are decoded.
+func ScanParquet(path string) *LazyFrame {
+	return &LazyFrame{plan: &plan{kind: planScan, src: &parquetSource{path: path}}}
+}
+
+// CollectStreaming executes the query like Collect, but reads the scanned
+// files and frames opts.BatchSize rows at a time. Select, Filter, WithColumn
+// and the left side of inner and left joins handle one batch at a time, so
+// only the rows they keep are ever held together. Sort and GroupBy gather
+// their input until it exceeds opts.MemoryBudget and then spill it to disk:
+// a sort as sorted runs that are merged at the end, an aggregation as hash
+// partitions of its input that are aggregated one by one, so a single
+// partition must fit in memory. The right side of a join, right and outer
+// joins, and expressions that look beyond their row, such as aggregations,
+// windows and shifts, are computed in memory. Spilled rows must hold
+// Arrow-compatible columns. The column types of a CSV file are inferred
+// from its first batch; later values that do not parse as that type fail
+// with a *ParseError.
+func (lf *LazyFrame) CollectStreaming(opts StreamOptions) (out *DataFrame, err error) {
+	if opts.BatchSize < 0 || opts.MemoryBudget < 0 {
+		return nil, fmt.Errorf("batch size and memory budget must not be negative")
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = streamingChunkSize
+	}
+	if _, err := lf.plan.schema(); err != nil {
+		return nil, err
+	}
+	s := &streamer{opts: opts}
+	defer func() {
+		if cerr := s.cleanup(); err == nil && cerr != nil {
+			out, err = nil, cerr
+		}
+	}()
+	return s.collect(optimize(lf.plan))
+}
+
+// source is a file read by a scan.
+type source interface {
+	fmt.Stringer
+	// columns lists the columns of the file in order.
+	columns() ([]string, error)
+	// read loads the given columns, or all of them when nil, at once.
+	read(columns []string) (*DataFrame, error)
+	// open reads the given columns, or all of them when nil, in batches of
+	// batchSize rows.
+	open(columns []string, batchSize int) (batches, error)
+}
+
+// batches yields the output of a plan step one batch at a time. next
+// returns io.EOF after the last batch; every stream yields at least one
+// batch, possibly empty, so that its columns are known. close releases the
+// input and may be called at any time.
+type batches struct {
+	next  func() (*DataFrame, error)
+	close func() error
+}
+
+func noClose() error { return nil }
+
+// frameBatches yields df in slices of size rows.
+func frameBatches(df *DataFrame, size int) batches {
+	offset := 0
+	return batches{
+		next: func() (*DataFrame, error) {
+			if offset >= df.length && (offset > 0 || df.length > 0) {
+				return nil, io.EOF
+			}
+			n := min(size, df.length-offset)
+			if offset == 0 && n == df.length {
+				offset = max(n, 1)
+				return df, nil
+			}
+			b := df.slice(offset, n)
+			offset += n
+			return b, nil
+		},
+		close: noClose,
+	}
+}
+
+type streamer struct {
+	opts  StreamOptions
+	dir   string // created with the first spill file
+	files int
+}
+
+// collect streams p and concatenates its batches.
+func (s *streamer) collect(p *plan) (*DataFrame, error) {
+	in, err := s.stream(p)
+	if err != nil {
+		return nil, err
+	}
+	defer in.close()
+	var parts []*DataFrame
+	for {
+		b, err := in.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, b)
+	}
+	return concatBatches(parts)
+}
+
+func concatBatches(parts []*DataFrame) (*DataFrame, error) {
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+	return Concat(parts, HowVertical)
+}
+
+func (s *streamer) stream(p *plan) (batches, error) {
+	switch p.kind {
+	case planScan:
+		if p.src != nil {
+			return p.src.open(p.columns, s.opts.BatchSize)
+		}
+		df, err := p.execute()
+		if err != nil {
+			return batches{}, err
+		}
+		return frameBatches(df, s.opts.BatchSize), nil
+	case planSelect:
+		return s.mapInput(p, func(df *DataFrame) (*DataFrame, error) { return df.Select(p.columns) })
+	case planFilter:
+		if rowWise(p.expr) {
+			return s.mapInput(p, func(df *DataFrame) (*DataFrame, error) { return df.FilterExpr(p.expr) })
+		}
+	case planWithColumn:
+		if rowWise(p.expr) {
+			return s.mapInput(p, func(df *DataFrame) (*DataFrame, error) { return df.WithColumnExpr(p.name, p.expr) })
+		}
+	case planSort:
+		return s.sort(p)
+	case planAggregate:
+		df, err := s.aggregate(p)
+		if err != nil {
+			return batches{}, err
+		}
+		return frameBatches(df, s.opts.BatchSize), nil
+	case planJoin:
+		if p.how == JoinInner || p.how == JoinLeft {
+			right, err := s.collect(p.right)
+			if err != nil {
+				return batches{}, err
+			}
+			return s.mapInput(p, func(df *DataFrame) (*DataFrame, error) {
+				return df.Join(right, p.columns, p.rightOn, p.how, p.opts...)
+			})
+		}
+	}
+
+	// The step needs all of its input at once.
+	q := *p
+	in, err := s.collect(p.input)
+	if err != nil {
+		return batches{}, err
+	}
+	q.input = &plan{kind: planScan, df: in}
+	if p.right != nil {
+		right, err := s.collect(p.right)
+		if err != nil {
+			return batches{}, err
+		}
+		q.right = &plan{kind: planScan, df: right}
+	}
+	df, err := q.execute()
+	if err != nil {
+		return batches{}, err
+	}
+	return frameBatches(df, s.opts.BatchSize), nil
+}
+
+// mapInput applies fn to every batch of the input of p.
+func (s *streamer) mapInput(p *plan, fn func(*DataFrame) (*DataFrame, error)) (batches, error) {
+	in, err := s.stream(p.input)
+	if err != nil {
+		return batches{}, err
+	}
+	return batches{
+		next: func() (*DataFrame, error) {
+			b, err := in.next()
+			if err != nil {
+				return nil, err
+			}
+			return fn(b)
+		},
+		close: in.close,
+	}, nil
+}
+
+// overBudget reports whether size bytes of gathered rows must be spilled.
+func (s *streamer) overBudget(size int64) bool {
+	return s.opts.MemoryBudget > 0 && size > s.opts.MemoryBudget
+}
+
+// sort gathers the input into sorted runs, spilling each run once it
+// exceeds the memory budget, and merges the runs.
+func (s *streamer) sort(p *plan) (batches, error) {
+	in, err := s.stream(p.input)
+	if err != nil {
+		return batches{}, err
+	}
+	defer in.close()
+
+	var buf []*DataFrame
+	var size int64
+	var runs []string
+	sorted := func() (*DataFrame, error) {
+		df, err := concatBatches(buf)
+		if err != nil {
+			return nil, err
+		}
+		buf, size = buf[:0], 0
+		return df.SortByColumn(p.name, p.ascending)
+	}
+	spill := func() error {
+		run, err := sorted()
+		if err != nil || run.length == 0 {
+			return err
+		}
+		w, err := s.spillWriter()
+		if err != nil {
+			return err
+		}
+		for offset := 0; offset < run.length; offset += s.opts.BatchSize {
+			if err := w.write(run.slice(offset, min(s.opts.BatchSize, run.length-offset))); err != nil {
+				w.close()
+				return err
+			}
+		}
+		runs = append(runs, w.path)
+		logDecision("streaming sort spilled", "rows", run.length)
+		return w.close()
+	}
+
+	for {
+		b, err := in.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return batches{}, err
+		}
+		buf = append(buf, b)
+		if size += frameBytes(b); s.overBudget(size) {
+			if err := spill(); err != nil {
+				return batches{}, err
+			}
+		}
+	}
+	if len(runs) == 0 {
+		df, err := sorted()
+		if err != nil {
+			return batches{}, err
+		}
+		return frameBatches(df, s.opts.BatchSize), nil
+	}
+	if len(buf) > 0 {
+		if err := spill(); err != nil {
+			return batches{}, err
+		}
+	}
+	return s.merge(runs, p.name, p.ascending)
+}
+
+// runCursor is the position of a merge in one sorted run.
+type runCursor struct {
+	run   int
+	in    batches
+	batch *DataFrame
+	key   *types.Series // the sort column of batch, widened as in sortedRows
+	row   int
+}
+
+// advance moves to the next row, loading the next batch of the run when
+// needed. It returns false at the end of the run.
+func (c *runCursor) advance(column string) (bool, error) {
+	c.row++
+	for c.batch == nil || c.row >= c.batch.length {
+		b, err := c.in.next()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		key := b.series[column]
+		if _, unsigned := key.Data.([]uint64); !unsigned {
+			var err error
+			if key, err = key.Widen(); err != nil {
+				return false, err
+			}
+		}
+		c.batch, c.key, c.row = b, key, 0
+	}
+	return true, nil
+}
+
+// mergeHeap orders run cursors by their current row, nulls last and ties
+// broken by run so that the merge is stable.
+type mergeHeap struct {
+	cursors   []*runCursor
+	ascending bool
+}
+
+func (h *mergeHeap) Len() int      { return len(h.cursors) }
+func (h *mergeHeap) Swap(i, j int) { h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i] }
+func (h *mergeHeap) Push(x any)    { h.cursors = append(h.cursors, x.(*runCursor)) }
+func (h *mergeHeap) Pop() any {
+	c := h.cursors[len(h.cursors)-1]
+	h.cursors = h.cursors[:len(h.cursors)-1]
+	return c
+}
+
+func (h *mergeHeap) Less(i, j int) bool {
+	a, b := h.cursors[i], h.cursors[j]
+	av, bv := a.key.IsValid(a.row), b.key.IsValid(b.row)
+	if av != bv {
+		return av
+	}
+	if av {
+		c := compareSortKeys(a.key, a.row, b.key, b.row)
+		if !h.ascending {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+	}
+	return a.run < b.run
+}
+
+// compareSortKeys compares valid elements of two widened Series of the
+// same type in the order of SortByColumn.
+func compareSortKeys(a *types.Series, i int, b *types.Series, j int) int {
+	switch x := a.Data.(type) {
+	case []int64:
+		return cmpOrdered(x[i], b.Data.([]int64)[j])
+	case []uint64:
+		return cmpOrdered(x[i], b.Data.([]uint64)[j])
+	case []float64:
+		return cmpOrdered(floatSortKey(x[i]), floatSortKey(b.Data.([]float64)[j]))
+	case []string:
+		return strings.Compare(x[i], b.Data.([]string)[j])
+	case *types.Categorical:
+		y := b.Data.(*types.Categorical)
+		return strings.Compare(x.Categories[x.Codes[i]], y.Categories[y.Codes[j]])
+	case []bool:
+		y := b.Data.([]bool)[j]
+		switch {
+		case x[i] == y:
+			return 0
+		case y:
+			return -1
+		default:
+			return 1
+		}
+	default:
+		return 0
+	}
+}
+
+func cmpOrdered[T int64 | uint64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// merge yields the rows of the sorted runs at paths in one sorted order.
+func (s *streamer) merge(paths []string, column string, ascending bool) (batches, error) {
+	h := &mergeHeap{ascending: ascending}
+	closeAll := func() error {
+		var errs []error
+		for _, c := range h.cursors {
+			errs = append(errs, c.in.close())
+		}
+		h.cursors = nil
+		return errors.Join(errs...)
+	}
+	for run, path := range paths {
+		in, err := readSpill(path)
+		if err != nil {
+			closeAll()
+			return batches{}, err
+		}
+		c := &runCursor{run: run, in: in, row: -1}
+		ok, err := c.advance(column)
+		if err != nil {
+			in.close()
+			closeAll()
+			return batches{}, err
+		}
+		if !ok {
+			in.close()
+			continue
+		}
+		h.cursors = append(h.cursors, c)
+	}
+	heap.Init(h)
+
+	return batches{
+		next: func() (*DataFrame, error) {
+			// Take rows in order, gathering runs of rows from the same
+			// batch with one take each.
+			var parts []*DataFrame
+			var from *DataFrame
+			var rows []int
+			flush := func() error {
+				if from == nil {
+					return nil
+				}
+				part, err := from.take(rows)
+				parts, from, rows = append(parts, part), nil, nil
+				return err
+			}
+			for n := 0; n < s.opts.BatchSize && h.Len() > 0; n++ {
+				c := h.cursors[0]
+				if c.batch != from {
+					if err := flush(); err != nil {
+						return nil, err
+					}
+					from = c.batch
+				}
+				rows = append(rows, c.row)
+				ok, err := c.advance(column)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					heap.Fix(h, 0)
+				} else {
+					heap.Pop(h)
+					if err := c.in.close(); err != nil {
+						return nil, err
+					}
+				}
+			}
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			if len(parts) == 0 {
+				// Runs are never empty, so the first batch has rows.
+				return nil, io.EOF
+			}
+			return concatBatches(parts)
+		},
+		close: closeAll,
+	}, nil
+}
+
+// aggregate gathers the input of an aggregation. Once it exceeds the memory
+// budget, rows are spilled into hash partitions of their group key, which
+// are then aggregated one at a time.
+func (s *streamer) aggregate(p *plan) (*DataFrame, error) {
+	in, err := s.stream(p.input)
+	if err != nil {
+		return nil, err
+	}
+	defer in.close()
+
+	var buf []*DataFrame
+	var size int64
+	var empty *DataFrame // the columns of the input, without rows
+	var parts []*spillFile
+	spill := func() error {
+		logDecision("streaming aggregation spilled", "bytes", size, "partitions", spillPartitions)
+		if parts == nil {
+			parts = make([]*spillFile, spillPartitions)
+			for i := range parts {
+				var err error
+				if parts[i], err = s.spillWriter(); err != nil {
+					return err
+				}
+			}
+		}
+		for _, b := range buf {
+			rows := make([][]int, spillPartitions)
+			for i := 0; i < b.length; i++ {
+				k := buildKey128(b, p.columns, i)
+				part := (k.hi ^ k.lo) % spillPartitions
+				rows[part] = append(rows[part], i)
+			}
+			for part, idx := range rows {
+				if len(idx) == 0 {
+					continue
+				}
+				t, err := b.take(idx)
+				if err != nil {
+					return err
+				}
+				if err := parts[part].write(t); err != nil {
+					return err
+				}
+			}
+		}
+		buf, size = buf[:0], 0
+		return nil
+	}
+	defer func() {
+		for _, w := range parts {
+			w.close()
+		}
+	}()
+
+	for {
+		b, err := in.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if empty == nil {
+			empty = b.slice(0, 0)
+		}
+		buf = append(buf, b)
+		if size += frameBytes(b); s.overBudget(size) {
+			if err := spill(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	aggregate := func(df *DataFrame) (*DataFrame, error) {
+		gdf, err := df.GroupBy(p.columns)
+		if err != nil {
+			return nil, err
+		}
+		return gdf.Aggregate(p.name, p.agg)
+	}
+	if parts == nil {
+		df, err := concatBatches(buf)
+		if err != nil {
+			return nil, err
+		}
+		return aggregate(df)
+	}
+	if err := spill(); err != nil {
+		return nil, err
+	}
+	var results []*DataFrame
+	for _, w := range parts {
+		if err := w.close(); err != nil {
+			return nil, err
+		}
+		if w.rows == 0 {
+			continue
+		}
+		df, err := s.collectSpill(w.path)
+		if err != nil {
+			return nil, err
+		}
+		out, err := aggregate(df)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, out)
+	}
+	if len(results) == 0 {
+		return aggregate(empty)
+	}
+	return concatBatches(results)
+}
+
+// spillFile is a spill file being written as an Arrow IPC stream.
+type spillFile struct {
+	path string
+	f    *os.File
+	w    *ipc.Writer
+	rows int
+}
+
+func (s *streamer) spillWriter() (*spillFile, error) {
+	if s.dir == "" {
+		dir, err := os.MkdirTemp(s.opts.SpillDir, "gopolars-spill-")
+		if err != nil {
+			return nil, err
+		}
+		s.dir = dir
+	}
+	s.files++
+	path := filepath.Join(s.dir, strconv.Itoa(s.files)+".arrows")
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &spillFile{path: path, f: f}, nil
+}
+
+func (w *spillFile) write(df *DataFrame) error {
+	rec, err := df.ToArrow()
+	if err != nil {
+		return fmt.Errorf("spill: %w", err)
+	}
+	defer rec.Release()
+	if w.w == nil {
+		w.w = ipc.NewWriter(w.f, ipc.WithSchema(rec.Schema()))
+	}
+	w.rows += df.length
+	return w.w.Write(rec)
+}
+
+// close finishes the file; it may be called more than once.
+func (w *spillFile) close() error {
+	if w.f == nil {
+		return nil
+	}
+	var err error
+	if w.w != nil {
+		err = w.w.Close()
+	}
+	err = errors.Join(err, w.f.Close())
+	w.f = nil
+	return err
+}
+
+// readSpill reads back the batches written to a spill file.
+func readSpill(path string) (batches, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return batches{}, err
+	}
+	r, err := ipc.NewReader(f)
+	if err != nil {
+		f.Close()
+		return batches{}, err
+	}
+	return batches{
+		next: func() (*DataFrame, error) {
+			if !r.Next() {
+				if err := r.Err(); err != nil {
+					return nil, err
+				}
+				return nil, io.EOF
+			}
+			return FromArrow(r.RecordBatch())
+		},
+		close: func() error {
+			r.Release()
+			return f.Close()
+		},
+	}, nil
+}
+
+func (s *streamer) collectSpill(path string) (*DataFrame, error) {
+	in, err := readSpill(path)
+	if err != nil {
+		return nil, err
+	}
+	defer in.close()
+	var parts []*DataFrame
+	for {
+		b, err := in.next()
+		if err == io.EOF {
+			return concatBatches(parts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, b)
+	}
+}
+
+func (s *streamer) cleanup() error {
+	if s.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(s.dir)
+}
+
+// frameBytes estimates the memory held by the columns of df.
+func frameBytes(df *DataFrame) int64 {
+	var n int64
+	for _, s := range df.series {
+		switch data := s.Data.(type) {
+		case []string:
+			for _, v := range data {
+				n += int64(len(v)) + 16
+			}
+		case []bool, []int8, []uint8:
+			n += int64(s.Length)
+		case []int16, []uint16:
+			n += 2 * int64(s.Length)
+		case []int32, []uint32, []float32, *types.Categorical:
+			n += 4 * int64(s.Length)
+		default:
+			n += 8 * int64(s.Length)
+		}
+	}
+	return n
+}
+
+// csvSource is a CSV file read by ScanCSV.
+type csvSource struct {
+	path  string
+	opts  CSVOptions
+	names []string
+}
+
+func (c *csvSource) String() string { return "csv " + c.path }
+
+func (c *csvSource) columns() ([]string, error) {
+	if c.names != nil {
+		return c.names, nil
+	}
+	if isGlob(c.path) {
+		return nil, fmt.Errorf("csv: cannot scan glob %s", c.path)
+	}
+	f, err := openFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	recs, err := newCSVRecords(f, c.opts)
+	if err != nil {
+		return nil, err
+	}
+	c.names = append([]string{}, recs.names...)
+	return c.names, nil
+}
+
+func (c *csvSource) read(columns []string) (*DataFrame, error) {
+	df, err := ReadCSV(c.path, c.opts)
+	if err != nil || columns == nil {
+		return df, err
+	}
+	return df.Select(columns)
+}
+
+func (c *csvSource) open(columns []string, batchSize int) (batches, error) {
+	f, err := openFile(c.path)
+	if err != nil {
+		return batches{}, err
+	}
+	recs, err := newCSVRecords(f, c.opts)
+	if err != nil {
+		f.Close()
+		return batches{}, err
+	}
+	names := recs.names
+	var keep []int
+	if columns != nil {
+		pos := make(map[string]int, len(names))
+		for i, name := range names {
+			pos[name] = i
+		}
+		names = columns
+		for _, col := range columns {
+			keep = append(keep, pos[col])
+		}
+	}
+
+	// The column types and their parsers, set by the first batch.
+	var like []*types.Series
+	var parsers []csvParser
+	return batches{
+		next: func() (*DataFrame, error) {
+			first := recs.rows
+			fields := make([][]string, len(names))
+			n, err := recs.read(fields, keep, batchSize)
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			if n == 0 && like != nil {
+				return nil, io.EOF
+			}
+			series := make(map[string]*types.Series, len(names))
+			for i, name := range names {
+				if like == nil {
+					var parse csvParser
+					series[name], parse = inferCSVColumn(name, fields[i], recs.opts.Locale)
+					parsers = append(parsers, parse)
+					continue
+				}
+				s, row, ok := parsers[i](name, fields[i])
+				if !ok {
+					return nil, newParseError(first+row, 0, -1, name, fields[i][row],
+						fmt.Errorf("does not parse as %s, the type inferred from the first rows", like[i].DataType))
+				}
+				series[name] = s
+			}
+			if like == nil {
+				like = make([]*types.Series, len(names))
+				for i, name := range names {
+					like[i] = series[name]
+				}
+			}
+			return newOrdered(series, names)
+		},
+		close: f.Close,
+	}, nil
+}
+
+// parquetSource is a Parquet file read by ScanParquet.
+type parquetSource struct {
+	path  string
+	names []string
+}
+
+func (p *parquetSource) String() string { return "parquet " + p.path }
+
+func (p *parquetSource) columns() ([]string, error) {
+	if p.names != nil {
+		return p.names, nil
+	}
+	rdr, fr, err := p.reader(0)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+	schema, err := fr.Schema()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, schema.NumFields())
+	for _, field := range schema.Fields() {
+		names = append(names, field.Name)
+	}
+	p.names = names
+	return names, nil
+}
+
+func (p *parquetSource) reader(batchSize int) (*file.Reader, *pqarrow.FileReader, error) {
+	rdr, err := file.OpenParquetFile(p.path, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	fr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{BatchSize: int64(batchSize)}, memory.DefaultAllocator)
+	if err != nil {
+		rdr.Close()
+		return nil, nil, err
+	}
+	return rdr, fr, nil
+}
+
+func (p *parquetSource) read(columns []string) (*DataFrame, error) {
+	in, err := p.open(columns, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer in.close()
+	var parts []*DataFrame
+	for {
+		b, err := in.next()
+		if err == io.EOF {
+			return concatBatches(parts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, b)
+	}
+}
+
+// open reads the columns in batches; a batchSize of zero reads the whole
+// file as one batch.
+func (p *parquetSource) open(columns []string, batchSize int) (batches, error) {
+	rdr, fr, err := p.reader(batchSize)
+	if err != nil {
+		return batches{}, err
+	}
+	schema, err := fr.Schema()
+	if err != nil {
+		rdr.Close()
+		return batches{}, err
+	}
+	var indices []int
+	if columns != nil {
+		// Leaf columns match fields one to one in the flat files this
+		// package writes.
+		indices = make([]int, 0, len(columns))
+		for _, col := range columns {
+			idx := schema.FieldIndices(col)
+			if len(idx) == 0 {
+				rdr.Close()
+				return batches{}, fmt.Errorf("column %s not found", col)
+			}
+			indices = append(indices, idx[0])
+		}
+		if len(indices) == 0 {
+			// Nothing to decode, but the rows must still be counted.
+			rows := int(rdr.NumRows())
+			rdr.Close()
+			return frameBatches(&DataFrame{series: map[string]*types.Series{}, length: rows}, max(batchSize, 1)), nil
+		}
+	}
+	rr, err := fr.GetRecordReader(context.Background(), indices, nil)
+	if err != nil {
+		rdr.Close()
+		return batches{}, err
+	}
+
+	first := true
+	return batches{
+		next: func() (*DataFrame, error) {
+			rec, err := rr.Read()
+			if err == io.EOF && first {
+				// Keep the columns of a file without rows.
+				first = false
+				return fromArrowBatches(rr.Schema(), []arrow.RecordBatch{})
+			}
+			if err != nil {
+				return nil, err
+			}
+			first = false
+			return FromArrow(rec)
+		},
+		close: func() error {
+			rr.Release()
+			return rdr.Close()
+		},
+	}, nil
+}
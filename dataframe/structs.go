@@ -0,0 +1,314 @@
+package dataframe
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go-polars/types"
+)
+
+// FromStructs builds a DataFrame from a slice of structs or of pointers to
+// structs, with one column per exported field in field order, named by the
+// field's `polars:"name"` tag or else its name; fields tagged `polars:"-"`
+// are skipped. Fields of an int, uint or float kind give the column type of
+// their size (int and uint are 64 bits), strings give String and bools
+// Boolean columns, and slices of those give List columns of Int64, Float64,
+// String or Boolean. Pointer fields give nullable columns, nil being null,
+// and so does a nil pointer in place of a struct, unless WithDefaults gives
+// the column a default to use instead, converted as ToStructs converts
+// values. See ToStructs for the reverse.
+func FromStructs(slice any, opts ...BuildOption) (*DataFrame, error) {
+	cfg := buildOptions(opts)
+	rows := reflect.ValueOf(slice)
+	if rows.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("FromStructs needs a slice of structs, got %T", slice)
+	}
+	structType := rows.Type().Elem()
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("FromStructs needs a slice of structs, got %T", slice)
+	}
+
+	series := make(map[string]*types.Series)
+	var order []string
+	for _, f := range reflect.VisibleFields(structType) {
+		name, ok := fieldColumn(f)
+		if !ok {
+			continue
+		}
+		if _, dup := series[name]; dup {
+			return nil, fmt.Errorf("two fields map to column %s", name)
+		}
+		var def *ColumnDefault
+		if d, ok := cfg.defaults[name]; ok {
+			def = &d
+		}
+		s, err := structColumn(name, rows, f, def)
+		if err != nil {
+			return nil, err
+		}
+		series[name] = s
+		order = append(order, name)
+	}
+	for _, name := range defaultNames(cfg.defaults) {
+		if _, ok := series[name]; !ok {
+			return nil, fmt.Errorf("default given for column %s, which no field maps to", name)
+		}
+	}
+	return newOrdered(series, order)
+}
+
+// fieldColumn returns the column an exported struct field maps to
+func fieldColumn(f reflect.StructField) (string, bool) {
+	if !f.IsExported() || f.Anonymous {
+		return "", false
+	}
+	tag, ok := f.Tag.Lookup("polars")
+	if !ok {
+		return f.Name, true
+	}
+	switch tag = strings.Split(tag, ",")[0]; tag {
+	case "-":
+		return "", false
+	case "":
+		return f.Name, true
+	default:
+		return tag, true
+	}
+}
+
+// columnElem returns the element type of the column data holding values of
+// kind t, a list column when list is set.
+func columnElem(t reflect.Type) (elem reflect.Type, list bool, err error) {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64:
+		return reflect.TypeFor[int64](), false, nil
+	case reflect.Int8:
+		return reflect.TypeFor[int8](), false, nil
+	case reflect.Int16:
+		return reflect.TypeFor[int16](), false, nil
+	case reflect.Int32:
+		return reflect.TypeFor[int32](), false, nil
+	case reflect.Uint, reflect.Uint64:
+		return reflect.TypeFor[uint64](), false, nil
+	case reflect.Uint8:
+		return reflect.TypeFor[uint8](), false, nil
+	case reflect.Uint16:
+		return reflect.TypeFor[uint16](), false, nil
+	case reflect.Uint32:
+		return reflect.TypeFor[uint32](), false, nil
+	case reflect.Float32:
+		return reflect.TypeFor[float32](), false, nil
+	case reflect.Float64:
+		return reflect.TypeFor[float64](), false, nil
+	case reflect.String:
+		return reflect.TypeFor[string](), false, nil
+	case reflect.Bool:
+		return reflect.TypeFor[bool](), false, nil
+	case reflect.Slice:
+		switch k := t.Elem().Kind(); {
+		case k >= reflect.Int && k <= reflect.Uint64 && k != reflect.Uintptr:
+			return reflect.TypeFor[int64](), true, nil
+		case k == reflect.Float32 || k == reflect.Float64:
+			return reflect.TypeFor[float64](), true, nil
+		case k == reflect.String:
+			return reflect.TypeFor[string](), true, nil
+		case k == reflect.Bool:
+			return reflect.TypeFor[bool](), true, nil
+		}
+	}
+	return nil, false, fmt.Errorf("unsupported field type %s", t)
+}
+
+// structColumn gathers field f of every struct in rows into a Series, using
+// def, unless it is nil, where the field or its struct is nil.
+func structColumn(name string, rows reflect.Value, f reflect.StructField, def *ColumnDefault) (*types.Series, error) {
+	t := f.Type
+	nullable := t.Kind() == reflect.Pointer
+	if nullable {
+		t = t.Elem()
+	}
+	elem, list, err := columnElem(t)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", f.Name, err)
+	}
+	colType := elem
+	if list {
+		colType = reflect.SliceOf(elem)
+	}
+	data := reflect.MakeSlice(reflect.SliceOf(colType), rows.Len(), rows.Len())
+	valid := make([]bool, rows.Len())
+	hasNull := false
+	for i := range valid {
+		v, ok := structField(rows.Index(i), f, nullable)
+		if !ok && def != nil {
+			if value := def.at(i); value != nil {
+				v = reflect.New(t).Elem()
+				if err := setField(v, value); err != nil {
+					return nil, fmt.Errorf("default of column %s at row %d: %w", name, i, err)
+				}
+				ok = true
+			}
+		}
+		if !ok {
+			hasNull = true
+			continue
+		}
+		valid[i] = true
+		if !list {
+			data.Index(i).Set(v.Convert(elem))
+			continue
+		}
+		items := reflect.MakeSlice(colType, v.Len(), v.Len())
+		for j := 0; j < v.Len(); j++ {
+			item := v.Index(j)
+			if item.CanUint() && item.Uint() > 1<<63-1 {
+				return nil, fmt.Errorf("field %s: value %d at row %d overflows Int64", f.Name, item.Uint(), i)
+			}
+			items.Index(j).Set(item.Convert(elem))
+		}
+		data.Index(i).Set(items)
+	}
+	if !hasNull {
+		return types.NewSeries(name, data.Interface()), nil
+	}
+	return types.NewNullableSeries(name, data.Interface(), valid), nil
+}
+
+// structField returns field f of row, a struct or pointer to one, through
+// the pointer of a nullable field, or false when row or the field is nil.
+func structField(row reflect.Value, f reflect.StructField, nullable bool) (reflect.Value, bool) {
+	if row.Kind() == reflect.Pointer {
+		if row.IsNil() {
+			return reflect.Value{}, false
+		}
+		row = row.Elem()
+	}
+	v := row.FieldByIndex(f.Index)
+	if nullable {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+// ToMaps returns every row as a map keyed by column name, see Row.Map
+func (df *DataFrame) ToMaps() []map[string]interface{} {
+	out := make([]map[string]interface{}, df.length)
+	for i := range out {
+		out[i] = Row{df: df, idx: i}.Map()
+	}
+	return out
+}
+
+// ToStructs fills dest, a pointer to a slice of structs or of pointers to
+// structs, with one element per row, the reverse of FromStructs. An
+// exported field is filled from the column named by its `polars:"name"`
+// tag, or else from the column of the field's name; fields tagged
+// `polars:"-"` and fields without a column are left zero. Values convert to
+// fields of any numeric kind they fit in, to string, bool and slice fields of
+// their own kind and to interface{} fields. A null leaves pointer and
+// interface fields nil and others zero.
+func (df *DataFrame) ToStructs(dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("destination must be a pointer to a slice, got %T", dest)
+	}
+	slice := v.Elem()
+	elem := slice.Type().Elem()
+	structType := elem
+	if elem.Kind() == reflect.Pointer {
+		structType = elem.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("destination must be a slice of structs, got %T", dest)
+	}
+
+	// Match the fields to columns once.
+	type binding struct {
+		field  []int
+		column string
+	}
+	var bindings []binding
+	for _, f := range reflect.VisibleFields(structType) {
+		name, ok := fieldColumn(f)
+		if !ok {
+			continue
+		}
+		if _, ok := df.series[name]; ok {
+			bindings = append(bindings, binding{f.Index, name})
+		}
+	}
+
+	out := reflect.MakeSlice(slice.Type(), df.length, df.length)
+	for i := 0; i < df.length; i++ {
+		target := out.Index(i)
+		if elem.Kind() == reflect.Pointer {
+			target.Set(reflect.New(structType))
+			target = target.Elem()
+		}
+		for _, b := range bindings {
+			value := rowValue(df.series[b.column], i)
+			if value == nil {
+				continue
+			}
+			if err := setField(target.FieldByIndex(b.field), value); err != nil {
+				return fmt.Errorf("row %d, column %s: %w", i, b.column, err)
+			}
+		}
+	}
+	slice.Set(out)
+	return nil
+}
+
+// setField stores value, a non-null element as returned by rowValue, in
+// field.
+func setField(field reflect.Value, value interface{}) error {
+	if field.Kind() == reflect.Pointer {
+		p := reflect.New(field.Type().Elem())
+		if err := setField(p.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(p)
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	switch {
+	case field.Kind() == reflect.Interface && v.Type().Implements(field.Type()):
+		field.Set(v)
+		return nil
+	case v.Type().AssignableTo(field.Type()):
+		field.Set(v)
+		return nil
+	case isNumericKind(v.Kind()) && isNumericKind(field.Kind()):
+		converted := v.Convert(field.Type())
+		if !isFloatKind(v.Kind()) || !isFloatKind(field.Kind()) {
+			// Converting back reveals truncation, but not a change of sign.
+			negative := v.CanInt() && v.Int() < 0 || v.CanFloat() && v.Float() < 0
+			if !converted.Convert(v.Type()).Equal(v) || negative && converted.CanUint() {
+				return fmt.Errorf("value %v does not fit in %s", value, field.Type())
+			}
+		}
+		field.Set(converted)
+		return nil
+	case v.Kind() == field.Kind() && v.Type().ConvertibleTo(field.Type()):
+		// Named types such as type Status string.
+		field.Set(v.Convert(field.Type()))
+		return nil
+	default:
+		return fmt.Errorf("cannot store %T in %s", value, field.Type())
+	}
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
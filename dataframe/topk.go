@@ -0,0 +1,123 @@
+package dataframe
+
+import (
+	"cmp"
+	"container/heap"
+	"fmt"
+	"sort"
+
+	"go-polars/types"
+)
+
+// TopK returns the k rows with the largest values of column by, largest
+// first, when descending is set, and the k rows with the smallest values,
+// smallest first, otherwise. It gives the first k rows of a stable sort by
+// the column, nulls last, but keeps only k rows at a time in a bounded heap,
+// so it takes O(n log k) time and gathers just the k rows, rather than
+// sorting and permuting the whole frame. Fewer rows are returned when the
+// DataFrame is shorter than k.
+func (df *DataFrame) TopK(k int, by string, descending bool) (out *DataFrame, err error) {
+	defer df.track("TopK", map[string]interface{}{"k": k, "by": by, "descending": descending})(&out, &err)
+	if k < 0 {
+		return nil, fmt.Errorf("top k needs a non-negative k, got %d", k)
+	}
+	series, ok := df.series[by]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", by)
+	}
+	rows, err := topRows(series, k, descending)
+	if err != nil {
+		return nil, err
+	}
+	return df.take(rows)
+}
+
+// topRows returns the positions of the first k rows of series in the order
+// of a stable sort, nulls last.
+func topRows(series *types.Series, k int, descending bool) ([]int, error) {
+	// Narrow integers and Float32 are ordered through their 64-bit widening.
+	if _, unsigned := series.Data.([]uint64); !unsigned {
+		var err error
+		if series, err = series.Widen(); err != nil {
+			return nil, err
+		}
+	}
+	var rows []int
+	switch data := series.Data.(type) {
+	case []uint64:
+		rows = selectRows(series, k, descending, func(row int) uint64 { return data[row] })
+	case []int64:
+		rows = selectRows(series, k, descending, func(row int) int64 { return data[row] })
+	case []float64:
+		rows = selectRows(series, k, descending, func(row int) uint64 { return floatSortKey(data[row]) })
+	case []string:
+		rows = selectRows(series, k, descending, func(row int) string { return data[row] })
+	case []bool:
+		rows = selectRows(series, k, descending, func(row int) uint64 {
+			if data[row] {
+				return 1
+			}
+			return 0
+		})
+	case *types.Categorical:
+		rank := textRanks(data.Categories)
+		rows = selectRows(series, k, descending, func(row int) uint64 { return rank[data.Codes[row]] })
+	default:
+		return nil, fmt.Errorf("unsupported data type for column %s", series.Name)
+	}
+	// Nulls sort last, so they only fill up the rows valid values leave.
+	for row := 0; row < series.Length && len(rows) < k; row++ {
+		if !series.IsValid(row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// selectRows returns the positions of the first k valid rows of series
+// ordered by key, in the direction asked, and then by position.
+func selectRows[T cmp.Ordered](series *types.Series, k int, descending bool, key func(row int) T) []int {
+	h := &topHeap[T]{key: key, descending: descending}
+	for row := 0; row < series.Length && k > 0; row++ {
+		if !series.IsValid(row) {
+			continue
+		}
+		switch {
+		case len(h.rows) < k:
+			heap.Push(h, row)
+		case h.before(row, h.rows[0]):
+			// The row ousts the last of the rows kept so far.
+			h.rows[0] = row
+			heap.Fix(h, 0)
+		}
+	}
+	sort.Slice(h.rows, func(i, j int) bool { return h.before(h.rows[i], h.rows[j]) })
+	return h.rows
+}
+
+// topHeap keeps the rows selected so far with the one coming last in the
+// output on top.
+type topHeap[T cmp.Ordered] struct {
+	rows       []int
+	key        func(row int) T
+	descending bool
+}
+
+// before reports whether row a comes before row b in the output
+func (h *topHeap[T]) before(a, b int) bool {
+	if c := cmp.Compare(h.key(a), h.key(b)); c != 0 {
+		return (c < 0) != h.descending
+	}
+	return a < b
+}
+
+func (h *topHeap[T]) Len() int           { return len(h.rows) }
+func (h *topHeap[T]) Less(i, j int) bool { return h.before(h.rows[j], h.rows[i]) }
+func (h *topHeap[T]) Swap(i, j int)      { h.rows[i], h.rows[j] = h.rows[j], h.rows[i] }
+func (h *topHeap[T]) Push(x any)         { h.rows = append(h.rows, x.(int)) }
+
+func (h *topHeap[T]) Pop() any {
+	row := h.rows[len(h.rows)-1]
+	h.rows = h.rows[:len(h.rows)-1]
+	return row
+}
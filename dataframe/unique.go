@@ -0,0 +1,164 @@
+package dataframe
+
+import (
+	"fmt"
+
+	"go-polars/types"
+)
+
+// KeepStrategy selects which rows Unique keeps of the rows sharing a key
+type KeepStrategy int
+
+const (
+	KeepFirst KeepStrategy = iota // the first row of every key
+	KeepLast                      // the last row of every key
+	KeepNone                      // only the rows whose key is not repeated
+)
+
+func (k KeepStrategy) String() string {
+	switch k {
+	case KeepFirst:
+		return "first"
+	case KeepLast:
+		return "last"
+	case KeepNone:
+		return "none"
+	default:
+		return fmt.Sprintf("KeepStrategy(%d)", int(k))
+	}
+}
+
+// Unique returns a new DataFrame without duplicate rows over subset (all
+// columns when subset is empty), keeping the rows chosen by keep in their
+// original order. Rows with a null key are each kept unless
+// JoinNullsEqual(true) is given.
+func (df *DataFrame) Unique(subset []string, keep KeepStrategy, opts ...JoinOption) (out *DataFrame, err error) {
+	defer df.track("Unique", map[string]interface{}{"subset": subset, "keep": keep.String()})(&out, &err)
+	if keep < KeepFirst || keep > KeepNone {
+		return nil, fmt.Errorf("unknown keep strategy %d", int(keep))
+	}
+	if keep == KeepFirst {
+		rows, _, err := df.firstOccurrences(subset, newJoinConfig(opts))
+		if err != nil {
+			return nil, err
+		}
+		return df.take(rows)
+	}
+	if err := df.checkSubset(&subset); err != nil {
+		return nil, err
+	}
+	cfg := newJoinConfig(opts)
+
+	// Remember the last row of every key and how often it occurs.
+	type occurrence struct {
+		last  int
+		count int
+	}
+	keys := make([]key128, df.length)
+	nullKey := make([]bool, df.length)
+	seen := make(map[key128]*occurrence, df.length)
+	for i := 0; i < df.length; i++ {
+		if !cfg.nullsEqual && df.hasNullKey(subset, i) {
+			nullKey[i] = true
+			continue
+		}
+		keys[i] = buildKey128(df, subset, i)
+		if o, ok := seen[keys[i]]; ok {
+			o.last = i
+			o.count++
+		} else {
+			seen[keys[i]] = &occurrence{last: i, count: 1}
+		}
+	}
+	rows := make([]int, 0, len(seen))
+	for i := 0; i < df.length; i++ {
+		if nullKey[i] {
+			rows = append(rows, i)
+			continue
+		}
+		o := seen[keys[i]]
+		if (keep == KeepLast && o.last == i) || (keep == KeepNone && o.count == 1) {
+			rows = append(rows, i)
+		}
+	}
+	return df.take(rows)
+}
+
+// ValueCounts counts the rows holding every distinct value of column, nulls
+// included as one value. The result holds column and "count", or
+// "proportion" with each value's share of the rows when normalize is set.
+// Values are in order of first appearance, or by descending count when
+// sortByCount is set.
+func (df *DataFrame) ValueCounts(column string, sortByCount, normalize bool) (out *DataFrame, err error) {
+	defer df.track("ValueCounts", map[string]interface{}{"column": column, "sort": sortByCount, "normalize": normalize})(&out, &err)
+	s, ok := df.series[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	values, counts, err := s.ValueCounts(sortByCount)
+	if err != nil {
+		return nil, err
+	}
+	if normalize {
+		n := counts.Data.([]int64)
+		shares := make([]float64, len(n))
+		for i, c := range n {
+			shares[i] = float64(c) / float64(df.length)
+		}
+		counts = types.NewSeries("proportion", shares)
+	}
+	if counts.Name == column {
+		return nil, fmt.Errorf("value counts of column %s would hold two columns named %s", column, column)
+	}
+	return newOrdered(map[string]*types.Series{column: values, counts.Name: counts}, []string{column, counts.Name})
+}
+
+// IsUnique reports whether no two rows share the same key over subset (all
+// columns when subset is empty). Null keys are treated as in Unique.
+func (df *DataFrame) IsUnique(subset []string, opts ...JoinOption) (bool, error) {
+	_, dup, err := df.firstOccurrences(subset, newJoinConfig(opts))
+	if err != nil {
+		return false, err
+	}
+	return !dup, nil
+}
+
+// checkSubset replaces an empty subset by all columns and checks that the
+// others exist.
+func (df *DataFrame) checkSubset(subset *[]string) error {
+	if len(*subset) == 0 {
+		*subset = df.Columns()
+	}
+	for _, col := range *subset {
+		if _, ok := df.series[col]; !ok {
+			return fmt.Errorf("column %s not found", col)
+		}
+	}
+	return nil
+}
+
+// firstOccurrences returns the rows holding the first occurrence of each key
+// and whether any key was repeated.
+func (df *DataFrame) firstOccurrences(subset []string, cfg joinConfig) ([]int, bool, error) {
+	if err := df.checkSubset(&subset); err != nil {
+		return nil, false, err
+	}
+
+	seen := make(map[key128]struct{}, df.length)
+	keep := make([]int, 0, df.length)
+	dup := false
+	for i := 0; i < df.length; i++ {
+		if !cfg.nullsEqual && df.hasNullKey(subset, i) {
+			keep = append(keep, i)
+			continue
+		}
+		k := buildKey128(df, subset, i)
+		if _, ok := seen[k]; ok {
+			dup = true
+			continue
+		}
+		seen[k] = struct{}{}
+		keep = append(keep, i)
+	}
+	return keep, dup, nil
+}
@@ -0,0 +1,464 @@
+package dataframe
+
+import (
+	"fmt"
+
+	"go-polars/expr"
+	"go-polars/types"
+)
+
+// Eval evaluates an expression against the DataFrame and returns a Series
+// with one value per row. Aggregations without Over are broadcast over the
+// whole frame, as are literals.
+func (df *DataFrame) Eval(e expr.Expr) (*types.Series, error) {
+	switch e.Kind {
+	case expr.KindColumn:
+		s, ok := df.series[e.Name]
+		if !ok {
+			return nil, fmt.Errorf("column %s not found", e.Name)
+		}
+		return s, nil
+	case expr.KindAgg:
+		return df.evalWindow(e, nil)
+	case expr.KindShift:
+		return df.evalShift(e, nil)
+	case expr.KindLiteral:
+		return literalSeries(e.Value, df.length)
+	case expr.KindBinary:
+		return df.evalBinary(e)
+	case expr.KindNot:
+		return df.evalNot(e)
+	case expr.KindCond:
+		return df.evalCond(e)
+	case expr.KindWindow:
+		switch e.Inputs[0].Kind {
+		case expr.KindAgg:
+			return df.evalWindow(e.Inputs[0], e.Partition)
+		case expr.KindShift:
+			return df.evalShift(e.Inputs[0], e.Partition)
+		default:
+			return nil, fmt.Errorf("over() requires an aggregation or shift, got %s", e.Inputs[0])
+		}
+	default:
+		return nil, fmt.Errorf("unsupported expression %s", e)
+	}
+}
+
+// evalWindow computes an aggregation per partition and broadcasts each
+// group's result to all of its rows.
+func (df *DataFrame) evalWindow(agg expr.Expr, partition []string) (*types.Series, error) {
+	input, err := df.Eval(agg.Inputs[0])
+	if err != nil {
+		return nil, err
+	}
+	if input, err = input.Widen(); err != nil {
+		return nil, err
+	}
+	ids, groups, err := df.partition(partition, true)
+	if err != nil {
+		return nil, err
+	}
+
+	name := input.Name
+	valid := input.Validity
+	counts := make([]int64, groups)
+	for i, id := range ids {
+		if valid == nil || valid.Get(i) {
+			counts[id]++
+		}
+	}
+	if agg.Agg == expr.AggCount {
+		out := make([]int64, len(ids))
+		for i, id := range ids {
+			out[i] = counts[id]
+		}
+		return types.NewSeries(name, out), nil
+	}
+
+	// Groups without a single valid value produce nulls for Mean/Min/Max.
+	rowValid := make([]bool, len(ids))
+	for i, id := range ids {
+		rowValid[i] = counts[id] > 0 || agg.Agg == expr.AggSum
+	}
+
+	switch data := input.Data.(type) {
+	case []int64:
+		acc := windowAccumulate(data, valid, ids, groups, agg.Agg)
+		if agg.Agg == expr.AggMean {
+			out := make([]float64, len(ids))
+			for i, id := range ids {
+				if counts[id] > 0 {
+					out[i] = float64(acc[id]) / float64(counts[id])
+				}
+			}
+			return types.NewNullableSeries(name, out, rowValid), nil
+		}
+		out := make([]int64, len(ids))
+		for i, id := range ids {
+			out[i] = acc[id]
+		}
+		return types.NewNullableSeries(name, out, rowValid), nil
+	case []float64:
+		acc := windowAccumulate(data, valid, ids, groups, agg.Agg)
+		out := make([]float64, len(ids))
+		for i, id := range ids {
+			out[i] = acc[id]
+			if agg.Agg == expr.AggMean && counts[id] > 0 {
+				out[i] /= float64(counts[id])
+			}
+		}
+		return types.NewNullableSeries(name, out, rowValid), nil
+	default:
+		return nil, fmt.Errorf("unsupported data type for aggregation")
+	}
+}
+
+// windowAccumulate reduces the valid values of each group. Mean is returned
+// as a sum; the caller divides by the group's valid count.
+func windowAccumulate[T int64 | float64](data []T, valid *types.Bitmap, ids []int, groups int, fn expr.AggFunc) []T {
+	acc := make([]T, groups)
+	seen := make([]bool, groups)
+	for i, id := range ids {
+		if valid != nil && !valid.Get(i) {
+			continue
+		}
+		v := data[i]
+		switch {
+		case fn == expr.AggSum || fn == expr.AggMean:
+			acc[id] += v
+		case !seen[id]:
+			acc[id] = v
+		case fn == expr.AggMin && v < acc[id]:
+			acc[id] = v
+		case fn == expr.AggMax && v > acc[id]:
+			acc[id] = v
+		}
+		seen[id] = true
+	}
+	return acc
+}
+
+// evalShift shifts values by e.Offset rows inside each partition. Rows whose
+// source would fall outside their group are null, or hold the fill value when
+// one is given. A fill value combines with the column the way a literal does:
+// narrow columns are widened, categoricals decoded and integers fill float
+// columns.
+func (df *DataFrame) evalShift(e expr.Expr, partition []string) (*types.Series, error) {
+	eval := df.Eval
+	if e.Value != nil {
+		eval = df.evalOperand
+	}
+	input, err := eval(e.Inputs[0])
+	if err != nil {
+		return nil, err
+	}
+	ids, groups, err := df.partition(partition, true)
+	if err != nil {
+		return nil, err
+	}
+
+	src := make([]int, df.length)
+	for _, rows := range partitionRows(ids, groups) {
+		for j, row := range rows {
+			k := j - e.Offset
+			if k < 0 || k >= len(rows) {
+				src[row] = -1
+				continue
+			}
+			src[row] = rows[k]
+		}
+	}
+	out := input.Take(src)
+	if e.Value == nil {
+		return out, nil
+	}
+
+	value := e.Value
+	if v, ok := value.(int); ok {
+		value = int64(v)
+	}
+	switch data := out.Data.(type) {
+	case []int64:
+		err = fillMissing(out, data, src, value)
+	case []float64:
+		if v, ok := value.(int64); ok {
+			value = float64(v)
+		}
+		err = fillMissing(out, data, src, value)
+	case []string:
+		err = fillMissing(out, data, src, value)
+	case []bool:
+		err = fillMissing(out, data, src, value)
+	default:
+		err = fmt.Errorf("cannot shift %s column with a fill value", out.DataType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// fillMissing writes fill into the rows of s whose source index is negative
+// and marks them valid. Nulls carried over from the input are left alone.
+func fillMissing[T any](s *types.Series, data []T, src []int, fill interface{}) error {
+	v, ok := fill.(T)
+	if !ok {
+		var zero T
+		return fmt.Errorf("fill value %v (%T) does not match column type %T", fill, fill, zero)
+	}
+	for i, j := range src {
+		if j < 0 {
+			data[i] = v
+			if s.Validity != nil {
+				s.Validity.Set(i, true)
+			}
+		}
+	}
+	if s.Validity != nil && s.Validity.NullCount() == 0 {
+		s.Validity = nil
+	}
+	return nil
+}
+
+// NormalizeBy divides every value of column by the total of its group,
+// returning a new DataFrame with the share stored in "<column>_norm". An empty
+// group list normalizes against the total of the whole column.
+func (df *DataFrame) NormalizeBy(group []string, column string) (out *DataFrame, err error) {
+	defer df.track("NormalizeBy", map[string]interface{}{"group": group, "column": column})(&out, &err)
+	values, err := df.Eval(expr.Col(column))
+	if err != nil {
+		return nil, err
+	}
+	// The totals come widened, so the values must be too.
+	if values, err = values.Widen(); err != nil {
+		return nil, err
+	}
+	totals, err := df.Eval(expr.Col(column).Sum().Over(group...))
+	if err != nil {
+		return nil, err
+	}
+
+	share := make([]float64, df.length)
+	valid := make([]bool, df.length)
+	for i := range valid {
+		valid[i] = values.IsValid(i) && totals.IsValid(i)
+	}
+	switch data := values.Data.(type) {
+	case []int64:
+		sums := totals.Data.([]int64)
+		for i, v := range data {
+			share[i] = float64(v) / float64(sums[i])
+		}
+	case []float64:
+		sums := totals.Data.([]float64)
+		for i, v := range data {
+			share[i] = v / sums[i]
+		}
+	default:
+		return nil, fmt.Errorf("cannot normalize %s column %s", values.DataType, column)
+	}
+
+	name := column + "_norm"
+	return df.withColumns(types.NewNullableSeries(name, share, valid))
+}
+
+// RollingBy aggregates column over a time window ending at every row: the
+// rows whose timeCol value lies in (t-period, t], t being the current row's.
+// The frame must be sorted by timeCol ascending. agg is one of Sum, Mean,
+// Min, Max or Std, and the result is stored in "<column>_rolling_<agg>";
+// windows with fewer than minPeriods valid values (at least one) are null.
+func (df *DataFrame) RollingBy(timeCol string, period int64, column string, agg AggregationType, minPeriods int) (out *DataFrame, err error) {
+	defer df.track("RollingBy", map[string]interface{}{"time": timeCol, "period": period, "column": column, "agg": agg.String()})(&out, &err)
+	ts, ok := df.series[timeCol]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", timeCol)
+	}
+	s, ok := df.series[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	r, err := s.RollingBy(ts, period, minPeriods)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *types.Series
+	switch agg {
+	case Sum:
+		result = r.Sum()
+	case Mean:
+		result = r.Mean()
+	case Min:
+		result = r.Min()
+	case Max:
+		result = r.Max()
+	case Std:
+		result = r.Std()
+	default:
+		return nil, fmt.Errorf("unsupported rolling aggregation %s", agg)
+	}
+	return df.withColumns(result.Rename(column + "_rolling_" + agg.String()))
+}
+
+// CumulativeOp selects the running aggregate computed by Cumulative
+type CumulativeOp int
+
+const (
+	CumSum CumulativeOp = iota
+	CumProd
+	CumMin
+	CumMax
+)
+
+func (op CumulativeOp) String() string {
+	switch op {
+	case CumSum:
+		return "cumsum"
+	case CumProd:
+		return "cumprod"
+	case CumMin:
+		return "cummin"
+	case CumMax:
+		return "cummax"
+	default:
+		return fmt.Sprintf("CumulativeOp(%d)", int(op))
+	}
+}
+
+func (op CumulativeOp) apply(s *types.Series) (*types.Series, error) {
+	switch op {
+	case CumSum:
+		return s.CumSum()
+	case CumProd:
+		return s.CumProd()
+	case CumMin:
+		return s.CumMin()
+	case CumMax:
+		return s.CumMax()
+	default:
+		return nil, fmt.Errorf("unknown cumulative operation %d", int(op))
+	}
+}
+
+// Cumulative replaces each of the given columns by its running aggregate in
+// row order. Nulls stay null and are skipped; see Series.CumSum.
+func (df *DataFrame) Cumulative(op CumulativeOp, columns ...string) (out *DataFrame, err error) {
+	defer df.track("Cumulative", map[string]interface{}{"op": op.String(), "columns": columns})(&out, &err)
+	result := make([]*types.Series, len(columns))
+	for i, col := range columns {
+		s, ok := df.series[col]
+		if !ok {
+			return nil, fmt.Errorf("column %s not found", col)
+		}
+		if result[i], err = op.apply(s); err != nil {
+			return nil, err
+		}
+	}
+	return df.withColumns(result...)
+}
+
+// Cumulative replaces each of the given columns by its running aggregate,
+// restarting in every group. Rows keep their original order.
+func (gdf *GroupedDataFrame) Cumulative(op CumulativeOp, columns ...string) (out *DataFrame, err error) {
+	defer gdf.df.track("Cumulative", map[string]interface{}{"op": op.String(), "columns": columns, "by": gdf.columns})(&out, &err)
+	return gdf.transform(columns, op.apply)
+}
+
+// Shift moves the values of each of the given columns n rows down within
+// every group, or up for a negative n; see Series.Shift.
+func (gdf *GroupedDataFrame) Shift(n int, columns ...string) (out *DataFrame, err error) {
+	defer gdf.df.track("Shift", map[string]interface{}{"n": n, "columns": columns, "by": gdf.columns})(&out, &err)
+	return gdf.transform(columns, func(s *types.Series) (*types.Series, error) {
+		return s.Shift(n), nil
+	})
+}
+
+// Diff replaces each of the given columns by the difference with the value
+// n rows before within the same group; see Series.Diff.
+func (gdf *GroupedDataFrame) Diff(n int, columns ...string) (out *DataFrame, err error) {
+	defer gdf.df.track("Diff", map[string]interface{}{"n": n, "columns": columns, "by": gdf.columns})(&out, &err)
+	return gdf.transform(columns, func(s *types.Series) (*types.Series, error) {
+		return s.Diff(n)
+	})
+}
+
+// PctChange replaces each of the given columns by the relative change from
+// the previous value within the same group; see Series.PctChange.
+func (gdf *GroupedDataFrame) PctChange(columns ...string) (out *DataFrame, err error) {
+	defer gdf.df.track("PctChange", map[string]interface{}{"columns": columns, "by": gdf.columns})(&out, &err)
+	return gdf.transform(columns, (*types.Series).PctChange)
+}
+
+// Rank adds, for each of the given columns, a column "<column>_rank" with
+// the rank of every value within its group; see Series.Rank.
+func (gdf *GroupedDataFrame) Rank(method types.RankMethod, descending bool, columns ...string) (out *DataFrame, err error) {
+	defer gdf.df.track("Rank", map[string]interface{}{"method": method.String(), "descending": descending, "columns": columns, "by": gdf.columns})(&out, &err)
+	ranks, err := gdf.transformSeries(columns, func(s *types.Series) (*types.Series, error) {
+		return s.Rank(method, descending)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, col := range columns {
+		ranks[i] = ranks[i].Rename(col + "_rank")
+	}
+	return gdf.df.withColumns(ranks...)
+}
+
+// transform replaces each of the given columns by fn applied to every group
+// on its own, keeping the rows in their original order.
+func (gdf *GroupedDataFrame) transform(columns []string, fn func(*types.Series) (*types.Series, error)) (*DataFrame, error) {
+	result, err := gdf.transformSeries(columns, fn)
+	if err != nil {
+		return nil, err
+	}
+	return gdf.df.withColumns(result...)
+}
+
+func (gdf *GroupedDataFrame) transformSeries(columns []string, fn func(*types.Series) (*types.Series, error)) ([]*types.Series, error) {
+	df := gdf.df
+	rows, err := gdf.groupRows()
+	if err != nil {
+		return nil, err
+	}
+	groups := len(rows)
+
+	// position[row] is the row's index in the concatenation of the groups.
+	position := make([]int, df.length)
+	next := 0
+	for _, r := range rows {
+		for _, row := range r {
+			position[row] = next
+			next++
+		}
+	}
+	if next != df.length {
+		return nil, fmt.Errorf("per-group transforms need every row in exactly one group")
+	}
+
+	result := make([]*types.Series, len(columns))
+	for i, col := range columns {
+		s, ok := df.series[col]
+		if !ok {
+			return nil, fmt.Errorf("column %s not found", col)
+		}
+		if groups == 0 {
+			if result[i], err = fn(s); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		parts := make([]*types.Series, groups)
+		for g, r := range rows {
+			if parts[g], err = fn(s.Take(r)); err != nil {
+				return nil, err
+			}
+		}
+		combined, err := concatSeries(parts)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = combined.Take(position)
+	}
+	return result, nil
+}
@@ -0,0 +1,131 @@
+/* A minimal C client of the go-polars shared library: it builds a frame,
+ * sorts it in the background, filters it with a batched predicate,
+ * aggregates it by group and reads the result through both the plain
+ * accessors and the Arrow C Data Interface. It exits non-zero when a result
+ * is not the expected one. */
+
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include <unistd.h>
+
+#include "gopolars.h"
+
+#define CHECK(cond)                                                  \
+    do {                                                             \
+        if (!(cond)) {                                               \
+            fprintf(stderr, "%s:%d: check failed: %s\n", __FILE__,   \
+                    __LINE__, #cond);                                \
+            exit(1);                                                 \
+        }                                                            \
+    } while (0)
+
+/* Keeps the amounts above the threshold passed as ctx. */
+static int above(const void *values, int64_t n, int dtype, uint8_t *keep, void *ctx)
+{
+    if (dtype != GOPOLARS_DTYPE_FLOAT64) {
+        return 1;
+    }
+    const double *amounts = values;
+    double threshold = *(const double *)ctx;
+    for (int64_t i = 0; i < n; i++) {
+        keep[i] = amounts[i] > threshold;
+    }
+    return 0;
+}
+
+int main(void)
+{
+    int major, minor, patch;
+    uint64_t features;
+    GetBridgeVersion(&major, &minor, &patch, &features);
+    printf("go-polars bridge %d.%d.%d\n", major, minor, patch);
+    CHECK(major == GOPOLARS_VERSION_MAJOR && minor >= GOPOLARS_VERSION_MINOR);
+    CHECK(features & GOPOLARS_FEATURE_ASYNC_JOBS);
+    CHECK(features & GOPOLARS_FEATURE_ARROW_EXPORT);
+    CHECK(features & GOPOLARS_FEATURE_BATCH_FILTER);
+    CHECK(features & GOPOLARS_FEATURE_LARGE_SIZES);
+
+    /* The library reads the columns in place, so they must outlive the frame. */
+    static int64_t store[] = {2, 1, 2, 1, 3};
+    static double amount[] = {10.0, 1.5, 20.0, 2.5, 7.0};
+    int64_t df = NewDataFrame();
+    CHECK(df != -1);
+    CHECK(AddSeries64(df, "store", store, 5, GOPOLARS_DTYPE_INT64) == 0);
+    CHECK(AddSeries64(df, "amount", amount, 5, GOPOLARS_DTYPE_FLOAT64) == 0);
+
+    int64_t rows, cols;
+    CHECK(GetShape64(df, &rows, &cols) == 0);
+    CHECK(rows == 5 && cols == 2);
+
+    /* Sort without blocking the calling thread. */
+    int64_t job = SubmitSort(df, "amount", 0);
+    CHECK(job != -1);
+    int64_t sorted;
+    int state;
+    while ((state = PollJob(job, &sorted)) == GOPOLARS_JOB_RUNNING) {
+        usleep(1000);
+    }
+    CHECK(state == GOPOLARS_JOB_DONE);
+
+    int64_t length;
+    int dtype;
+    double *top = GetSeries64(sorted, "amount", &length, &dtype);
+    CHECK(top != NULL && length == 5 && dtype == GOPOLARS_DTYPE_FLOAT64);
+    printf("largest amount: %.1f\n", top[0]);
+    CHECK(top[0] == 20.0);
+
+    char *row = GetRowJSON(sorted, 0);
+    CHECK(row != NULL);
+    printf("first sorted row: %s\n", row);
+    free(row);
+
+    /* Large amounts only, the predicate seeing the column a chunk at a time. */
+    double threshold = 5.0;
+    int64_t large = FilterBatch(df, "amount", above, &threshold);
+    CHECK(large != -1);
+    CHECK(GetShape64(large, &rows, &cols) == 0);
+    printf("amounts above %.1f: %lld\n", threshold, (long long)rows);
+    CHECK(rows == 3);
+
+    /* Total amount per store. */
+    char *by[] = {"store"};
+    int64_t grouped = GroupBy(df, by, 1);
+    CHECK(grouped != -1);
+    int64_t totals = Aggregate(grouped, "amount", GOPOLARS_AGG_SUM);
+    CHECK(totals != -1);
+
+    int64_t *keys = GetSeries64(totals, "store", &length, &dtype);
+    CHECK(keys != NULL && dtype == GOPOLARS_DTYPE_INT64);
+    double *sums = GetSeries64(totals, "amount", &length, &dtype);
+    CHECK(sums != NULL && length == 3);
+    for (int64_t i = 0; i < length; i++) {
+        printf("store %lld: %.1f\n", (long long)keys[i], sums[i]);
+        switch (keys[i]) {
+        case 1: CHECK(sums[i] == 4.0); break;
+        case 2: CHECK(sums[i] == 30.0); break;
+        case 3: CHECK(sums[i] == 7.0); break;
+        default: CHECK(0);
+        }
+    }
+
+    /* The same result through the Arrow C Data Interface. */
+    struct ArrowSchema schema;
+    struct ArrowArray array;
+    CHECK(ExportArrow(totals, &schema, &array) == 0);
+    CHECK(strcmp(schema.format, "+s") == 0 && schema.n_children == 2);
+    CHECK(array.length == 3 && array.n_children == 2);
+    printf("arrow columns: %s (%s), %s (%s)\n",
+           schema.children[0]->name, schema.children[0]->format,
+           schema.children[1]->name, schema.children[1]->format);
+    array.release(&array);
+    schema.release(&schema);
+
+    DeleteDataFrame(totals);
+    DeleteDataFrame(grouped);
+    DeleteDataFrame(large);
+    DeleteDataFrame(sorted);
+    DeleteDataFrame(df);
+    printf("ok\n");
+    return 0;
+}
@@ -0,0 +1,293 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind identifies the type of node an Expr represents
+type Kind int
+
+const (
+	KindColumn Kind = iota
+	KindAgg
+	KindWindow
+	KindShift
+	KindLiteral
+	KindBinary
+	KindNot
+	KindCond
+)
+
+// AggFunc is the reduction applied by an aggregation expression
+type AggFunc int
+
+const (
+	AggSum AggFunc = iota
+	AggMean
+	AggCount
+	AggMin
+	AggMax
+)
+
+func (a AggFunc) String() string {
+	switch a {
+	case AggSum:
+		return "sum"
+	case AggMean:
+		return "mean"
+	case AggCount:
+		return "count"
+	case AggMin:
+		return "min"
+	case AggMax:
+		return "max"
+	default:
+		return fmt.Sprintf("agg(%d)", int(a))
+	}
+}
+
+// Op is the operator of a binary expression
+type Op int
+
+const (
+	OpAdd Op = iota
+	OpSub
+	OpMul
+	OpDiv
+	OpEq
+	OpNe
+	OpLt
+	OpLe
+	OpGt
+	OpGe
+	OpAnd
+	OpOr
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpAdd:
+		return "+"
+	case OpSub:
+		return "-"
+	case OpMul:
+		return "*"
+	case OpDiv:
+		return "/"
+	case OpEq:
+		return "=="
+	case OpNe:
+		return "!="
+	case OpLt:
+		return "<"
+	case OpLe:
+		return "<="
+	case OpGt:
+		return ">"
+	case OpGe:
+		return ">="
+	case OpAnd:
+		return "&"
+	case OpOr:
+		return "|"
+	default:
+		return fmt.Sprintf("op(%d)", int(o))
+	}
+}
+
+// IsArithmetic reports whether o is one of Add, Sub, Mul or Div
+func (o Op) IsArithmetic() bool { return o >= OpAdd && o <= OpDiv }
+
+// IsComparison reports whether o compares its operands
+func (o Op) IsComparison() bool { return o >= OpEq && o <= OpGe }
+
+// Expr is a node in a column expression tree. Expressions only describe a
+// computation; they are evaluated against a DataFrame by the dataframe
+// package.
+type Expr struct {
+	Kind Kind
+	// Name is the referenced column for KindColumn.
+	Name string
+	// Agg is the reduction for KindAgg.
+	Agg AggFunc
+	// Op is the operator for KindBinary.
+	Op Op
+	// Inputs holds child expressions: the operands of KindBinary and
+	// KindNot, and the condition, then and otherwise branches of KindCond.
+	Inputs []Expr
+	// Partition lists the grouping columns for KindWindow.
+	Partition []string
+	// Offset is the number of rows moved by KindShift.
+	Offset int
+	// Value is the constant of KindLiteral, or the fill value for
+	// KindShift; nil stands for null.
+	Value interface{}
+}
+
+// Col references a column by name
+func Col(name string) Expr {
+	return Expr{Kind: KindColumn, Name: name}
+}
+
+// Lit is a constant broadcast to every row. v may be an int, int64,
+// float64, string or bool, or nil for a null that takes the type of the
+// expression it is combined with.
+func Lit(v interface{}) Expr {
+	return Expr{Kind: KindLiteral, Value: v}
+}
+
+func (e Expr) binary(op Op, other Expr) Expr {
+	return Expr{Kind: KindBinary, Op: op, Inputs: []Expr{e, other}}
+}
+
+// Add sums the expression and other row by row
+func (e Expr) Add(other Expr) Expr { return e.binary(OpAdd, other) }
+
+// Sub subtracts other from the expression row by row
+func (e Expr) Sub(other Expr) Expr { return e.binary(OpSub, other) }
+
+// Mul multiplies the expression and other row by row
+func (e Expr) Mul(other Expr) Expr { return e.binary(OpMul, other) }
+
+// Div divides the expression by other row by row. The result is always
+// floating point.
+func (e Expr) Div(other Expr) Expr { return e.binary(OpDiv, other) }
+
+// Eq tests the expression and other for equality row by row
+func (e Expr) Eq(other Expr) Expr { return e.binary(OpEq, other) }
+
+// Ne tests the expression and other for inequality row by row
+func (e Expr) Ne(other Expr) Expr { return e.binary(OpNe, other) }
+
+// Lt tests whether the expression is less than other row by row
+func (e Expr) Lt(other Expr) Expr { return e.binary(OpLt, other) }
+
+// Le tests whether the expression is at most other row by row
+func (e Expr) Le(other Expr) Expr { return e.binary(OpLe, other) }
+
+// Gt tests whether the expression is greater than other row by row
+func (e Expr) Gt(other Expr) Expr { return e.binary(OpGt, other) }
+
+// Ge tests whether the expression is at least other row by row
+func (e Expr) Ge(other Expr) Expr { return e.binary(OpGe, other) }
+
+// And is the logical conjunction of two boolean expressions, with nulls
+// following three-valued logic: false & null is false.
+func (e Expr) And(other Expr) Expr { return e.binary(OpAnd, other) }
+
+// Or is the logical disjunction of two boolean expressions, with nulls
+// following three-valued logic: true | null is true.
+func (e Expr) Or(other Expr) Expr { return e.binary(OpOr, other) }
+
+// Not negates a boolean expression; nulls stay null
+func (e Expr) Not() Expr {
+	return Expr{Kind: KindNot, Inputs: []Expr{e}}
+}
+
+// WhenClause is a condition waiting for the value it selects
+type WhenClause struct {
+	branches []Expr // conditions and values so far, alternating
+}
+
+// ThenClause is a chain of conditions with their values, completed by
+// Otherwise or extended by When
+type ThenClause struct {
+	branches []Expr
+}
+
+// When starts a conditional expression: the first condition that holds for
+// a row selects the row's value. A null condition counts as false.
+func When(cond Expr) WhenClause {
+	return WhenClause{branches: []Expr{cond}}
+}
+
+// Then sets the value selected by the pending condition
+func (w WhenClause) Then(value Expr) ThenClause {
+	return ThenClause{branches: append(append([]Expr(nil), w.branches...), value)}
+}
+
+// When adds a condition tried after the previous ones
+func (t ThenClause) When(cond Expr) WhenClause {
+	return WhenClause{branches: append(append([]Expr(nil), t.branches...), cond)}
+}
+
+// Otherwise sets the value of rows matching no condition and returns the
+// finished expression
+func (t ThenClause) Otherwise(value Expr) Expr {
+	out := value
+	for i := len(t.branches) - 2; i >= 0; i -= 2 {
+		out = Expr{Kind: KindCond, Inputs: []Expr{t.branches[i], t.branches[i+1], out}}
+	}
+	return out
+}
+
+func (e Expr) agg(fn AggFunc) Expr {
+	return Expr{Kind: KindAgg, Agg: fn, Inputs: []Expr{e}}
+}
+
+// Sum reduces the expression to its sum
+func (e Expr) Sum() Expr { return e.agg(AggSum) }
+
+// Mean reduces the expression to its arithmetic mean
+func (e Expr) Mean() Expr { return e.agg(AggMean) }
+
+// Count reduces the expression to its number of elements
+func (e Expr) Count() Expr { return e.agg(AggCount) }
+
+// Min reduces the expression to its minimum
+func (e Expr) Min() Expr { return e.agg(AggMin) }
+
+// Max reduces the expression to its maximum
+func (e Expr) Max() Expr { return e.agg(AggMax) }
+
+// Shift moves values n rows forward (lag) or, for negative n, backward
+// (lead). Rows without a source value are null.
+func (e Expr) Shift(n int) Expr {
+	return Expr{Kind: KindShift, Inputs: []Expr{e}, Offset: n}
+}
+
+// ShiftFill is Shift with an explicit value for rows without a source. fill
+// takes the same values as Lit and, like a literal, widens the column it is
+// combined with.
+func (e Expr) ShiftFill(n int, fill interface{}) Expr {
+	return Expr{Kind: KindShift, Inputs: []Expr{e}, Offset: n, Value: fill}
+}
+
+// Over evaluates an aggregation or shift separately within each group of the
+// given partition columns. Aggregations are broadcast back to every row of the
+// group and shifts never cross group boundaries. Calling Over with no columns
+// treats the whole frame as a single group.
+func (e Expr) Over(partition ...string) Expr {
+	return Expr{Kind: KindWindow, Inputs: []Expr{e}, Partition: partition}
+}
+
+// String renders the expression in a compact, polars-like notation
+func (e Expr) String() string {
+	switch e.Kind {
+	case KindColumn:
+		return fmt.Sprintf("col(%q)", e.Name)
+	case KindAgg:
+		return fmt.Sprintf("%s.%s()", e.Inputs[0], e.Agg)
+	case KindWindow:
+		return fmt.Sprintf("%s.over(%s)", e.Inputs[0], strings.Join(e.Partition, ", "))
+	case KindShift:
+		return fmt.Sprintf("%s.shift(%d)", e.Inputs[0], e.Offset)
+	case KindLiteral:
+		if s, ok := e.Value.(string); ok {
+			return fmt.Sprintf("lit(%q)", s)
+		}
+		if e.Value == nil {
+			return "lit(null)"
+		}
+		return fmt.Sprintf("lit(%v)", e.Value)
+	case KindBinary:
+		return fmt.Sprintf("(%s %s %s)", e.Inputs[0], e.Op, e.Inputs[1])
+	case KindNot:
+		return fmt.Sprintf("~%s", e.Inputs[0])
+	case KindCond:
+		return fmt.Sprintf("when(%s).then(%s).otherwise(%s)", e.Inputs[0], e.Inputs[1], e.Inputs[2])
+	default:
+		return fmt.Sprintf("expr(%d)", int(e.Kind))
+	}
+}
@@ -1,16 +1,47 @@
 """Python interface for go-polars."""
 
 from ._go_polars import DataFrame as _DataFrame
+from ._go_polars import aggregation_types as _aggregation_types
+from ._go_polars import bridge_version as _bridge_version
+from ._go_polars import cancel_job as _cancel_job
+from ._go_polars import poll_job as _poll_job
 from enum import Enum
+import json
 import numpy as np
 import ctypes
-
-class AggType(Enum):
-    SUM = 0
-    MEAN = 1
-    COUNT = 2
-    MIN = 3
-    MAX = 4
+import time
+
+# Version of the Go bridge API this wrapper is written against, and the
+# feature bits (see GetBridgeVersion) it relies on.
+_BRIDGE_MAJOR = 1
+_BRIDGE_MIN_MINOR = 7
+_FEATURE_ROW_ACCESS = 1 << 0
+_FEATURE_TYPE_QUERIES = 1 << 1
+_FEATURE_ASYNC_JOBS = 1 << 2
+_FEATURE_SHARED_MEMORY = 1 << 3
+_FEATURE_BATCH_FILTER = 1 << 5
+_FEATURE_LARGE_SIZES = 1 << 6
+_REQUIRED_FEATURES = (_FEATURE_ROW_ACCESS | _FEATURE_TYPE_QUERIES | _FEATURE_ASYNC_JOBS
+                      | _FEATURE_SHARED_MEMORY | _FEATURE_BATCH_FILTER | _FEATURE_LARGE_SIZES)
+
+
+def _check_bridge():
+    major, minor, patch, features = _bridge_version()
+    if major != _BRIDGE_MAJOR or minor < _BRIDGE_MIN_MINOR:
+        raise ImportError(
+            f"go_polars needs bridge version {_BRIDGE_MAJOR}.{_BRIDGE_MIN_MINOR}.x or a later "
+            f"{_BRIDGE_MAJOR}.x, but the shared library is {major}.{minor}.{patch}")
+    missing = _REQUIRED_FEATURES & ~features
+    if missing:
+        raise ImportError(f"go_polars shared library lacks required features (mask {missing:#x})")
+
+
+_check_bridge()
+
+# Aggregation codes come from the Go library so they cannot drift from its
+# enum; see GetAggregationTypes in the bridge.
+_AGG_CONTRACT = json.loads(_aggregation_types())
+AggType = Enum('AggType', {name.upper(): code for name, code in _AGG_CONTRACT['aggregations'].items()})
 
 class Series:
     """
@@ -76,7 +107,7 @@ class DataFrame:
         df = cls()
         for name, array in data.items():
             arr = np.asarray(array)
-            if arr.dtype not in [np.int64, np.float64, np.bool_]:
+            if arr.dtype not in [np.int64, np.float64, np.float32, np.bool_]:
                 raise TypeError(f"Unsupported dtype: {arr.dtype}")
             df._df.add_series(name, arr)
         return df
@@ -113,6 +144,90 @@ class DataFrame:
             raise RuntimeError("Failed to get head of DataFrame")
         return result
 
+    def slice(self, offset, length):
+        """
+        Return rows offset to offset + length of the DataFrame without
+        copying the column data.
+
+        Parameters
+        ----------
+        offset : int
+            Index of the first row
+        length : int
+            Number of rows; truncated at the end of the DataFrame
+
+        Returns
+        -------
+        DataFrame
+            The selected rows
+        """
+        result = DataFrame()
+        result._df = self._df.slice(offset, length)
+        return result
+
+    def filter(self, column, predicate):
+        """
+        Keep the rows where predicate is true for the value of column.
+
+        Parameters
+        ----------
+        column : str
+            Name of a numeric or boolean column
+        predicate : callable
+            Called with consecutive chunks of the column as numpy arrays,
+            returning a boolean array of the same length for each, so it
+            runs once per chunk rather than once per row
+
+        Returns
+        -------
+        DataFrame
+            The rows that passed
+
+        Examples
+        --------
+        >>> df.filter("amount", lambda values: values > 5.0)
+        """
+        result = DataFrame()
+        result._df = self._df.filter_batch(column, predicate)
+        return result
+
+    def row(self, index):
+        """
+        Return a single row as a dictionary.
+
+        Parameters
+        ----------
+        index : int
+            Position of the row
+
+        Returns
+        -------
+        dict
+            Column name to value, with None for missing values
+        """
+        return json.loads(self._df.get_row_json(index))
+
+    def to_shared_memory(self):
+        """
+        Write the DataFrame in the Arrow IPC file format into a new
+        shared-memory segment that other processes can map without copying.
+
+        Returns
+        -------
+        tuple
+            (name, size): the segment name for
+            multiprocessing.shared_memory.SharedMemory and the number of
+            bytes of IPC data at its start. Whoever reads it last must
+            unlink the segment.
+
+        Examples
+        --------
+        >>> name, size = df.to_shared_memory()
+        >>> shm = shared_memory.SharedMemory(name)
+        >>> table = pyarrow.ipc.open_file(pyarrow.py_buffer(shm.buf[:size])).read_all()
+        """
+        return tuple(self._df.to_shared_memory())
+
     def describe(self):
         """
         Generate descriptive statistics.
@@ -131,6 +246,11 @@ class DataFrame:
                 if series.dtype == np.bool_:
                     # Convert boolean to int64 for statistics
                     series = series.astype(np.int64)
+                if len(series) == 0:
+                    # Statistics of no values are undefined rather than errors
+                    stats[col] = {stat: np.nan for stat in ('mean', 'std', 'min', '25%', '50%', '75%', 'max')}
+                    stats[col]['count'] = 0
+                    continue
                 stats[col] = {
                     'count': len(series),
                     'mean': np.mean(series),
@@ -176,6 +296,28 @@ class DataFrame:
         sorted_df = self._df.sort_by_column(by, ascending)
         return self.__class__._from_internal(sorted_df)
 
+    def sort_values_async(self, by, ascending=True):
+        """
+        Start sorting DataFrame by the specified column in the background.
+
+        Parameters
+        ----------
+        by : str
+            Name of the column to sort by
+        ascending : bool, default True
+            Sort ascending vs. descending
+
+        Returns
+        -------
+        Job
+            A job whose result is the sorted DataFrame
+        """
+        if not isinstance(by, str):
+            raise TypeError("'by' must be a string")
+
+        job = self._df.submit_sort(by, ascending)
+        return Job(job, False, self.__class__._from_internal)
+
     def sort_index(self, ascending=True):
         """
         Sort DataFrame by the index.
@@ -223,6 +365,28 @@ class DataFrame:
             raise RuntimeError("Failed to group DataFrame")
         return GroupedDataFrame(grouped_df)
 
+    def groupby_async(self, by):
+        """
+        Start grouping DataFrame by one or more columns in the background.
+
+        Parameters
+        ----------
+        by : str or list of str
+            Column name(s) to group by
+
+        Returns
+        -------
+        Job
+            A job whose result is a GroupedDataFrame
+        """
+        if isinstance(by, str):
+            by = [by]
+        elif not isinstance(by, list) or not all(isinstance(col, str) for col in by):
+            raise TypeError("'by' must be a string or list of strings")
+
+        job = self._df.submit_group_by(by)
+        return Job(job, True, GroupedDataFrame)
+
     def columns(self):
         """
         Get the column names of the DataFrame.
@@ -321,6 +485,75 @@ class DataFrame:
         """
         return self.__str__()
 
+class Job:
+    """
+    An operation running in the Go library without holding the GIL, as
+    returned by DataFrame.sort_values_async and DataFrame.groupby_async.
+    """
+    def __init__(self, job_id, grouped, wrap):
+        self._id = job_id
+        self._grouped = grouped
+        self._wrap = wrap
+        self._result = None
+        self._finished = False
+        self._cancelled = False
+
+    def poll(self):
+        """
+        Check whether the job has finished, without blocking.
+
+        Returns
+        -------
+        bool
+            True once the result is available
+
+        Raises
+        ------
+        RuntimeError
+            If the operation failed or the job was cancelled
+        """
+        if self._cancelled:
+            raise RuntimeError("job was cancelled")
+        if not self._finished:
+            internal = _poll_job(self._id, self._grouped)
+            if internal is None:
+                return False
+            self._result = self._wrap(internal)
+            self._finished = True
+        return True
+
+    def result(self, timeout=None, interval=0.01):
+        """
+        Wait for the job to finish and return its result.
+
+        Parameters
+        ----------
+        timeout : float, optional
+            Seconds to wait before raising TimeoutError; wait forever if None
+        interval : float, default 0.01
+            Seconds to sleep between polls
+
+        Returns
+        -------
+        DataFrame or GroupedDataFrame
+            The result of the operation
+        """
+        deadline = None if timeout is None else time.monotonic() + timeout
+        while not self.poll():
+            if deadline is not None and time.monotonic() >= deadline:
+                raise TimeoutError("job did not finish in time")
+            time.sleep(interval)
+        return self._result
+
+    def cancel(self):
+        """
+        Cancel the job and discard its result. The Go operation stops at
+        its next check for cancellation, within a few thousand steps.
+        """
+        if not self._finished and not self._cancelled:
+            _cancel_job(self._id)
+            self._cancelled = True
+
 class GroupedDataFrame:
     """
     A grouped DataFrame.
@@ -351,13 +584,7 @@ class GroupedDataFrame:
         result = DataFrame()
         
         # Map string aggregation names to AggType enum
-        agg_map = {
-            'sum': AggType.SUM,
-            'mean': AggType.MEAN,
-            'count': AggType.COUNT,
-            'min': AggType.MIN,
-            'max': AggType.MAX
-        }
+        agg_map = {agg.name.lower(): agg for agg in AggType}
 
         # Process each aggregation
         for col, agg_name in aggs.items():
@@ -2,6 +2,7 @@
 #include <Python.h>
 #include "numpy/arrayobject.h"
 #include <stdint.h>
+#include <string.h>
 
 #ifdef __APPLE__
     #define LIB_NAME "libgo_polars.dylib"
@@ -17,17 +18,30 @@ static PyTypeObject GroupedDataFrameType;
 
 // Function declarations from Go - no underscore prefix
 extern int64_t NewDataFrame(void);
-extern int AddSeries(int64_t handle, const char* name, void* data, int length, int dtype);
-extern int GetShape(int64_t handle, int* rows, int* cols);
+extern int AddSeries64(int64_t handle, const char* name, void* data, int64_t length, int dtype);
+extern int GetShape64(int64_t handle, int64_t* rows, int64_t* cols);
 extern void DeleteDataFrame(int64_t handle);
 extern int64_t SortByColumn(int64_t handle, const char* column, int ascending);
 extern int64_t SortByIndex(int64_t handle, int ascending);
 extern int64_t GroupBy(int64_t handle, const char** columns, int num_columns);
 extern int64_t Aggregate(int64_t handle, const char* column, int agg_type);
 extern int64_t Head(int64_t handle, int n);
-extern void* GetSeries(int64_t handle, const char* name, int* length, int* dtype);
+extern void* GetSeries64(int64_t handle, const char* name, int64_t* length, int* dtype);
 extern char* GetColumn(int64_t handle, int index);
 extern int GetColumnCount(int64_t handle);
+extern char* GetRowJSON(int64_t handle, int64_t row);
+extern int64_t SliceHandle(int64_t handle, int64_t offset, int64_t length);
+extern char* GetAggregationTypes(void);
+extern char* GetDTypeName(int code);
+extern void GetBridgeVersion(int* major, int* minor, int* patch, uint64_t* features);
+extern int64_t SubmitSort(int64_t handle, const char* column, int ascending);
+extern int64_t SubmitGroupBy(int64_t handle, const char** columns, int num_columns);
+extern int PollJob(int64_t job, int64_t* result);
+extern int CancelJob(int64_t job);
+extern char* ExportSharedMemory(int64_t handle, int64_t* size);
+
+typedef int (*gopolars_predicate)(const void* values, int64_t n, int dtype, uint8_t* keep, void* ctx);
+extern int64_t FilterBatch(int64_t handle, const char* column, gopolars_predicate predicate, void* ctx);
 
 typedef struct {
     PyObject_HEAD
@@ -89,16 +103,19 @@ DataFrame_add_series(DataFrameObject *self, PyObject *args)
         case NPY_BOOL:
             dtype = 2;
             break;
+        case NPY_FLOAT32:
+            dtype = 3;
+            break;
         default:
             PyErr_SetString(PyExc_TypeError, "Unsupported dtype");
             return NULL;
     }
 
-    int result = AddSeries(
+    int result = AddSeries64(
         self->handle,
         name,
         PyArray_DATA(arr),
-        (int)PyArray_SIZE(arr),
+        (int64_t)PyArray_SIZE(arr),
         dtype
     );
 
@@ -113,12 +130,12 @@ DataFrame_add_series(DataFrameObject *self, PyObject *args)
 static PyObject *
 DataFrame_shape(DataFrameObject *self, PyObject *Py_UNUSED(ignored))
 {
-    int rows, cols;
-    if (GetShape(self->handle, &rows, &cols) != 0) {
+    int64_t rows, cols;
+    if (GetShape64(self->handle, &rows, &cols) != 0) {
         PyErr_SetString(PyExc_RuntimeError, "Failed to get shape");
         return NULL;
     }
-    return Py_BuildValue("(ii)", rows, cols);
+    return Py_BuildValue("(LL)", (long long)rows, (long long)cols);
 }
 
 static PyObject *
@@ -219,28 +236,24 @@ DataFrame_sort_by_index(DataFrameObject *self, PyObject *args)
     return (PyObject*)sorted;
 }
 
-static PyObject *
-DataFrame_group_by(DataFrameObject *self, PyObject *args)
+/* Convert a list of str into a malloc'ed array of UTF-8 names, which stay
+ * valid as long as the list does. The caller frees the array. */
+static const char **
+column_names(PyObject *columns_list, Py_ssize_t *num_columns)
 {
-    PyObject *columns_list;
-    
-    if (!PyArg_ParseTuple(args, "O", &columns_list)) {
-        return NULL;
-    }
-
     if (!PyList_Check(columns_list)) {
         PyErr_SetString(PyExc_TypeError, "Expected list of column names");
         return NULL;
     }
 
-    Py_ssize_t num_columns = PyList_Size(columns_list);
-    const char **columns = malloc(num_columns * sizeof(char*));
+    *num_columns = PyList_Size(columns_list);
+    const char **columns = malloc((*num_columns + 1) * sizeof(char*));
     if (!columns) {
         PyErr_NoMemory();
         return NULL;
     }
 
-    for (Py_ssize_t i = 0; i < num_columns; i++) {
+    for (Py_ssize_t i = 0; i < *num_columns; i++) {
         PyObject *item = PyList_GetItem(columns_list, i);
         if (!PyUnicode_Check(item)) {
             free(columns);
@@ -249,6 +262,23 @@ DataFrame_group_by(DataFrameObject *self, PyObject *args)
         }
         columns[i] = PyUnicode_AsUTF8(item);
     }
+    return columns;
+}
+
+static PyObject *
+DataFrame_group_by(DataFrameObject *self, PyObject *args)
+{
+    PyObject *columns_list;
+    
+    if (!PyArg_ParseTuple(args, "O", &columns_list)) {
+        return NULL;
+    }
+
+    Py_ssize_t num_columns;
+    const char **columns = column_names(columns_list, &num_columns);
+    if (!columns) {
+        return NULL;
+    }
 
     int64_t grouped_handle = GroupBy(self->handle, columns, (int)num_columns);
     free(columns);
@@ -267,6 +297,49 @@ DataFrame_group_by(DataFrameObject *self, PyObject *args)
     return (PyObject*)grouped;
 }
 
+static PyObject *
+DataFrame_submit_sort(DataFrameObject *self, PyObject *args)
+{
+    const char *column;
+    int ascending = 1;  // default to True
+
+    if (!PyArg_ParseTuple(args, "s|p", &column, &ascending)) {
+        return NULL;
+    }
+
+    int64_t job = SubmitSort(self->handle, column, ascending);
+    if (job == -1) {
+        PyErr_SetString(PyExc_RuntimeError, "Failed to submit sort");
+        return NULL;
+    }
+    return PyLong_FromLongLong(job);
+}
+
+static PyObject *
+DataFrame_submit_group_by(DataFrameObject *self, PyObject *args)
+{
+    PyObject *columns_list;
+
+    if (!PyArg_ParseTuple(args, "O", &columns_list)) {
+        return NULL;
+    }
+
+    Py_ssize_t num_columns;
+    const char **columns = column_names(columns_list, &num_columns);
+    if (!columns) {
+        return NULL;
+    }
+
+    int64_t job = SubmitGroupBy(self->handle, columns, (int)num_columns);
+    free(columns);
+
+    if (job == -1) {
+        PyErr_SetString(PyExc_RuntimeError, "Failed to submit group by");
+        return NULL;
+    }
+    return PyLong_FromLongLong(job);
+}
+
 static PyObject *
 DataFrame_head(DataFrameObject *self, PyObject *args)
 {
@@ -321,6 +394,135 @@ DataFrame_get_column(DataFrameObject *self, PyObject *args)
     return result;
 }
 
+static PyObject *
+DataFrame_get_row_json(DataFrameObject *self, PyObject *args)
+{
+    long long row;
+    if (!PyArg_ParseTuple(args, "L", &row)) {
+        return NULL;
+    }
+
+    char *json = GetRowJSON(self->handle, row);
+    if (json == NULL) {
+        PyErr_SetString(PyExc_IndexError, "Failed to get row");
+        return NULL;
+    }
+
+    PyObject *result = PyUnicode_FromString(json);
+    free(json);  // Free the C string
+    return result;
+}
+
+static PyObject *
+DataFrame_slice(DataFrameObject *self, PyObject *args)
+{
+    long long offset, length;
+    if (!PyArg_ParseTuple(args, "LL", &offset, &length)) {
+        return NULL;
+    }
+
+    int64_t slice_handle = SliceHandle(self->handle, offset, length);
+    if (slice_handle == -1) {
+        PyErr_SetString(PyExc_RuntimeError, "Failed to slice DataFrame");
+        return NULL;
+    }
+
+    DataFrameObject *slice = (DataFrameObject*)PyType_GenericNew(&DataFrameType, NULL, NULL);
+    if (!slice) {
+        return NULL;
+    }
+
+    slice->handle = slice_handle;
+    return (PyObject*)slice;
+}
+
+// Calls the Python predicate of DataFrame_filter_batch with one chunk of
+// values as a numpy array and copies the boolean array it returns into
+// keep. The chunk is copied since the callable may hold on to the array.
+static int
+call_python_predicate(const void *values, int64_t n, int dtype, uint8_t *keep, void *ctx)
+{
+    static const int np_types[] = {NPY_INT64, NPY_FLOAT64, NPY_BOOL, NPY_FLOAT32};
+    if (dtype < 0 || dtype > 3) {
+        PyErr_SetString(PyExc_RuntimeError, "Unknown dtype");
+        return -1;
+    }
+
+    npy_intp dims[1] = {(npy_intp)n};
+    PyObject *chunk = PyArray_SimpleNew(1, dims, np_types[dtype]);
+    if (chunk == NULL) {
+        return -1;
+    }
+    memcpy(PyArray_DATA((PyArrayObject *)chunk), values, PyArray_NBYTES((PyArrayObject *)chunk));
+
+    PyObject *result = PyObject_CallFunctionObjArgs((PyObject *)ctx, chunk, NULL);
+    Py_DECREF(chunk);
+    if (result == NULL) {
+        return -1;
+    }
+    PyArrayObject *mask = (PyArrayObject *)PyArray_FROMANY(result, NPY_BOOL, 1, 1, NPY_ARRAY_IN_ARRAY);
+    Py_DECREF(result);
+    if (mask == NULL) {
+        return -1;
+    }
+    if (PyArray_SIZE(mask) != n) {
+        PyErr_Format(PyExc_ValueError, "predicate returned %zd values for %lld",
+                     (Py_ssize_t)PyArray_SIZE(mask), (long long)n);
+        Py_DECREF(mask);
+        return -1;
+    }
+    memcpy(keep, PyArray_DATA(mask), (size_t)n);
+    Py_DECREF(mask);
+    return 0;
+}
+
+static PyObject *
+DataFrame_filter_batch(DataFrameObject *self, PyObject *args)
+{
+    const char *column;
+    PyObject *predicate;
+    if (!PyArg_ParseTuple(args, "sO", &column, &predicate)) {
+        return NULL;
+    }
+    if (!PyCallable_Check(predicate)) {
+        PyErr_SetString(PyExc_TypeError, "predicate must be callable");
+        return NULL;
+    }
+
+    int64_t filtered_handle = FilterBatch(self->handle, column, call_python_predicate, predicate);
+    if (filtered_handle == -1) {
+        // An exception raised by the predicate explains the failure better.
+        if (!PyErr_Occurred()) {
+            PyErr_SetString(PyExc_RuntimeError, "Failed to filter DataFrame");
+        }
+        return NULL;
+    }
+
+    DataFrameObject *filtered = (DataFrameObject*)PyType_GenericNew(&DataFrameType, NULL, NULL);
+    if (!filtered) {
+        DeleteDataFrame(filtered_handle);
+        return NULL;
+    }
+
+    filtered->handle = filtered_handle;
+    return (PyObject*)filtered;
+}
+
+static PyObject *
+DataFrame_to_shared_memory(DataFrameObject *self, PyObject *Py_UNUSED(ignored))
+{
+    int64_t size;
+    char *name = ExportSharedMemory(self->handle, &size);
+    if (name == NULL) {
+        PyErr_SetString(PyExc_RuntimeError, "Failed to export DataFrame to shared memory");
+        return NULL;
+    }
+
+    PyObject *result = Py_BuildValue("(sL)", name, (long long)size);
+    free(name);  // Free the C string
+    return result;
+}
+
 static PyObject *
 DataFrame_get_series(DataFrameObject *self, PyObject *args)
 {
@@ -329,8 +531,9 @@ DataFrame_get_series(DataFrameObject *self, PyObject *args)
         return NULL;
     }
 
-    int length, dtype;
-    void *data = GetSeries(self->handle, name, &length, &dtype);
+    int64_t length;
+    int dtype;
+    void *data = GetSeries64(self->handle, name, &length, &dtype);
     if (data == NULL) {
         PyErr_SetString(PyExc_RuntimeError, "Failed to get series");
         return NULL;
@@ -348,6 +551,9 @@ DataFrame_get_series(DataFrameObject *self, PyObject *args)
         case 2:  // bool
             np_type = NPY_BOOL;
             break;
+        case 3:  // float32
+            np_type = NPY_FLOAT32;
+            break;
         default:
             PyErr_SetString(PyExc_RuntimeError, "Unknown dtype");
             return NULL;
@@ -430,6 +636,18 @@ static PyMethodDef DataFrame_methods[] = {
      "Get the name of a column by index"},
     {"get_series", (PyCFunction)DataFrame_get_series, METH_VARARGS,
      "Get a series from the DataFrame"},
+    {"get_row_json", (PyCFunction)DataFrame_get_row_json, METH_VARARGS,
+     "Get a row of the DataFrame as a JSON object"},
+    {"slice", (PyCFunction)DataFrame_slice, METH_VARARGS,
+     "Get a range of rows of the DataFrame"},
+    {"submit_sort", (PyCFunction)DataFrame_submit_sort, METH_VARARGS,
+     "Start sorting the DataFrame by a column in the background"},
+    {"submit_group_by", (PyCFunction)DataFrame_submit_group_by, METH_VARARGS,
+     "Start grouping the DataFrame by columns in the background"},
+    {"to_shared_memory", (PyCFunction)DataFrame_to_shared_memory, METH_NOARGS,
+     "Write the DataFrame as Arrow IPC into a shared-memory segment"},
+    {"filter_batch", (PyCFunction)DataFrame_filter_batch, METH_VARARGS,
+     "Keep the rows where a predicate over chunks of a column is true"},
     {NULL}  /* Sentinel */
 };
 
@@ -445,11 +663,113 @@ static PyTypeObject DataFrameType = {
     .tp_methods = DataFrame_methods,
 };
 
+static PyObject *
+module_aggregation_types(PyObject *module, PyObject *Py_UNUSED(ignored))
+{
+    char *json = GetAggregationTypes();
+    if (json == NULL) {
+        PyErr_SetString(PyExc_RuntimeError, "Failed to get aggregation types");
+        return NULL;
+    }
+
+    PyObject *result = PyUnicode_FromString(json);
+    free(json);  // Free the C string
+    return result;
+}
+
+static PyObject *
+module_dtype_name(PyObject *module, PyObject *args)
+{
+    int code;
+    if (!PyArg_ParseTuple(args, "i", &code)) {
+        return NULL;
+    }
+
+    char *name = GetDTypeName(code);
+    if (name == NULL) {
+        Py_RETURN_NONE;
+    }
+
+    PyObject *result = PyUnicode_FromString(name);
+    free(name);  // Free the C string
+    return result;
+}
+
+static PyObject *
+module_bridge_version(PyObject *module, PyObject *Py_UNUSED(ignored))
+{
+    int major, minor, patch;
+    uint64_t features;
+    GetBridgeVersion(&major, &minor, &patch, &features);
+    return Py_BuildValue("(iiiK)", major, minor, patch, (unsigned long long)features);
+}
+
+static PyObject *
+module_poll_job(PyObject *module, PyObject *args)
+{
+    long long job;
+    int grouped = 0;
+    if (!PyArg_ParseTuple(args, "L|p", &job, &grouped)) {
+        return NULL;
+    }
+
+    int64_t result;
+    switch (PollJob(job, &result)) {
+        case 0:  // still running
+            Py_RETURN_NONE;
+        case 1:  // done
+            break;
+        default:
+            PyErr_SetString(PyExc_RuntimeError, "Job failed");
+            return NULL;
+    }
+
+    // Both object types only hold the handle
+    PyTypeObject *type = grouped ? &GroupedDataFrameType : &DataFrameType;
+    DataFrameObject *df = (DataFrameObject*)PyType_GenericNew(type, NULL, NULL);
+    if (!df) {
+        DeleteDataFrame(result);
+        return NULL;
+    }
+    df->handle = result;
+    return (PyObject*)df;
+}
+
+static PyObject *
+module_cancel_job(PyObject *module, PyObject *args)
+{
+    long long job;
+    if (!PyArg_ParseTuple(args, "L", &job)) {
+        return NULL;
+    }
+
+    if (CancelJob(job) != 0) {
+        PyErr_SetString(PyExc_RuntimeError, "Unknown job");
+        return NULL;
+    }
+    Py_RETURN_NONE;
+}
+
+static PyMethodDef module_methods[] = {
+    {"bridge_version", (PyCFunction)module_bridge_version, METH_NOARGS,
+     "Get the (major, minor, patch, features) version of the Go library"},
+    {"aggregation_types", (PyCFunction)module_aggregation_types, METH_NOARGS,
+     "Get the aggregation codes as a JSON object"},
+    {"dtype_name", (PyCFunction)module_dtype_name, METH_VARARGS,
+     "Get the name of a column type code"},
+    {"poll_job", (PyCFunction)module_poll_job, METH_VARARGS,
+     "Get the result of a background job, or None while it runs"},
+    {"cancel_job", (PyCFunction)module_cancel_job, METH_VARARGS,
+     "Cancel a background job and discard its result"},
+    {NULL}  /* Sentinel */
+};
+
 static PyModuleDef go_polarsmodule = {
     PyModuleDef_HEAD_INIT,
     .m_name = "go_polars._go_polars",
     .m_doc = "Python interface for go-polars.",
     .m_size = -1,
+    .m_methods = module_methods,
 };
 
 PyMODINIT_FUNC
@@ -96,7 +96,15 @@ extern "C" {
 
 extern int64_t NewDataFrame();
 extern int AddSeries(int64_t hID, char* name, void* data, int length, int dtype);
+
+// AddSeries64 is AddSeries with a 64-bit length.
+//
+extern int AddSeries64(int64_t hID, char* name, void* data, int64_t length, int dtype);
 extern int GetShape(int64_t hID, int* rows, int* cols);
+
+// GetShape64 is GetShape with 64-bit counts.
+//
+extern int GetShape64(int64_t hID, int64_t* rows, int64_t* cols);
 extern void DeleteDataFrame(int64_t hID);
 extern int64_t SortByColumn(int64_t hID, char* column, int asc);
 extern int64_t SortByIndex(int64_t hID, int asc);
@@ -107,6 +115,91 @@ extern int GetColumnCount(int64_t hID);
 extern char* GetColumn(int64_t hID, int idx);
 extern void* GetSeries(int64_t hID, char* name, int* length, int* dtype);
 
+// GetSeries64 is GetSeries with a 64-bit count.
+//
+extern void* GetSeries64(int64_t hID, char* name, int64_t* length, int* dtype);
+
+// GetRowJSON returns row i as a JSON object keyed by column name, in column
+// order, with nulls and non-finite floats as null. The caller frees the
+// string.
+//
+extern char* GetRowJSON(int64_t hID, int64_t row);
+
+// SliceHandle registers the rows [offset, offset+length) of a frame as a new
+// handle sharing the column data, so callers can page through large frames.
+//
+extern int64_t SliceHandle(int64_t hID, int64_t offset, int64_t length);
+
+// GetAggregationTypes returns the aggregation codes accepted by Aggregate as
+// a JSON object {"version": n, "aggregations": {"sum": 0, ...}}. The caller
+// frees the string.
+//
+extern char* GetAggregationTypes();
+
+// GetDTypeName returns the name of a column type code, or NULL for an
+// unknown code. The caller frees the string.
+//
+extern char* GetDTypeName(int code);
+
+// GetBridgeVersion reports the API version of the library and the feature
+// bits it supports. Bindings call it when loading the library and refuse to
+// run against an incompatible major version.
+//
+extern void GetBridgeVersion(int* major, int* minor, int* patch, uint64_t* features);
+
+// SubmitSort starts SortByColumn as a job and returns its id, or -1 for an
+// unknown handle.
+//
+extern int64_t SubmitSort(int64_t hID, char* column, int asc);
+
+// SubmitGroupBy starts GroupBy as a job and returns its id, or -1 for an
+// unknown handle.
+//
+extern int64_t SubmitGroupBy(int64_t hID, char** cols, int n);
+
+// PollJob reports the state of a job without blocking. Once it returns done
+// (1), the result handle is stored in *result, is owned by the caller and the
+// job id is released; failed (-1) also releases it. A job still running
+// returns 0.
+//
+extern int PollJob(int64_t jobID, int64_t* result);
+
+// CancelJob releases a job and discards its result. An operation already
+// running cannot be interrupted; it finishes in the background and its
+// result is dropped. It returns 0, or -1 for an unknown job.
+//
+extern int CancelJob(int64_t jobID);
+
+// ExportSharedMemory writes a frame in the Arrow IPC file format into a new
+// POSIX shared-memory segment and returns the segment's name, without the
+// leading slash, storing its size in bytes in *size. Other processes map it
+// by name, e.g. with Python's multiprocessing.shared_memory, and read the
+// first *size bytes; the segment may be rounded up to a page. The caller
+// frees the string and unlinks the segment. It returns NULL on failure.
+//
+extern char* ExportSharedMemory(int64_t hID, int64_t* size);
+
+struct ArrowSchema;
+struct ArrowArray;
+
+// ExportArrow exports a frame through the Arrow C Data Interface as a struct
+// array with one child per column, filling the caller-allocated schema and
+// array. The columns are copied once into Arrow buffers, which the consumer
+// then reads in place and frees by calling the release callbacks. It returns
+// 0, or -1 on failure.
+//
+extern int ExportArrow(int64_t hID, struct ArrowSchema* schema, struct ArrowArray* array);
+
+typedef int (*gopolars_predicate)(const void* values, int64_t n, int dtype, uint8_t* keep, void* ctx);
+
+// FilterBatch keeps the rows of a frame where predicate, called with ctx on
+// consecutive chunks of the values of a numeric or Boolean column, sets
+// keep, instead of calling back once per row. Rows where the column is null
+// are dropped. It returns the handle of the result, or -1 on failure or
+// when predicate returns non-zero.
+//
+extern int64_t FilterBatch(int64_t hID, char* column, gopolars_predicate predicate, void* ctx);
+
 #ifdef __cplusplus
 }
 #endif
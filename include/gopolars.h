@@ -0,0 +1,261 @@
+/* Code generated by gen_header.go; DO NOT EDIT. */
+
+/* gopolars.h is the C API of the go-polars shared library. Frames live in
+ * the library and are referenced by int64_t handles; every handle returned
+ * by a function must be released with DeleteDataFrame. */
+
+#ifndef GOPOLARS_H
+#define GOPOLARS_H
+
+#include <stdint.h>
+
+// Arrow C Data Interface, copied verbatim from the specification at
+// https://arrow.apache.org/docs/format/CDataInterface.html as it
+// recommends. The guard lets it coexist with other copies.
+
+#ifndef ARROW_C_DATA_INTERFACE
+#define ARROW_C_DATA_INTERFACE
+
+#include <stdint.h>
+
+#define ARROW_FLAG_DICTIONARY_ORDERED 1
+#define ARROW_FLAG_NULLABLE 2
+#define ARROW_FLAG_MAP_KEYS_SORTED 4
+
+struct ArrowSchema {
+  // Array type description
+  const char* format;
+  const char* name;
+  const char* metadata;
+  int64_t flags;
+  int64_t n_children;
+  struct ArrowSchema** children;
+  struct ArrowSchema* dictionary;
+
+  // Release callback
+  void (*release)(struct ArrowSchema*);
+  // Opaque producer-specific data
+  void* private_data;
+};
+
+struct ArrowArray {
+  // Array data description
+  int64_t length;
+  int64_t null_count;
+  int64_t offset;
+  int64_t n_buffers;
+  int64_t n_children;
+  const void** buffers;
+  struct ArrowArray** children;
+  struct ArrowArray* dictionary;
+
+  // Release callback
+  void (*release)(struct ArrowArray*);
+  // Opaque producer-specific data
+  void* private_data;
+};
+
+#endif  // ARROW_C_DATA_INTERFACE
+
+// Callback types of the go-polars C API. The guard lets the header be
+// included by every file of the bridge that needs them.
+
+#ifndef GOPOLARS_CALLBACKS_H
+#define GOPOLARS_CALLBACKS_H
+
+#include <stdint.h>
+
+// gopolars_predicate decides which of n values of type dtype, a
+// gopolars_dtype code, to keep, setting keep[i] to 1 or 0 for each. The
+// values and keep are only valid during the call. ctx is passed through
+// unchanged. A non-zero return aborts the operation.
+typedef int (*gopolars_predicate)(const void* values, int64_t n, int dtype, uint8_t* keep, void* ctx);
+
+#endif  // GOPOLARS_CALLBACKS_H
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+// Semantic version of the exported C API. The major version changes when an
+// export is removed or changes signature or meaning, the minor version when
+// exports are added.
+#define GOPOLARS_VERSION_MAJOR 1
+#define GOPOLARS_VERSION_MINOR 7
+#define GOPOLARS_VERSION_PATCH 0
+
+// Version of the type and aggregation code tables, incremented whenever a
+// code is added to or dropped from either.
+#define GOPOLARS_CONTRACT_VERSION 2
+
+// Column type codes used by AddSeries and GetSeries. They are part of the
+// bridge contract: a code never changes meaning once published.
+enum gopolars_dtype {
+  GOPOLARS_DTYPE_INT64 = 0,
+  GOPOLARS_DTYPE_FLOAT64 = 1,
+  GOPOLARS_DTYPE_BOOL = 2,
+  GOPOLARS_DTYPE_FLOAT32 = 3,
+};
+
+// Job states returned by PollJob.
+enum gopolars_job_state {
+  GOPOLARS_JOB_FAILED = -1,
+  GOPOLARS_JOB_RUNNING = 0,
+  GOPOLARS_JOB_DONE = 1,
+};
+
+// Feature bits reported by GetBridgeVersion, so bindings can check for the
+// exports they rely on.
+#define GOPOLARS_FEATURE_ROW_ACCESS UINT64_C(0x1)
+#define GOPOLARS_FEATURE_TYPE_QUERIES UINT64_C(0x2)
+#define GOPOLARS_FEATURE_ASYNC_JOBS UINT64_C(0x4)
+#define GOPOLARS_FEATURE_SHARED_MEMORY UINT64_C(0x8)
+#define GOPOLARS_FEATURE_ARROW_EXPORT UINT64_C(0x10)
+#define GOPOLARS_FEATURE_BATCH_FILTER UINT64_C(0x20)
+#define GOPOLARS_FEATURE_LARGE_SIZES UINT64_C(0x40)
+
+// Aggregation codes accepted by Aggregate.
+enum gopolars_agg {
+  GOPOLARS_AGG_SUM = 0,
+  GOPOLARS_AGG_MEAN = 1,
+  GOPOLARS_AGG_COUNT = 2,
+  GOPOLARS_AGG_MIN = 3,
+  GOPOLARS_AGG_MAX = 4,
+  GOPOLARS_AGG_VAR = 5,
+  GOPOLARS_AGG_STD = 6,
+  GOPOLARS_AGG_MEDIAN = 7,
+  GOPOLARS_AGG_QUANTILE = 8,
+  GOPOLARS_AGG_FIRST = 9,
+  GOPOLARS_AGG_LAST = 10,
+  GOPOLARS_AGG_N_UNIQUE = 11,
+};
+
+// NewDataFrame registers an empty frame and returns its handle, or -1.
+int64_t NewDataFrame(void);
+
+// AddSeries adds, or replaces, column name of a frame with length values of
+// type dtype read from data, which must stay valid while the frame is used.
+// It returns 0, or -1 on failure. AddSeries64 takes columns of any length.
+int AddSeries(int64_t handle, char* name, void* data, int length, int dtype);
+
+// GetShape stores the number of rows and columns of a frame. It returns 0,
+// or -1 for an unknown handle or when a count does not fit in an int, see
+// GetShape64.
+int GetShape(int64_t handle, int* rows, int* cols);
+
+// DeleteDataFrame releases a handle. Unknown handles are ignored.
+void DeleteDataFrame(int64_t handle);
+
+// SortByColumn returns the handle of a copy of the frame sorted by column,
+// ascending unless ascending is 0, or -1 on failure.
+int64_t SortByColumn(int64_t handle, char* column, int ascending);
+
+// SortByIndex returns the handle of a copy of the frame in ascending row
+// order, or reversed when ascending is 0, or -1 on failure.
+int64_t SortByIndex(int64_t handle, int ascending);
+
+// GroupBy groups a frame by num_columns columns and returns the handle of
+// the grouped frame for Aggregate, or -1 on failure.
+int64_t GroupBy(int64_t handle, char** columns, int num_columns);
+
+// Aggregate reduces column of a grouped frame with the aggregation code
+// agg_type (see GetAggregationTypes) and returns the handle of the result,
+// or -1 on failure.
+int64_t Aggregate(int64_t handle, char* column, int agg_type);
+
+// Head returns the handle of the first n rows of a frame, or -1.
+int64_t Head(int64_t handle, int n);
+
+// GetSeries returns a pointer to the values of a numeric or Boolean column
+// and stores their count and type code, or returns NULL. The values belong
+// to the frame and stay valid until it is deleted. Columns of more values
+// than an int holds give NULL, see GetSeries64.
+void* GetSeries(int64_t handle, char* name, int* length, int* dtype);
+
+// GetColumn returns the name of column index, or NULL when out of range.
+// The caller frees the string.
+char* GetColumn(int64_t handle, int index);
+
+// GetColumnCount returns the number of columns of a frame, or -1.
+int GetColumnCount(int64_t handle);
+
+// GetRowJSON returns row i as a JSON object keyed by column name, in column
+// order, with nulls and non-finite floats as null. The caller frees the
+// string.
+char* GetRowJSON(int64_t handle, int64_t row);
+
+// SliceHandle registers the rows [offset, offset+length) of a frame as a new
+// handle sharing the column data, so callers can page through large frames.
+int64_t SliceHandle(int64_t handle, int64_t offset, int64_t length);
+
+// GetAggregationTypes returns the aggregation codes accepted by Aggregate as
+// a JSON object {"version": n, "aggregations": {"sum": 0, ...}}. The caller
+// frees the string.
+char* GetAggregationTypes(void);
+
+// GetDTypeName returns the name of a column type code, or NULL for an
+// unknown code. The caller frees the string.
+char* GetDTypeName(int code);
+
+// GetBridgeVersion reports the API version of the library and the feature
+// bits it supports. Bindings call it when loading the library and refuse to
+// run against an incompatible major version.
+void GetBridgeVersion(int* major, int* minor, int* patch, uint64_t* features);
+
+// SubmitSort starts SortByColumn as a job and returns its id, or -1 for an
+// unknown handle. The job sorts the columns the frame has now; the handle
+// may be changed while it runs.
+int64_t SubmitSort(int64_t handle, char* column, int ascending);
+
+// SubmitGroupBy starts GroupBy as a job and returns its id, or -1 for an
+// unknown handle. Like SubmitSort, it works on the columns the frame has
+// now.
+int64_t SubmitGroupBy(int64_t handle, char** columns, int num_columns);
+
+// PollJob reports the state of a job without blocking. Once it returns done
+// (1), the result handle is stored in *result, is owned by the caller and the
+// job id is released; failed (-1) also releases it. A job still running
+// returns 0.
+int PollJob(int64_t job, int64_t* result);
+
+// CancelJob releases a job and discards its result. An operation still
+// running is interrupted at its next check of the job's context, which it
+// makes every few thousand steps. It returns 0, or -1 for an unknown job.
+int CancelJob(int64_t job);
+
+// ExportSharedMemory writes a frame in the Arrow IPC file format into a new
+// POSIX shared-memory segment and returns the segment's name, without the
+// leading slash, storing its size in bytes in *size. Other processes map it
+// by name, e.g. with Python's multiprocessing.shared_memory, and read the
+// first *size bytes; the segment may be rounded up to a page. The caller
+// frees the string and unlinks the segment. It returns NULL on failure.
+char* ExportSharedMemory(int64_t handle, int64_t* size);
+
+// ExportArrow exports a frame through the Arrow C Data Interface as a struct
+// array with one child per column, filling the caller-allocated schema and
+// array. The columns are copied once into Arrow buffers, which the consumer
+// then reads in place and frees by calling the release callbacks. It returns
+// 0, or -1 on failure.
+int ExportArrow(int64_t handle, struct ArrowSchema* schema, struct ArrowArray* array);
+
+// FilterBatch keeps the rows of a frame where predicate, called with ctx on
+// consecutive chunks of the values of a numeric or Boolean column, sets
+// keep, instead of calling back once per row. Rows where the column is null
+// are dropped. It returns the handle of the result, or -1 on failure or
+// when predicate returns non-zero.
+int64_t FilterBatch(int64_t handle, char* column, gopolars_predicate predicate, void* ctx);
+
+// AddSeries64 is AddSeries with a 64-bit length.
+int AddSeries64(int64_t handle, char* name, void* data, int64_t length, int dtype);
+
+// GetShape64 is GetShape with 64-bit counts.
+int GetShape64(int64_t handle, int64_t* rows, int64_t* cols);
+
+// GetSeries64 is GetSeries with a 64-bit count.
+void* GetSeries64(int64_t handle, char* name, int64_t* length, int* dtype);
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif  // GOPOLARS_H
@@ -0,0 +1,454 @@
+package incremental
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+)
+
+// Agg is one aggregation maintained by a View
+type Agg struct {
+	Column string
+	Type   dataframe.AggregationType
+	// Name is the name of the result column, Column when empty.
+	Name string
+}
+
+func (a Agg) name() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.Column
+}
+
+// View is a group-by aggregation over a base frame that is kept up to date
+// as batches of rows are appended to it, such as the totals behind a
+// dashboard fed by a stream. Append aggregates just the new rows and merges
+// them into the groups, so each update costs the size of the batch rather
+// than of everything appended so far. The result is that of GroupedDataFrame
+// .Aggregate over the base and batches concatenated, with the groups in
+// order of their first appearance as after GroupedDataFrame.Groups; Var and
+// Std may differ in the last bits, as they are merged from the moments of
+// each batch. Median, Quantile, NUnique and GeoMean need every value and
+// cannot be maintained. A View is safe for concurrent use.
+type View struct {
+	by     []string
+	aggs   []Agg
+	schema map[string]types.DataType
+
+	mu    sync.Mutex
+	slots map[string]int    // group of every key, see groupKey
+	accs  []accumulator     // one per aggregation
+	parts [][]*types.Series // keys split by batch, concatenated by Result
+	rows  int               // rows folded in so far
+}
+
+// NewView starts maintaining the aggregations aggs of base grouped by the
+// columns by.
+func NewView(base *dataframe.DataFrame, by []string, aggs ...Agg) (*View, error) {
+	if len(by) == 0 {
+		return nil, fmt.Errorf("incremental view needs at least one group column")
+	}
+	if len(aggs) == 0 {
+		return nil, fmt.Errorf("incremental view needs at least one aggregation")
+	}
+	v := &View{by: by, aggs: aggs, schema: make(map[string]types.DataType), slots: make(map[string]int)}
+	names := make(map[string]bool, len(by)+len(aggs))
+	// The first part holds no groups but gives Result the key types.
+	empty := make([]*types.Series, len(by))
+	for i, col := range by {
+		s, err := column(base, col)
+		if err != nil {
+			return nil, err
+		}
+		v.schema[col] = s.DataType
+		names[col] = true
+		empty[i] = s.Take([]int{})
+	}
+	v.parts = [][]*types.Series{empty}
+	for _, a := range aggs {
+		s, err := column(base, a.Column)
+		if err != nil {
+			return nil, err
+		}
+		if names[a.name()] {
+			return nil, fmt.Errorf("duplicate result column %s", a.name())
+		}
+		names[a.name()] = true
+		v.schema[a.Column] = s.DataType
+		acc, err := newAccumulator(a, s.DataType)
+		if err != nil {
+			return nil, err
+		}
+		v.accs = append(v.accs, acc)
+	}
+	if err := v.Append(base); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Append merges the rows of batch, which must hold the group and aggregated
+// columns with the types of the base frame, into the groups. On error the
+// View is left as it was.
+func (v *View) Append(batch *dataframe.DataFrame) error {
+	for col, dt := range v.schema {
+		s, err := column(batch, col)
+		if err != nil {
+			return err
+		}
+		if s.DataType.String() != dt.String() {
+			return fmt.Errorf("column %s is %s in the batch but %s in the view", col, s.DataType, dt)
+		}
+	}
+	rows, _ := batch.Shape()
+	if rows == 0 {
+		return nil
+	}
+	gdf, err := batch.GroupBy(v.by)
+	if err != nil {
+		return err
+	}
+	groups, err := gdf.Groups()
+	if err != nil {
+		return err
+	}
+	partials := make([][]*types.Series, len(v.accs))
+	for i, acc := range v.accs {
+		if partials[i], err = acc.partials(gdf); err != nil {
+			return err
+		}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	var fresh []int
+	for g := 0; g < groups.Len(); g++ {
+		key := groupKey(groups.Keys, g)
+		slot, ok := v.slots[key]
+		if !ok {
+			slot = len(v.slots)
+			v.slots[key] = slot
+			fresh = append(fresh, g)
+		}
+		for i, acc := range v.accs {
+			acc.fold(partials[i], g, slot, !ok)
+		}
+	}
+	if len(fresh) > 0 {
+		part := make([]*types.Series, len(v.by))
+		for i, k := range groups.Keys {
+			part[i] = k.Take(fresh)
+		}
+		v.parts = append(v.parts, part)
+	}
+	v.rows += rows
+	return nil
+}
+
+// Rows returns the number of rows aggregated so far, base included
+func (v *View) Rows() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.rows
+}
+
+// Result returns the current aggregates: the group columns followed by one
+// column per aggregation, one row per group.
+func (v *View) Result() (*dataframe.DataFrame, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	frames := make([]*dataframe.DataFrame, len(v.parts))
+	for i, part := range v.parts {
+		var err error
+		if frames[i], err = dataframe.FromColumns(part); err != nil {
+			return nil, err
+		}
+	}
+	keys, err := dataframe.Concat(frames, dataframe.HowVertical)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]*types.Series, len(v.accs))
+	for i, acc := range v.accs {
+		columns[i] = acc.result(v.aggs[i].name())
+	}
+	return keys.WithColumns(columns...)
+}
+
+// groupKey encodes the key of group g so that equal keys, and only those,
+// give equal strings.
+func groupKey(keys []*types.Series, g int) string {
+	var b strings.Builder
+	for _, k := range keys {
+		if !k.IsValid(g) {
+			b.WriteString("-;")
+			continue
+		}
+		var v string
+		if cat, ok := k.Data.(*types.Categorical); ok {
+			v = cat.Value(g)
+		} else {
+			v = fmt.Sprint(reflect.ValueOf(k.Data).Index(g).Interface())
+		}
+		b.WriteString(strconv.Itoa(len(v)))
+		b.WriteByte(':')
+		b.WriteString(v)
+	}
+	return b.String()
+}
+
+// accumulator maintains one aggregation per group. partials aggregates a
+// batch into the Series fold merges; fold merges those of batch group g
+// into group slot, which is new, and then the next slot, when fresh is set.
+type accumulator interface {
+	partials(gdf *dataframe.GroupedDataFrame) ([]*types.Series, error)
+	fold(parts []*types.Series, g, slot int, fresh bool)
+	result(name string) *types.Series
+}
+
+func newAccumulator(a Agg, dt types.DataType) (accumulator, error) {
+	switch a.Type {
+	case dataframe.Median, dataframe.Quantile, dataframe.NUnique, dataframe.GeoMean:
+		return nil, fmt.Errorf("%s aggregation cannot be maintained incrementally", a.Type)
+	case dataframe.Any, dataframe.All:
+		return &boolAcc{column: a.Column, kind: a.Type}, nil
+	case dataframe.Var, dataframe.Std:
+		return &momentAcc{column: a.Column, kind: a.Type}, nil
+	}
+	switch dt.(type) {
+	case types.Float64Type, types.Float32Type:
+		return newNumericAcc[float64](a), nil
+	default:
+		// Integers are aggregated as Int64; other types fail on the first
+		// batch as they would in Aggregate.
+		return newNumericAcc[int64](a), nil
+	}
+}
+
+// numericAcc maintains the aggregations whose results merge into a value of
+// the column's aggregation type, and Mean from a sum and a count.
+type numericAcc[T int64 | float64] struct {
+	column string
+	kind   dataframe.AggregationType
+	data   []T
+	valid  []bool
+	counts []T // of Mean
+}
+
+func newNumericAcc[T int64 | float64](a Agg) *numericAcc[T] {
+	return &numericAcc[T]{column: a.Column, kind: a.Type}
+}
+
+func (m *numericAcc[T]) partials(gdf *dataframe.GroupedDataFrame) ([]*types.Series, error) {
+	if m.kind == dataframe.Mean {
+		return aggregateAll(gdf, m.column, dataframe.Sum, dataframe.Count)
+	}
+	return aggregateAll(gdf, m.column, m.kind)
+}
+
+func (m *numericAcc[T]) fold(parts []*types.Series, g, slot int, fresh bool) {
+	v, ok := valueAt[T](parts[0], g)
+	if fresh {
+		m.data, m.valid = append(m.data, v), append(m.valid, ok)
+		if m.kind == dataframe.Mean {
+			n, _ := valueAt[T](parts[1], g)
+			m.counts = append(m.counts, n)
+		}
+		return
+	}
+	switch {
+	case m.kind == dataframe.Last:
+		m.data[slot], m.valid[slot] = v, ok
+	case m.kind == dataframe.First || !ok:
+	case !m.valid[slot]:
+		m.data[slot], m.valid[slot] = v, true
+	default:
+		m.data[slot] = merge(m.kind, m.data[slot], v)
+		if m.kind == dataframe.Mean {
+			n, _ := valueAt[T](parts[1], g)
+			m.counts[slot] += n
+		}
+	}
+}
+
+// merge combines the results of kind over two sets of rows
+func merge[T int64 | float64](kind dataframe.AggregationType, a, b T) T {
+	switch kind {
+	case dataframe.Min:
+		return min(a, b)
+	case dataframe.Max:
+		return max(a, b)
+	case dataframe.Product:
+		return a * b
+	case dataframe.BitAnd:
+		return T(int64(a) & int64(b))
+	case dataframe.BitOr:
+		return T(int64(a) | int64(b))
+	case dataframe.BitXor:
+		return T(int64(a) ^ int64(b))
+	default: // Sum, Mean's sum, Count and NullCount
+		return a + b
+	}
+}
+
+func (m *numericAcc[T]) result(name string) *types.Series {
+	if m.kind != dataframe.Mean {
+		return types.NewNullableSeries(name, append([]T(nil), m.data...), append([]bool(nil), m.valid...))
+	}
+	data := make([]T, len(m.data))
+	valid := make([]bool, len(m.data))
+	for i, sum := range m.data {
+		if m.counts[i] != 0 {
+			data[i], valid[i] = sum/m.counts[i], true
+		}
+	}
+	return types.NewNullableSeries(name, data, valid)
+}
+
+// momentAcc maintains Var and Std from the count, mean and sum of squared
+// deviations of every group, merged as the streaming aggregation does.
+type momentAcc struct {
+	column string
+	kind   dataframe.AggregationType
+	counts []float64
+	means  []float64
+	m2     []float64
+}
+
+func (m *momentAcc) partials(gdf *dataframe.GroupedDataFrame) ([]*types.Series, error) {
+	return aggregateAll(gdf, m.column, dataframe.Count, dataframe.Sum, dataframe.Var)
+}
+
+func (m *momentAcc) fold(parts []*types.Series, g, slot int, fresh bool) {
+	n, mean, m2 := floatAt(parts[0], g), 0.0, 0.0
+	if n > 0 {
+		mean = floatAt(parts[1], g) / n
+	}
+	if n > 1 {
+		m2 = floatAt(parts[2], g) * (n - 1)
+	}
+	if fresh {
+		m.counts, m.means, m.m2 = append(m.counts, n), append(m.means, mean), append(m.m2, m2)
+		return
+	}
+	if n == 0 {
+		return
+	}
+	// Chan et al.'s pairwise update of the moments.
+	total := m.counts[slot] + n
+	delta := mean - m.means[slot]
+	m.means[slot] += delta * n / total
+	m.m2[slot] += m2 + delta*delta*m.counts[slot]*n/total
+	m.counts[slot] = total
+}
+
+func (m *momentAcc) result(name string) *types.Series {
+	data := make([]float64, len(m.counts))
+	valid := make([]bool, len(m.counts))
+	for i, n := range m.counts {
+		if n < 2 {
+			continue
+		}
+		data[i], valid[i] = m.m2[i]/(n-1), true
+		if m.kind == dataframe.Std {
+			data[i] = math.Sqrt(data[i])
+		}
+	}
+	return types.NewNullableSeries(name, data, valid)
+}
+
+// boolAcc maintains Any and All with three-valued logic: decided records a
+// value settling the result, true for Any and false for All, and nulls a
+// null that makes an unsettled result null.
+type boolAcc struct {
+	column  string
+	kind    dataframe.AggregationType
+	decided []bool
+	nulls   []bool
+}
+
+func (m *boolAcc) partials(gdf *dataframe.GroupedDataFrame) ([]*types.Series, error) {
+	return aggregateAll(gdf, m.column, m.kind)
+}
+
+func (m *boolAcc) fold(parts []*types.Series, g, slot int, fresh bool) {
+	if fresh {
+		m.decided, m.nulls = append(m.decided, false), append(m.nulls, false)
+	}
+	switch {
+	case !parts[0].IsValid(g):
+		m.nulls[slot] = true
+	case parts[0].Data.([]bool)[g] == (m.kind == dataframe.Any):
+		m.decided[slot] = true
+	}
+}
+
+func (m *boolAcc) result(name string) *types.Series {
+	data := make([]bool, len(m.decided))
+	valid := make([]bool, len(m.decided))
+	for i, decided := range m.decided {
+		if decided {
+			data[i], valid[i] = m.kind == dataframe.Any, true
+		} else {
+			data[i], valid[i] = m.kind == dataframe.All, !m.nulls[i]
+		}
+	}
+	return types.NewNullableSeries(name, data, valid)
+}
+
+// aggregateAll aggregates the column name by each of kinds, the results following
+// the order of the grouping.
+func aggregateAll(gdf *dataframe.GroupedDataFrame, name string, kinds ...dataframe.AggregationType) ([]*types.Series, error) {
+	out := make([]*types.Series, len(kinds))
+	for i, kind := range kinds {
+		res, err := gdf.Aggregate(name, kind)
+		if err != nil {
+			return nil, err
+		}
+		if out[i], err = res.ToSeries(name); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// column returns the column name of df
+func column(df *dataframe.DataFrame, name string) (*types.Series, error) {
+	s, err := df.ToSeries(name)
+	if err != nil {
+		return nil, err
+	}
+	s.Name = name
+	return s, nil
+}
+
+func valueAt[T int64 | float64](s *types.Series, i int) (T, bool) {
+	data, ok := s.Data.([]T)
+	if !ok || !s.IsValid(i) {
+		var zero T
+		return zero, false
+	}
+	return data[i], true
+}
+
+// floatAt reads element i of an Int64 or Float64 Series as a float, 0 when
+// it is null.
+func floatAt(s *types.Series, i int) float64 {
+	if !s.IsValid(i) {
+		return 0
+	}
+	switch data := s.Data.(type) {
+	case []int64:
+		return float64(data[i])
+	case []float64:
+		return data[i]
+	}
+	return 0
+}
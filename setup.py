@@ -51,7 +51,7 @@ class CustomBuildExt(build_ext):
                 go_env['CGO_LDFLAGS'] = '-mmacosx-version-min=11.0'
             lib_name = 'libgo_polars.dylib'
             subprocess.check_call(['go', 'build', '-buildmode=c-shared',
-                                '-o', lib_name, './bridge/bridge.go'],
+                                '-o', lib_name, './bridge'],
                                 env=go_env)
             
             # Create the package directory and copy the shared library
@@ -85,7 +85,7 @@ class CustomBuildExt(build_ext):
             go_env['GOARCH'] = 'amd64'
             lib_name = 'go_polars.dll'
             subprocess.check_call(['go', 'build', '-buildmode=c-shared',
-                                '-o', lib_name, './bridge/bridge.go'],
+                                '-o', lib_name, './bridge'],
                                 env=go_env)
             os.makedirs('go_polars', exist_ok=True)
             shutil.copy2(lib_name, 'go_polars/')
@@ -96,7 +96,7 @@ class CustomBuildExt(build_ext):
             go_env['GOARCH'] = 'amd64'
             lib_name = 'libgo_polars.so'
             subprocess.check_call(['go', 'build', '-buildmode=c-shared',
-                                '-o', lib_name, './bridge/bridge.go'],
+                                '-o', lib_name, './bridge'],
                                 env=go_env)
             os.makedirs('go_polars', exist_ok=True)
             shutil.copy2(lib_name, 'go_polars/')
@@ -0,0 +1,733 @@
+package unit
+
+import (
+	"bytes"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"go-polars/dataframe"
+	"go-polars/incremental"
+	"go-polars/types"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupStatistics(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"k": types.NewSeries("k", []string{"a", "b", "a", "a", "b", "c"}),
+		"v": types.NewNullableSeries("v", []int64{1, 5, 2, 3, 0, 9}, []bool{true, true, true, true, false, true}),
+	})
+	assert.NoError(t, err)
+	gdf, err := df.GroupBy([]string{"k"})
+	assert.NoError(t, err)
+
+	float := func(agg dataframe.AggregationType) ([]float64, []bool) {
+		out, err := gdf.Aggregate("v", agg)
+		assert.NoError(t, err)
+		out, err = out.SortByColumn("k", true)
+		assert.NoError(t, err)
+		rec, err := out.ToArrow()
+		assert.NoError(t, err)
+		defer rec.Release()
+		col := rec.Column(1).(*array.Float64)
+		valid := make([]bool, col.Len())
+		for i := range valid {
+			valid[i] = col.IsValid(i)
+		}
+		return col.Float64Values(), valid
+	}
+	vals, valid := float(dataframe.Var)
+	assert.Equal(t, 1.0, vals[0])
+	assert.Equal(t, []bool{true, false, false}, valid)
+	vals, _ = float(dataframe.Median)
+	assert.Equal(t, []float64{2, 5, 9}, vals)
+
+	ints := func(agg dataframe.AggregationType) ([]int64, []bool) {
+		out, err := gdf.Aggregate("v", agg)
+		assert.NoError(t, err)
+		out, err = out.SortByColumn("k", true)
+		assert.NoError(t, err)
+		rec, err := out.ToArrow()
+		assert.NoError(t, err)
+		defer rec.Release()
+		col := rec.Column(1).(*array.Int64)
+		valid := make([]bool, col.Len())
+		for i := range valid {
+			valid[i] = col.IsValid(i)
+		}
+		return col.Int64Values(), valid
+	}
+	vals64, _ := ints(dataframe.First)
+	assert.Equal(t, []int64{1, 5, 9}, vals64)
+	_, valid = ints(dataframe.Last)
+	assert.Equal(t, []bool{true, false, true}, valid)
+	vals64, _ = ints(dataframe.NUnique)
+	assert.Equal(t, []int64{3, 1, 1}, vals64)
+
+	q, err := gdf.AggregateQuantile("v", 0.25)
+	assert.NoError(t, err)
+	q, err = q.SortByColumn("k", true)
+	assert.NoError(t, err)
+	rec, err := q.ToArrow()
+	assert.NoError(t, err)
+	defer rec.Release()
+	assert.Equal(t, 1.5, rec.Column(1).(*array.Float64).Value(0))
+
+	_, err = gdf.Aggregate("v", dataframe.Quantile)
+	assert.Error(t, err)
+	_, err = gdf.AggregateQuantile("v", 1.5)
+	assert.Error(t, err)
+}
+
+func TestBridgeFrameAggregate(t *testing.T) {
+	df, err := types.New(map[string]*types.Series{
+		"k": types.NewSeries("k", []int64{2, 1, 2, 1}),
+		"s": types.NewSeries("s", []string{"x", "x", "y", "x"}),
+		"v": types.NewSeries("v", []int64{10, 20, 30, 40}),
+	})
+	assert.NoError(t, err)
+
+	grouped, err := df.GroupBy([]string{"k"})
+	assert.NoError(t, err)
+	sums, err := grouped.Aggregate("v", types.Sum)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, sums.Series["k"].Data)
+	assert.Equal(t, []int64{60, 40}, sums.Series["v"].Data)
+
+	grouped, err = df.GroupBy([]string{"k", "s"})
+	assert.NoError(t, err)
+	std, err := grouped.Aggregate("v", types.Std)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 2}, std.Series["k"].Data)
+	assert.Equal(t, []string{"x", "x", "y"}, std.Series["s"].Data)
+	assert.InDelta(t, 14.142, std.Series["v"].Data.([]float64)[0], 1e-3)
+	assert.Equal(t, 2, std.Series["v"].NullCount())
+}
+
+func TestQuantileInterpolation(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{
+		"v": types.NewSeries("v", []int64{40, 10, 30, 20}),
+	})
+	gdf, _ := df.GroupBy(nil)
+	// q = 0.5 falls halfway between 20 and 30.
+	for method, want := range map[types.QuantileInterpolation]float64{
+		types.QuantileLinear:   25,
+		types.QuantileNearest:  30,
+		types.QuantileLower:    20,
+		types.QuantileHigher:   30,
+		types.QuantileMidpoint: 25,
+	} {
+		out, err := gdf.AggregateQuantileWith("v", 0.5, method)
+		assert.NoError(t, err, method.String())
+		v, _ := out.ToSeries("v")
+		assert.Equal(t, []float64{want}, v.Data, method.String())
+	}
+	_, err := gdf.AggregateQuantileWith("v", 0.5, types.QuantileInterpolation(9))
+	assert.Error(t, err)
+
+	// Selection agrees with sorting on large inputs with many ties.
+	vals := make([]float64, 1001)
+	for i := range vals {
+		vals[i] = float64((i * 7919) % 101)
+	}
+	sorted := append([]float64(nil), vals...)
+	sort.Float64s(sorted)
+	for _, q := range []float64{0, 0.1, 0.333, 0.5, 0.99, 1} {
+		got, ok := types.QuantileOf(append([]float64(nil), vals...), q, types.QuantileLower)
+		assert.True(t, ok)
+		assert.Equal(t, sorted[int(q*1000)], got, q)
+	}
+}
+
+func TestAggregateAnyAll(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{
+		"session": types.NewSeries("session", []int64{1, 1, 2, 2, 3, 3, 4, 5, 5}),
+		"failed": types.NewNullableSeries("failed",
+			[]bool{true, false, false, false, false, false, false, true, false},
+			[]bool{true, true, true, false, true, true, false, true, false}),
+	})
+	gdf, _ := df.GroupBy([]string{"session"})
+	check := func(agg dataframe.AggregationType, want []bool, valid []bool) {
+		out, err := gdf.Aggregate("failed", agg)
+		assert.NoError(t, err)
+		out, _ = out.SortByColumn("session", true)
+		s, _ := out.ToSeries("failed")
+		for i := range want {
+			assert.Equal(t, valid[i], s.IsValid(i), "%s of session %d", agg, i+1)
+			if valid[i] {
+				assert.Equal(t, want[i], s.Data.([]bool)[i], "%s of session %d", agg, i+1)
+			}
+		}
+	}
+	check(dataframe.Any, []bool{true, false, false, false, true}, []bool{true, false, true, false, true})
+	check(dataframe.All, []bool{false, false, false, false, true}, []bool{true, true, true, false, false})
+
+	all, _ := df.GroupBy(nil)
+	out, err := all.Aggregate("failed", dataframe.Any)
+	assert.NoError(t, err)
+	s, _ := out.ToSeries("failed")
+	assert.Equal(t, []bool{true}, s.Data)
+
+	_, err = gdf.Aggregate("session", dataframe.All)
+	assert.Error(t, err)
+}
+
+func TestAggregateProduct(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{
+		"k": types.NewSeries("k", []int64{1, 1, 1, 2, 2, 3}),
+		"r": types.NewNullableSeries("r", []float64{1.1, 1.2, 0, 0.9, 1, 0}, []bool{true, true, false, true, true, false}),
+		"n": types.NewSeries("n", []int32{2, 3, 4, 5, -1, 7}),
+	})
+	gdf, _ := df.GroupBy([]string{"k"})
+	values := func(column string, agg dataframe.AggregationType) *types.Series {
+		out, err := gdf.Aggregate(column, agg)
+		assert.NoError(t, err)
+		out, _ = out.SortByColumn("k", true)
+		s, _ := out.ToSeries(column)
+		return s
+	}
+	// A group without values multiplies to 1 and has no geometric mean.
+	assert.InDeltaSlice(t, []float64{1.32, 0.9, 1}, values("r", dataframe.Product).Data, 1e-12)
+	geo := values("r", dataframe.GeoMean)
+	assert.InDelta(t, math.Sqrt(1.32), geo.Data.([]float64)[0], 1e-12)
+	assert.False(t, geo.IsValid(2))
+	assert.Equal(t, []int64{24, -5, 7}, values("n", dataframe.Product).Data)
+	assert.True(t, math.IsNaN(values("n", dataframe.GeoMean).Data.([]float64)[1]))
+
+	all, _ := df.GroupBy(nil)
+	out, err := all.Aggregate("n", dataframe.Product)
+	assert.NoError(t, err)
+	s, _ := out.ToSeries("n")
+	assert.Equal(t, []int64{-840}, s.Data)
+}
+
+func TestBitwise(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{
+		"device": types.NewSeries("device", []int64{1, 1, 1, 2, 2}),
+		"flags":  types.NewNullableSeries("flags", []uint8{0b0011, 0b0110, 0, 0b1000, 0}, []bool{true, true, false, true, false}),
+		"temp":   types.NewSeries("temp", []float64{1, 2, 3, 4, 5}),
+	})
+	gdf, _ := df.GroupBy([]string{"device"})
+	for agg, want := range map[dataframe.AggregationType][]int64{
+		dataframe.BitAnd: {0b0010, 0b1000},
+		dataframe.BitOr:  {0b0111, 0b1000},
+		dataframe.BitXor: {0b0101, 0b1000},
+	} {
+		out, err := gdf.Aggregate("flags", agg)
+		assert.NoError(t, err)
+		out, _ = out.SortByColumn("device", true)
+		s, _ := out.ToSeries("flags")
+		assert.Equal(t, want, s.Data, agg.String())
+	}
+	_, err := gdf.Aggregate("temp", dataframe.BitOr)
+	assert.Error(t, err)
+
+	a := types.NewNullableSeries("a", []int8{-1, 0b0101, 0b0110}, []bool{true, true, false})
+	b := types.NewSeries("b", []uint8{0x0f, 0b0011, 1})
+	and, err := a.BitAnd(b)
+	assert.NoError(t, err)
+	// Int8 and UInt8 meet in Int16.
+	assert.Equal(t, []int16{0x0f, 0b0001, 0}, and.Data)
+	assert.False(t, and.IsValid(2))
+	xor, _ := b.BitXor(b)
+	assert.Equal(t, []uint8{0, 0, 0}, xor.Data)
+	not, _ := a.BitNot()
+	assert.Equal(t, []int8{0, -6, -7}, not.Data)
+	_, err = a.BitOr(types.NewSeries("f", []float64{1, 2, 3}))
+	assert.Error(t, err)
+}
+
+func TestAggregateEmpty(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"k": types.NewSeries("k", []string{}),
+		"v": types.NewSeries("v", []int32{}),
+	})
+	assert.NoError(t, err)
+	tdf, err := types.New(map[string]*types.Series{"v": types.NewSeries("v", []float64{})})
+	assert.NoError(t, err)
+
+	for agg := dataframe.Sum; agg <= dataframe.NUnique; agg++ {
+		if agg == dataframe.Quantile {
+			continue
+		}
+		gdf, err := df.GroupBy([]string{"k"})
+		assert.NoError(t, err)
+		out, err := gdf.Aggregate("v", agg)
+		assert.NoError(t, err)
+		rows, cols := out.Shape()
+		assert.Equal(t, 0, rows, agg.String())
+		assert.Equal(t, 2, cols)
+
+		// No group columns: one row, 0 for counts and sums, else null
+		gdf, err = df.GroupBy(nil)
+		assert.NoError(t, err)
+		out, err = gdf.Aggregate("v", agg)
+		assert.NoError(t, err)
+		v, err := out.ToSeries("v")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v.Length, agg.String())
+		zero := agg == dataframe.Sum || agg == dataframe.Count || agg == dataframe.NullCount || agg == dataframe.NUnique
+		assert.Equal(t, zero, v.IsValid(0), agg.String())
+	}
+
+	grouped, err := tdf.GroupBy(nil)
+	assert.NoError(t, err)
+	mean, err := grouped.Aggregate("v", types.Mean)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, mean.Series["v"].Length)
+	assert.Equal(t, 1, mean.Series["v"].NullCount())
+	last, err := grouped.Aggregate("v", types.Last)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, last.Series["v"].NullCount())
+}
+
+func TestGroupApply(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"k": types.NewSeries("k", []string{"b", "a", "b", "a", "b"}),
+		"v": types.NewSeries("v", []int64{1, 2, 3, 4, 5}),
+	})
+	assert.NoError(t, err)
+	gdf, err := df.GroupBy([]string{"k"})
+	assert.NoError(t, err)
+
+	// Keep the top two rows of every group
+	out, err := gdf.Apply(func(g *dataframe.DataFrame) (*dataframe.DataFrame, error) {
+		sorted, err := g.SortByColumn("v", false)
+		if err != nil {
+			return nil, err
+		}
+		return sorted.Head(2)
+	})
+	assert.NoError(t, err)
+	s, err := out.ToSeries("v")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{5, 3, 4, 2}, s.Data)
+
+	_, err = gdf.Apply(func(g *dataframe.DataFrame) (*dataframe.DataFrame, error) {
+		s, _ := g.ToSeries("k")
+		if s.Data.([]string)[0] == "a" {
+			return g.Select([]string{"k"})
+		}
+		return g, nil
+	})
+	assert.Error(t, err)
+}
+
+func TestGroupVerifyKeys(t *testing.T) {
+	nan := math.NaN()
+	df, err := dataframe.New(map[string]*types.Series{
+		"a": types.NewNullableSeries("a", []string{"x", "x", "y", "", ""}, []bool{true, true, true, false, false}),
+		"b": types.NewSeries("b", []float64{nan, nan, 1, 2, 2}),
+		"v": types.NewSeries("v", []int64{1, 2, 3, 4, 5}),
+	})
+	assert.NoError(t, err)
+
+	for _, verify := range []bool{false, true} {
+		gdf, err := df.GroupBy([]string{"a", "b"}, dataframe.GroupVerifyKeys(verify))
+		assert.NoError(t, err)
+		out, err := gdf.Aggregate("v", dataframe.Sum)
+		assert.NoError(t, err)
+		out, err = out.SortByColumn("v", true)
+		assert.NoError(t, err)
+		s, err := out.ToSeries("v")
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{3, 3, 9}, s.Data, "verify=%v", verify)
+	}
+}
+
+func TestPivot(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"day":   types.NewSeries("day", []string{"mon", "mon", "tue", "mon", "wed"}),
+		"store": types.NewSeries("store", []string{"b", "a", "a", "b", "b"}),
+		"sales": types.NewSeries("sales", []int32{1, 2, 3, 4, 5}),
+	})
+	assert.NoError(t, err)
+
+	wide, err := df.Pivot("day", "store", "sales", dataframe.Sum)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"day", "b", "a"}, wide.Columns())
+	b, err := wide.ToSeries("b")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{5, 0, 5}, b.Data)
+	assert.False(t, b.IsValid(1))
+	a, err := wide.ToSeries("a")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 3, 0}, a.Data)
+	assert.False(t, a.IsValid(2))
+
+	_, err = df.Pivot("day", "store", "sales", dataframe.Quantile)
+	assert.Error(t, err)
+}
+
+func TestGroupsReuse(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"k": types.NewSeries("k", []string{"b", "a", "b", "c", "a"}),
+		"v": types.NewNullableSeries("v", []int64{1, 2, 3, 4, 5}, []bool{true, true, false, true, true}),
+	})
+	assert.NoError(t, err)
+
+	gdf, err := df.GroupBy([]string{"k"})
+	assert.NoError(t, err)
+	groups, err := gdf.Groups()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, groups.Len())
+	assert.Equal(t, []string{"b", "a", "c"}, groups.Keys[0].Data)
+	assert.Equal(t, []int{0, 2}, groups.Rows(0))
+	assert.Equal(t, []int{1, 4}, groups.Rows(1))
+	again, err := gdf.Groups()
+	assert.NoError(t, err)
+	assert.Same(t, groups, again)
+
+	sums, err := gdf.Aggregate("v", dataframe.Sum)
+	assert.NoError(t, err)
+	s, err := sums.ToSeries("v")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 7, 4}, s.Data)
+	counts, err := gdf.Aggregate("v", dataframe.NullCount)
+	assert.NoError(t, err)
+	s, err = counts.ToSeries("v")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 0, 0}, s.Data)
+
+	parts, err := df.PartitionBy([]string{"k"})
+	assert.NoError(t, err)
+	assert.Len(t, parts, 3)
+	rows, cols := parts[1].Shape()
+	assert.Equal(t, 2, rows)
+	assert.Equal(t, 2, cols)
+	s, err = parts[1].ToSeries("v")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 5}, s.Data)
+
+	grouped, err := types.New(map[string]*types.Series{
+		"k": types.NewSeries("k", []int64{2, 1, 2}),
+		"v": types.NewSeries("v", []int64{10, 20, 30}),
+	})
+	assert.NoError(t, err)
+	grouped, err = grouped.GroupBy([]string{"k"})
+	assert.NoError(t, err)
+	groups, err = grouped.Groups()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, groups.Keys[0].Data)
+	assert.Equal(t, []int{1, 0, 2}, groups.Perm)
+	assert.Equal(t, []int{0, 1, 3}, groups.Offsets)
+}
+
+func TestGroupsConcurrentAggregate(t *testing.T) {
+	keys := make([]int64, 1000)
+	values := make([]int64, len(keys))
+	for i := range keys {
+		keys[i], values[i] = int64(i%7), int64(i)
+	}
+	df, err := dataframe.New(map[string]*types.Series{
+		"k": types.NewSeries("k", keys),
+		"v": types.NewSeries("v", values),
+	})
+	assert.NoError(t, err)
+	want := map[int64]int64{}
+	for i, k := range keys {
+		want[k] += values[i]
+	}
+
+	// Aggregate may run before, during or after Groups builds the grouping;
+	// whichever path it takes, the sums agree. Run with -race.
+	gdf, err := df.GroupBy([]string{"k"})
+	assert.NoError(t, err)
+	var wg sync.WaitGroup
+	results := make([]*dataframe.DataFrame, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				_, err := gdf.Groups()
+				assert.NoError(t, err)
+			}
+			out, err := gdf.Aggregate("v", dataframe.Sum)
+			assert.NoError(t, err)
+			results[i] = out
+		}(i)
+	}
+	wg.Wait()
+	for _, out := range results {
+		k, err := out.ToSeries("k")
+		assert.NoError(t, err)
+		v, err := out.ToSeries("v")
+		assert.NoError(t, err)
+		got := map[int64]int64{}
+		for i, key := range k.Data.([]int64) {
+			got[key] = v.Data.([]int64)[i]
+		}
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestGroupOffsets(t *testing.T) {
+	df, err := types.New(map[string]*types.Series{
+		"f": types.NewSeries("f", []float64{2, math.NaN(), 1, 2, math.NaN()}),
+		"b": types.NewSeries("b", []bool{true, false, true, true, false}),
+		"v": types.NewSeries("v", []int64{1, 2, 3, 4, 5}),
+	})
+	assert.NoError(t, err)
+
+	grouped, err := df.GroupBy([]string{"f"})
+	assert.NoError(t, err)
+	groups, err := grouped.Groups()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, groups.Len())
+	assert.Equal(t, []int{1, 4, 2, 0, 3}, groups.Perm)
+	assert.Equal(t, []int{0, 1, 2, 3, 5}, groups.Offsets)
+	assert.Equal(t, []int{0, 3}, grouped.GroupIndices["2"])
+
+	grouped, err = df.GroupBy([]string{"b"})
+	assert.NoError(t, err)
+	sums, err := grouped.Aggregate("v", types.Sum)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, true}, sums.Series["b"].Data)
+	assert.Equal(t, []int64{7, 8}, sums.Series["v"].Data)
+
+	frame, err := dataframe.New(map[string]*types.Series{
+		"b": types.NewSeries("b", []bool{true, false, true, true, false}),
+		"v": types.NewSeries("v", []float64{1, 2, 3, 4, 5}),
+	})
+	assert.NoError(t, err)
+	gdf, err := frame.GroupBy([]string{"b"})
+	assert.NoError(t, err)
+	_, err = gdf.Groups()
+	assert.NoError(t, err)
+	for agg, want := range map[dataframe.AggregationType][]float64{
+		dataframe.Sum: {8, 7},
+		dataframe.Min: {1, 2},
+		dataframe.Max: {4, 5},
+	} {
+		out, err := gdf.Aggregate("v", agg)
+		assert.NoError(t, err)
+		s, err := out.ToSeries("v")
+		assert.NoError(t, err)
+		assert.Equal(t, want, s.Data, agg.String())
+	}
+}
+
+func TestScalarKernels(t *testing.T) {
+	defer dataframe.ForceScalarKernels(false)
+
+	n := 1 << 16
+	keys := make([]int64, n)
+	values := make([]int64, n)
+	for i := range keys {
+		keys[i] = int64(i*7919) % 97
+		values[i] = int64(i%1000) - 500
+	}
+	df, err := dataframe.New(map[string]*types.Series{
+		"k": types.NewSeries("k", keys),
+		"v": types.NewSeries("v", values),
+	})
+	assert.NoError(t, err)
+
+	run := func() (sorted, maxima interface{}) {
+		out, err := df.SortByColumn("k", true)
+		assert.NoError(t, err)
+		s, err := out.ToSeries("v")
+		assert.NoError(t, err)
+		gdf, err := df.GroupBy([]string{"k"})
+		assert.NoError(t, err)
+		_, err = gdf.Groups()
+		assert.NoError(t, err)
+		agg, err := gdf.Aggregate("v", dataframe.Max)
+		assert.NoError(t, err)
+		m, err := agg.ToSeries("v")
+		assert.NoError(t, err)
+		return s.Data, m.Data
+	}
+
+	sorted, maxima := run()
+	dataframe.ForceScalarKernels(true)
+	assert.True(t, dataframe.ScalarKernels())
+	scalarSorted, scalarMaxima := run()
+	assert.Equal(t, sorted, scalarSorted)
+	assert.Equal(t, maxima, scalarMaxima)
+}
+
+func TestMaxThreads(t *testing.T) {
+	defer types.SetMaxThreads(0)
+
+	n := 1 << 17
+	values := make([]int64, n)
+	for i := range values {
+		values[i] = int64(i*7919)%1000 - 500
+	}
+	df, err := dataframe.New(map[string]*types.Series{"v": types.NewSeries("v", values)})
+	assert.NoError(t, err)
+	run := func() (sorted, abs interface{}) {
+		out, err := df.SortByColumn("v", false)
+		assert.NoError(t, err)
+		s, err := out.ToSeries("v")
+		assert.NoError(t, err)
+		a, err := s.Abs()
+		assert.NoError(t, err)
+		return s.Data, a.Data
+	}
+
+	sorted, abs := run()
+	assert.NoError(t, types.SetMaxThreads(1))
+	assert.Equal(t, 1, types.MaxThreads())
+	serialSorted, serialAbs := run()
+	assert.Equal(t, sorted, serialSorted)
+	assert.Equal(t, abs, serialAbs)
+	assert.Error(t, types.SetMaxThreads(-1))
+}
+
+func TestGroupByDynamic(t *testing.T) {
+	base := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) int64 { return base.Add(d).UnixNano() }
+	df, err := dataframe.New(map[string]*types.Series{
+		"ts": types.NewSeries("ts", []int64{at(7 * time.Minute), at(time.Minute), at(3 * time.Minute), at(2 * time.Hour)}),
+		"v":  types.NewSeries("v", []float64{3, 1, 2, 10}),
+	})
+	assert.NoError(t, err)
+
+	every, err := types.ParseDuration("5m")
+	assert.NoError(t, err)
+	gdf, err := df.GroupByDynamic("ts", every, types.Duration{}, types.Duration{})
+	assert.NoError(t, err)
+	means, err := gdf.Aggregate("v", dataframe.Mean)
+	assert.NoError(t, err)
+	starts, err := means.ToSeries("ts")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{at(0), at(5 * time.Minute), at(2 * time.Hour)}, starts.Data)
+	v, err := means.ToSeries("v")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1.5, 3, 10}, v.Data)
+
+	// Ten-minute windows every five minutes share rows.
+	gdf, err = df.GroupByDynamic("ts", every, types.Fixed(10*time.Minute), types.Duration{})
+	assert.NoError(t, err)
+	counts, err := gdf.Aggregate("v", dataframe.Count)
+	assert.NoError(t, err)
+	c, err := counts.ToSeries("v")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3, 1, 1, 1}, c.Data)
+	_, err = gdf.Cumulative(dataframe.CumSum, "v")
+	assert.Error(t, err)
+
+	gdf, err = df.GroupByDynamic("ts", types.Months(1), types.Duration{}, types.Days(1))
+	assert.NoError(t, err)
+	groups, err := gdf.Groups()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC).UnixNano(), groups.Keys[0].Data.([]int64)[0])
+	assert.Equal(t, 1, groups.Len())
+
+	_, err = df.GroupByDynamic("v", every, types.Duration{}, types.Duration{})
+	assert.Error(t, err)
+}
+
+func TestIncrementalView(t *testing.T) {
+	frame := func(k []string, v []float64, valid []bool) *dataframe.DataFrame {
+		df, err := dataframe.New(map[string]*types.Series{
+			"k": types.NewSeries("k", k),
+			"v": types.NewNullableSeries("v", v, valid),
+		})
+		assert.NoError(t, err)
+		return df
+	}
+	base := frame([]string{"a", "b", "a"}, []float64{1, 2, 4}, []bool{true, true, true})
+	batches := []*dataframe.DataFrame{
+		frame([]string{"c", "a", "b"}, []float64{3, 0, 8}, []bool{true, false, true}),
+		frame([]string{"b", "d", "c", "c"}, []float64{-1, 5, 7, 2}, []bool{true, false, true, true}),
+	}
+	kinds := []dataframe.AggregationType{dataframe.Sum, dataframe.Count, dataframe.Min, dataframe.Max,
+		dataframe.Mean, dataframe.Var, dataframe.Std, dataframe.First, dataframe.Last, dataframe.NullCount}
+	aggs := make([]incremental.Agg, len(kinds))
+	for i, kind := range kinds {
+		aggs[i] = incremental.Agg{Column: "v", Type: kind, Name: kind.String()}
+	}
+	view, err := incremental.NewView(base, []string{"k"}, aggs...)
+	assert.NoError(t, err)
+	for _, batch := range batches {
+		assert.NoError(t, view.Append(batch))
+	}
+	assert.Equal(t, 10, view.Rows())
+	got, err := view.Result()
+	assert.NoError(t, err)
+
+	all, err := dataframe.Concat(append([]*dataframe.DataFrame{base}, batches...), dataframe.HowVertical)
+	assert.NoError(t, err)
+	gdf, err := all.GroupBy([]string{"k"})
+	assert.NoError(t, err)
+	_, err = gdf.Groups()
+	assert.NoError(t, err)
+	gotKeys, _ := got.ToSeries("k")
+	assert.Equal(t, []string{"a", "b", "c", "d"}, gotKeys.Data)
+	for _, kind := range kinds {
+		res, err := gdf.Aggregate("v", kind)
+		assert.NoError(t, err)
+		want, _ := res.ToSeries("v")
+		have, _ := got.ToSeries(kind.String())
+		assert.Equal(t, want.DataType.String(), have.DataType.String(), kind.String())
+		for i := 0; i < want.Length; i++ {
+			assert.Equal(t, want.IsValid(i), have.IsValid(i), "%s row %d", kind, i)
+			if !want.IsValid(i) {
+				continue
+			}
+			if w, ok := want.Data.([]float64); ok {
+				assert.InDelta(t, w[i], have.Data.([]float64)[i], 1e-9, "%s row %d", kind, i)
+			} else {
+				assert.Equal(t, want.Data.([]int64)[i], have.Data.([]int64)[i], "%s row %d", kind, i)
+			}
+		}
+	}
+
+	_, err = incremental.NewView(base, []string{"k"}, incremental.Agg{Column: "v", Type: dataframe.Median})
+	assert.Error(t, err)
+	keysOnly, err := base.Select([]string{"k"})
+	assert.NoError(t, err)
+	assert.Error(t, view.Append(keysOnly))
+}
+
+func TestGroupByFloatKeys(t *testing.T) {
+	var buf bytes.Buffer
+	dataframe.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer dataframe.SetLogger(nil)
+
+	negZero, nan := math.Copysign(0, -1), math.NaN()
+	spread := func(n int) []float64 {
+		keys := make([]float64, n)
+		for i := range keys {
+			keys[i] = float64(i * 37 % n)
+		}
+		keys[10], keys[50] = nan, nan
+		return keys
+	}
+	// Keys are grouped by their bits whichever strategy runs: -0 and 0
+	// apart, and NaNs of the same bits together.
+	cases := []struct {
+		keys     []float64
+		groups   int
+		strategy string
+	}{
+		{[]float64{negZero, 0, 1}, 3, `strategy="sorted runs"`},
+		{[]float64{0, 1, negZero}, 3, `strategy=hash`},
+		{spread(100), 99, `strategy=hash`},
+		{spread(5000), 4999, `strategy="sorted runs" sorted_first=true`},
+	}
+	for _, c := range cases {
+		buf.Reset()
+		df, err := dataframe.New(map[string]*types.Series{
+			"k": types.NewSeries("k", c.keys),
+			"v": types.NewSeries("v", make([]int64, len(c.keys))),
+		})
+		assert.NoError(t, err)
+		gdf, err := df.GroupBy([]string{"k"})
+		assert.NoError(t, err)
+		out, err := gdf.Aggregate("v", dataframe.Count)
+		assert.NoError(t, err)
+		rows, _ := out.Shape()
+		assert.Equal(t, c.groups, rows, c.strategy)
+		assert.Contains(t, buf.String(), c.strategy)
+	}
+}
@@ -0,0 +1,131 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnOrder(t *testing.T) {
+	df, err := dataframe.ParseCSV(strings.NewReader("z,b,a\n1,2,3\n"), dataframe.CSVOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"z", "b", "a"}, df.Columns())
+
+	renamed, err := df.Rename(map[string]string{"z": "a", "a": "z"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "z"}, renamed.Columns())
+
+	_, err = df.Rename(map[string]string{"z": "b"})
+	assert.Error(t, err)
+
+	reordered, err := df.SelectOrdered([]string{"a"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "z", "b"}, reordered.Columns())
+
+	dropped, err := reordered.Drop("z")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, dropped.Columns())
+
+	sorted, err := dropped.SortByColumn("b", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, sorted.Columns())
+}
+
+func TestBridgeFrameColumnOrder(t *testing.T) {
+	df, err := types.NewOrdered(map[string]*types.Series{
+		"z": types.NewSeries("z", []int64{3, 1}),
+		"a": types.NewSeries("a", []float64{1, 2}),
+	}, []string{"z", "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"z", "a"}, df.Columns())
+
+	sorted, err := df.SortByColumn("z", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"z", "a"}, sorted.Columns())
+
+	df.Series["b"] = types.NewSeries("b", []int64{0, 0})
+	assert.Equal(t, []string{"z", "a", "b"}, df.Columns())
+}
+
+func TestBridgeFrameSlice(t *testing.T) {
+	df, err := types.New(map[string]*types.Series{
+		"a": types.NewNullableSeries("a", []int64{1, 2, 3, 4}, []bool{true, false, true, true}),
+		"b": types.NewSeries("b", []string{"w", "x", "y", "z"}),
+	})
+	assert.NoError(t, err)
+
+	page, err := df.Slice(1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, page.Length)
+	assert.Equal(t, []string{"x", "y", "z"}, page.Series["b"].Data)
+	assert.False(t, page.Series["a"].IsValid(0))
+
+	_, err = df.Slice(5, 1)
+	assert.Error(t, err)
+}
+
+func TestReshape(t *testing.T) {
+	s := types.NewNullableSeries("v", []int64{1, 2, 3, 4, 5, 6}, []bool{true, true, false, true, true, true})
+	cases := []struct {
+		nCols int
+		first []int64 // column_0, nil when rejected
+	}{
+		{1, []int64{1, 2, 3, 4, 5, 6}},
+		{2, []int64{1, 3, 5}},
+		{3, []int64{1, 4}},
+		{6, []int64{1}},
+		{4, nil},
+		{0, nil},
+		{-1, nil},
+	}
+	for _, c := range cases {
+		cols, err := s.Reshape(c.nCols)
+		if c.first == nil {
+			assert.Error(t, err, "%d columns", c.nCols)
+			continue
+		}
+		assert.NoError(t, err, "%d columns", c.nCols)
+		assert.Len(t, cols, c.nCols)
+		assert.Equal(t, "column_0", cols[0].Name)
+		assert.Equal(t, c.first, cols[0].Data, "%d columns", c.nCols)
+
+		// Flattening the columns again restores the Series, nulls included.
+		df, err := dataframe.FromColumns(cols)
+		assert.NoError(t, err)
+		flat, err := df.ToSeries()
+		assert.NoError(t, err)
+		assert.Equal(t, "values", flat.Name)
+		assert.Equal(t, s.Data, flat.Data, "%d columns", c.nCols)
+		assert.False(t, flat.IsValid(2))
+		assert.Equal(t, 1, flat.NullCount())
+	}
+
+	empty, err := types.NewSeries("v", []int64{}).Reshape(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, empty[1].Length)
+
+	df, err := dataframe.FromColumns([]*types.Series{
+		types.NewSeries("a", []int64{1, 2}),
+		types.NewSeries("b", []int64{3, 4}),
+		types.NewSeries("c", []string{"x", "y"}),
+	})
+	assert.NoError(t, err)
+	flat, err := df.ToSeries("b", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 1, 4, 2}, flat.Data)
+	_, err = df.ToSeries()
+	assert.Error(t, err, "mixed column types")
+	_, err = df.ToSeries("a", "missing")
+	assert.Error(t, err)
+	none, err := dataframe.New(nil)
+	assert.NoError(t, err)
+	_, err = none.ToSeries()
+	assert.Error(t, err)
+
+	_, err = dataframe.FromColumns([]*types.Series{types.NewSeries("a", []int64{1}), types.NewSeries("a", []int64{2})})
+	assert.Error(t, err)
+}
@@ -0,0 +1,189 @@
+package unit
+
+import (
+	"math"
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcatDiagonal(t *testing.T) {
+	a, _ := dataframe.New(map[string]*types.Series{
+		"id":   types.NewSeries("id", []int64{1, 2}),
+		"name": types.NewSeries("name", []string{"a", "b"}),
+	})
+	b, _ := dataframe.New(map[string]*types.Series{
+		"id":    types.NewSeries("id", []int64{3}),
+		"score": types.NewSeries("score", []float64{0.5}),
+	})
+
+	_, err := dataframe.Concat([]*dataframe.DataFrame{a, b}, dataframe.HowVertical)
+	assert.Error(t, err)
+
+	out, err := dataframe.Concat([]*dataframe.DataFrame{a, b}, dataframe.HowDiagonal)
+	assert.NoError(t, err)
+	rows, cols := out.Shape()
+	assert.Equal(t, 3, rows)
+	assert.Equal(t, 3, cols)
+
+	nulls, err := out.DropNulls()
+	assert.NoError(t, err)
+	rows, _ = nulls.Shape()
+	assert.Equal(t, 0, rows)
+}
+
+func TestHStack(t *testing.T) {
+	a, _ := dataframe.New(map[string]*types.Series{"id": types.NewSeries("id", []int64{1, 2})})
+	b, _ := dataframe.New(map[string]*types.Series{"id": types.NewSeries("id", []int64{3, 4})})
+
+	_, err := a.HStack(b, dataframe.CollisionError)
+	assert.Error(t, err)
+
+	out, err := a.HStack(b, dataframe.CollisionSuffix)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"id", "id_right"}, out.Columns())
+
+	short, _ := dataframe.New(map[string]*types.Series{"v": types.NewSeries("v", []int64{1})})
+	_, err = a.HStack(short, dataframe.CollisionOverwrite)
+	assert.Error(t, err)
+}
+
+func TestConcatFilesSchemaPolicies(t *testing.T) {
+	a, _ := dataframe.New(map[string]*types.Series{"v": types.NewSeries("v", []int64{1})})
+	b, _ := dataframe.New(map[string]*types.Series{
+		"v": types.NewSeries("v", []float64{2.5}),
+		"w": types.NewSeries("w", []bool{true}),
+	})
+	dfs := []*dataframe.DataFrame{a, b}
+
+	out, devs, err := dataframe.ConcatFiles(dfs, []string{"a.csv", "b.csv"}, dataframe.SchemaOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, devs, 2)
+	assert.Equal(t, "b.csv", devs[0].Source)
+	rows, cols := out.Shape()
+	assert.Equal(t, 2, rows)
+	assert.Equal(t, 2, cols)
+
+	_, devs, err = dataframe.ConcatFiles(dfs, nil, dataframe.SchemaOptions{Policy: dataframe.SchemaAddMissing})
+	assert.Error(t, err)
+	assert.Equal(t, dataframe.DeviationType, devs[0].Kind)
+
+	_, _, err = dataframe.ConcatFiles(dfs, nil, dataframe.SchemaOptions{Policy: dataframe.SchemaStrict})
+	assert.Error(t, err)
+}
+
+func TestMergeSorted(t *testing.T) {
+	frame := func(keys []int64, valid []bool, tags ...string) *dataframe.DataFrame {
+		df, err := dataframe.FromColumns([]*types.Series{
+			types.NewNullableSeries("k", keys, valid),
+			types.NewSeries("tag", tags),
+		})
+		assert.NoError(t, err)
+		return df
+	}
+	all := func(n int) []bool {
+		v := make([]bool, n)
+		for i := range v {
+			v[i] = true
+		}
+		return v
+	}
+
+	cases := []struct {
+		name        string
+		left, right *dataframe.DataFrame
+		want        []string // tags in merged order, nil when rejected
+	}{
+		{"ties and nulls",
+			frame([]int64{1, 3, 3, 0}, []bool{true, true, true, false}, "l0", "l1", "l2", "l3"),
+			frame([]int64{2, 3, 0}, []bool{true, true, false}, "r0", "r1", "r2"),
+			[]string{"l0", "r0", "l1", "l2", "r1", "l3", "r2"}},
+		{"empty left",
+			frame([]int64{}, nil),
+			frame([]int64{1, 2}, all(2), "r0", "r1"),
+			[]string{"r0", "r1"}},
+		{"both empty", frame([]int64{}, nil), frame([]int64{}, nil), []string{}},
+		{"unsorted left",
+			frame([]int64{2, 1}, all(2), "l0", "l1"),
+			frame([]int64{3}, all(1), "r0"),
+			nil},
+		{"descending right",
+			frame([]int64{1}, all(1), "l0"),
+			frame([]int64{3, 2}, all(2), "r0", "r1"),
+			nil},
+		{"null before values",
+			frame([]int64{0, 1}, []bool{false, true}, "l0", "l1"),
+			frame([]int64{2}, all(1), "r0"),
+			nil},
+	}
+	for _, c := range cases {
+		out, err := dataframe.MergeSorted(c.left, c.right, "k")
+		if c.want == nil {
+			assert.Error(t, err, c.name)
+			continue
+		}
+		assert.NoError(t, err, c.name)
+		tags, err := out.ToSeries("tag")
+		assert.NoError(t, err)
+		assert.Equal(t, c.want, tags.Data, c.name)
+	}
+
+	// NaN sorts after every other float, as in SortByColumn.
+	left, err := dataframe.FromColumns([]*types.Series{types.NewSeries("k", []float64{1, math.NaN()})})
+	assert.NoError(t, err)
+	right, err := dataframe.FromColumns([]*types.Series{types.NewSeries("k", []float64{2})})
+	assert.NoError(t, err)
+	out, err := dataframe.MergeSorted(left, right, "k")
+	assert.NoError(t, err)
+	k, err := out.ToSeries("k")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2}, k.Data.([]float64)[:2])
+	assert.True(t, math.IsNaN(k.Data.([]float64)[2]))
+
+	_, err = dataframe.MergeSorted(left, right, "missing")
+	assert.Error(t, err)
+	_, err = dataframe.MergeSorted(left, frame([]int64{1}, all(1), "r0"), "k")
+	assert.Error(t, err)
+}
+
+func TestConcatInterleave(t *testing.T) {
+	frame := func(tags ...string) *dataframe.DataFrame {
+		valid := make([]bool, len(tags))
+		for i, tag := range tags {
+			valid[i] = tag != ""
+		}
+		df, err := dataframe.FromColumns([]*types.Series{types.NewNullableSeries("tag", tags, valid)})
+		assert.NoError(t, err)
+		return df
+	}
+
+	cases := []struct {
+		name string
+		dfs  []*dataframe.DataFrame
+		want []string
+	}{
+		{"equal lengths", []*dataframe.DataFrame{frame("a0", "a1"), frame("b0", "b1")}, []string{"a0", "b0", "a1", "b1"}},
+		{"uneven lengths", []*dataframe.DataFrame{frame("a0"), frame("b0", "b1", "b2"), frame("c0", "c1")}, []string{"a0", "b0", "c0", "b1", "c1", "b2"}},
+		{"empty source", []*dataframe.DataFrame{frame(), frame("b0", "b1")}, []string{"b0", "b1"}},
+		{"single source", []*dataframe.DataFrame{frame("a0", "a1")}, []string{"a0", "a1"}},
+		{"nulls", []*dataframe.DataFrame{frame("a0", ""), frame("", "b1")}, []string{"a0", "", "", "b1"}},
+	}
+	for _, c := range cases {
+		out, err := dataframe.Concat(c.dfs, dataframe.HowInterleave)
+		assert.NoError(t, err, c.name)
+		tags, err := out.ToSeries("tag")
+		assert.NoError(t, err)
+		assert.Equal(t, c.want, tags.Data, c.name)
+		for i, tag := range c.want {
+			assert.Equal(t, tag != "", tags.IsValid(i), "%s row %d", c.name, i)
+		}
+	}
+
+	other, err := dataframe.FromColumns([]*types.Series{types.NewSeries("tag", []int64{1})})
+	assert.NoError(t, err)
+	_, err = dataframe.Concat([]*dataframe.DataFrame{frame("a0"), other}, dataframe.HowInterleave)
+	assert.Error(t, err)
+}
@@ -0,0 +1,232 @@
+package unit
+
+import (
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-polars/dataframe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const csvSample = "id,name,score\n1,a,0.5\n2,,1.5\n3,c,\n"
+
+func TestParseCSV(t *testing.T) {
+	df, err := dataframe.ParseCSV(strings.NewReader(csvSample), dataframe.CSVOptions{})
+	assert.NoError(t, err)
+	rows, cols := df.Shape()
+	assert.Equal(t, 3, rows)
+	assert.Equal(t, 3, cols)
+
+	_, err = dataframe.ParseCSV(strings.NewReader("a,b\n1,2\n3\n"), dataframe.CSVOptions{})
+	var perr *dataframe.ParseError
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, 1, perr.Row)
+	assert.Equal(t, 3, perr.Line)
+	assert.Equal(t, int64(8), perr.Offset)
+}
+
+func TestReadLimits(t *testing.T) {
+	_, err := dataframe.ParseCSV(strings.NewReader(csvSample), dataframe.CSVOptions{
+		ReadLimits: dataframe.ReadLimits{MaxRows: 2},
+	})
+	assert.ErrorIs(t, err, dataframe.ErrLimitExceeded)
+
+	df, err := dataframe.ParseCSV(strings.NewReader(csvSample), dataframe.CSVOptions{
+		ReadLimits: dataframe.ReadLimits{MaxBytes: 30, OnLimit: dataframe.LimitTruncate},
+	})
+	assert.NoError(t, err)
+	rows, _ := df.Shape()
+	assert.Equal(t, 2, rows)
+}
+
+func TestParseCSVDialect(t *testing.T) {
+	in := "exported by vendor\n# generated\ntitle||\nid||name\n1||\"a||b\"\n2||c\n"
+	df, err := dataframe.ParseCSV(strings.NewReader(in), dataframe.CSVOptions{
+		Delimiter: "||",
+		Comment:   "#",
+		SkipRows:  1,
+		HeaderRow: 1,
+	})
+	assert.NoError(t, err)
+	rows, cols := df.Shape()
+	assert.Equal(t, 2, rows)
+	assert.Equal(t, 2, cols)
+}
+
+func TestReadCSVGlob(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "day-1.csv"), []byte("id,v\n1,2\n"), 0o644))
+	f, err := os.Create(filepath.Join(dir, "day-2.csv.gz"))
+	assert.NoError(t, err)
+	zw := gzip.NewWriter(f)
+	zw.Write([]byte("id,v\n2,2.5\n3,\n"))
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, f.Close())
+
+	df, err := dataframe.ReadCSV(filepath.Join(dir, "day-*"), dataframe.CSVOptions{})
+	assert.NoError(t, err)
+	rows, _ := df.Shape()
+	assert.Equal(t, 3, rows)
+	present, err := df.Filter("v", func(v interface{}) bool { return v.(float64) > 2 })
+	assert.NoError(t, err)
+	rows, _ = present.Shape()
+	assert.Equal(t, 1, rows)
+}
+
+func TestParseCSVLocale(t *testing.T) {
+	in := "amount;count;day;note\n1.234,50;1.000;31.12.2024;a\n-0,5;7;02.01.2025 14:30;1,5\n;12;;\n"
+	opts := dataframe.CSVOptions{
+		Delimiter: ";",
+		Locale:    dataframe.LocaleOptions{DecimalComma: true, Thousands: ".", ParseDates: true, DayFirst: true},
+	}
+	df, err := dataframe.ParseCSV(strings.NewReader(in), opts)
+	assert.NoError(t, err)
+	amount, err := df.ToSeries("amount")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1234.5, -0.5}, amount.Data.([]float64)[:2])
+	assert.False(t, amount.IsValid(2))
+	count, err := df.ToSeries("count")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1000, 7, 12}, count.Data)
+	day, err := df.ToSeries("day")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 2, 14, 30, 0, 0, time.UTC).UnixNano(), day.Data.([]int64)[1])
+	note, err := df.ToSeries("note")
+	assert.NoError(t, err)
+	assert.Equal(t, "1,5", note.Data.([]string)[1])
+
+	// Without the options the European columns stay strings.
+	df, err = dataframe.ParseCSV(strings.NewReader(in), dataframe.CSVOptions{Delimiter: ";"})
+	assert.NoError(t, err)
+	amount, err = df.ToSeries("amount")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.234,50", amount.Data.([]string)[0])
+
+	_, err = dataframe.ParseCSV(strings.NewReader(in), dataframe.CSVOptions{Locale: dataframe.LocaleOptions{DecimalComma: true}})
+	assert.Error(t, err)
+}
+
+func TestReadJSON(t *testing.T) {
+	const input = `[
+		{"id": 1, "score": 1.5, "ok": true, "name": "a", "tags": [1, 2], "meta": {"x": 1}},
+		{"id": 2, "score": 2, "ok": null, "tags": [3]},
+		{"id": 3, "score": null, "name": "c", "ok": false, "meta": {"x": 2.5}}
+	]`
+	df, err := dataframe.ReadJSON(strings.NewReader(input), dataframe.JSONOptions{})
+	assert.NoError(t, err)
+	cases := []struct {
+		column string
+		data   interface{}
+		nulls  int
+	}{
+		{"id", []int64{1, 2, 3}, 0},
+		{"score", []float64{1.5, 2, 0}, 1},
+		{"ok", []bool{true, false, false}, 1},
+		{"name", []string{"a", "", "c"}, 1},
+		{"tags", [][]int64{{1, 2}, {3}, nil}, 1},
+		{"meta", []string{`{"x":1}`, "", `{"x":2.5}`}, 1},
+	}
+	for _, c := range cases {
+		s, err := df.ToSeries(c.column)
+		assert.NoError(t, err, c.column)
+		assert.Equal(t, c.data, s.Data, c.column)
+		assert.Equal(t, c.nulls, s.NullCount(), c.column)
+	}
+
+	flat, err := dataframe.ReadJSON(strings.NewReader(input), dataframe.JSONOptions{Flatten: true})
+	assert.NoError(t, err)
+	x, err := flat.ToSeries("meta.x")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 0, 2.5}, x.Data)
+	assert.False(t, x.IsValid(1))
+
+	// A flattened name may not collide with a key of the record.
+	clash := `[{"a.b": 1, "a": {"b": 2}}]`
+	_, err = dataframe.ReadJSON(strings.NewReader(clash), dataframe.JSONOptions{Flatten: true})
+	var perr *dataframe.ParseError
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, 0, perr.Row)
+	df, err = dataframe.ReadJSON(strings.NewReader(clash), dataframe.JSONOptions{})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "a.b"}, df.Columns())
+
+	_, err = dataframe.ReadJSON(strings.NewReader(`{"id": 1}`), dataframe.JSONOptions{})
+	assert.Error(t, err)
+}
+
+func TestReadNDJSON(t *testing.T) {
+	df, err := dataframe.ReadNDJSON(strings.NewReader("{\"id\": 1}\n\n{\"id\": 2.5, \"s\": \"x\"}\n"), dataframe.JSONOptions{})
+	assert.NoError(t, err)
+	id, err := df.ToSeries("id")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2.5}, id.Data)
+	s, err := df.ToSeries("s")
+	assert.NoError(t, err)
+	assert.False(t, s.IsValid(0))
+
+	_, err = dataframe.ReadNDJSON(strings.NewReader("{\"id\": 1}\n{\"id\":\n{\"id\": 3}\n"), dataframe.JSONOptions{})
+	var perr *dataframe.ParseError
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, 1, perr.Row)
+	assert.Equal(t, 2, perr.Line)
+	assert.Equal(t, int64(10), perr.Offset)
+	assert.Equal(t, `{"id":`, perr.Value)
+}
+
+func TestNormalizeColumnNames(t *testing.T) {
+	cases := []struct {
+		name  string
+		in    []string
+		opts  dataframe.NormalizeOptions
+		want  []string
+		moved int // number of renames reported
+	}{
+		{"none", []string{"A", ""}, dataframe.NormalizeOptions{}, []string{"A", ""}, 0},
+		{"trim", []string{"  id ", "Name"}, dataframe.NormalizeOptions{Trim: true}, []string{"id", "Name"}, 1},
+		{"lowercase", []string{"ID", "name"}, dataframe.NormalizeOptions{Lowercase: true}, []string{"id", "name"}, 1},
+		{"snake case", []string{"userID", "First Name", "HTTPServer", "already_snake", "x--y"}, dataframe.NormalizeOptions{SnakeCase: true},
+			[]string{"user_id", "first_name", "http_server", "already_snake", "x_y"}, 4},
+		{"empty names", []string{"", " "}, dataframe.NormalizeOptions{Trim: true}, []string{"column_0", "column_1"}, 2},
+		{"clash kept without dedupe", []string{"A", "a"}, dataframe.NormalizeOptions{Lowercase: true}, []string{"a", "a"}, 1},
+		{"dedupe", []string{"A", "a"}, dataframe.NormalizeOptions{Lowercase: true, Dedupe: true}, []string{"a", "a_1"}, 2},
+		{"dedupe skips taken suffixes", []string{"a", "a", "a_1"}, dataframe.NormalizeOptions{Dedupe: true}, []string{"a", "a_2", "a_1"}, 1},
+	}
+	for _, c := range cases {
+		got, renames := dataframe.NormalizeColumnNames(c.in, c.opts)
+		assert.Equal(t, c.want, got, c.name)
+		assert.Len(t, renames, c.moved, c.name)
+		for _, r := range renames {
+			assert.NotEqual(t, r.From, r.To, c.name)
+		}
+	}
+
+	// Readers refuse names that still clash and report every rename.
+	const header = "Id,id\n1,2\n"
+	_, err := dataframe.ParseCSV(strings.NewReader(header), dataframe.CSVOptions{Normalize: dataframe.NormalizeOptions{Lowercase: true}})
+	assert.Error(t, err)
+	var renames []dataframe.Rename
+	df, err := dataframe.ParseCSV(strings.NewReader(header), dataframe.CSVOptions{Normalize: dataframe.NormalizeOptions{
+		Lowercase: true,
+		Dedupe:    true,
+		OnRename:  func(r dataframe.Rename) { renames = append(renames, r) },
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "id_1"}, df.Columns())
+	assert.Equal(t, []dataframe.Rename{{From: "Id", To: "id"}, {From: "id", To: "id_1"}}, renames)
+
+	_, _, err = df.NormalizeColumns(dataframe.NormalizeOptions{SnakeCase: true})
+	assert.NoError(t, err)
+	df, err = dataframe.ParseCSV(strings.NewReader(header), dataframe.CSVOptions{})
+	assert.NoError(t, err)
+	_, _, err = df.NormalizeColumns(dataframe.NormalizeOptions{Lowercase: true})
+	assert.Error(t, err)
+	clash, err := dataframe.ReadJSON(strings.NewReader(`[{"Id": 1, "id": 2}]`), dataframe.JSONOptions{Normalize: dataframe.NormalizeOptions{Lowercase: true}})
+	assert.Error(t, err)
+	assert.Nil(t, clash)
+}
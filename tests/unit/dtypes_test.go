@@ -0,0 +1,508 @@
+package unit
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupertype(t *testing.T) {
+	cases := []struct {
+		a, b, want types.DataType
+	}{
+		{types.Int8Type{}, types.Int32Type{}, types.Int32Type{}},
+		{types.UInt8Type{}, types.Int8Type{}, types.Int16Type{}},
+		{types.UInt32Type{}, types.Int64Type{}, types.Int64Type{}},
+		{types.UInt64Type{}, types.Int32Type{}, types.Float64Type{}},
+		{types.Int16Type{}, types.Float64Type{}, types.Float64Type{}},
+	}
+	for _, c := range cases {
+		got, err := types.Supertype(c.a, c.b)
+		assert.NoError(t, err)
+		assert.Equal(t, c.want, got, "%s + %s", c.a, c.b)
+	}
+
+	_, err := types.Supertype(types.StringType{}, types.Int64Type{})
+	assert.Error(t, err)
+}
+
+func TestNarrowIntAggregate(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"k": types.NewSeries("k", []int8{1, 1, 2}),
+		"v": types.NewSeries("v", []uint8{200, 250, 7}),
+	})
+	assert.NoError(t, err)
+
+	gdf, err := df.GroupBy([]string{"k"})
+	assert.NoError(t, err)
+	sums, err := gdf.Aggregate("v", dataframe.Sum)
+	assert.NoError(t, err)
+	sorted, err := sums.SortByColumn("k", true)
+	assert.NoError(t, err)
+
+	rec, err := sorted.ToArrow()
+	assert.NoError(t, err)
+	defer rec.Release()
+	col := rec.Column(int(rec.Schema().FieldIndices("v")[0])).(*array.Int64)
+	assert.Equal(t, []int64{450, 7}, col.Int64Values())
+
+	big := types.NewSeries("u", []uint64{1 << 63})
+	_, err = big.Widen()
+	assert.Error(t, err)
+}
+
+func TestUInt64Aggregate(t *testing.T) {
+	const top = math.MaxUint64
+	keys := types.NewSeries("k", []int64{1, 1, 2, 2, 3})
+	values := types.NewNullableSeries("v", []uint64{top - 1, 1, top, 0, 5}, []bool{true, true, true, true, false})
+	cases := []struct {
+		agg   dataframe.AggregationType
+		typed types.AggregationType
+		data  []uint64
+		valid []bool
+	}{
+		{dataframe.Sum, types.Sum, []uint64{top, top, 0}, []bool{true, true, true}},
+		{dataframe.Min, types.Min, []uint64{1, 0, 0}, []bool{true, true, false}},
+		{dataframe.Max, types.Max, []uint64{top - 1, top, 0}, []bool{true, true, false}},
+	}
+
+	df, err := dataframe.FromColumns([]*types.Series{keys, values})
+	assert.NoError(t, err)
+	frame, err := types.NewOrdered(map[string]*types.Series{"k": keys, "v": values}, []string{"k", "v"})
+	assert.NoError(t, err)
+	grouped, err := frame.GroupBy([]string{"k"})
+	assert.NoError(t, err)
+	for _, c := range cases {
+		// Once with the grouping cached, once without.
+		for _, cached := range []bool{false, true} {
+			gdf, err := df.GroupBy([]string{"k"})
+			assert.NoError(t, err)
+			if cached {
+				_, err = gdf.Groups()
+				assert.NoError(t, err)
+			}
+			out, err := gdf.Aggregate("v", c.agg)
+			assert.NoError(t, err, c.agg)
+			s, err := out.ToSeries("v")
+			assert.NoError(t, err, c.agg)
+			assert.Equal(t, c.data, s.Data, c.agg)
+			for i, ok := range c.valid {
+				assert.Equal(t, ok, s.IsValid(i), "%s group %d", c.agg, i)
+			}
+		}
+
+		out, err := grouped.Aggregate("v", c.typed)
+		assert.NoError(t, err, c.agg)
+		assert.Equal(t, c.data, out.Series["v"].Data, c.agg)
+		for i, ok := range c.valid {
+			assert.Equal(t, ok, out.Series["v"].IsValid(i), "%s group %d", c.agg, i)
+		}
+	}
+
+	// A Sum past MaxUint64 fails instead of wrapping.
+	over, err := dataframe.FromColumns([]*types.Series{types.NewSeries("v", []uint64{top, 1})})
+	assert.NoError(t, err)
+	gdf, err := over.GroupBy(nil)
+	assert.NoError(t, err)
+	_, err = gdf.Aggregate("v", dataframe.Sum)
+	assert.ErrorContains(t, err, "overflows UInt64")
+	out, err := gdf.Aggregate("v", dataframe.Max)
+	assert.NoError(t, err)
+	s, err := out.ToSeries("v")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{top}, s.Data)
+	typed, err := types.NewOrdered(map[string]*types.Series{"v": types.NewSeries("v", []uint64{top, 1})}, []string{"v"})
+	assert.NoError(t, err)
+	typed, err = typed.GroupBy(nil)
+	assert.NoError(t, err)
+	_, err = typed.Aggregate("v", types.Sum)
+	assert.ErrorContains(t, err, "overflows UInt64")
+
+	// An empty frame without group columns is still one group.
+	empty, err := dataframe.FromColumns([]*types.Series{types.NewSeries("v", []uint64{})})
+	assert.NoError(t, err)
+	gdf, err = empty.GroupBy(nil)
+	assert.NoError(t, err)
+	out, err = gdf.Aggregate("v", dataframe.Sum)
+	assert.NoError(t, err)
+	s, err = out.ToSeries("v")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{0}, s.Data)
+	out, err = gdf.Aggregate("v", dataframe.Min)
+	assert.NoError(t, err)
+	s, err = out.ToSeries("v")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, s.Length)
+	assert.False(t, s.IsValid(0))
+}
+
+func TestSeriesCast(t *testing.T) {
+	s := types.NewSeries("v", []int64{1, 300, -5})
+
+	narrow, err := s.Cast(types.Int8Type{}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int8{1, 0, -5}, narrow.Data)
+	assert.Equal(t, 1, narrow.NullCount())
+
+	_, err = s.Cast(types.Int8Type{}, true)
+	assert.Error(t, err)
+
+	parsed, err := types.NewSeries("s", []string{"1.5", "x"}).Cast(types.Float64Type{}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, parsed.Data.([]float64)[0])
+	assert.False(t, parsed.IsValid(1))
+
+	text, err := types.NewSeries("b", []bool{true, false}).Cast(types.StringType{}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"true", "false"}, text.Data)
+}
+
+func TestSeriesArithmetic(t *testing.T) {
+	a := types.NewNullableSeries("a", []int32{7, -7, 4, 5}, []bool{true, true, true, false})
+	b := types.NewSeries("b", []int64{2, 2, 0, 1})
+
+	sum, err := a.Add(b)
+	assert.NoError(t, err)
+	assert.Equal(t, types.Int64Type{}, sum.DataType)
+	assert.Equal(t, []int64{9, -5, 4}, sum.Data.([]int64)[:3])
+	assert.False(t, sum.IsValid(3))
+
+	inc, err := a.Add(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{8, -6, 5}, inc.Data.([]int32)[:3])
+
+	quot, err := a.Div(b)
+	assert.NoError(t, err)
+	assert.Equal(t, types.Float64Type{}, quot.DataType)
+	assert.Equal(t, 3.5, quot.Data.([]float64)[0])
+	assert.False(t, quot.IsValid(2), "division by zero is null")
+
+	rem, err := a.Mod(b)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, -1}, rem.Data.([]int64)[:2])
+	assert.Equal(t, 2, rem.NullCount())
+
+	half, err := types.NewSeries("f", []float32{1, 3}).Mul(0.5)
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.5, 1.5}, half.Data)
+
+	scaled, err := types.NewSeries("one", []int64{10}).Sub(b)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{8, 8, 10, 9}, scaled.Data)
+
+	gt, err := a.Gt(b)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false, true}, gt.Data.([]bool)[:3])
+	assert.False(t, gt.IsValid(3))
+	eq, err := types.NewCategoricalSeries("c", []string{"x", "y"}).Eq("x")
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, eq.Data)
+	lt, err := types.NewSeries("p", []bool{false, true}).Lt(true)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, lt.Data)
+
+	_, err = a.Add(types.NewSeries("s", []string{"x", "y", "z", "w"}))
+	assert.Error(t, err)
+	_, err = a.Eq(types.NewSeries("short", []int64{1, 2}))
+	assert.Error(t, err)
+}
+
+func TestSeriesMath(t *testing.T) {
+	ints := types.NewNullableSeries("i", []int8{-3, 5, -128, 0}, []bool{true, true, true, false})
+	abs, err := ints.Abs()
+	assert.NoError(t, err)
+	assert.Equal(t, []int8{3, 5, -128}, abs.Data.([]int8)[:3])
+	assert.False(t, abs.IsValid(3))
+
+	clipped, err := ints.Clip(-2.5, 300)
+	assert.NoError(t, err)
+	assert.Equal(t, []int8{-2, 5, -2}, clipped.Data.([]int8)[:3])
+	_, err = ints.Clip(2, 1)
+	assert.Error(t, err)
+
+	floats := types.NewSeries("f", []float64{1.25, -1.25, 100, 2.5})
+	rounded, err := floats.Round(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1.3, -1.3, 100, 2.5}, rounded.Data)
+	floor, err := floats.Floor()
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, -2, 100, 2}, floor.Data)
+	ceil, err := floats.Ceil()
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, -1, 100, 3}, ceil.Data)
+
+	log, err := types.NewSeries("n", []int64{1, 100, 0}).Log10()
+	assert.NoError(t, err)
+	assert.Equal(t, types.Float64Type{}, log.DataType)
+	assert.Equal(t, []float64{0, 2, math.Inf(-1)}, log.Data)
+	sqrt, err := types.NewSeries("g", []float32{4, 9}).Sqrt()
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{2, 3}, sqrt.Data)
+	pow, err := types.NewSeries("p", []int64{2, 3}).Pow(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{4, 9}, pow.Data)
+
+	// Long columns are processed in parallel chunks.
+	long := make([]float64, 200000)
+	for i := range long {
+		long[i] = float64(i)
+	}
+	exp, err := types.NewSeries("l", long).Exp()
+	assert.NoError(t, err)
+	assert.Equal(t, math.Exp(199999), exp.Data.([]float64)[199999])
+	assert.Equal(t, 1.0, exp.Data.([]float64)[0])
+
+	_, err = types.NewSeries("s", []string{"x"}).Abs()
+	assert.Error(t, err)
+}
+
+func TestArrowLargeStrings(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"s": types.NewNullableSeries("s", []string{"a", "", "ccc"}, []bool{true, false, true}),
+	})
+	assert.NoError(t, err)
+
+	rec, err := df.ToArrow()
+	assert.NoError(t, err)
+	assert.Equal(t, "utf8", rec.Schema().Field(0).Type.String())
+	rec.Release()
+
+	rec, err = df.ToArrow(dataframe.ArrowLargeStrings(true))
+	assert.NoError(t, err)
+	defer rec.Release()
+	assert.Equal(t, "large_utf8", rec.Schema().Field(0).Type.String())
+	back, err := dataframe.FromArrow(rec)
+	assert.NoError(t, err)
+	s, err := back.ToSeries("s")
+	assert.NoError(t, err)
+	assert.Equal(t, "ccc", s.Data.([]string)[2])
+	assert.False(t, s.IsValid(1))
+}
+
+func TestArrowCategorical(t *testing.T) {
+	cat := types.NewCategoricalSeries("c", []string{"b", "a", "b", "a"})
+	cat.Validity = types.NewBitmap(4, true)
+	cat.Validity.Set(3, false)
+	df, err := dataframe.FromColumns([]*types.Series{cat, types.NewSeries("v", []int64{1, 2, 3, 4})})
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "c.arrow")
+	assert.NoError(t, df.WriteIPC(path))
+	back, err := dataframe.ReadIPC(path)
+	assert.NoError(t, err)
+	c, err := back.ToSeries("c")
+	assert.NoError(t, err)
+	assert.Equal(t, types.CategoricalType{}, c.DataType)
+	assert.Equal(t, []string{"b", "a", "b"}, c.Data.(*types.Categorical).Decode()[:3])
+	assert.False(t, c.IsValid(3))
+
+	// Values the database has no type for fail even into an existing table.
+	lists, err := dataframe.FromColumns([]*types.Series{types.NewSeries("l", [][]int64{{1}})})
+	assert.NoError(t, err)
+	assert.Error(t, lists.WriteSQL(nil, "t", dataframe.WithCreateTable(false)))
+}
+
+func TestStringNamespace(t *testing.T) {
+	s := types.NewNullableSeries("s", []string{"  Apple pie ", "banana", "", "cherry-42"}, []bool{true, true, false, true})
+
+	mask, err := s.Str().Contains(`\d+$`, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, false}, mask.Data.([]bool)[:2])
+	assert.True(t, mask.Data.([]bool)[3])
+	assert.False(t, mask.IsValid(2))
+	mask, err = s.Str().StartsWith("ban")
+	assert.NoError(t, err)
+	assert.Equal(t, types.BooleanType{}, mask.DataType)
+	assert.True(t, mask.Data.([]bool)[1])
+
+	stripped, err := s.Str().Strip("")
+	assert.NoError(t, err)
+	upper, err := stripped.Str().Upper()
+	assert.NoError(t, err)
+	assert.Equal(t, "APPLE PIE", upper.Data.([]string)[0])
+	replaced, err := s.Str().Replace(`(\w+)-(\d+)`, "$2-$1", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "42-cherry", replaced.Data.([]string)[3])
+	replaced, err = s.Str().ReplaceAll("a", "o", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "bonono", replaced.Data.([]string)[1])
+
+	parts, err := types.NewCategoricalSeries("c", []string{"a,b", "c"}).Str().Split(",")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, parts.Data)
+
+	_, err = s.Str().Contains("(", false)
+	assert.Error(t, err)
+	_, err = types.NewSeries("i", []int64{1}).Str().Lower()
+	assert.Error(t, err)
+}
+
+func TestStringExtract(t *testing.T) {
+	s := types.NewNullableSeries("log", []string{"ERROR db: timeout 30s", "INFO start", "", "WARN disk: 91% 95%"}, []bool{true, true, false, true})
+
+	level, err := s.Str().Extract(`^(\w+)`, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "WARN", level.Data.([]string)[3])
+	assert.False(t, level.IsValid(2))
+	source, err := s.Str().Extract(`(\w+):`, 1)
+	assert.NoError(t, err)
+	assert.False(t, source.IsValid(1))
+	_, err = s.Str().Extract(`(\w+)`, 2)
+	assert.Error(t, err)
+
+	numbers, err := s.Str().ExtractAll(`(\d+)%`, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"91", "95"}, numbers.Data.([][]string)[3])
+	assert.Equal(t, []string{}, numbers.Data.([][]string)[0])
+
+	df, err := dataframe.New(map[string]*types.Series{"log": s})
+	assert.NoError(t, err)
+	parsed, err := df.ExtractInto("log", `^(?P<level>\w+) (?:(?P<source>\w+): )?(?P<msg>.*)$`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log", "level", "source", "msg"}, parsed.Columns())
+	msg, err := parsed.ToSeries("msg")
+	assert.NoError(t, err)
+	assert.Equal(t, "timeout 30s", msg.Data.([]string)[0])
+	src, err := parsed.ToSeries("source")
+	assert.NoError(t, err)
+	assert.False(t, src.IsValid(1))
+	_, err = df.ExtractInto("log", `(\w+)`)
+	assert.Error(t, err)
+}
+
+func TestStringCollation(t *testing.T) {
+	words := []string{"zebra", "Äpfel", "apple", "Zoo", "éclair"}
+	df, err := dataframe.New(map[string]*types.Series{
+		"w": types.NewNullableSeries("w", append(words, ""), []bool{true, true, true, true, true, false}),
+		"c": types.NewCategoricalSeries("c", append(words, "")),
+	})
+	assert.NoError(t, err)
+
+	sorted, err := df.SortByColumn("w", true)
+	assert.NoError(t, err)
+	w, err := sorted.ToSeries("w")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Zoo", "apple", "zebra", "Äpfel", "éclair"}, w.Data.([]string)[:5])
+
+	de, err := types.NewCollation("de")
+	assert.NoError(t, err)
+	sorted, err = df.SortByColumn("w", true, dataframe.SortCollation(de))
+	assert.NoError(t, err)
+	w, err = sorted.ToSeries("w")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Äpfel", "apple", "éclair", "zebra", "Zoo"}, w.Data.([]string)[:5])
+	assert.False(t, w.IsValid(5))
+	sorted, err = df.SortByColumn("c", false, dataframe.SortCollation(de))
+	assert.NoError(t, err)
+	c, err := sorted.ToSeries("c")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Zoo", "zebra", "éclair", "apple", "Äpfel", ""}, c.Data.(*types.Categorical).Decode())
+
+	s := types.NewSeries("w", words)
+	min, ok, err := s.Str().Min(de)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Äpfel", min)
+	max, _, err := s.Str().Max(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "éclair", max)
+
+	_, err = types.NewCollation("not a locale!")
+	assert.Error(t, err)
+}
+
+func TestDatetimeNamespace(t *testing.T) {
+	text := types.NewNullableSeries("ts", []string{"2024-01-31 23:30", "2024-02-29 08:15", "", "2024-03-03 12:00"}, []bool{true, true, false, true})
+	ts, err := text.Str().ParseTime("2006-01-02 15:04", nil, true)
+	assert.NoError(t, err)
+	assert.False(t, ts.IsValid(2))
+	_, err = text.Str().ParseTime("2006-01-02", nil, true)
+	assert.Error(t, err)
+
+	month, err := ts.Dt().Month()
+	assert.NoError(t, err)
+	assert.Equal(t, types.Int8Type{}, month.DataType)
+	assert.Equal(t, []int8{1, 2}, month.Data.([]int8)[:2])
+	weekday, err := ts.Dt().Weekday()
+	assert.NoError(t, err)
+	assert.Equal(t, int8(7), weekday.Data.([]int8)[3])
+
+	// An hour east, the first timestamp falls in February.
+	east := time.FixedZone("UTC+1", 3600)
+	month, err = ts.Dt().In(east).Month()
+	assert.NoError(t, err)
+	assert.Equal(t, int8(2), month.Data.([]int8)[0])
+	formatted, err := ts.Dt().In(east).Format(time.DateTime)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-02-01 00:30:00", formatted.Data.([]string)[0])
+	replaced, err := ts.Dt().ReplaceZone(east)
+	assert.NoError(t, err)
+	assert.Equal(t, ts.Data.([]int64)[1]-int64(time.Hour), replaced.Data.([]int64)[1])
+
+	week, err := types.ParseDuration("1w")
+	assert.NoError(t, err)
+	monday, err := ts.Dt().Truncate(week)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 26, 0, 0, 0, 0, time.UTC).UnixNano(), monday.Data.([]int64)[3])
+	quarter, err := ts.Dt().Truncate(types.Months(3))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano(), quarter.Data.([]int64)[3])
+	quarterHour, err := types.ParseDuration("15m")
+	assert.NoError(t, err)
+	truncated, err := ts.Dt().Truncate(quarterHour)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 29, 8, 15, 0, 0, time.UTC).UnixNano(), truncated.Data.([]int64)[1])
+	_, err = types.ParseDuration("1d2h")
+	assert.Error(t, err)
+
+	// Group by month.
+	starts, err := ts.Dt().Truncate(types.Months(1))
+	assert.NoError(t, err)
+	df, err := dataframe.New(map[string]*types.Series{
+		"month": starts,
+		"v":     types.NewSeries("v", []int64{1, 2, 3, 4}),
+	})
+	assert.NoError(t, err)
+	gdf, err := df.GroupBy([]string{"month"})
+	assert.NoError(t, err)
+	sums, err := gdf.Aggregate("v", dataframe.Sum)
+	assert.NoError(t, err)
+	rows, _ := sums.Shape()
+	assert.Equal(t, 4, rows)
+
+	_, err = text.Dt().Year()
+	assert.Error(t, err)
+}
+
+func TestStringNumericCleanup(t *testing.T) {
+	s := types.NewNullableSeries("amount", []string{"$1,234.50", "12%", "(42.00)", "-€ 3 000", "n/a", ""}, []bool{true, true, true, true, true, false})
+
+	clean, err := s.Str().StripChars("$,% ")
+	assert.NoError(t, err)
+	assert.Equal(t, "1234.50", clean.Data.([]string)[0])
+	floats, err := clean.Str().ParseFloat(false)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1234.5, 12}, floats.Data.([]float64)[:2])
+	assert.False(t, floats.IsValid(4))
+	_, err = clean.Str().ParseFloat(true)
+	assert.Error(t, err)
+
+	loose, err := s.Str().ParseNumericLoose()
+	assert.NoError(t, err)
+	assert.Equal(t, types.Float64Type{}, loose.DataType)
+	assert.Equal(t, []float64{1234.5, 0.12, -42, -3000}, loose.Data.([]float64)[:4])
+	assert.False(t, loose.IsValid(4))
+	assert.False(t, loose.IsValid(5))
+
+	ints, err := types.NewSeries("n", []string{"7", "x"}).Str().ParseInt(false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), ints.Data.([]int64)[0])
+	assert.False(t, ints.IsValid(1))
+}
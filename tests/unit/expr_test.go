@@ -0,0 +1,119 @@
+package unit
+
+import (
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/expr"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithColumnExpr(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"a": types.NewNullableSeries("a", []int32{1, 2, 3, 4}, []bool{true, true, false, true}),
+		"b": types.NewSeries("b", []float64{0.5, 1, 1.5, 2}),
+		"s": types.NewSeries("s", []string{"x", "y", "x", "z"}),
+	})
+	assert.NoError(t, err)
+
+	out, err := df.WithColumnExpr("sum", expr.Col("a").Mul(expr.Lit(2)).Add(expr.Col("b")))
+	assert.NoError(t, err)
+	sum, err := out.ToSeries("sum")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2.5, 5}, sum.Data.([]float64)[:2])
+	assert.Equal(t, 10.0, sum.Data.([]float64)[3])
+	assert.False(t, sum.IsValid(2))
+
+	// Row 2 is null & false, which is false
+	cond := expr.Col("a").Gt(expr.Lit(1)).And(expr.Col("s").Eq(expr.Lit("x")).Not())
+	out, err = df.WithColumnExpr("keep", cond)
+	assert.NoError(t, err)
+	keep, err := out.ToSeries("keep")
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, true, false, true}, keep.Data)
+	assert.Equal(t, 0, keep.NullCount())
+
+	label := expr.When(expr.Col("b").Ge(expr.Lit(1.5))).Then(expr.Lit("high")).
+		When(expr.Col("b").Ge(expr.Lit(1))).Then(expr.Lit("mid")).
+		Otherwise(expr.Lit(nil))
+	out, err = df.WithColumnExpr("label", label)
+	assert.NoError(t, err)
+	l, err := out.ToSeries("label")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mid", "high", "high"}, l.Data.([]string)[1:])
+	assert.False(t, l.IsValid(0))
+
+	_, err = df.WithColumnExpr("bad", expr.Col("s").Add(expr.Lit(1)))
+	assert.Error(t, err)
+}
+
+func TestNormalizeBy(t *testing.T) {
+	for _, v := range []*types.Series{
+		types.NewNullableSeries("v", []int32{1, 3, 2, 0}, []bool{true, true, true, false}),
+		types.NewNullableSeries("v", []float32{1, 3, 2, 0}, []bool{true, true, true, false}),
+		types.NewNullableSeries("v", []int64{1, 3, 2, 0}, []bool{true, true, true, false}),
+	} {
+		df, err := dataframe.New(map[string]*types.Series{
+			"g": types.NewSeries("g", []string{"a", "a", "b", "b"}),
+			"v": v,
+		})
+		assert.NoError(t, err)
+
+		totals, err := df.Eval(expr.Col("v").Sum().Over("g"))
+		assert.NoError(t, err)
+		assert.Equal(t, 4, totals.Length)
+		assert.True(t, totals.IsValid(3))
+
+		out, err := df.NormalizeBy([]string{"g"}, "v")
+		assert.NoError(t, err, v.DataType.String())
+		norm, err := out.ToSeries("v_norm")
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{0.25, 0.75, 1}, norm.Data.([]float64)[:3], v.DataType.String())
+		assert.False(t, norm.IsValid(3))
+	}
+
+	df, err := dataframe.New(map[string]*types.Series{"s": types.NewSeries("s", []string{"x"})})
+	assert.NoError(t, err)
+	_, err = df.NormalizeBy(nil, "s")
+	assert.Error(t, err)
+}
+
+func TestShiftFill(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"g":   types.NewSeries("g", []string{"a", "a", "b", "b"}),
+		"i64": types.NewSeries("i64", []int64{1, 2, 3, 4}),
+		"i32": types.NewSeries("i32", []int32{1, 2, 3, 4}),
+		"f32": types.NewSeries("f32", []float32{1, 2, 3, 4}),
+		"f64": types.NewSeries("f64", []float64{1, 2, 3, 4}),
+		"s":   types.NewSeries("s", []string{"w", "x", "y", "z"}),
+	})
+	assert.NoError(t, err)
+
+	for col, want := range map[string]interface{}{
+		"i64": []int64{0, 1, 0, 3},
+		"i32": []int64{0, 1, 0, 3},
+		"f32": []float64{0, 1, 0, 3},
+		"f64": []float64{0, 1, 0, 3},
+	} {
+		out, err := df.Eval(expr.Col(col).ShiftFill(1, 0).Over("g"))
+		assert.NoError(t, err, col)
+		assert.Equal(t, want, out.Data, col)
+		assert.Equal(t, 0, out.NullCount(), col)
+	}
+
+	out, err := df.Eval(expr.Col("f32").ShiftFill(-1, 0.5))
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 3, 4, 0.5}, out.Data)
+
+	out, err = df.Eval(expr.Col("i32").Shift(1))
+	assert.NoError(t, err)
+	assert.Equal(t, types.Int32Type{}, out.DataType)
+	assert.False(t, out.IsValid(0))
+
+	_, err = df.Eval(expr.Col("s").ShiftFill(1, 0))
+	assert.Error(t, err)
+	_, err = df.Eval(expr.Col("i64").ShiftFill(1, 0.5))
+	assert.Error(t, err)
+}
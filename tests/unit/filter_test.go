@@ -0,0 +1,454 @@
+package unit
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/expr"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedFilters(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{
+		"id":    types.NewSeries("id", []int64{1, 2, 3, 4, 5}),
+		"small": types.NewSeries("small", []uint8{5, 4, 3, 2, 1}),
+		"price": types.NewNullableSeries("price", []float64{1.5, 2.5, 0, 4.5, 5.5}, []bool{true, true, false, true, true}),
+		"city":  types.NewCategoricalSeries("city", []string{"oslo", "rome", "oslo", "lima", "rome"}),
+		"paid":  types.NewSeries("paid", []bool{true, false, true, true, false}),
+	})
+	ids := func(df *dataframe.DataFrame) interface{} {
+		s, _ := df.ToSeries("id")
+		return s.Data
+	}
+
+	out, err := df.FilterInt64("id", expr.OpGe, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4, 5}, ids(out))
+	out, err = df.FilterInt64("small", expr.OpLt, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4, 5}, ids(out))
+	// The null price never matches, not even !=.
+	out, err = df.FilterFloat64("price", expr.OpNe, 2.5)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 4, 5}, ids(out))
+	out, err = df.FilterString("city", expr.OpEq, "rome")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 5}, ids(out))
+	out, err = df.FilterBool("paid", expr.OpEq, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 5}, ids(out))
+
+	_, err = df.FilterInt64("id", expr.OpAdd, 1)
+	assert.Error(t, err)
+	_, err = df.FilterString("id", expr.OpEq, "1")
+	assert.Error(t, err)
+
+	// (price > 2 or city == "oslo") and not paid
+	expensive, _ := df.CompareFloat64("price", expr.OpGt, 2)
+	oslo, _ := df.CompareString("city", expr.OpEq, "oslo")
+	paid, _ := df.CompareBool("paid", expr.OpEq, true)
+	either, err := expensive.Or(oslo)
+	assert.NoError(t, err)
+	// Row 3 has a null price but is in oslo, so the Or is still true.
+	assert.True(t, either.IsValid(2))
+	unpaid, _ := paid.Not()
+	mask, err := either.And(unpaid)
+	assert.NoError(t, err)
+	out, err = df.FilterMask(mask)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 5}, ids(out))
+
+	_, err = df.FilterMask(types.NewSeries("m", []bool{true}))
+	assert.Error(t, err)
+}
+
+func TestFilterExpr(t *testing.T) {
+	// More rows than one chunk, so that some chunks run out of rows early.
+	n := 100000
+	a := make([]int64, n)
+	b := make([]string, n)
+	valid := make([]bool, n)
+	for i := range a {
+		a[i] = int64(i)
+		b[i] = []string{"x", "y"}[i%2]
+		valid[i] = i%3 != 0
+	}
+	df, _ := dataframe.New(map[string]*types.Series{
+		"a": types.NewNullableSeries("a", a, valid),
+		"b": types.NewSeries("b", b),
+	})
+
+	pred := expr.Col("a").Ge(expr.Lit(70000)).And(expr.Col("b").Eq(expr.Lit("x"))).And(expr.Col("a").Lt(expr.Lit(70010)))
+	out, err := df.FilterExpr(pred)
+	assert.NoError(t, err)
+	col, _ := out.ToSeries("a")
+	// The even rows are x; 70002 and 70008 are null.
+	assert.Equal(t, []int64{70000, 70004, 70006}, col.Data)
+	assert.Equal(t, []string{"a", "b"}, out.Columns())
+
+	// Terms that never run still report their errors.
+	_, err = df.FilterExpr(expr.Col("a").Lt(expr.Lit(0)).And(expr.Col("b").Add(expr.Lit(1)).Gt(expr.Lit(0))))
+	assert.Error(t, err)
+	_, err = df.FilterExpr(expr.Col("a"))
+	assert.Error(t, err)
+
+	// Aggregations see every row.
+	out, err = df.FilterExpr(expr.Col("a").Gt(expr.Col("a").Mean()).And(expr.Col("a").Lt(expr.Lit(50005))))
+	assert.NoError(t, err)
+	col, _ = out.ToSeries("a")
+	assert.Equal(t, []int64{50000, 50002, 50003}, col.Data)
+}
+
+func TestTake(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{
+		"a": types.NewSeries("a", []int64{10, 20, 30}),
+		"b": types.NewNullableSeries("b", []string{"x", "", "z"}, []bool{true, false, true}),
+	})
+	out, err := df.Take([]int{2, 0, 2, -1, 1})
+	assert.NoError(t, err)
+	a, _ := out.ToSeries("a")
+	b, _ := out.ToSeries("b")
+	assert.Equal(t, []int64{30, 10, 30, 0, 20}, a.Data)
+	assert.False(t, a.IsValid(3))
+	assert.Equal(t, 2, b.NullCount())
+	_, err = df.Take([]int{3})
+	assert.Error(t, err)
+
+	// Large frames are gathered column by column in parallel.
+	n := 60000
+	ints := make([]int64, n)
+	floats := make([]float64, n)
+	rev := make([]int, n)
+	for i := range ints {
+		ints[i], floats[i], rev[i] = int64(i), float64(i), n-1-i
+	}
+	big, _ := dataframe.New(map[string]*types.Series{
+		"i": types.NewSeries("i", ints),
+		"f": types.NewSeries("f", floats),
+	})
+	out, err = big.Take(rev)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"f", "i"}, out.Columns())
+	f, _ := out.ToSeries("f")
+	assert.Equal(t, float64(n-1), f.Data.([]float64)[0])
+}
+
+func TestSelection(t *testing.T) {
+	n := 1000
+	values := make([]int64, n)
+	valid := make([]bool, n)
+	for i := range values {
+		values[i], valid[i] = int64(i), i%7 != 0
+	}
+	s := types.NewNullableSeries("v", values, valid)
+
+	// Few selected rows are held as positions, many as a mask; both gather
+	// the same elements as Take.
+	for _, every := range []int{100, 2} {
+		keep := make([]bool, n)
+		var rows []int
+		for i := 0; i < n; i += every {
+			keep[i] = true
+			rows = append(rows, i)
+		}
+		sel, err := types.NewSelection(types.NewSeries("m", keep))
+		assert.NoError(t, err)
+		assert.Equal(t, every == 100, sel.IsSparse())
+		assert.Equal(t, len(rows), sel.Count())
+		assert.Equal(t, rows, sel.Rows())
+		out, want := s.Filter(sel), s.Take(rows)
+		assert.Equal(t, want.Data, out.Data)
+		assert.Equal(t, want.NullCount(), out.NullCount())
+	}
+
+	_, err := types.NewSelection(s)
+	assert.Error(t, err)
+}
+
+func TestTailSliceSample(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{
+		"a": types.NewSeries("a", []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}),
+	})
+	values := func(out *dataframe.DataFrame, err error) []int64 {
+		assert.NoError(t, err)
+		s, _ := out.ToSeries("a")
+		return s.Data.([]int64)
+	}
+
+	assert.Equal(t, []int64{7, 8, 9}, values(df.Tail(3)))
+	assert.Len(t, values(df.Tail(20)), 10)
+	assert.Equal(t, []int64{2, 3, 4}, values(df.Slice(2, 3)))
+	assert.Equal(t, []int64{8, 9}, values(df.Slice(8, 5)))
+	_, err := df.Slice(11, 1)
+	assert.Error(t, err)
+
+	// The same seed draws the same rows; without replacement none repeat.
+	sample := values(df.Sample(10, false, 42))
+	assert.Equal(t, sample, values(df.Sample(10, false, 42)))
+	assert.ElementsMatch(t, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, sample)
+	assert.Len(t, values(df.Sample(25, true, 7)), 25)
+	_, err = df.Sample(11, false, 1)
+	assert.Error(t, err)
+}
+
+func TestFilterBatch(t *testing.T) {
+	n := 10000
+	ids := make([]int32, n)
+	valid := make([]bool, n)
+	for i := range ids {
+		ids[i], valid[i] = int32(i), i != 10
+	}
+	df, _ := dataframe.New(map[string]*types.Series{
+		"id":   types.NewNullableSeries("id", ids, valid),
+		"name": types.NewSeries("name", make([]string, n)),
+	})
+
+	// The predicate sees the column in chunks and never a null row.
+	calls := 0
+	out, err := df.FilterInt64Batch("id", func(values []int64, keep []bool) {
+		calls++
+		assert.Equal(t, len(values), len(keep))
+		for i, v := range values {
+			keep[i] = v%1000 == 10
+		}
+	})
+	assert.NoError(t, err)
+	assert.Greater(t, calls, 1)
+	assert.Less(t, calls, n)
+	rows, _ := out.Shape()
+	assert.Equal(t, 9, rows)
+
+	out, err = df.FilterFloat64Batch("id", func(values []float64, keep []bool) {
+		for i, v := range values {
+			keep[i] = v >= 9998
+		}
+	})
+	assert.NoError(t, err)
+	rows, _ = out.Shape()
+	assert.Equal(t, 2, rows)
+
+	_, err = df.FilterStringBatch("id", func([]string, []bool) {})
+	assert.Error(t, err)
+}
+
+func TestProjectedFilterSort(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{
+		"id":    types.NewSeries("id", []int64{3, 1, 2, 4}),
+		"wide":  types.NewSeries("wide", []string{"c", "a", "b", "d"}),
+		"score": types.NewSeries("score", []float64{0.3, 0.1, 0.2, 0.4}),
+	})
+
+	out, err := df.FilterColumns([]string{"wide"}, expr.Col("score").Gt(expr.Lit(0.15)))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"wide"}, out.Columns())
+	wide, _ := out.ToSeries("wide")
+	assert.Equal(t, []string{"c", "b", "d"}, wide.Data)
+
+	out, err = df.SortColumns([]string{"wide", "score"}, "id", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"wide", "score"}, out.Columns())
+	wide, _ = out.ToSeries("wide")
+	assert.Equal(t, []string{"d", "c", "b", "a"}, wide.Data)
+
+	_, err = df.FilterColumns([]string{"missing"}, expr.Col("id").Gt(expr.Lit(1)))
+	assert.Error(t, err)
+}
+
+func TestSortFields(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"id": types.NewSeries("id", []int64{0, 1, 2, 3, 4, 5}),
+		"g":  types.NewNullableSeries("g", []string{"b", "a", "", "b", "a", "b"}, []bool{true, true, false, true, true, true}),
+		"v":  types.NewNullableSeries("v", []int64{1, 5, 7, 0, 5, 3}, []bool{true, true, true, false, true, true}),
+	})
+	assert.NoError(t, err)
+
+	out, err := df.Sort([]dataframe.SortField{
+		{Column: "g", Ascending: true, Nulls: dataframe.NullsFirst},
+		{Column: "v", Ascending: false},
+	})
+	assert.NoError(t, err)
+	id, _ := out.ToSeries("id")
+	assert.Equal(t, []int64{2, 1, 4, 5, 0, 3}, id.Data)
+
+	_, err = df.Sort(nil)
+	assert.Error(t, err)
+	_, err = df.Sort([]dataframe.SortField{{Column: "missing"}})
+	assert.Error(t, err)
+
+	// Ties keep their order in both directions, also on the parallel path.
+	defer types.SetMaxThreads(0)
+	assert.NoError(t, types.SetMaxThreads(4))
+	n := 1 << 16
+	keys, ids := make([]int64, n), make([]int64, n)
+	for i := range keys {
+		keys[i], ids[i] = int64(i%3), int64(i)
+	}
+	big, err := dataframe.New(map[string]*types.Series{
+		"k":  types.NewSeries("k", keys),
+		"id": types.NewSeries("id", ids),
+	})
+	assert.NoError(t, err)
+	for _, ascending := range []bool{true, false} {
+		out, err := big.Sort([]dataframe.SortField{{Column: "k", Ascending: ascending}})
+		assert.NoError(t, err)
+		k, _ := out.ToSeries("k")
+		id, _ := out.ToSeries("id")
+		kd, idd := k.Data.([]int64), id.Data.([]int64)
+		for i := 1; i < n; i++ {
+			if kd[i] == kd[i-1] && idd[i] < idd[i-1] {
+				t.Fatalf("rows %d and %d with key %d swapped", idd[i-1], idd[i], kd[i])
+			}
+		}
+	}
+}
+
+func TestSortOptions(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"id": types.NewSeries("id", []int64{0, 1, 2, 3, 4, 5}),
+		"s":  types.NewSeries("s", []string{"b", "a", "b", "a", "c", "a"}),
+		"b":  types.NewSeries("b", []bool{true, false, true, false, false, true}),
+		"f":  types.NewSeries("f", []float64{2, 1, 2, 1, 3, 1}),
+	})
+	assert.NoError(t, err)
+
+	want := map[string][]int64{
+		"s": {4, 0, 2, 1, 3, 5},
+		"b": {0, 2, 5, 1, 3, 4},
+		"f": {4, 0, 2, 1, 3, 5},
+	}
+	for _, kind := range []dataframe.SortKind{dataframe.SortAuto, dataframe.SortRadix, dataframe.SortComparison} {
+		for column, ids := range want {
+			opt := dataframe.WithSortOptions(dataframe.SortOptions{Stable: true, Algorithm: kind})
+			out, err := df.SortByColumn(column, false, opt)
+			assert.NoError(t, err)
+			id, _ := out.ToSeries("id")
+			assert.Equal(t, ids, id.Data, "%s by %s", column, kind)
+		}
+	}
+
+	_, err = df.SortByColumn("s", true, dataframe.WithSortOptions(dataframe.SortOptions{Algorithm: 7}))
+	assert.Error(t, err)
+}
+
+func TestTopK(t *testing.T) {
+	n := 1000
+	ints, floats, ids := make([]int64, n), make([]float64, n), make([]int64, n)
+	words, valid := make([]string, n), make([]bool, n)
+	for i := range ints {
+		ints[i] = int64(i*7919) % 37
+		floats[i] = float64(int64(i*104729)%101) / 4
+		words[i] = string(rune('a' + i*31%26))
+		ids[i] = int64(i)
+		valid[i] = i%9 != 0
+	}
+	df, err := dataframe.New(map[string]*types.Series{
+		"i":  types.NewNullableSeries("i", ints, valid),
+		"f":  types.NewSeries("f", floats),
+		"w":  types.NewSeries("w", words),
+		"id": types.NewSeries("id", ids),
+	})
+	assert.NoError(t, err)
+
+	stable := dataframe.WithSortOptions(dataframe.SortOptions{Stable: true})
+	for _, by := range []string{"i", "f", "w"} {
+		for _, descending := range []bool{true, false} {
+			for _, k := range []int{0, 5, 950, 2000} {
+				top, err := df.TopK(k, by, descending)
+				assert.NoError(t, err)
+				sorted, err := df.SortByColumn(by, !descending, stable)
+				assert.NoError(t, err)
+				want, err := sorted.Head(k)
+				assert.NoError(t, err)
+				got, _ := top.ToSeries("id")
+				exp, _ := want.ToSeries("id")
+				assert.Equal(t, exp.Data, got.Data, "%s descending=%t k=%d", by, descending, k)
+			}
+		}
+	}
+
+	_, err = df.TopK(-1, "i", true)
+	assert.Error(t, err)
+	_, err = df.TopK(1, "missing", true)
+	assert.Error(t, err)
+}
+
+func TestArgSort(t *testing.T) {
+	prices, err := dataframe.New(map[string]*types.Series{
+		"day":   types.NewSeries("day", []int64{3, 1, 2, 1}),
+		"price": types.NewSeries("price", []float64{30, 11, 20, 10}),
+	})
+	assert.NoError(t, err)
+	volumes, err := dataframe.New(map[string]*types.Series{
+		"volume": types.NewSeries("volume", []int64{300, 110, 200, 100}),
+	})
+	assert.NoError(t, err)
+
+	perm, err := prices.ArgSort([]dataframe.SortField{{Column: "day", Ascending: true}, {Column: "price", Ascending: true}})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 1, 2, 0}, perm)
+	sorted, err := volumes.TakeOrdered(perm)
+	assert.NoError(t, err)
+	volume, _ := sorted.ToSeries("volume")
+	assert.Equal(t, []int64{100, 110, 200, 300}, volume.Data)
+
+	_, err = volumes.TakeOrdered([]int{0, 1, 2})
+	assert.Error(t, err)
+	_, err = volumes.TakeOrdered([]int{0, 1, 1, 3})
+	assert.Error(t, err)
+	_, err = prices.ArgSort(nil)
+	assert.Error(t, err)
+}
+
+// TestEnvConfig runs the test binary again with GOPOLARS_* variables set, as
+// they are only read at startup. The child reports what it picked up.
+func TestEnvConfig(t *testing.T) {
+	if os.Getenv("GOPOLARS_TEST_CHILD") == "1" {
+		fmt.Printf("threads=%d verbose=%v\n", types.MaxThreads(), dataframe.Verbose())
+		return
+	}
+
+	cases := []struct {
+		name    string
+		env     []string
+		stdout  string
+		stderr  []string // substrings expected on stderr
+		ignored int      // number of values reported as ignored
+	}{
+		{"valid",
+			[]string{"GOPOLARS_MAX_THREADS=3", "GOPOLARS_STREAMING_CHUNK_SIZE=100", "GOPOLARS_VERBOSE=1"},
+			"threads=3 verbose=true", []string{"max_threads=3", "streaming_chunk_size=100"}, 0},
+		{"verbose off",
+			[]string{"GOPOLARS_MAX_THREADS=2", "GOPOLARS_VERBOSE=0"},
+			"threads=2 verbose=false", nil, 0},
+		{"empty values",
+			[]string{"GOPOLARS_MAX_THREADS=", "GOPOLARS_STREAMING_CHUNK_SIZE=", "GOPOLARS_VERBOSE="},
+			fmt.Sprintf("threads=%d verbose=false", runtime.GOMAXPROCS(0)), nil, 0},
+		{"invalid",
+			[]string{"GOPOLARS_MAX_THREADS=abc", "GOPOLARS_STREAMING_CHUNK_SIZE=-5", "GOPOLARS_VERBOSE=maybe"},
+			fmt.Sprintf("threads=%d verbose=false", runtime.GOMAXPROCS(0)),
+			[]string{`GOPOLARS_MAX_THREADS="abc"`, `GOPOLARS_STREAMING_CHUNK_SIZE="-5"`, `GOPOLARS_VERBOSE="maybe"`}, 3},
+		{"zero threads",
+			[]string{"GOPOLARS_MAX_THREADS=0", "GOPOLARS_STREAMING_CHUNK_SIZE=0", "GOPOLARS_VERBOSE=1"},
+			fmt.Sprintf("threads=%d verbose=true", runtime.GOMAXPROCS(0)), []string{"streaming_chunk_size=65536"}, 2},
+	}
+	for _, c := range cases {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestEnvConfig$")
+		cmd.Env = append(append(os.Environ(), "GOPOLARS_TEST_CHILD=1"), c.env...)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		assert.NoError(t, cmd.Run(), c.name)
+		assert.Contains(t, stdout.String(), c.stdout, c.name)
+		for _, want := range c.stderr {
+			assert.Contains(t, stderr.String(), want, c.name)
+		}
+		assert.Equal(t, c.ignored, strings.Count(stderr.String(), "gopolars: ignoring"), c.name)
+	}
+}
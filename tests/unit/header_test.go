@@ -0,0 +1,33 @@
+package unit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratedHeader(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "gopolars.h")
+	cmd := exec.Command("go", "run", "gen_header.go", "-o", out)
+	cmd.Dir = "../../bridge"
+	msg, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(msg))
+
+	want, err := os.ReadFile("../../include/gopolars.h")
+	assert.NoError(t, err)
+	got, err := os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "include/gopolars.h is stale; run go generate in bridge")
+
+	// The C example must compile against the header.
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("no C compiler")
+	}
+	cmd = exec.Command(cc, "-fsyntax-only", "-Wall", "-Werror", "-I../../include", "../../examples/c/example.c")
+	msg, err = cmd.CombinedOutput()
+	assert.NoError(t, err, string(msg))
+}
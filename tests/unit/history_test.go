@@ -0,0 +1,30 @@
+package unit
+
+import (
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitAndAt(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{"a": types.NewSeries("a", []int64{1, 2, 3})})
+	df = df.Commit("raw")
+
+	filtered, err := df.Filter("a", func(v interface{}) bool { return v.(int64) > 1 })
+	assert.NoError(t, err)
+	filtered = filtered.Commit("filtered")
+	head, err := filtered.Head(1)
+	assert.NoError(t, err)
+
+	raw, err := head.At("raw")
+	assert.NoError(t, err)
+	rows, _ := raw.Shape()
+	assert.Equal(t, 3, rows)
+	assert.Equal(t, []string{"raw", "filtered"}, head.Versions())
+
+	_, err = head.At("missing")
+	assert.Error(t, err)
+}
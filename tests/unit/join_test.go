@@ -0,0 +1,324 @@
+package unit
+
+import (
+	"math"
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinTypes(t *testing.T) {
+	left, err := dataframe.New(map[string]*types.Series{
+		"id": types.NewNullableSeries("id", []int64{1, 2, 3, 0}, []bool{true, true, true, false}),
+		"v":  types.NewSeries("v", []string{"a", "b", "c", "n"}),
+	})
+	assert.NoError(t, err)
+	right, err := dataframe.New(map[string]*types.Series{
+		"key": types.NewNullableSeries("key", []int64{2, 2, 4, 0}, []bool{true, true, true, false}),
+		"v":   types.NewSeries("v", []string{"x", "y", "z", "m"}),
+	})
+	assert.NoError(t, err)
+
+	cases := []struct {
+		how  dataframe.JoinType
+		rows int
+	}{
+		{dataframe.JoinInner, 2},
+		{dataframe.JoinLeft, 5},
+		{dataframe.JoinRight, 4},
+		{dataframe.JoinOuter, 7},
+	}
+	for _, c := range cases {
+		joined, err := left.Join(right, []string{"id"}, []string{"key"}, c.how)
+		assert.NoError(t, err)
+		rows, cols := joined.Shape()
+		assert.Equal(t, c.rows, rows, "join type %d", c.how)
+		assert.Equal(t, 3, cols)
+	}
+
+	nullsEqual, err := left.Join(right, []string{"id"}, []string{"key"}, dataframe.JoinInner, dataframe.JoinNullsEqual(true))
+	assert.NoError(t, err)
+	rows, _ := nullsEqual.Shape()
+	assert.Equal(t, 3, rows)
+
+	_, err = left.Join(right, []string{"v"}, []string{"key"}, dataframe.JoinInner)
+	assert.Error(t, err)
+}
+
+func TestJoinSuffixClash(t *testing.T) {
+	left, err := dataframe.FromColumns([]*types.Series{
+		types.NewSeries("id", []int64{1, 2}),
+		types.NewSeries("v", []int64{1, 2}),
+	})
+	assert.NoError(t, err)
+	for _, order := range [][]string{{"id", "v_right", "v"}, {"id", "v", "v_right"}} {
+		cols := map[string]*types.Series{
+			"id":      types.NewSeries("id", []int64{1, 2}),
+			"v":       types.NewSeries("v", []int64{5, 6}),
+			"v_right": types.NewSeries("v_right", []int64{7, 8}),
+		}
+		series := make([]*types.Series, len(order))
+		for i, name := range order {
+			series[i] = cols[name]
+		}
+		right, err := dataframe.FromColumns(series)
+		assert.NoError(t, err)
+		_, err = left.Join(right, []string{"id"}, []string{"id"}, dataframe.JoinInner)
+		assert.Error(t, err, "right columns %v", order)
+		_, err = left.Lazy().Join(right.Lazy(), []string{"id"}, []string{"id"}, dataframe.JoinInner).Collect()
+		assert.Error(t, err, "right columns %v", order)
+	}
+}
+
+func TestJoinAsof(t *testing.T) {
+	left, err := dataframe.FromColumns([]*types.Series{
+		types.NewNullableSeries("ts", []int64{1, 5, 9, 20, 0, 3}, []bool{true, true, true, true, false, true}),
+		types.NewNullableSeries("sym", []string{"a", "a", "b", "b", "a", ""}, []bool{true, true, true, true, true, false}),
+	})
+	assert.NoError(t, err)
+	right, err := dataframe.FromColumns([]*types.Series{
+		types.NewSeries("ts", []int64{0, 4, 9, 30, 0}),
+		types.NewNullableSeries("sym", []string{"a", "a", "b", "a", ""}, []bool{true, true, true, true, false}),
+		types.NewSeries("price", []int64{10, 11, 20, 12, 99}),
+	})
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name      string
+		tolerance int64
+		opts      []dataframe.JoinOption
+		want      []int64
+		valid     []bool
+	}{
+		{"nearest", -1, nil, []int64{10, 11, 20, 20, 0, 0}, []bool{true, true, true, true, false, false}},
+		{"tolerance", 5, nil, []int64{10, 11, 20, 0, 0, 0}, []bool{true, true, true, false, false, false}},
+		{"exact", 0, nil, []int64{0, 0, 20, 0, 0, 0}, []bool{false, false, true, false, false, false}},
+		{"nulls equal", -1, []dataframe.JoinOption{dataframe.JoinNullsEqual(true)}, []int64{10, 11, 20, 20, 0, 99}, []bool{true, true, true, true, false, true}},
+	}
+	for _, c := range cases {
+		out, err := left.JoinAsof(right, "ts", []string{"sym"}, c.tolerance, c.opts...)
+		assert.NoError(t, err, c.name)
+		assert.Equal(t, []string{"ts", "sym", "price"}, out.Columns(), c.name)
+		price, err := out.ToSeries("price")
+		assert.NoError(t, err)
+		for i, v := range c.valid {
+			assert.Equal(t, v, price.IsValid(i), "%s row %d", c.name, i)
+			if v {
+				assert.Equal(t, c.want[i], price.Data.([]int64)[i], "%s row %d", c.name, i)
+			}
+		}
+	}
+
+	// Narrow on columns are widened for matching but must share a type.
+	narrow, err := left.WithColumn("ts", types.NewSeries("ts", []int32{1, 5, 9, 20, 0, 3}))
+	assert.NoError(t, err)
+	_, err = narrow.JoinAsof(right, "ts", []string{"sym"}, -1)
+	assert.Error(t, err)
+	narrowRight, err := right.WithColumn("ts", types.NewSeries("ts", []int32{0, 4, 9, 30, 0}))
+	assert.NoError(t, err)
+	out, err := narrow.JoinAsof(narrowRight, "ts", []string{"sym"}, -1)
+	assert.NoError(t, err)
+	price, err := out.ToSeries("price")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{10, 11, 20, 20}, price.Data.([]int64)[:4])
+}
+
+func TestConstraints(t *testing.T) {
+	orders, err := dataframe.New(map[string]*types.Series{
+		"id":    types.NewSeries("id", []int64{1, 2, 3, 4}),
+		"store": types.NewSeries("store", []int64{10, 20, 10, 30}),
+	})
+	assert.NoError(t, err)
+	stores, err := dataframe.New(map[string]*types.Series{
+		"store": types.NewSeries("store", []int64{10, 20, 30}),
+		"city":  types.NewNullableSeries("city", []string{"Oslo", "", "Rome"}, []bool{true, false, true}),
+	})
+	assert.NoError(t, err)
+
+	orders, err = orders.WithConstraints("id", dataframe.NotNull|dataframe.Unique|dataframe.Ascending)
+	assert.NoError(t, err)
+	_, err = orders.WithConstraints("store", dataframe.Unique)
+	assert.Error(t, err)
+	_, err = stores.WithConstraints("city", dataframe.NotNull)
+	assert.Error(t, err)
+	stores, err = stores.WithConstraints("store", dataframe.Unique)
+	assert.NoError(t, err)
+	assert.Equal(t, "unique", stores.Constraints("store").String())
+
+	// Filters keep every constraint, sorts those not about the order, and
+	// a replaced column loses its own.
+	filtered, err := orders.Filter("store", func(v interface{}) bool { return v.(int64) < 30 })
+	assert.NoError(t, err)
+	assert.Equal(t, dataframe.NotNull|dataframe.Unique|dataframe.Ascending, filtered.Constraints("id"))
+	sorted, err := orders.SortByColumn("store", false)
+	assert.NoError(t, err)
+	assert.Equal(t, dataframe.NotNull|dataframe.Unique, sorted.Constraints("id"))
+	replaced, err := orders.WithColumn("id", types.NewSeries("id", []int64{4, 3, 2, 1}))
+	assert.NoError(t, err)
+	assert.Equal(t, dataframe.Constraint(0), replaced.Constraints("id"))
+	kept, err := orders.WithColumn("total", types.NewSeries("total", []float64{1, 2, 3, 4}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id"}, kept.ConstrainedColumns())
+
+	// A unique right key cannot fan the left rows out.
+	joined, err := orders.Join(stores, []string{"store"}, []string{"store"}, dataframe.JoinLeft)
+	assert.NoError(t, err)
+	rows, _ := joined.Shape()
+	assert.Equal(t, 4, rows)
+	assert.Equal(t, dataframe.NotNull|dataframe.Unique|dataframe.Ascending, joined.Constraints("id"))
+	city, _ := joined.ToSeries("city")
+	assert.Equal(t, []string{"Oslo", "", "Oslo", "Rome"}, city.Data)
+}
+
+func TestJoinNullsEqual(t *testing.T) {
+	left, err := dataframe.FromColumns([]*types.Series{
+		types.NewNullableSeries("id", []int64{1, 0, 3}, []bool{true, false, true}),
+		types.NewSeries("lv", []string{"l1", "lnull", "l3"}),
+	})
+	assert.NoError(t, err)
+	right, err := dataframe.FromColumns([]*types.Series{
+		types.NewNullableSeries("id", []int64{1, 0, 0}, []bool{true, false, false}),
+		types.NewSeries("rv", []string{"r1", "rnull1", "rnull2"}),
+	})
+	assert.NoError(t, err)
+	// A Unique constraint does not stop nulls fanning out when they match.
+	uniqueRight, err := right.WithConstraints("id", dataframe.Unique)
+	assert.NoError(t, err)
+	empty, err := dataframe.FromColumns([]*types.Series{
+		types.NewSeries("id", []int64{}),
+		types.NewSeries("lv", []string{}),
+	})
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name        string
+		left, right *dataframe.DataFrame
+		how         dataframe.JoinType
+		nullsEqual  bool
+		rows        int
+	}{
+		{"inner", left, right, dataframe.JoinInner, false, 1},
+		{"inner nulls equal", left, right, dataframe.JoinInner, true, 3},
+		{"left", left, right, dataframe.JoinLeft, false, 3},
+		{"left nulls equal", left, right, dataframe.JoinLeft, true, 4},
+		{"right", left, right, dataframe.JoinRight, false, 3},
+		{"right nulls equal", left, right, dataframe.JoinRight, true, 3},
+		{"outer", left, right, dataframe.JoinOuter, false, 5},
+		{"outer nulls equal", left, right, dataframe.JoinOuter, true, 4},
+		{"unique right nulls equal", left, uniqueRight, dataframe.JoinInner, true, 3},
+		{"empty left", empty, right, dataframe.JoinInner, true, 0},
+	}
+	for _, c := range cases {
+		opt := dataframe.JoinNullsEqual(c.nullsEqual)
+		out, err := c.left.Join(c.right, []string{"id"}, []string{"id"}, c.how, opt)
+		assert.NoError(t, err, c.name)
+		rows, _ := out.Shape()
+		assert.Equal(t, c.rows, rows, c.name)
+
+		lazy, err := c.left.Lazy().Join(c.right.Lazy(), []string{"id"}, []string{"id"}, c.how, opt).Collect()
+		assert.NoError(t, err, c.name)
+		rows, _ = lazy.Shape()
+		assert.Equal(t, c.rows, rows, "lazy %s", c.name)
+	}
+
+	// Uniqueness checks follow the same rule.
+	unique, err := right.IsUnique([]string{"id"})
+	assert.NoError(t, err)
+	assert.True(t, unique)
+	unique, err = right.IsUnique([]string{"id"}, dataframe.JoinNullsEqual(true))
+	assert.NoError(t, err)
+	assert.False(t, unique)
+}
+
+func TestJoinSignedZero(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	cases := []struct {
+		name        string
+		left, right *types.Series
+	}{
+		{"float64", types.NewSeries("k", []float64{negZero, 1}), types.NewSeries("k", []float64{0, 2})},
+		{"float32", types.NewSeries("k", []float32{float32(negZero), 1}), types.NewSeries("k", []float32{0, 2})},
+	}
+	for _, c := range cases {
+		left, err := dataframe.FromColumns([]*types.Series{c.left, types.NewSeries("lv", []string{"a", "b"})})
+		assert.NoError(t, err)
+		right, err := dataframe.FromColumns([]*types.Series{c.right, types.NewSeries("rv", []string{"x", "y"})})
+		assert.NoError(t, err)
+		out, err := left.Join(right, []string{"k"}, []string{"k"}, dataframe.JoinInner)
+		assert.NoError(t, err, c.name)
+		rv, err := out.ToSeries("rv")
+		assert.NoError(t, err, c.name)
+		assert.Equal(t, []string{"x"}, rv.Data, c.name)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before, err := dataframe.FromColumns([]*types.Series{
+		types.NewNullableSeries("id", []int64{1, 2, 3, 4, 0}, []bool{true, true, true, true, false}),
+		types.NewNullableSeries("v", []float64{1, math.NaN(), 3, 0, 5}, []bool{true, true, true, false, true}),
+		types.NewSeries("s", []string{"a", "b", "c", "d", "e"}),
+		types.NewSeries("only_before", []int64{1, 2, 3, 4, 5}),
+	})
+	assert.NoError(t, err)
+	after, err := dataframe.FromColumns([]*types.Series{
+		types.NewNullableSeries("id", []int64{2, 3, 4, 5, 0}, []bool{true, true, true, true, false}),
+		types.NewNullableSeries("v", []float64{math.NaN(), 30, 0, 6, 5}, []bool{true, true, false, true, true}),
+		types.NewSeries("s", []string{"b", "c", "D", "f", "e"}),
+	})
+	assert.NoError(t, err)
+
+	cmp, err := dataframe.Compare(before, after, []string{"id"})
+	assert.NoError(t, err)
+	// NaNs and nulls, null keys included, compare equal.
+	assert.Equal(t, 2, cmp.Unchanged)
+	added, _ := cmp.Added.ToSeries("id")
+	assert.Equal(t, []int64{5}, added.Data)
+	removed, _ := cmp.Removed.ToSeries("id")
+	assert.Equal(t, []int64{1}, removed.Data)
+	assert.Equal(t, []string{"id", "v_before", "v_after", "v_changed", "s_before", "s_after", "s_changed"}, cmp.Changed.Columns())
+	for col, want := range map[string]interface{}{
+		"id":        []int64{3, 4},
+		"v_after":   []float64{30, 0},
+		"v_changed": []bool{true, false},
+		"s_changed": []bool{false, true},
+	} {
+		s, err := cmp.Changed.ToSeries(col)
+		assert.NoError(t, err)
+		assert.Equal(t, want, s.Data, col)
+	}
+
+	empty, err := before.Head(0)
+	assert.NoError(t, err)
+	cmp, err = dataframe.Compare(empty, empty, []string{"id"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cmp.Unchanged)
+	rows, _ := cmp.Changed.Shape()
+	assert.Equal(t, 0, rows)
+
+	dupIDs := types.NewSeries("id", []int64{2, 3, 4, 5, 5})
+	dup, err := after.WithColumn("id", dupIDs)
+	assert.NoError(t, err)
+	clash, err := after.WithColumn("v_before", types.NewSeries("v_before", []int64{1, 2, 3, 4, 5}))
+	assert.NoError(t, err)
+	clashBefore, err := before.WithColumn("v_before", types.NewSeries("v_before", []int64{1, 2, 3, 4, 5}))
+	assert.NoError(t, err)
+	retyped, err := after.WithColumn("s", types.NewSeries("s", []int64{1, 2, 3, 4, 5}))
+	assert.NoError(t, err)
+	for name, c := range map[string]struct {
+		before, after *dataframe.DataFrame
+		keys          []string
+	}{
+		"no keys":          {before, after, nil},
+		"missing key":      {before, after, []string{"missing"}},
+		"duplicate before": {dup, before, []string{"id"}},
+		"duplicate after":  {before, dup, []string{"id"}},
+		"name clash":       {clashBefore, clash, []string{"id", "v_before"}},
+		"type mismatch":    {before, retyped, []string{"id"}},
+	} {
+		_, err := dataframe.Compare(c.before, c.after, c.keys)
+		assert.Error(t, err, name)
+	}
+}
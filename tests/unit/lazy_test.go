@@ -0,0 +1,220 @@
+package unit
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/expr"
+	"go-polars/types"
+
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazyFrame(t *testing.T) {
+	sales, _ := dataframe.New(map[string]*types.Series{
+		"store":  types.NewSeries("store", []int64{1, 2, 1, 3, 2}),
+		"amount": types.NewNullableSeries("amount", []float64{10, 20, 5, 7, 0}, []bool{true, true, true, true, false}),
+		"note":   types.NewSeries("note", []string{"a", "b", "c", "d", "e"}),
+	})
+	stores, _ := dataframe.New(map[string]*types.Series{
+		"id":     types.NewSeries("id", []int64{1, 2, 3}),
+		"region": types.NewSeries("region", []string{"north", "south", "north"}),
+		"size":   types.NewSeries("size", []int64{10, 20, 30}),
+	})
+
+	q := sales.Lazy().
+		WithColumn("double", expr.Col("amount").Mul(expr.Lit(2))).
+		Join(stores.Lazy(), []string{"store"}, []string{"id"}, dataframe.JoinInner).
+		Filter(expr.Col("region").Eq(expr.Lit("north"))).
+		Filter(expr.Col("double").Gt(expr.Lit(10.0))).
+		Sort("double", false).
+		Select("store", "double")
+
+	plan, err := q.Explain(true)
+	assert.NoError(t, err)
+	// Both filters are pushed below the join, the one on the new column
+	// stops at WithColumn, and unread columns are never scanned.
+	assert.Equal(t, strings.Join([]string{
+		"SELECT [store, double]",
+		"  SORT BY double descending",
+		"    INNER JOIN ON [store] = [id]",
+		`      FILTER (col("double") > lit(10))`,
+		`        WITH COLUMN double = (col("amount") * lit(2))`,
+		"          SCAN [amount, store] of 3 columns",
+		`      FILTER (col("region") == lit("north"))`,
+		"        SCAN [id, region] of 3 columns",
+		"",
+	}, "\n"), plan)
+
+	out, err := q.Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"store", "double"}, out.Columns())
+	double, _ := out.ToSeries("double")
+	store, _ := out.ToSeries("store")
+	assert.Equal(t, []float64{20, 14}, double.Data)
+	assert.Equal(t, []int64{1, 3}, store.Data)
+
+	// Filters on group keys run before the aggregation, others after it.
+	totals, err := sales.Lazy().
+		GroupBy("store").Agg("amount", dataframe.Sum).
+		Filter(expr.Col("store").Ne(expr.Lit(3)).And(expr.Col("amount").Gt(expr.Lit(10.0)))).
+		Collect()
+	assert.NoError(t, err)
+	rows, _ := totals.Shape()
+	assert.Equal(t, 2, rows)
+
+	_, err = sales.Lazy().Select("missing").Collect()
+	assert.Error(t, err)
+}
+
+func TestWriteParquet(t *testing.T) {
+	dir := t.TempDir()
+	df, err := dataframe.FromColumns([]*types.Series{
+		types.NewSeries("id", []int64{1, 2, 3, 4, 5}),
+		types.NewNullableSeries("score", []float64{0.5, 0, 2, 3.5, 1}, []bool{true, false, true, true, true}),
+		types.NewNullableSeries("name", []string{"a", "b", "", "a", "b"}, []bool{true, true, false, true, true}),
+		types.NewSeries("flag", []bool{true, false, true, false, true}),
+	})
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name      string
+		opts      dataframe.WriteParquetOptions
+		rowGroups int
+	}{
+		{"defaults", dataframe.WriteParquetOptions{}, 1},
+		{"zstd", dataframe.WriteParquetOptions{Compression: dataframe.CompressionZstd}, 1},
+		{"gzip row groups", dataframe.WriteParquetOptions{Compression: dataframe.CompressionGzip, RowGroupSize: 2}, 3},
+		{"dictionary", dataframe.WriteParquetOptions{Compression: dataframe.CompressionNone, DictionaryStrings: true}, 1},
+	}
+	for _, c := range cases {
+		path := filepath.Join(dir, c.name+".parquet")
+		assert.NoError(t, df.WriteParquet(path, c.opts), c.name)
+		f, err := file.OpenParquetFile(path, false)
+		assert.NoError(t, err, c.name)
+		assert.Equal(t, c.rowGroups, f.NumRowGroups(), c.name)
+		name, err := f.MetaData().RowGroup(0).ColumnChunk(2)
+		assert.NoError(t, err, c.name)
+		assert.Equal(t, c.opts.DictionaryStrings, name.HasDictionaryPage(), c.name)
+		f.Close()
+
+		back, err := dataframe.ScanParquet(path).Collect()
+		assert.NoError(t, err, c.name)
+		assert.Equal(t, df.Columns(), back.Columns(), c.name)
+		for _, col := range df.Columns() {
+			want, _ := df.ToSeries(col)
+			got, _ := back.ToSeries(col)
+			assert.Equal(t, want.Data, got.Data, "%s %s", c.name, col)
+			for i := 0; i < want.Length; i++ {
+				assert.Equal(t, want.IsValid(i), got.IsValid(i), "%s %s", c.name, col)
+			}
+		}
+	}
+
+	// A failed write leaves neither a partial file nor its temporary behind,
+	// and keeps the file it would have replaced.
+	path := filepath.Join(dir, "defaults.parquet")
+	before, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Error(t, df.WriteParquet(path, dataframe.WriteParquetOptions{Compression: 99}))
+	after, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, before, after)
+	busy := filepath.Join(dir, "busy")
+	assert.NoError(t, os.MkdirAll(filepath.Join(busy, "child"), 0o755))
+	assert.Error(t, df.WriteParquet(busy, dataframe.WriteParquetOptions{}))
+	_, err = os.Stat(busy + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCollectStreaming(t *testing.T) {
+	dir := t.TempDir()
+	var csv strings.Builder
+	csv.WriteString("store,amount,note\n")
+	for i := 0; i < 200; i++ {
+		amount := strconv.FormatFloat(float64(i)*1.5, 'f', -1, 64)
+		if i%7 == 3 {
+			amount = ""
+		}
+		fmt.Fprintf(&csv, "%d,%s,n%d\n", i%5, amount, i)
+	}
+	csvPath := filepath.Join(dir, "sales.csv")
+	assert.NoError(t, os.WriteFile(csvPath, []byte(csv.String()), 0o644))
+	df, err := dataframe.ReadCSV(csvPath, dataframe.CSVOptions{})
+	assert.NoError(t, err)
+	parquetPath := filepath.Join(dir, "sales.parquet")
+	assert.NoError(t, df.WriteParquet(parquetPath, dataframe.WriteParquetOptions{}))
+
+	spill := t.TempDir()
+	// A budget this small spills every few batches.
+	opts := dataframe.StreamOptions{BatchSize: 16, MemoryBudget: 512, SpillDir: spill}
+	for _, scan := range []*dataframe.LazyFrame{
+		dataframe.ScanCSV(csvPath, dataframe.CSVOptions{}),
+		dataframe.ScanParquet(parquetPath),
+	} {
+		for _, q := range []*dataframe.LazyFrame{
+			scan.Filter(expr.Col("store").Ne(expr.Lit(0))).
+				WithColumn("double", expr.Col("amount").Mul(expr.Lit(2))).
+				Sort("amount", false),
+			scan.GroupBy("store").Agg("amount", dataframe.Sum).Sort("store", true),
+			scan.Select("note"),
+		} {
+			want, err := q.Collect()
+			assert.NoError(t, err)
+			got, err := q.CollectStreaming(opts)
+			assert.NoError(t, err)
+			assert.Equal(t, want.Columns(), got.Columns())
+			for _, col := range want.Columns() {
+				w, _ := want.ToSeries(col)
+				g, _ := got.ToSeries(col)
+				assert.Equal(t, w.Data, g.Data, col)
+				for i := 0; i < w.Length; i++ {
+					assert.Equal(t, w.IsValid(i), g.IsValid(i), col)
+				}
+			}
+		}
+	}
+	left, _ := os.ReadDir(spill)
+	assert.Empty(t, left)
+
+	plan, err := dataframe.ScanCSV(csvPath, dataframe.CSVOptions{}).Select("note").Explain(true)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT [note]\n  SCAN csv "+csvPath+" [note] of 3 columns\n", plan)
+}
+
+func TestDecisionLog(t *testing.T) {
+	var buf bytes.Buffer
+	dataframe.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer dataframe.SetLogger(nil)
+	assert.True(t, dataframe.Verbose())
+
+	df, err := dataframe.New(map[string]*types.Series{
+		"k": types.NewSeries("k", []int64{1, 2, 1}),
+		"v": types.NewSeries("v", []float64{1, 2, 3}),
+		"w": types.NewSeries("w", []string{"a", "b", "c"}),
+	})
+	assert.NoError(t, err)
+	_, err = df.Lazy().
+		Filter(expr.Col("v").Gt(expr.Lit(1.0))).
+		GroupBy("k").Agg("v", dataframe.Sum).
+		Collect()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `msg="predicate pushdown" scan=frame predicates=1`)
+	assert.Contains(t, buf.String(), `msg="projection pushdown" scan=frame columns="[k v]" of=3`)
+	assert.Contains(t, buf.String(), `msg=aggregate path=streaming column=v`)
+
+	dataframe.SetLogger(nil)
+	buf.Reset()
+	_, err = df.SortByColumn("k", true)
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String())
+	assert.False(t, dataframe.Verbose())
+}
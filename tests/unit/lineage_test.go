@@ -0,0 +1,37 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineage(t *testing.T) {
+	l := dataframe.NewLineage()
+	df, _ := dataframe.New(map[string]*types.Series{"a": types.NewSeries("a", []int64{1, 2, 3})})
+
+	filtered, err := df.WithLineage(l).Filter("a", func(v interface{}) bool { return v.(int64) > 1 })
+	assert.NoError(t, err)
+	_, err = filtered.Head(1)
+	assert.NoError(t, err)
+	_, err = df.Head(1) // not recorded
+	assert.NoError(t, err)
+
+	entries := l.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "Filter", entries[0].Op)
+	assert.Equal(t, 3, entries[0].InputRows)
+	assert.Equal(t, 2, entries[0].OutputRows)
+	assert.Equal(t, "Head", entries[1].Op)
+
+	var buf bytes.Buffer
+	assert.NoError(t, l.WriteJSON(&buf))
+	var decoded []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "Filter", decoded[0]["op"])
+}
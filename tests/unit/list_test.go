@@ -0,0 +1,50 @@
+package unit
+
+import (
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListSeries(t *testing.T) {
+	s := types.NewNullableSeries("v", [][]int64{{1, 2}, {}, nil, {5}}, []bool{true, true, false, true})
+	assert.Equal(t, "List(Int64)", s.DataType.String())
+
+	lens, err := s.ListLengths()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 0, 0, 1}, lens.Data)
+	assert.True(t, lens.IsNull().Data.([]bool)[2])
+
+	sums, err := s.ListSum()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 0, 0, 5}, sums.Data)
+
+	flat, err := s.Explode()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 0, 0, 5}, flat.Data)
+	assert.Equal(t, 2, flat.NullCount())
+}
+
+func TestExplodeLockstep(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"ts": types.NewSeries("ts", [][]int64{{1, 2}, {3}}),
+		"v":  types.NewSeries("v", [][]float64{{0.1, 0.2}, {0.3}}),
+	})
+	assert.NoError(t, err)
+
+	out, err := df.Explode("ts", "v")
+	assert.NoError(t, err)
+	rows, _ := out.Shape()
+	assert.Equal(t, 3, rows)
+
+	bad, err := dataframe.New(map[string]*types.Series{
+		"ts": types.NewSeries("ts", [][]int64{{1, 2}}),
+		"v":  types.NewSeries("v", [][]float64{{0.1}}),
+	})
+	assert.NoError(t, err)
+	_, err = bad.Explode("ts", "v")
+	assert.Error(t, err)
+}
@@ -0,0 +1,202 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeriesNulls(t *testing.T) {
+	s := types.NewNullableSeries("v", []int64{1, 0, 3}, []bool{true, false, true})
+	assert.Equal(t, 1, s.NullCount())
+	assert.Equal(t, []bool{false, true, false}, s.IsNull().Data)
+
+	filled, err := s.FillNull(int64(7))
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 7, 3}, filled.Data)
+	assert.False(t, filled.HasNulls())
+
+	_, err = s.FillNull("x")
+	assert.Error(t, err)
+
+	dropped := s.DropNulls()
+	assert.Equal(t, []int64{1, 3}, dropped.Data)
+
+	taken := s.Take([]int{1, -1, 2})
+	assert.Equal(t, 2, taken.NullCount())
+	assert.True(t, taken.IsValid(2))
+}
+
+func TestDataFrameDropNulls(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"a": types.NewNullableSeries("a", []int64{1, 2, 3}, []bool{true, false, true}),
+		"b": types.NewNullableSeries("b", []string{"x", "y", ""}, []bool{true, true, false}),
+	})
+	assert.NoError(t, err)
+
+	onlyA, err := df.DropNulls("a")
+	assert.NoError(t, err)
+	rows, _ := onlyA.Shape()
+	assert.Equal(t, 2, rows)
+
+	all, err := df.DropNulls()
+	assert.NoError(t, err)
+	rows, _ = all.Shape()
+	assert.Equal(t, 1, rows)
+}
+
+func TestTypesSortNulls(t *testing.T) {
+	df, err := types.New(map[string]*types.Series{
+		"k": types.NewNullableSeries("k", []int64{3, 0, 1}, []bool{true, false, true}),
+		"n": types.NewSeries("n", []int32{30, 0, 10}),
+		"l": types.NewSeries("l", [][]string{{"c"}, nil, {"a"}}),
+	})
+	assert.NoError(t, err)
+
+	for _, asc := range []bool{true, false} {
+		sorted, err := df.SortByColumn("k", asc)
+		assert.NoError(t, err)
+		k := sorted.Series["k"]
+		assert.Equal(t, 1, k.NullCount())
+		assert.False(t, k.IsValid(2))
+		assert.Equal(t, 3, len(sorted.Series))
+		if asc {
+			assert.Equal(t, []int32{10, 30, 0}, sorted.Series["n"].Data)
+		} else {
+			assert.Equal(t, []int32{30, 10, 0}, sorted.Series["n"].Data)
+		}
+	}
+
+	byN, err := df.SortByColumn("n", true)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{nil, {"a"}, {"c"}}, byN.Series["l"].Data)
+	_, err = df.SortByColumn("l", true)
+	assert.Error(t, err)
+
+	head, err := df.Head(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(head.Series))
+	assert.False(t, head.Series["k"].IsValid(1))
+	assert.Equal(t, []int32{30, 0}, head.Series["n"].Data)
+}
+
+func TestWithColumn(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{"a": types.NewSeries("a", []int64{1, 2})})
+
+	out, err := df.WithColumn("b", types.NewSeries("x", []string{"p", "q"}))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, out.Columns())
+	assert.Equal(t, []string{"a"}, df.Columns())
+
+	out, err = out.WithColumns(types.NewSeries("a", []float64{0.5, 1.5}))
+	assert.NoError(t, err)
+	_, cols := out.Shape()
+	assert.Equal(t, 2, cols)
+
+	_, err = df.WithColumn("c", types.NewSeries("c", []int64{1}))
+	assert.Error(t, err)
+}
+
+func TestFilterRows(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"price": types.NewNullableSeries("price", []float64{10, 20, 0, 40}, []bool{true, true, false, true}),
+		"qty":   types.NewSeries("qty", []int32{5, 1, 3, 2}),
+	})
+	assert.NoError(t, err)
+
+	pred := func(r dataframe.Row) bool {
+		p, ok := r.Float64("price")
+		q, _ := r.Int64("qty")
+		return ok && p*float64(q) >= 50
+	}
+	out, err := df.FilterRows(pred)
+	assert.NoError(t, err)
+	s, err := out.ToSeries("qty")
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{5, 2}, s.Data)
+
+	par, err := df.FilterRowsParallel(pred, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, out.Columns(), par.Columns())
+	s, err = par.ToSeries("qty")
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{5, 2}, s.Data)
+}
+
+func TestWithColumnsFn(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"addr": types.NewSeries("addr", []string{"1 Main St|Springfield|12345", "bad"}),
+	})
+	assert.NoError(t, err)
+
+	out, err := df.WithColumnsFn(func(r dataframe.Row) map[string]interface{} {
+		s, _ := r.String("addr")
+		parts := strings.Split(s, "|")
+		if len(parts) != 3 {
+			return map[string]interface{}{"ok": false}
+		}
+		return map[string]interface{}{"street": parts[0], "city": parts[1], "zip": parts[2], "ok": true}
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"addr", "city", "ok", "street", "zip"}, out.Columns())
+	city, err := out.ToSeries("city")
+	assert.NoError(t, err)
+	assert.Equal(t, "Springfield", city.Data.([]string)[0])
+	assert.False(t, city.IsValid(1))
+
+	_, err = df.WithColumnsFn(func(r dataframe.Row) map[string]interface{} {
+		if r.Index() == 0 {
+			return map[string]interface{}{"x": 1}
+		}
+		return map[string]interface{}{"x": "one"}
+	})
+	assert.Error(t, err)
+}
+
+func TestFillAndInterpolate(t *testing.T) {
+	temp := types.NewNullableSeries("temp", []int64{0, 10, 0, 0, 40, 0}, []bool{false, true, false, false, true, false})
+
+	forward, err := temp.FillNullForward(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{10, 10}, forward.Data.([]int64)[1:3])
+	assert.False(t, forward.IsValid(0))
+	assert.False(t, forward.IsValid(3))
+	assert.True(t, forward.IsValid(5))
+	backward, err := temp.FillNullBackward(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{10, 10, 40, 40, 40}, backward.Data.([]int64)[:5])
+	assert.False(t, backward.IsValid(5))
+
+	linear, err := temp.Interpolate(types.InterpolateLinear)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{10, 20, 30, 40}, linear.Data.([]float64)[1:5])
+	assert.False(t, linear.IsValid(0))
+	assert.False(t, linear.IsValid(5))
+	nearest, err := temp.Interpolate(types.InterpolateNearest)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{10, 10, 40, 40}, nearest.Data.([]int64)[1:5])
+	_, err = types.NewSeries("s", []string{"a"}).Interpolate(types.InterpolateLinear)
+	assert.Error(t, err)
+
+	df, err := dataframe.New(map[string]*types.Series{
+		"temp":   temp,
+		"sensor": types.NewNullableSeries("sensor", []string{"a", "", "", "b", "", ""}, []bool{true, false, false, true, false, false}),
+	})
+	assert.NoError(t, err)
+	filled, err := df.FillNullForward(0, "sensor")
+	assert.NoError(t, err)
+	sensor, _ := filled.ToSeries("sensor")
+	assert.Equal(t, []string{"a", "a", "a", "b", "b", "b"}, sensor.Data)
+	same, _ := filled.ToSeries("temp")
+	assert.Equal(t, 4, same.NullCount())
+	smooth, err := df.Interpolate(types.InterpolateLinear)
+	assert.NoError(t, err)
+	s, _ := smooth.ToSeries("temp")
+	assert.Equal(t, types.Float64Type{}, s.DataType)
+	_, err = df.FillNullBackward(0, "missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineCheckpointResume(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{"a": types.NewSeries("a", []int64{1, 2, 3})})
+	dir := t.TempDir()
+	runs := 0
+	failLate := true
+	pipeline := func() *dataframe.Pipeline {
+		return dataframe.NewPipeline().
+			Then("filter:a>1", func(d *dataframe.DataFrame) (*dataframe.DataFrame, error) {
+				runs++
+				return d.Filter("a", func(v interface{}) bool { return v.(int64) > 1 })
+			}).
+			Then("head:1", func(d *dataframe.DataFrame) (*dataframe.DataFrame, error) {
+				if failLate {
+					return nil, errors.New("late failure")
+				}
+				return d.Head(1)
+			}).
+			Checkpoint(dir)
+	}
+
+	_, err := pipeline().Run(df)
+	assert.Error(t, err)
+
+	failLate = false
+	out, err := pipeline().Run(df)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, runs)
+	rows, _ := out.Shape()
+	assert.Equal(t, 1, rows)
+}
@@ -0,0 +1,44 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{
+		"id":    types.NewSeries("id", []int64{1, 2, 3, 4}),
+		"price": types.NewNullableSeries("price", []float64{1, 2.5, 0, 10.129}, []bool{true, true, false, true}),
+		"city":  types.NewSeries("city", []string{"oslo", "rome", "lima", "a very long city name"}),
+	})
+
+	assert.Equal(t, strings.Join([]string{
+		"shape: (4, 3)",
+		"┌───────────────────────┬───────┬─────────┐",
+		"│ city                  ┆ id    ┆ price   │",
+		"│ String                ┆ Int64 ┆ Float64 │",
+		"╞═══════════════════════╪═══════╪═════════╡",
+		"│ oslo                  ┆     1 ┆     1.0 │",
+		"│ rome                  ┆     2 ┆     2.5 │",
+		"│ lima                  ┆     3 ┆    null │",
+		"│ a very long city name ┆     4 ┆  10.129 │",
+		"└───────────────────────┴───────┴─────────┘",
+	}, "\n"), df.String())
+
+	assert.Equal(t, strings.Join([]string{
+		"shape: (4, 3)",
+		"┌────────┬───┬─────────┐",
+		"│ city   ┆ … ┆ price   │",
+		"│ String ┆   ┆ Float64 │",
+		"╞════════╪═══╪═════════╡",
+		"│ oslo   ┆ … ┆    1.00 │",
+		"│ …      ┆ … ┆       … │",
+		"│ a ver… ┆ … ┆   10.13 │",
+		"└────────┴───┴─────────┘",
+	}, "\n"), df.Format(dataframe.PrintOptions{MaxRows: 2, MaxCols: 2, FloatPrecision: 2, MaxWidth: 6}))
+}
@@ -0,0 +1,173 @@
+package unit
+
+import (
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRolling(t *testing.T) {
+	s := types.NewNullableSeries("v", []int64{4, 2, 0, 8, 6, 1}, []bool{true, true, false, true, true, true})
+	r, err := s.Rolling(3, 2)
+	assert.NoError(t, err)
+
+	sum := r.Sum()
+	assert.Equal(t, []int64{4, 6, 6, 10, 14, 15}, sum.Data)
+	assert.False(t, sum.IsValid(0))
+	assert.True(t, sum.IsValid(2))
+
+	assert.Equal(t, []float64{4, 3, 3, 5, 7, 5}, r.Mean().Data)
+	assert.Equal(t, []int64{2, 2, 6, 1}, r.Min().Data.([]int64)[2:])
+	assert.Equal(t, []int64{4, 8, 8, 8}, r.Max().Data.([]int64)[2:])
+	std := r.Std()
+	assert.InDelta(t, 1.41421, std.Data.([]float64)[1], 1e-5)
+	assert.InDelta(t, 3.60555, std.Data.([]float64)[5], 1e-5)
+
+	_, err = s.Rolling(0, 1)
+	assert.Error(t, err)
+}
+
+func TestRollingBy(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"ts":    types.NewSeries("ts", []int64{0, 10, 15, 40, 41}),
+		"price": types.NewSeries("price", []float64{1, 2, 3, 4, 5}),
+	})
+	assert.NoError(t, err)
+
+	out, err := df.RollingBy("ts", 20, "price", dataframe.Mean, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"price", "ts", "price_rolling_mean"}, out.Columns())
+	avg, err := out.ToSeries("price_rolling_mean")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 1.5, 2, 4, 4.5}, avg.Data)
+
+	unsorted, err := df.SortByColumn("ts", false)
+	assert.NoError(t, err)
+	_, err = unsorted.RollingBy("ts", 20, "price", dataframe.Sum, 0)
+	assert.Error(t, err)
+}
+
+func TestCumulative(t *testing.T) {
+	s := types.NewNullableSeries("v", []int32{3, 1, 0, 4, 2}, []bool{true, true, false, true, true})
+	sum, err := s.CumSum()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 4, 0, 8, 10}, sum.Data)
+	assert.False(t, sum.IsValid(2))
+	lo, err := s.CumMin()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 1, 0, 1, 1}, lo.Data)
+
+	df, err := dataframe.New(map[string]*types.Series{
+		"k": types.NewSeries("k", []string{"a", "b", "a", "b", "a"}),
+		"v": types.NewSeries("v", []float64{1, 2, 3, 4, 5}),
+	})
+	assert.NoError(t, err)
+	gdf, err := df.GroupBy([]string{"k"})
+	assert.NoError(t, err)
+	out, err := gdf.Cumulative(dataframe.CumProd, "v")
+	assert.NoError(t, err)
+	v, err := out.ToSeries("v")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 3, 8, 15}, v.Data)
+
+	out, err = df.Cumulative(dataframe.CumMax, "v")
+	assert.NoError(t, err)
+	v, err = out.ToSeries("v")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 3, 4, 5}, v.Data)
+}
+
+func TestShiftDiff(t *testing.T) {
+	s := types.NewNullableSeries("v", []int64{10, 12, 0, 15, 18}, []bool{true, true, false, true, true})
+	shifted := s.Shift(1)
+	assert.Equal(t, []int64{10, 12, 0, 15}, shifted.Data.([]int64)[1:])
+	assert.False(t, shifted.IsValid(0))
+	assert.False(t, shifted.IsValid(3))
+	assert.False(t, s.Shift(-2).IsValid(3))
+
+	diff, err := s.Diff(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), diff.Data.([]int64)[1])
+	assert.Equal(t, int64(3), diff.Data.([]int64)[4])
+	assert.Equal(t, 3, diff.NullCount())
+
+	pct, err := s.PctChange()
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.2, pct.Data.([]float64)[1], 1e-9)
+	assert.InDelta(t, 0.2, pct.Data.([]float64)[4], 1e-9)
+
+	df, err := dataframe.New(map[string]*types.Series{
+		"k": types.NewSeries("k", []string{"a", "b", "a", "b", "a"}),
+		"v": types.NewSeries("v", []float64{1, 2, 3, 6, 4}),
+	})
+	assert.NoError(t, err)
+	gdf, err := df.GroupBy([]string{"k"})
+	assert.NoError(t, err)
+	out, err := gdf.Diff(1, "v")
+	assert.NoError(t, err)
+	v, err := out.ToSeries("v")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 4, 1}, v.Data.([]float64)[2:])
+	assert.False(t, v.IsValid(0))
+	assert.False(t, v.IsValid(1))
+
+	out, err = gdf.Shift(1, "v")
+	assert.NoError(t, err)
+	v, err = out.ToSeries("v")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 3}, v.Data.([]float64)[2:])
+}
+
+func TestRank(t *testing.T) {
+	s := types.NewNullableSeries("v", []float64{3, 1, 3, 0, 2, 3}, []bool{true, true, true, false, true, true})
+	for method, want := range map[types.RankMethod][]int64{
+		types.RankMin:     {3, 1, 3, 0, 2, 3},
+		types.RankMax:     {5, 1, 5, 0, 2, 5},
+		types.RankDense:   {3, 1, 3, 0, 2, 3},
+		types.RankOrdinal: {3, 1, 4, 0, 2, 5},
+	} {
+		r, err := s.Rank(method, false)
+		assert.NoError(t, err)
+		assert.Equal(t, want, r.Data, method.String())
+		assert.False(t, r.IsValid(3))
+	}
+	avg, err := s.Rank(types.RankAverage, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 5, 2, 0, 4, 2}, avg.Data)
+
+	df, err := dataframe.New(map[string]*types.Series{
+		"team":  types.NewSeries("team", []string{"a", "b", "a", "b", "a"}),
+		"score": types.NewSeries("score", []int64{10, 7, 30, 9, 20}),
+	})
+	assert.NoError(t, err)
+	gdf, err := df.GroupBy([]string{"team"})
+	assert.NoError(t, err)
+	out, err := gdf.Rank(types.RankDense, true, "score")
+	assert.NoError(t, err)
+	rank, err := out.ToSeries("score_rank")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 2, 1, 1, 2}, rank.Data)
+}
+
+func TestSessionize(t *testing.T) {
+	df, err := dataframe.New(map[string]*types.Series{
+		"u": types.NewSeries("u", []string{"a", "a", "a", "b", "b", "a"}),
+		"t": types.NewNullableSeries("t", []int32{0, 0, 5, 0, 30, 40}, []bool{true, false, true, false, true, true}),
+	})
+	assert.NoError(t, err)
+
+	out, err := df.Sessionize([]string{"u"}, "t", 10)
+	assert.NoError(t, err)
+	s, err := out.ToSeries(dataframe.SessionColumn)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0, 0, 0, 0, 2, 1}, s.Data)
+	assert.False(t, s.IsValid(1))
+	assert.False(t, s.IsValid(3))
+	assert.Equal(t, 2, s.NullCount())
+
+	_, err = df.Sessionize([]string{"u"}, "t", -1)
+	assert.Error(t, err)
+}
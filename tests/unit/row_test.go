@@ -0,0 +1,178 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowAccess(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{
+		"id":    types.NewSeries("id", []int32{1, 2, 3}),
+		"name":  types.NewNullableSeries("name", []string{"ann", "", "cy"}, []bool{true, false, true}),
+		"score": types.NewSeries("score", []float64{1.5, 2, -3}),
+	})
+
+	row, err := df.Row(1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": int32(2), "name": nil, "score": 2.0}, row.Map())
+	_, err = df.Row(3)
+	assert.Error(t, err)
+
+	var ids []int64
+	it := df.Iterator()
+	for it.Next() {
+		id, _ := it.Row().Int64("id")
+		ids = append(ids, id)
+	}
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	for i, r := range df.Rows() {
+		assert.Equal(t, i, r.Index())
+		if i == 1 {
+			break
+		}
+	}
+	assert.Len(t, df.ToMaps(), 3)
+
+	type record struct {
+		ID    int     `polars:"id"`
+		Name  *string `polars:"name"`
+		Score float32 `polars:"score"`
+		Other string
+	}
+	var records []record
+	assert.NoError(t, df.ToStructs(&records))
+	assert.Len(t, records, 3)
+	assert.Equal(t, 1, records[0].ID)
+	assert.Equal(t, "ann", *records[0].Name)
+	assert.Nil(t, records[1].Name)
+	assert.Equal(t, float32(-3), records[2].Score)
+
+	// Values must fit their fields.
+	var unsigned []struct {
+		Score uint `polars:"score"`
+	}
+	assert.Error(t, df.ToStructs(&unsigned))
+	assert.Error(t, df.ToStructs(records))
+}
+
+func TestFromStructs(t *testing.T) {
+	type trade struct {
+		Symbol string `polars:"symbol"`
+		Qty    int
+		Price  *float64 `polars:"price"`
+		Tags   []string `polars:"tags"`
+		Note   string   `polars:"-"`
+		hidden bool
+	}
+	price := 9.5
+	trades := []trade{
+		{Symbol: "ab", Qty: 3, Price: &price, Tags: []string{"x"}},
+		{Symbol: "cd", Qty: -1, Note: "skipped", hidden: true},
+	}
+	df, err := dataframe.FromStructs(trades)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"symbol", "Qty", "price", "tags"}, df.Columns())
+	qty, _ := df.ToSeries("Qty")
+	assert.Equal(t, []int64{3, -1}, qty.Data)
+	prices, _ := df.ToSeries("price")
+	assert.Equal(t, 1, prices.NullCount())
+	tags, _ := df.ToSeries("tags")
+	assert.True(t, tags.IsList())
+
+	// ToStructs reverses it.
+	var back []*trade
+	assert.NoError(t, df.ToStructs(&back))
+	assert.Equal(t, "cd", back[1].Symbol)
+	assert.Equal(t, 9.5, *back[0].Price)
+	assert.Equal(t, []string{"x"}, back[0].Tags)
+
+	_, err = dataframe.FromStructs([]struct{ C chan int }{{}})
+	assert.Error(t, err)
+	_, err = dataframe.FromStructs(trades[0])
+	assert.Error(t, err)
+}
+
+func TestFromRecordsAndValues(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	df, err := dataframe.FromRecords([]map[string]any{
+		{"id": int32(1), "score": float32(0.5), "at": at},
+		{"id": 2, "score": 3, "name": "b"},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"at", "id", "name", "score"}, df.Columns())
+	id, _ := df.ToSeries("id")
+	assert.Equal(t, []int64{1, 2}, id.Data)
+	score, _ := df.ToSeries("score")
+	assert.Equal(t, []float64{0.5, 3}, score.Data)
+	when, _ := df.ToSeries("at")
+	assert.Equal(t, "2024-05-01T12:00:00Z", when.Data.([]string)[0])
+	assert.Equal(t, 1, when.NullCount())
+
+	// The schema overrides the inferred types.
+	df, err = dataframe.FromValues(map[string][]any{
+		"id": {1, 2, nil},
+		"at": {at, at, at},
+	}, map[string]types.DataType{"id": types.Int16Type{}, "at": types.Int64Type{}})
+	assert.NoError(t, err)
+	id, _ = df.ToSeries("id")
+	assert.Equal(t, types.Int16Type{}, id.DataType)
+	when, _ = df.ToSeries("at")
+	assert.Equal(t, at.UnixNano(), when.Data.([]int64)[0])
+
+	_, err = dataframe.FromValues(map[string][]any{"a": {1}, "b": {1, 2}}, nil)
+	assert.Error(t, err)
+	_, err = dataframe.FromValues(map[string][]any{"a": {1, "x"}}, nil)
+	assert.Error(t, err)
+	_, err = dataframe.FromValues(map[string][]any{"a": {1}}, map[string]types.DataType{"b": types.Int64Type{}})
+	assert.Error(t, err)
+}
+
+func TestColumnDefaults(t *testing.T) {
+	source := dataframe.ColumnDefault{Value: "api"}
+	rowID := dataframe.ColumnDefault{Func: func(row int) any { return 100 + row }}
+
+	df, err := dataframe.FromRecords([]map[string]any{
+		{"id": 1, "source": "csv"},
+		{"source": nil},
+	}, nil, dataframe.WithDefaults(map[string]dataframe.ColumnDefault{"id": rowID, "source": source, "batch": {Value: 7}}))
+	assert.NoError(t, err)
+	ids, _ := df.ToSeries("id")
+	assert.Equal(t, []int64{1, 101}, ids.Data)
+	sources, _ := df.ToSeries("source")
+	assert.False(t, sources.IsValid(1))
+	batch, _ := df.ToSeries("batch")
+	assert.Equal(t, []int64{7, 7}, batch.Data)
+
+	type reading struct {
+		Sensor string
+		Value  *float64
+	}
+	v := 1.5
+	df, err = dataframe.FromStructs([]*reading{{Sensor: "a", Value: &v}, {Sensor: "b"}, nil},
+		dataframe.WithDefaults(map[string]dataframe.ColumnDefault{"Value": {Value: 0}, "Sensor": {Value: "unknown"}}))
+	assert.NoError(t, err)
+	values, _ := df.ToSeries("Value")
+	assert.Equal(t, []float64{1.5, 0, 0}, values.Data)
+	sensors, _ := df.ToSeries("Sensor")
+	assert.Equal(t, "unknown", sensors.Data.([]string)[2])
+	_, err = dataframe.FromStructs([]reading{{}}, dataframe.WithDefaults(map[string]dataframe.ColumnDefault{"Value": {Value: "x"}}))
+	assert.Error(t, err)
+	_, err = dataframe.FromStructs([]reading{{}}, dataframe.WithDefaults(map[string]dataframe.ColumnDefault{"Missing": {Value: 1}}))
+	assert.Error(t, err)
+
+	df, err = dataframe.ParseCSV(strings.NewReader("id,qty\n1,\n2,5\n"), dataframe.CSVOptions{
+		Defaults: map[string]dataframe.ColumnDefault{"qty": {Value: 0}, "row": rowID},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "qty", "row"}, df.Columns())
+	qty, _ := df.ToSeries("qty")
+	assert.Equal(t, []int64{0, 5}, qty.Data)
+	rows, _ := df.ToSeries("row")
+	assert.Equal(t, []int64{100, 101}, rows.Data)
+}
@@ -0,0 +1,253 @@
+package unit
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDB is an in-memory database understanding just the statements the
+// SQL reader and writer issue.
+type fakeDB struct {
+	mu     sync.Mutex
+	tables map[string]*fakeTable
+	ddl    []string
+}
+
+type fakeTable struct {
+	columns []string
+	rows    [][]driver.Value
+	failAt  int // row at which reading fails, 0 for never
+}
+
+var (
+	fakeMu  sync.Mutex
+	fakeDBs = map[string]*fakeDB{}
+)
+
+type fakeDriver struct{}
+
+func init() { sql.Register("fake", fakeDriver{}) }
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	db, ok := fakeDBs[name]
+	if !ok {
+		return nil, fmt.Errorf("no database %s", name)
+	}
+	return &fakeConn{db: db}, nil
+}
+
+// openFake opens a database holding tables, private to the test.
+func openFake(t *testing.T, tables map[string]*fakeTable) (*sql.DB, *fakeDB) {
+	if tables == nil {
+		tables = map[string]*fakeTable{}
+	}
+	fdb := &fakeDB{tables: tables}
+	fakeMu.Lock()
+	fakeDBs[t.Name()] = fdb
+	fakeMu.Unlock()
+	db, err := sql.Open("fake", t.Name())
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db, fdb
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+var (
+	selectRe = regexp.MustCompile(`^SELECT \* FROM (\w+)$`)
+	createRe = regexp.MustCompile(`^CREATE TABLE IF NOT EXISTS "(\w+)" \((.*)\)$`)
+	insertRe = regexp.MustCompile(`^INSERT INTO "(\w+)" \((.*?)\) VALUES `)
+)
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if m := createRe.FindStringSubmatch(s.query); m != nil {
+		s.db.ddl = append(s.db.ddl, s.query)
+		if _, ok := s.db.tables[m[1]]; !ok {
+			table := &fakeTable{}
+			for _, def := range strings.Split(m[2], ", ") {
+				table.columns = append(table.columns, strings.Trim(strings.Fields(def)[0], `"`))
+			}
+			s.db.tables[m[1]] = table
+		}
+		return driver.RowsAffected(0), nil
+	}
+	m := insertRe.FindStringSubmatch(s.query)
+	if m == nil {
+		return nil, fmt.Errorf("unsupported statement %q", s.query)
+	}
+	table, ok := s.db.tables[m[1]]
+	if !ok {
+		return nil, fmt.Errorf("no such table: %s", m[1])
+	}
+	var pos []int
+	for _, name := range strings.Split(m[2], ", ") {
+		p := slices.Index(table.columns, strings.Trim(name, `"`))
+		if p < 0 {
+			return nil, fmt.Errorf("no such column: %s", name)
+		}
+		pos = append(pos, p)
+	}
+	n := len(args) / len(pos)
+	for ; len(args) > 0; args = args[len(pos):] {
+		row := make([]driver.Value, len(table.columns))
+		for i, p := range pos {
+			row[p] = args[i]
+		}
+		table.rows = append(table.rows, row)
+	}
+	return driver.RowsAffected(n), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	m := selectRe.FindStringSubmatch(s.query)
+	if m == nil {
+		return nil, fmt.Errorf("unsupported query %q", s.query)
+	}
+	table, ok := s.db.tables[m[1]]
+	if !ok {
+		return nil, fmt.Errorf("no such table: %s", m[1])
+	}
+	return &fakeRows{table: table}, nil
+}
+
+type fakeRows struct {
+	table *fakeTable
+	next  int
+}
+
+func (r *fakeRows) Columns() []string { return r.table.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	switch {
+	case r.table.failAt > 0 && r.next == r.table.failAt:
+		return errors.New("connection lost")
+	case r.next == len(r.table.rows):
+		return io.EOF
+	}
+	copy(dest, r.table.rows[r.next])
+	r.next++
+	return nil
+}
+
+func TestReadSQL(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	db, _ := openFake(t, map[string]*fakeTable{
+		"readings": {
+			columns: []string{"id", "value", "ok", "name", "at", "mixed", "empty"},
+			rows: [][]driver.Value{
+				{int64(1), 1.5, true, []byte("a"), at, int64(1), nil},
+				{int64(2), nil, false, "b", nil, 2.5, nil},
+				{nil, 3.0, nil, nil, at, "x", nil},
+			},
+		},
+		"dup":   {columns: []string{"a", "a"}},
+		"flaky": {columns: []string{"a"}, rows: [][]driver.Value{{int64(1)}, {int64(2)}}, failAt: 1},
+	})
+
+	df, err := dataframe.ReadSQL(db, "SELECT * FROM readings")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "value", "ok", "name", "at", "mixed", "empty"}, df.Columns())
+	cases := []struct {
+		column string
+		data   interface{}
+		nulls  int
+	}{
+		{"id", []int64{1, 2, 0}, 1},
+		{"value", []float64{1.5, 0, 3}, 1},
+		{"ok", []bool{true, false, false}, 1},
+		{"name", []string{"a", "b", ""}, 1},
+		{"at", []string{"2024-03-01T12:00:00Z", "", "2024-03-01T12:00:00Z"}, 1},
+		{"mixed", []string{"1", "2.5", "x"}, 0},
+		{"empty", []string{"", "", ""}, 3},
+	}
+	for _, c := range cases {
+		s, err := df.ToSeries(c.column)
+		assert.NoError(t, err, c.column)
+		assert.Equal(t, c.data, s.Data, c.column)
+		assert.Equal(t, c.nulls, s.NullCount(), c.column)
+	}
+
+	_, err = dataframe.ReadSQL(db, "SELECT * FROM missing")
+	assert.ErrorContains(t, err, "no such table")
+	_, err = dataframe.ReadSQL(db, "SELECT * FROM dup")
+	assert.ErrorContains(t, err, "duplicate column a")
+	_, err = dataframe.ReadSQL(db, "SELECT * FROM flaky")
+	var perr *dataframe.ParseError
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, 1, perr.Row)
+}
+
+func TestWriteSQL(t *testing.T) {
+	df, err := dataframe.FromColumns([]*types.Series{
+		types.NewSeries("id", []int64{1, 2, 3}),
+		types.NewNullableSeries("score", []float64{0.5, 0, 2}, []bool{true, false, true}),
+		types.NewNullableSeries("name", []string{"a", "", "c"}, []bool{true, false, true}),
+		types.NewSeries("flag", []bool{true, false, true}),
+		types.NewSeries("small", []int16{1, -2, 3}),
+		types.NewCategoricalSeries("tier", []string{"gold", "gold", "basic"}),
+	})
+	assert.NoError(t, err)
+	db, fdb := openFake(t, nil)
+
+	assert.NoError(t, df.WriteSQL(db, "scores", dataframe.WithBatchSize(2)))
+	assert.Equal(t, []string{`CREATE TABLE IF NOT EXISTS "scores" ("id" BIGINT, "score" DOUBLE PRECISION, "name" TEXT, "flag" BOOLEAN, "small" SMALLINT, "tier" TEXT)`}, fdb.ddl)
+	assert.Equal(t, [][]driver.Value{
+		{int64(1), 0.5, "a", true, int64(1), "gold"},
+		{int64(2), nil, nil, false, int64(-2), "gold"},
+		{int64(3), 2.0, "c", true, int64(3), "basic"},
+	}, fdb.tables["scores"].rows)
+
+	back, err := dataframe.ReadSQL(db, "SELECT * FROM scores")
+	assert.NoError(t, err)
+	score, err := back.ToSeries("score")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, score.NullCount())
+
+	// Without CREATE TABLE the table and every column must already exist.
+	err = df.WriteSQL(db, "missing", dataframe.WithCreateTable(false))
+	assert.ErrorContains(t, err, "no such table")
+	fdb.tables["narrow"] = &fakeTable{columns: []string{"id", "score"}}
+	err = df.WriteSQL(db, "narrow", dataframe.WithCreateTable(false))
+	assert.ErrorContains(t, err, "no such column")
+	assert.Error(t, df.WriteSQL(db, "scores", dataframe.WithBatchSize(0)))
+}
@@ -0,0 +1,128 @@
+package unit
+
+import (
+	"math"
+	"testing"
+
+	"go-polars/dataframe"
+	"go-polars/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnique(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{
+		"id":   types.NewSeries("id", []int64{1, 2, 3, 4, 5, 6}),
+		"user": types.NewNullableSeries("user", []string{"a", "b", "a", "", "c", ""}, []bool{true, true, true, false, true, false}),
+	})
+	ids := func(keep dataframe.KeepStrategy, opts ...dataframe.JoinOption) interface{} {
+		out, err := df.Unique([]string{"user"}, keep, opts...)
+		assert.NoError(t, err)
+		s, _ := out.ToSeries("id")
+		return s.Data
+	}
+	// Null users are distinct unless nulls compare equal.
+	assert.Equal(t, []int64{1, 2, 4, 5, 6}, ids(dataframe.KeepFirst))
+	assert.Equal(t, []int64{2, 3, 4, 5, 6}, ids(dataframe.KeepLast))
+	assert.Equal(t, []int64{2, 4, 5, 6}, ids(dataframe.KeepNone))
+	assert.Equal(t, []int64{2, 3, 5, 6}, ids(dataframe.KeepLast, dataframe.JoinNullsEqual(true)))
+	assert.Equal(t, []int64{2, 5}, ids(dataframe.KeepNone, dataframe.JoinNullsEqual(true)))
+
+	out, err := df.Unique(nil, dataframe.KeepNone)
+	assert.NoError(t, err)
+	rows, _ := out.Shape()
+	assert.Equal(t, 6, rows)
+	_, err = df.Unique([]string{"missing"}, dataframe.KeepLast)
+	assert.Error(t, err)
+
+	s := types.NewNullableSeries("v", []float64{2, math.NaN(), 1, 2, 0, math.NaN()}, []bool{true, true, true, true, false, true})
+	u, err := s.Unique()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, u.Length)
+	assert.Equal(t, 1, u.NullCount())
+	n, err := s.NUnique()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	c, err := types.NewCategoricalSeries("c", []string{"x", "y", "x"}).Unique()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x", "y"}, c.Data.(*types.Categorical).Decode())
+}
+
+func TestValueCounts(t *testing.T) {
+	df, _ := dataframe.New(map[string]*types.Series{
+		"city": types.NewNullableSeries("city", []string{"oslo", "rome", "rome", "", "rome", "oslo", "lima"}, []bool{true, true, true, false, true, true, true}),
+	})
+	out, err := df.ValueCounts("city", true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"city", "count"}, out.Columns())
+	city, _ := out.ToSeries("city")
+	count, _ := out.ToSeries("count")
+	assert.Equal(t, []string{"rome", "oslo", "", "lima"}, city.Data)
+	assert.False(t, city.IsValid(2))
+	assert.Equal(t, []int64{3, 2, 1, 1}, count.Data)
+
+	out, err = df.ValueCounts("city", false, true)
+	assert.NoError(t, err)
+	share, _ := out.ToSeries("proportion")
+	assert.InDeltaSlice(t, []float64{2.0 / 7, 3.0 / 7, 1.0 / 7, 1.0 / 7}, share.Data, 1e-12)
+}
+
+func TestRunLengths(t *testing.T) {
+	s := types.NewNullableSeries("x", []int32{1, 1, 0, 0, 2, 0}, []bool{true, true, false, false, true, true})
+	values, lengths, err := s.RLE()
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{1, 0, 2, 0}, values.Data)
+	assert.False(t, values.IsValid(1))
+	assert.Equal(t, []int64{2, 2, 1, 1}, lengths.Data)
+	ids, err := s.RunID()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0, 0, 1, 1, 2, 3}, ids.Data)
+
+	// A null between equal values splits them.
+	values, lengths, err = types.NewNullableSeries("x", []int64{0, 0, 0}, []bool{true, false, true}).RLE()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, values.NullCount())
+	assert.Equal(t, []int64{1, 1, 1}, lengths.Data)
+
+	_, _, err = types.NewSeries("l", [][]int64{{1}}).RLE()
+	assert.Error(t, err)
+	_, err = types.NewSeries("l", [][]int64{{1}}).RunID()
+	assert.Error(t, err)
+}
+
+func TestStats(t *testing.T) {
+	cases := []struct {
+		name string
+		s    *types.Series
+		want types.Stats
+	}{
+		{"ints", types.NewSeries("v", []int64{3, 1, 3, 2}),
+			types.Stats{Min: int64(1), Max: int64(3), NDV: 3}},
+		{"ascending with trailing nulls", types.NewNullableSeries("v", []int32{1, 2, 2, 0}, []bool{true, true, true, false}),
+			types.Stats{Min: int32(1), Max: int32(2), NullCount: 1, NDV: 2, SortedAscending: true}},
+		{"leading null", types.NewNullableSeries("v", []float64{0, 1, 2}, []bool{false, true, true}),
+			types.Stats{Min: 1.0, Max: 2.0, NullCount: 1, NDV: 2}},
+		{"descending strings", types.NewSeries("v", []string{"c", "b", "a"}),
+			types.Stats{Min: "a", Max: "c", NDV: 3, SortedDescending: true}},
+		{"categorical", types.NewCategoricalSeries("v", []string{"x", "y", "x"}),
+			types.Stats{Min: "x", Max: "y", NDV: 2}},
+		{"bools", types.NewSeries("v", []bool{false, true}),
+			types.Stats{Min: false, Max: true, NDV: 2, SortedAscending: true}},
+		{"all null", types.NewNullableSeries("v", []int64{0, 0}, []bool{false, false}),
+			types.Stats{NullCount: 2, SortedAscending: true, SortedDescending: true}},
+		{"empty", types.NewSeries("v", []int64{}),
+			types.Stats{SortedAscending: true, SortedDescending: true}},
+		{"lists", types.NewSeries("v", [][]int64{{1}, {2}}),
+			types.Stats{}},
+	}
+	for _, c := range cases {
+		_, cached := c.s.CachedStats()
+		assert.False(t, cached, c.name)
+		st := c.s.Stats()
+		assert.Equal(t, c.want, *st, c.name)
+		again, cached := c.s.CachedStats()
+		assert.True(t, cached, c.name)
+		assert.Same(t, st, again, c.name)
+	}
+}
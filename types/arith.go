@@ -0,0 +1,372 @@
+package types
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+)
+
+// Add adds other to s element by element. other is a Series of the same
+// length, a Series of length one or a Go scalar, either side of length one
+// being repeated over the other. Numeric Series of different types are
+// first cast to their supertype; an integer scalar takes the type of an
+// integer or float Series when it fits in it, and a float scalar that of a
+// Float32 Series, so Int32 + 1 stays Int32. Integer results wrap around on
+// overflow. An element is null when either operand is.
+func (s *Series) Add(other interface{}) (*Series, error) {
+	return s.arith("add", other)
+}
+
+// Sub subtracts other from s element by element, see Add
+func (s *Series) Sub(other interface{}) (*Series, error) {
+	return s.arith("sub", other)
+}
+
+// Mul multiplies s by other element by element, see Add
+func (s *Series) Mul(other interface{}) (*Series, error) {
+	return s.arith("mul", other)
+}
+
+// Div divides s by other element by element, see Add. Division is true
+// division: integers give Float64, and Float32 operands Float32. Dividing
+// by zero gives null rather than an infinity or NaN.
+func (s *Series) Div(other interface{}) (*Series, error) {
+	return s.arith("div", other)
+}
+
+// Mod returns the remainder of dividing s by other element by element, see
+// Add. The remainder has the sign of the dividend, as with Go's % operator
+// and math.Mod, and the remainder of a division by zero is null.
+func (s *Series) Mod(other interface{}) (*Series, error) {
+	return s.arith("mod", other)
+}
+
+// Eq compares s with other element by element into a Boolean Series, true
+// where they are equal. other is given as for Add; numeric operands are
+// compared in their supertype, and String and Categorical operands as text.
+// An element is null when either operand is, and NaN equals nothing.
+func (s *Series) Eq(other interface{}) (*Series, error) {
+	return s.compare("eq", other)
+}
+
+// Neq is true where s and other differ, see Eq
+func (s *Series) Neq(other interface{}) (*Series, error) {
+	return s.compare("neq", other)
+}
+
+// Gt is true where s is greater than other, see Eq. false sorts before true.
+func (s *Series) Gt(other interface{}) (*Series, error) {
+	return s.compare("gt", other)
+}
+
+// GtEq is true where s is greater than or equal to other, see Gt
+func (s *Series) GtEq(other interface{}) (*Series, error) {
+	return s.compare("gt_eq", other)
+}
+
+// Lt is true where s is less than other, see Gt
+func (s *Series) Lt(other interface{}) (*Series, error) {
+	return s.compare("lt", other)
+}
+
+// LtEq is true where s is less than or equal to other, see Gt
+func (s *Series) LtEq(other interface{}) (*Series, error) {
+	return s.compare("lt_eq", other)
+}
+
+// operands resolves other against s and checks that their lengths match
+// or broadcast, returning the length of the result.
+func (s *Series) operands(op string, other interface{}) (*Series, int, error) {
+	r, ok := other.(*Series)
+	if !ok {
+		var err error
+		if r, err = scalarSeries(s.Name, other, s.DataType); err != nil {
+			return nil, 0, fmt.Errorf("cannot apply %s: %w", op, err)
+		}
+	}
+	n := s.Length
+	switch {
+	case s.Length == r.Length:
+	case r.Length == 1:
+	case s.Length == 1:
+		n = r.Length
+	default:
+		return nil, 0, fmt.Errorf("cannot apply %s to lengths %d and %d", op, s.Length, r.Length)
+	}
+	return r, n, nil
+}
+
+// scalarSeries wraps a Go scalar into a Series of length one, of the type
+// of a Series of type like when the value is numeric and fits in it.
+func scalarSeries(name string, v interface{}, like DataType) (*Series, error) {
+	var lit *Series
+	switch x := v.(type) {
+	case int:
+		lit = NewSeries(name, []int64{int64(x)})
+	case int8:
+		lit = NewSeries(name, []int64{int64(x)})
+	case int16:
+		lit = NewSeries(name, []int64{int64(x)})
+	case int32:
+		lit = NewSeries(name, []int64{int64(x)})
+	case int64:
+		lit = NewSeries(name, []int64{x})
+	case uint:
+		lit = NewSeries(name, []uint64{uint64(x)})
+	case uint8:
+		lit = NewSeries(name, []int64{int64(x)})
+	case uint16:
+		lit = NewSeries(name, []int64{int64(x)})
+	case uint32:
+		lit = NewSeries(name, []int64{int64(x)})
+	case uint64:
+		lit = NewSeries(name, []uint64{x})
+	case float32:
+		lit = NewSeries(name, []float64{float64(x)})
+	case float64:
+		lit = NewSeries(name, []float64{x})
+	case string:
+		return NewSeries(name, []string{x}), nil
+	case bool:
+		return NewSeries(name, []bool{x}), nil
+	default:
+		return nil, fmt.Errorf("unsupported operand %T", v)
+	}
+
+	switch {
+	case IsInteger(lit.DataType) && IsNumeric(like):
+		if cast, err := lit.Cast(like, true); err == nil {
+			return cast, nil
+		}
+	case lit.DataType == Float64Type{} && like == Float32Type{}:
+		return lit.Cast(like, false)
+	}
+	return lit, nil
+}
+
+func (s *Series) arith(op string, other interface{}) (*Series, error) {
+	r, n, err := s.operands(op, other)
+	if err != nil {
+		return nil, err
+	}
+	if !IsNumeric(s.DataType) || !IsNumeric(r.DataType) {
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", op, s.DataType, r.DataType)
+	}
+	dt, err := Supertype(s.DataType, r.DataType)
+	if err != nil {
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", op, s.DataType, r.DataType)
+	}
+	if op == "div" && IsInteger(dt) {
+		dt = Float64Type{}
+	}
+	l, err := s.Cast(dt, false)
+	if err != nil {
+		return nil, err
+	}
+	if r, err = r.Cast(dt, false); err != nil {
+		return nil, err
+	}
+
+	valid := broadcastValidity(s, r, n)
+	var out interface{}
+	switch a := l.Data.(type) {
+	case []int8:
+		out = arithInts(op, a, r.Data.([]int8), valid)
+	case []int16:
+		out = arithInts(op, a, r.Data.([]int16), valid)
+	case []int32:
+		out = arithInts(op, a, r.Data.([]int32), valid)
+	case []int64:
+		out = arithInts(op, a, r.Data.([]int64), valid)
+	case []uint8:
+		out = arithInts(op, a, r.Data.([]uint8), valid)
+	case []uint16:
+		out = arithInts(op, a, r.Data.([]uint16), valid)
+	case []uint32:
+		out = arithInts(op, a, r.Data.([]uint32), valid)
+	case []uint64:
+		out = arithInts(op, a, r.Data.([]uint64), valid)
+	case []float32:
+		out = arithFloats(op, a, r.Data.([]float32), valid)
+	case []float64:
+		out = arithFloats(op, a, r.Data.([]float64), valid)
+	default:
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", op, s.DataType, r.DataType)
+	}
+	return NewNullableSeries(s.Name, out, valid), nil
+}
+
+// broadcastValidity returns whether both operands are valid at each of the
+// n positions of the result.
+func broadcastValidity(l, r *Series, n int) []bool {
+	ls, rs := stride(l.Length), stride(r.Length)
+	valid := make([]bool, n)
+	for i := range valid {
+		valid[i] = l.IsValid(i*ls) && r.IsValid(i*rs)
+	}
+	return valid
+}
+
+// stride is 0 for an operand of length one, which is broadcast, and 1
+// otherwise.
+func stride(length int) int {
+	if length == 1 {
+		return 0
+	}
+	return 1
+}
+
+// arithInts computes op over integer operands. A zero divisor clears valid
+// instead of panicking.
+func arithInts[T Integer](op string, a, b []T, valid []bool) []T {
+	out := make([]T, len(valid))
+	as, bs := stride(len(a)), stride(len(b))
+	for i := range out {
+		x, y := a[i*as], b[i*bs]
+		switch op {
+		case "add":
+			out[i] = x + y
+		case "sub":
+			out[i] = x - y
+		case "mul":
+			out[i] = x * y
+		case "mod":
+			if y == 0 {
+				valid[i] = false
+				continue
+			}
+			out[i] = x % y
+		}
+	}
+	return out
+}
+
+// arithFloats computes op over float operands, a zero divisor giving null.
+func arithFloats[T float32 | float64](op string, a, b []T, valid []bool) []T {
+	out := make([]T, len(valid))
+	as, bs := stride(len(a)), stride(len(b))
+	for i := range out {
+		x, y := a[i*as], b[i*bs]
+		switch op {
+		case "add":
+			out[i] = x + y
+		case "sub":
+			out[i] = x - y
+		case "mul":
+			out[i] = x * y
+		case "div", "mod":
+			if y == 0 {
+				valid[i] = false
+				continue
+			}
+			if op == "div" {
+				out[i] = x / y
+			} else {
+				out[i] = T(math.Mod(float64(x), float64(y)))
+			}
+		}
+	}
+	return out
+}
+
+func (s *Series) compare(op string, other interface{}) (*Series, error) {
+	r, n, err := s.operands(op, other)
+	if err != nil {
+		return nil, err
+	}
+	l := s
+	mismatch := fmt.Errorf("cannot apply %s to %s and %s", op, s.DataType, r.DataType)
+	switch {
+	case IsNumeric(l.DataType) && IsNumeric(r.DataType):
+		dt, err := Supertype(l.DataType, r.DataType)
+		if err != nil {
+			return nil, mismatch
+		}
+		if l, err = l.Cast(dt, false); err != nil {
+			return nil, err
+		}
+		if r, err = r.Cast(dt, false); err != nil {
+			return nil, err
+		}
+	case isText(l.DataType) && isText(r.DataType):
+		if l, err = l.Cast(StringType{}, false); err != nil {
+			return nil, err
+		}
+		if r, err = r.Cast(StringType{}, false); err != nil {
+			return nil, err
+		}
+	case l.DataType == BooleanType{} && r.DataType == BooleanType{}:
+	default:
+		return nil, mismatch
+	}
+
+	valid := broadcastValidity(s, r, n)
+	var out []bool
+	switch a := l.Data.(type) {
+	case []int8:
+		out = compareValues(op, a, r.Data.([]int8), n)
+	case []int16:
+		out = compareValues(op, a, r.Data.([]int16), n)
+	case []int32:
+		out = compareValues(op, a, r.Data.([]int32), n)
+	case []int64:
+		out = compareValues(op, a, r.Data.([]int64), n)
+	case []uint8:
+		out = compareValues(op, a, r.Data.([]uint8), n)
+	case []uint16:
+		out = compareValues(op, a, r.Data.([]uint16), n)
+	case []uint32:
+		out = compareValues(op, a, r.Data.([]uint32), n)
+	case []uint64:
+		out = compareValues(op, a, r.Data.([]uint64), n)
+	case []float32:
+		out = compareValues(op, a, r.Data.([]float32), n)
+	case []float64:
+		out = compareValues(op, a, r.Data.([]float64), n)
+	case []string:
+		out = compareValues(op, a, r.Data.([]string), n)
+	case []bool:
+		out = compareValues(op, boolBits(a), boolBits(r.Data.([]bool)), n)
+	default:
+		return nil, mismatch
+	}
+	return NewNullableSeries(s.Name, out, valid), nil
+}
+
+func isText(dt DataType) bool {
+	return dt == StringType{} || dt == CategoricalType{}
+}
+
+// boolBits maps false to 0 and true to 1 so Booleans can be ordered
+func boolBits(data []bool) []uint8 {
+	out := make([]uint8, len(data))
+	for i, v := range data {
+		if v {
+			out[i] = 1
+		}
+	}
+	return out
+}
+
+func compareValues[T cmp.Ordered](op string, a, b []T, n int) []bool {
+	out := make([]bool, n)
+	as, bs := stride(len(a)), stride(len(b))
+	for i := range out {
+		x, y := a[i*as], b[i*bs]
+		switch op {
+		case "eq":
+			out[i] = x == y
+		case "neq":
+			out[i] = x != y
+		case "gt":
+			out[i] = x > y
+		case "gt_eq":
+			out[i] = x >= y
+		case "lt":
+			out[i] = x < y
+		case "lt_eq":
+			out[i] = x <= y
+		}
+	}
+	return out
+}
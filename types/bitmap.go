@@ -0,0 +1,106 @@
+package types
+
+import "math/bits"
+
+// Bitmap is a packed validity mask. Bit i is set when element i holds a value
+// and cleared when it is null.
+type Bitmap struct {
+	words []uint64
+	n     int
+}
+
+// NewBitmap creates a Bitmap of length n with every bit set to valid
+func NewBitmap(n int, valid bool) *Bitmap {
+	b := &Bitmap{words: make([]uint64, (n+63)/64), n: n}
+	if valid {
+		for i := range b.words {
+			b.words[i] = ^uint64(0)
+		}
+		b.clearTail()
+	}
+	return b
+}
+
+// BitmapFromBools builds a Bitmap from a slice of validity flags
+func BitmapFromBools(valid []bool) *Bitmap {
+	b := NewBitmap(len(valid), false)
+	for i, v := range valid {
+		if v {
+			b.words[i>>6] |= 1 << (uint(i) & 63)
+		}
+	}
+	return b
+}
+
+// clearTail zeroes the unused bits of the last word so that popcounts stay
+// exact.
+func (b *Bitmap) clearTail() {
+	if rem := uint(b.n) & 63; rem != 0 {
+		b.words[len(b.words)-1] &= (1 << rem) - 1
+	}
+}
+
+// Len returns the number of elements covered by the Bitmap
+func (b *Bitmap) Len() int { return b.n }
+
+// Get reports whether element i is valid
+func (b *Bitmap) Get(i int) bool {
+	return b.words[i>>6]&(1<<(uint(i)&63)) != 0
+}
+
+// Set marks element i as valid or null
+func (b *Bitmap) Set(i int, valid bool) {
+	if valid {
+		b.words[i>>6] |= 1 << (uint(i) & 63)
+	} else {
+		b.words[i>>6] &^= 1 << (uint(i) & 63)
+	}
+}
+
+// NullCount returns the number of null elements
+func (b *Bitmap) NullCount() int {
+	valid := 0
+	for _, w := range b.words {
+		valid += bits.OnesCount64(w)
+	}
+	return b.n - valid
+}
+
+// eachSet calls fn with every set position in ascending order
+func (b *Bitmap) eachSet(fn func(i int)) {
+	for w, word := range b.words {
+		for word != 0 {
+			fn(w<<6 + bits.TrailingZeros64(word))
+			word &= word - 1
+		}
+	}
+}
+
+// Clone returns an independent copy of the Bitmap
+func (b *Bitmap) Clone() *Bitmap {
+	words := make([]uint64, len(b.words))
+	copy(words, b.words)
+	return &Bitmap{words: words, n: b.n}
+}
+
+// Take gathers the validity of the given positions into a new Bitmap
+func (b *Bitmap) Take(idx []int) *Bitmap {
+	out := NewBitmap(len(idx), false)
+	for i, j := range idx {
+		if b.Get(j) {
+			out.words[i>>6] |= 1 << (uint(i) & 63)
+		}
+	}
+	return out
+}
+
+// Slice returns the validity of elements [start, end) as a new Bitmap
+func (b *Bitmap) Slice(start, end int) *Bitmap {
+	out := NewBitmap(end-start, false)
+	for i := start; i < end; i++ {
+		if b.Get(i) {
+			out.Set(i-start, true)
+		}
+	}
+	return out
+}
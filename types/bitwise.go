@@ -0,0 +1,93 @@
+package types
+
+import "fmt"
+
+// BitAnd combines two integer Series of the same length bit by bit. Series
+// of different integer types are first cast to their supertype, which must
+// be an integer type. An element is null when either operand is.
+func (s *Series) BitAnd(other *Series) (*Series, error) {
+	return s.bitwise("bit_and", other, func(a, b uint64) uint64 { return a & b })
+}
+
+// BitOr combines two integer Series bit by bit, see BitAnd
+func (s *Series) BitOr(other *Series) (*Series, error) {
+	return s.bitwise("bit_or", other, func(a, b uint64) uint64 { return a | b })
+}
+
+// BitXor combines two integer Series bit by bit, see BitAnd
+func (s *Series) BitXor(other *Series) (*Series, error) {
+	return s.bitwise("bit_xor", other, func(a, b uint64) uint64 { return a ^ b })
+}
+
+// BitNot flips every bit of an integer Series, keeping its type and nulls
+func (s *Series) BitNot() (*Series, error) {
+	if !IsInteger(s.DataType) {
+		return nil, fmt.Errorf("cannot apply bit_not to %s", s.DataType)
+	}
+	out := mapBits(s.Data, s.Data, func(a, _ uint64) uint64 { return ^a })
+	res := NewSeries(s.Name, out)
+	res.Validity = s.Validity
+	return res, nil
+}
+
+func (s *Series) bitwise(op string, other *Series, fn func(a, b uint64) uint64) (*Series, error) {
+	if s.Length != other.Length {
+		return nil, fmt.Errorf("cannot apply %s to lengths %d and %d", op, s.Length, other.Length)
+	}
+	dt, err := Supertype(s.DataType, other.DataType)
+	if err != nil || !IsInteger(dt) {
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", op, s.DataType, other.DataType)
+	}
+	l, err := s.Cast(dt, true)
+	if err != nil {
+		return nil, err
+	}
+	r, err := other.Cast(dt, true)
+	if err != nil {
+		return nil, err
+	}
+	out := NewSeries(s.Name, mapBits(l.Data, r.Data, fn))
+	if s.Validity != nil || other.Validity != nil {
+		valid := make([]bool, s.Length)
+		for i := range valid {
+			valid[i] = s.IsValid(i) && other.IsValid(i)
+		}
+		out = NewNullableSeries(s.Name, out.Data, valid)
+	}
+	return out, nil
+}
+
+// mapBits applies fn to the bits of the elements of two integer slices of
+// the same type, returning a slice of that type.
+func mapBits(a, b interface{}, fn func(a, b uint64) uint64) interface{} {
+	switch x := a.(type) {
+	case []int8:
+		return combineBits(x, b.([]int8), fn)
+	case []int16:
+		return combineBits(x, b.([]int16), fn)
+	case []int32:
+		return combineBits(x, b.([]int32), fn)
+	case []int64:
+		return combineBits(x, b.([]int64), fn)
+	case []uint8:
+		return combineBits(x, b.([]uint8), fn)
+	case []uint16:
+		return combineBits(x, b.([]uint16), fn)
+	case []uint32:
+		return combineBits(x, b.([]uint32), fn)
+	case []uint64:
+		return combineBits(x, b.([]uint64), fn)
+	default:
+		panic("unsupported data type")
+	}
+}
+
+// combineBits works on the two's complement bits of every element: the
+// conversions to uint64 sign-extend, and those back truncate.
+func combineBits[T Integer](a, b []T, fn func(a, b uint64) uint64) []T {
+	out := make([]T, len(a))
+	for i := range out {
+		out[i] = T(fn(uint64(a[i]), uint64(b[i])))
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package types
+
+import "context"
+
+// pollEvery is the number of steps the loops of the Context variants of
+// operations take between two looks at their context.
+const pollEvery = 1 << 12
+
+// stopped carries the error of a done context out of a loop that cannot
+// return one, such as the less function of a sort.
+type stopped struct{ err error }
+
+// poller looks at a context every pollEvery calls to poll and panics with
+// stopped once it is done. A nil poller never stops.
+type poller struct {
+	ctx context.Context
+	n   int
+}
+
+func newPoller(ctx context.Context) *poller {
+	if ctx.Done() == nil {
+		return nil
+	}
+	return &poller{ctx: ctx}
+}
+
+func (p *poller) poll() {
+	if p == nil {
+		return
+	}
+	if p.n++; p.n%pollEvery == 0 {
+		if err := p.ctx.Err(); err != nil {
+			panic(stopped{err})
+		}
+	}
+}
+
+// recoverStopped turns the panic of a poller into the error of the
+// operation running it; other panics go on unwinding.
+func recoverStopped(err *error) {
+	if r := recover(); r != nil {
+		s, ok := r.(stopped)
+		if !ok {
+			panic(r)
+		}
+		*err = s.err
+	}
+}
@@ -0,0 +1,259 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Cast converts the Series to the given data type and returns a new Series.
+// It covers widening and narrowing between the numeric types, parsing and
+// formatting between strings and numbers or booleans, bool<->number
+// conversion (non-zero is true) and string<->Categorical. Floats cast to
+// integers are truncated toward zero. Values that cannot be represented in
+// the target type (overflow, NaN, unparsable text) produce an error when
+// strict is true and a null otherwise.
+func (s *Series) Cast(to DataType, strict bool) (*Series, error) {
+	if s.DataType.String() == to.String() {
+		return s, nil
+	}
+	in, err := castSource(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot cast column %s from %s to %s", s.Name, s.DataType, to)
+	}
+
+	switch to.(type) {
+	case Int8Type:
+		return castLoop(s, in, to, strict, toInt[int8](in))
+	case Int16Type:
+		return castLoop(s, in, to, strict, toInt[int16](in))
+	case Int32Type:
+		return castLoop(s, in, to, strict, toInt[int32](in))
+	case Int64Type:
+		return castLoop(s, in, to, strict, toInt[int64](in))
+	case UInt8Type:
+		return castLoop(s, in, to, strict, toInt[uint8](in))
+	case UInt16Type:
+		return castLoop(s, in, to, strict, toInt[uint16](in))
+	case UInt32Type:
+		return castLoop(s, in, to, strict, toInt[uint32](in))
+	case UInt64Type:
+		return castLoop(s, in, to, strict, toInt[uint64](in))
+	case Float32Type:
+		return castLoop(s, in, to, strict, toFloat[float32](in))
+	case Float64Type:
+		return castLoop(s, in, to, strict, toFloat[float64](in))
+	case BooleanType:
+		return castLoop(s, in, to, strict, toBool(in))
+	case StringType:
+		return castLoop(s, in, to, strict, toString(in))
+	case CategoricalType:
+		str, err := castLoop(s, in, StringType{}, strict, toString(in))
+		if err != nil {
+			return nil, err
+		}
+		out := NewCategoricalSeries(s.Name, str.Data.([]string))
+		out.Validity = str.Validity
+		return out, nil
+	default:
+		return nil, fmt.Errorf("cannot cast column %s from %s to %s", s.Name, s.DataType, to)
+	}
+}
+
+// castInput holds the source values normalised to one of five families.
+type castInput struct {
+	ints      []int64
+	uints     []uint64
+	floats    []float64
+	strs      []string
+	bools     []bool
+	floatBits int // 32 when the floats came from a Float32 column
+}
+
+func castSource(s *Series) (castInput, error) {
+	switch d := s.Data.(type) {
+	case []int8:
+		return castInput{ints: widenInts(d)}, nil
+	case []int16:
+		return castInput{ints: widenInts(d)}, nil
+	case []int32:
+		return castInput{ints: widenInts(d)}, nil
+	case []int64:
+		return castInput{ints: d}, nil
+	case []uint8:
+		return castInput{uints: toUint64(d)}, nil
+	case []uint16:
+		return castInput{uints: toUint64(d)}, nil
+	case []uint32:
+		return castInput{uints: toUint64(d)}, nil
+	case []uint64:
+		return castInput{uints: d}, nil
+	case []float32:
+		return castInput{floats: widenFloats(d), floatBits: 32}, nil
+	case []float64:
+		return castInput{floats: d, floatBits: 64}, nil
+	case []string:
+		return castInput{strs: d}, nil
+	case *Categorical:
+		return castInput{strs: d.Decode()}, nil
+	case []bool:
+		return castInput{bools: d}, nil
+	default:
+		return castInput{}, fmt.Errorf("unsupported cast source %T", d)
+	}
+}
+
+func (in castInput) value(i int) interface{} {
+	switch {
+	case in.ints != nil:
+		return in.ints[i]
+	case in.uints != nil:
+		return in.uints[i]
+	case in.floats != nil:
+		return in.floats[i]
+	case in.strs != nil:
+		return strconv.Quote(in.strs[i])
+	default:
+		return in.bools[i]
+	}
+}
+
+// castLoop applies conv to every valid row. Rows conv rejects become nulls,
+// or abort the cast when strict is set.
+func castLoop[D any](s *Series, in castInput, to DataType, strict bool, conv func(i int) (D, bool)) (*Series, error) {
+	out := make([]D, s.Length)
+	valid := make([]bool, s.Length)
+	for i := range out {
+		if !s.IsValid(i) {
+			continue
+		}
+		v, ok := conv(i)
+		if !ok {
+			if strict {
+				return nil, fmt.Errorf("column %s: cannot cast value %v at row %d to %s", s.Name, in.value(i), i, to)
+			}
+			continue
+		}
+		out[i], valid[i] = v, true
+	}
+	return NewNullableSeries(s.Name, out, valid), nil
+}
+
+func toInt[D Integer](in castInput) func(int) (D, bool) {
+	fromInt := func(v int64) (D, bool) {
+		d := D(v)
+		return d, int64(d) == v && (v < 0) == (d < 0)
+	}
+	fromUint := func(v uint64) (D, bool) {
+		d := D(v)
+		return d, uint64(d) == v && d >= 0
+	}
+	fromFloat := func(f float64) (D, bool) {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, false
+		}
+		t := math.Trunc(f)
+		d := D(t)
+		return d, float64(d) == t
+	}
+
+	switch {
+	case in.ints != nil:
+		return func(i int) (D, bool) { return fromInt(in.ints[i]) }
+	case in.uints != nil:
+		return func(i int) (D, bool) { return fromUint(in.uints[i]) }
+	case in.floats != nil:
+		return func(i int) (D, bool) { return fromFloat(in.floats[i]) }
+	case in.strs != nil:
+		return func(i int) (D, bool) {
+			if v, err := strconv.ParseInt(in.strs[i], 10, 64); err == nil {
+				return fromInt(v)
+			}
+			if v, err := strconv.ParseUint(in.strs[i], 10, 64); err == nil {
+				return fromUint(v)
+			}
+			return 0, false
+		}
+	default:
+		return func(i int) (D, bool) {
+			if in.bools[i] {
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+}
+
+func toFloat[D float32 | float64](in castInput) func(int) (D, bool) {
+	fromFloat := func(f float64) (D, bool) {
+		d := D(f)
+		return d, !math.IsInf(float64(d), 0) || math.IsInf(f, 0)
+	}
+
+	switch {
+	case in.ints != nil:
+		return func(i int) (D, bool) { return D(in.ints[i]), true }
+	case in.uints != nil:
+		return func(i int) (D, bool) { return D(in.uints[i]), true }
+	case in.floats != nil:
+		return func(i int) (D, bool) { return fromFloat(in.floats[i]) }
+	case in.strs != nil:
+		return func(i int) (D, bool) {
+			f, err := strconv.ParseFloat(in.strs[i], 64)
+			if err != nil {
+				return 0, false
+			}
+			return fromFloat(f)
+		}
+	default:
+		return func(i int) (D, bool) {
+			if in.bools[i] {
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+}
+
+func toBool(in castInput) func(int) (bool, bool) {
+	switch {
+	case in.ints != nil:
+		return func(i int) (bool, bool) { return in.ints[i] != 0, true }
+	case in.uints != nil:
+		return func(i int) (bool, bool) { return in.uints[i] != 0, true }
+	case in.floats != nil:
+		return func(i int) (bool, bool) { return in.floats[i] != 0, !math.IsNaN(in.floats[i]) }
+	case in.strs != nil:
+		return func(i int) (bool, bool) {
+			b, err := strconv.ParseBool(in.strs[i])
+			return b, err == nil
+		}
+	default:
+		return func(i int) (bool, bool) { return in.bools[i], true }
+	}
+}
+
+func toString(in castInput) func(int) (string, bool) {
+	switch {
+	case in.ints != nil:
+		return func(i int) (string, bool) { return strconv.FormatInt(in.ints[i], 10), true }
+	case in.uints != nil:
+		return func(i int) (string, bool) { return strconv.FormatUint(in.uints[i], 10), true }
+	case in.floats != nil:
+		return func(i int) (string, bool) {
+			return strconv.FormatFloat(in.floats[i], 'g', -1, in.floatBits), true
+		}
+	case in.strs != nil:
+		return func(i int) (string, bool) { return in.strs[i], true }
+	default:
+		return func(i int) (string, bool) { return strconv.FormatBool(in.bools[i]), true }
+	}
+}
+
+func toUint64[T Integer](data []T) []uint64 {
+	out := make([]uint64, len(data))
+	for i, v := range data {
+		out[i] = uint64(v)
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package types
+
+// CategoricalType describes dictionary-encoded string data
+type CategoricalType struct{}
+
+func (CategoricalType) String() string { return "Categorical" }
+
+// Categorical holds dictionary-encoded strings. Each code indexes into
+// Categories, which lists every distinct value once.
+type Categorical struct {
+	Codes      []uint32
+	Categories []string
+}
+
+// NewCategoricalSeries dictionary-encodes values. Categories are numbered in
+// order of first appearance.
+func NewCategoricalSeries(name string, values []string) *Series {
+	lookup := make(map[string]uint32)
+	cat := &Categorical{Codes: make([]uint32, len(values))}
+	for i, v := range values {
+		code, ok := lookup[v]
+		if !ok {
+			code = uint32(len(cat.Categories))
+			lookup[v] = code
+			cat.Categories = append(cat.Categories, v)
+		}
+		cat.Codes[i] = code
+	}
+	return NewSeries(name, cat)
+}
+
+// Value returns the string stored at row i
+func (c *Categorical) Value(i int) string {
+	return c.Categories[c.Codes[i]]
+}
+
+// Decode expands the codes back into plain strings
+func (c *Categorical) Decode() []string {
+	out := make([]string, len(c.Codes))
+	for i, code := range c.Codes {
+		out[i] = c.Categories[code]
+	}
+	return out
+}
+
+func (c *Categorical) take(idx []int) *Categorical {
+	return &Categorical{Codes: gather(c.Codes, idx), Categories: c.Categories}
+}
+
+func (c *Categorical) slice(start, end int) *Categorical {
+	return &Categorical{Codes: c.Codes[start:end], Categories: c.Categories}
+}
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Collation orders strings by the linguistic rules of a language, using
+// the Unicode Collation Algorithm with the tailorings of CLDR, where the
+// default byte-wise order sorts "Zebra" before "apple" and "é" after "z".
+// It is safe for concurrent use.
+type Collation struct {
+	tag  language.Tag
+	pool sync.Pool // *collate.Collator, which keeps scratch buffers
+}
+
+// NewCollation returns the collation of locale, a BCP 47 language tag such
+// as "en", "de" or "sv-SE"; "und" gives the language-neutral root order.
+func NewCollation(locale string) (*Collation, error) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collation locale %q: %w", locale, err)
+	}
+	c := &Collation{tag: tag}
+	c.pool.New = func() any { return collate.New(tag) }
+	return c, nil
+}
+
+func (c *Collation) String() string { return c.tag.String() }
+
+// Compare returns -1, 0 or 1 as a sorts before, with or after b
+func (c *Collation) Compare(a, b string) int {
+	col := c.pool.Get().(*collate.Collator)
+	defer c.pool.Put(col)
+	return col.CompareString(a, b)
+}
+
+// Keys returns a sort key for every string of data, such that comparing
+// two keys with bytes.Compare orders their strings as Compare does. Sorting
+// many strings by their keys is much faster than calling Compare.
+func (c *Collation) Keys(data []string) [][]byte {
+	col := c.pool.Get().(*collate.Collator)
+	defer c.pool.Put(col)
+	var buf collate.Buffer
+	keys := make([][]byte, len(data))
+	for i, v := range data {
+		keys[i] = col.KeyFromString(&buf, v)
+	}
+	return keys
+}
+
+// Min returns the smallest valid element of a String or Categorical Series
+// in the order of collation, byte-wise when it is nil, and false when
+// there is none.
+func (n StringNamespace) Min(collation *Collation) (string, bool, error) {
+	return n.extreme("min", collation, -1)
+}
+
+// Max returns the largest valid element, see Min
+func (n StringNamespace) Max(collation *Collation) (string, bool, error) {
+	return n.extreme("max", collation, 1)
+}
+
+// extreme returns the valid element v for which comparing v with every
+// other one never gives -want.
+func (n StringNamespace) extreme(op string, collation *Collation, want int) (string, bool, error) {
+	data, err := n.values(op)
+	if err != nil {
+		return "", false, err
+	}
+	compare := strings.Compare
+	if collation != nil {
+		col := collation.pool.Get().(*collate.Collator)
+		defer collation.pool.Put(col)
+		compare = col.CompareString
+	}
+	best, found := "", false
+	for i, v := range data {
+		if !n.s.IsValid(i) {
+			continue
+		}
+		if !found || compare(v, best) == want {
+			best, found = v, true
+		}
+	}
+	return best, found, nil
+}
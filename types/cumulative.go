@@ -0,0 +1,64 @@
+package types
+
+import "fmt"
+
+// CumSum returns the running sum of the Series. Nulls stay null and are
+// skipped by the running total. Narrow integers are widened to Int64 and
+// Float32 to Float64.
+func (s *Series) CumSum() (*Series, error) {
+	return s.cumulative("cumsum", func(a, b int64) int64 { return a + b }, func(a, b float64) float64 { return a + b })
+}
+
+// CumProd returns the running product of the Series, with the null handling
+// and widening of CumSum.
+func (s *Series) CumProd() (*Series, error) {
+	return s.cumulative("cumprod", func(a, b int64) int64 { return a * b }, func(a, b float64) float64 { return a * b })
+}
+
+// CumMin returns the running minimum of the Series, with the null handling
+// and widening of CumSum.
+func (s *Series) CumMin() (*Series, error) {
+	return s.cumulative("cummin", func(a, b int64) int64 { return min(a, b) }, func(a, b float64) float64 { return min(a, b) })
+}
+
+// CumMax returns the running maximum of the Series, with the null handling
+// and widening of CumSum.
+func (s *Series) CumMax() (*Series, error) {
+	return s.cumulative("cummax", func(a, b int64) int64 { return max(a, b) }, func(a, b float64) float64 { return max(a, b) })
+}
+
+func (s *Series) cumulative(op string, ints func(a, b int64) int64, floats func(a, b float64) float64) (*Series, error) {
+	w, err := s.Widen()
+	if err != nil {
+		return nil, err
+	}
+	var out *Series
+	switch data := w.Data.(type) {
+	case []int64:
+		out = NewSeries(s.Name, cumulate(w, data, ints))
+	case []float64:
+		out = NewSeries(s.Name, cumulate(w, data, floats))
+	default:
+		return nil, fmt.Errorf("unsupported data type for %s: %s", op, s.DataType)
+	}
+	out.Validity = w.Validity
+	return out, nil
+}
+
+func cumulate[T int64 | float64](s *Series, data []T, op func(acc, v T) T) []T {
+	out := make([]T, len(data))
+	var acc T
+	started := false
+	for i, v := range data {
+		if !s.IsValid(i) {
+			continue
+		}
+		if started {
+			acc = op(acc, v)
+		} else {
+			acc, started = v, true
+		}
+		out[i] = acc
+	}
+	return out
+}
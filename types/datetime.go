@@ -0,0 +1,147 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// DatetimeNamespace holds the temporal operations of a Series, returned by
+// Series.Dt. Timestamps are Int64 nanoseconds since the Unix epoch, as
+// FromValues makes of time.Time values cast to Int64; Str().ParseTime
+// turns text into them. Calendar fields are read in the namespace's time
+// zone, UTC unless set with In. Every operation keeps the nulls of the
+// Series.
+type DatetimeNamespace struct {
+	s   *Series
+	loc *time.Location
+}
+
+// Dt returns the temporal operations of s
+func (s *Series) Dt() DatetimeNamespace {
+	return DatetimeNamespace{s: s, loc: time.UTC}
+}
+
+// In returns the namespace reading timestamps in the time zone loc, e.g.
+// from time.LoadLocation, which converts them for the calendar fields,
+// Truncate and Format without changing the instants they denote.
+func (n DatetimeNamespace) In(loc *time.Location) DatetimeNamespace {
+	n.loc = loc
+	return n
+}
+
+// Year returns the year of every timestamp as an Int32 Series
+func (n DatetimeNamespace) Year() (*Series, error) {
+	return dtField[int32](n, "year", func(t time.Time) int { return t.Year() })
+}
+
+// Month returns the month, 1 to 12, as an Int8 Series
+func (n DatetimeNamespace) Month() (*Series, error) {
+	return dtField[int8](n, "month", func(t time.Time) int { return int(t.Month()) })
+}
+
+// Day returns the day of the month, 1 to 31, as an Int8 Series
+func (n DatetimeNamespace) Day() (*Series, error) {
+	return dtField[int8](n, "day", func(t time.Time) int { return t.Day() })
+}
+
+// Hour returns the hour, 0 to 23, as an Int8 Series
+func (n DatetimeNamespace) Hour() (*Series, error) {
+	return dtField[int8](n, "hour", func(t time.Time) int { return t.Hour() })
+}
+
+// Minute returns the minute, 0 to 59, as an Int8 Series
+func (n DatetimeNamespace) Minute() (*Series, error) {
+	return dtField[int8](n, "minute", func(t time.Time) int { return t.Minute() })
+}
+
+// Second returns the second, 0 to 59, as an Int8 Series
+func (n DatetimeNamespace) Second() (*Series, error) {
+	return dtField[int8](n, "second", func(t time.Time) int { return t.Second() })
+}
+
+// Weekday returns the ISO day of the week, from 1 for Monday to 7 for
+// Sunday, as an Int8 Series.
+func (n DatetimeNamespace) Weekday() (*Series, error) {
+	return dtField[int8](n, "weekday", func(t time.Time) int {
+		if wd := t.Weekday(); wd != time.Sunday {
+			return int(wd)
+		}
+		return 7
+	})
+}
+
+// Truncate rounds every timestamp down to the start of its window of
+// length every, giving an Int64 Series of timestamps. Windows are aligned
+// on the Unix epoch in the wall-clock time of the namespace's time zone,
+// weeks start on Monday, and months and days follow the calendar, so
+// Truncate(Months(1)) gives the first instant of each month, ready to
+// group by.
+func (n DatetimeNamespace) Truncate(every Duration) (*Series, error) {
+	if err := every.validate(); err != nil {
+		return nil, fmt.Errorf("cannot apply dt.truncate: %w", err)
+	}
+	return dtMap(n, "truncate", func(t time.Time) int64 { return every.truncate(t).UnixNano() })
+}
+
+// ReplaceZone reinterprets the wall-clock time of every timestamp, as read
+// in the namespace's time zone, as a wall-clock time in loc, giving the
+// Int64 timestamps of those instants. It fixes timestamps recorded in local
+// time but stored as UTC: s.Dt().ReplaceZone(paris) keeps 09:00 as 09:00
+// Paris time. Wall-clock times skipped or repeated by a daylight saving
+// change in loc are resolved as time.Date does.
+func (n DatetimeNamespace) ReplaceZone(loc *time.Location) (*Series, error) {
+	return dtMap(n, "replace_zone", func(t time.Time) int64 {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc).UnixNano()
+	})
+}
+
+// Format formats every timestamp in the namespace's time zone with the Go
+// layout string, see time.Time.Format, into a String Series.
+func (n DatetimeNamespace) Format(layout string) (*Series, error) {
+	return dtMap(n, "format", func(t time.Time) string { return t.Format(layout) })
+}
+
+// ParseTime parses every element with the Go layout string, see
+// time.ParseInLocation, into Int64 Unix nanoseconds for Dt. Text without
+// a zone in the layout is read in loc, or UTC when loc is nil. An element
+// that does not parse is an error when strict is set and null otherwise.
+func (n StringNamespace) ParseTime(layout string, loc *time.Location, strict bool) (*Series, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return parseEach(n, "parse_time", strict, func(v string) (int64, error) {
+		t, err := time.ParseInLocation(layout, v, loc)
+		return t.UnixNano(), err
+	})
+}
+
+func (n DatetimeNamespace) nanos(op string) ([]int64, error) {
+	data, ok := n.s.Data.([]int64)
+	if !ok {
+		return nil, fmt.Errorf("cannot apply dt.%s to %s, timestamps are Int64 nanoseconds", op, n.s.DataType)
+	}
+	return data, nil
+}
+
+func dtField[T int8 | int32](n DatetimeNamespace, op string, fn func(time.Time) int) (*Series, error) {
+	return dtMap(n, op, func(t time.Time) T { return T(fn(t)) })
+}
+
+// dtMap applies fn to every valid timestamp in the namespace's time zone
+func dtMap[T any](n DatetimeNamespace, op string, fn func(time.Time) T) (*Series, error) {
+	data, err := n.nanos(op)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, len(data))
+	parallelChunks(len(data), func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			if n.s.IsValid(i) {
+				out[i] = fn(time.Unix(0, data[i]).In(n.loc))
+			}
+		}
+	})
+	res := NewSeries(n.s.Name, out)
+	res.Validity = n.s.Validity
+	return res, nil
+}
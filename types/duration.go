@@ -0,0 +1,170 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a length of time for truncating and bucketing timestamps.
+// Months and Days are calendar units, taken in the time zone of the
+// timestamps, so a day can last 23 or 25 hours across a daylight saving
+// change; Nanos is a fixed length. A Duration uses a single kind of unit.
+type Duration struct {
+	Months int
+	Days   int
+	Nanos  int64
+}
+
+// Months returns a Duration of n calendar months
+func Months(n int) Duration { return Duration{Months: n} }
+
+// Days returns a Duration of n calendar days
+func Days(n int) Duration { return Duration{Days: n} }
+
+// Fixed returns a Duration of the fixed length d
+func Fixed(d time.Duration) Duration { return Duration{Nanos: int64(d)} }
+
+// durationUnits maps the unit suffixes of ParseDuration to their length
+var durationUnits = map[string]struct {
+	months int
+	days   int
+	nanos  int64
+}{
+	"ns": {nanos: 1},
+	"us": {nanos: int64(time.Microsecond)},
+	"ms": {nanos: int64(time.Millisecond)},
+	"s":  {nanos: int64(time.Second)},
+	"m":  {nanos: int64(time.Minute)},
+	"h":  {nanos: int64(time.Hour)},
+	"d":  {days: 1},
+	"w":  {days: 7},
+	"mo": {months: 1},
+	"y":  {months: 12},
+}
+
+// ParseDuration parses the Polars duration syntax, a sequence of integers
+// each followed by a unit: ns, us, ms, s, m (minutes), h, d, w, mo or y,
+// e.g. "15m", "1h30m", "1d" or "3mo". Calendar units, d and up, cannot be
+// combined with fixed ones, nor days and weeks with months and years.
+func ParseDuration(s string) (Duration, error) {
+	var d Duration
+	rest := s
+	for rest != "" {
+		digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+		unitLen := len(rest[digits:]) - len(strings.TrimLeft(rest[digits:], "abcdefghijklmnopqrstuvwxyz"))
+		if digits == 0 || unitLen == 0 {
+			return Duration{}, fmt.Errorf("invalid duration %q", s)
+		}
+		n, err := strconv.Atoi(rest[:digits])
+		if err != nil {
+			return Duration{}, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		unit, ok := durationUnits[rest[digits:digits+unitLen]]
+		if !ok {
+			return Duration{}, fmt.Errorf("invalid duration %q: unknown unit %q", s, rest[digits:digits+unitLen])
+		}
+		d.Months += n * unit.months
+		d.Days += n * unit.days
+		d.Nanos += int64(n) * unit.nanos
+		rest = rest[digits+unitLen:]
+	}
+	if err := d.validate(); err != nil {
+		return Duration{}, fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	return d, nil
+}
+
+func (d Duration) validate() error {
+	kinds := 0
+	for _, set := range []bool{d.Months != 0, d.Days != 0, d.Nanos != 0} {
+		if set {
+			kinds++
+		}
+	}
+	switch {
+	case d.Months < 0 || d.Days < 0 || d.Nanos < 0:
+		return fmt.Errorf("duration must be positive")
+	case kinds == 0:
+		return fmt.Errorf("duration must not be zero")
+	case kinds > 1:
+		return fmt.Errorf("duration mixes months, days and fixed units")
+	}
+	return nil
+}
+
+func (d Duration) String() string {
+	switch {
+	case d.Months != 0:
+		return strconv.Itoa(d.Months) + "mo"
+	case d.Days != 0:
+		return strconv.Itoa(d.Days) + "d"
+	default:
+		return strconv.FormatInt(d.Nanos, 10) + "ns"
+	}
+}
+
+// truncate returns the start of the window of length d containing t, in
+// the time zone of t. Windows are aligned on the Unix epoch in wall-clock
+// time, weeks starting on Monday.
+func (d Duration) truncate(t time.Time) time.Time {
+	loc := t.Location()
+	y, m, day := t.Date()
+	switch {
+	case d.Months != 0:
+		months := (y-1970)*12 + int(m) - 1
+		months -= floorMod(months, d.Months)
+		return time.Date(1970+floorDiv(months, 12), time.Month(floorMod(months, 12)+1), 1, 0, 0, 0, 0, loc)
+	case d.Days != 0:
+		days := int(time.Date(y, m, day, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+		if d.Days%7 == 0 {
+			// The epoch was a Thursday; count from the Monday before.
+			days -= floorMod(days+3, d.Days)
+		} else {
+			days -= floorMod(days, d.Days)
+		}
+		y, m, day = time.Unix(int64(days)*86400, 0).UTC().Date()
+		return time.Date(y, m, day, 0, 0, 0, 0, loc)
+	default:
+		_, offset := t.Zone()
+		wall := t.UnixNano() + int64(offset)*int64(time.Second)
+		wall -= floorMod(wall, d.Nanos)
+		w := time.Unix(0, wall).UTC()
+		return time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), w.Nanosecond(), loc)
+	}
+}
+
+// add returns t moved n times d later. Timestamps are in UTC, where a day
+// always lasts 24 hours.
+func (d Duration) add(t time.Time, n int) time.Time {
+	switch {
+	case d.Months != 0:
+		return t.AddDate(0, n*d.Months, 0)
+	case d.Days != 0:
+		return t.AddDate(0, 0, n*d.Days)
+	default:
+		return t.Add(time.Duration(int64(n) * d.Nanos))
+	}
+}
+
+// fixed returns the length of d in nanoseconds in UTC, and false when it
+// counts months, whose length varies.
+func (d Duration) fixed() (int64, bool) {
+	if d.Months != 0 {
+		return 0, false
+	}
+	return int64(d.Days)*int64(24*time.Hour) + d.Nanos, true
+}
+
+func floorMod[T int | int64](a, b T) T {
+	r := a % b
+	if r < 0 {
+		r += b
+	}
+	return r
+}
+
+func floorDiv[T int | int64](a, b T) T {
+	return (a - floorMod(a, b)) / b
+}
@@ -0,0 +1,98 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DynamicGroups buckets the rows of times, Int64 nanoseconds since the
+// Unix epoch, into time windows read in UTC. Window k starts at the first
+// timestamp truncated to every, moved later by offset, plus k times every,
+// and lasts period, so a period equal to every gives tumbling windows and
+// a longer one sliding, overlapping windows. A zero period means every and
+// a zero offset none. The first window starts early enough to hold the
+// first timestamp; windows without rows are left out. Each group holds the
+// rows of one window in order of time, ties in row order, and Keys holds
+// the window starts as an Int64 Series named after times. Null timestamps
+// belong to no window.
+func DynamicGroups(times *Series, every, period, offset Duration) (*Groups, error) {
+	data, ok := times.Data.([]int64)
+	if !ok {
+		return nil, fmt.Errorf("dynamic groups need Int64 timestamps, %s is %s", times.Name, times.DataType)
+	}
+	if err := every.validate(); err != nil {
+		return nil, fmt.Errorf("invalid window every %s: %w", every, err)
+	}
+	if period == (Duration{}) {
+		period = every
+	} else if err := period.validate(); err != nil {
+		return nil, fmt.Errorf("invalid window period %s: %w", period, err)
+	}
+	if offset != (Duration{}) {
+		if err := offset.validate(); err != nil {
+			return nil, fmt.Errorf("invalid window offset %s: %w", offset, err)
+		}
+	}
+
+	rows := make([]int, 0, times.Length)
+	for i := range data {
+		if times.IsValid(i) {
+			rows = append(rows, i)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return data[rows[i]] < data[rows[j]] })
+
+	g := &Groups{Offsets: []int{0}}
+	starts := []int64{}
+	if len(rows) > 0 {
+		first, last := data[rows[0]], data[rows[len(rows)-1]]
+		origin := every.truncate(time.Unix(0, first).UTC())
+		if offset != (Duration{}) {
+			origin = offset.add(origin, 1)
+		}
+		k := 0
+		for every.add(origin, k).UnixNano() > first {
+			k--
+		}
+		everyLen, fixedEvery := every.fixed()
+		periodLen, fixedPeriod := period.fixed()
+		lo, hi := 0, 0
+		for {
+			start := every.add(origin, k)
+			if start.UnixNano() > last {
+				break
+			}
+			end := period.add(start, 1).UnixNano()
+			for lo < len(rows) && data[rows[lo]] < start.UnixNano() {
+				lo++
+			}
+			if hi < lo {
+				hi = lo
+			}
+			for hi < len(rows) && data[rows[hi]] < end {
+				hi++
+			}
+			if hi > lo {
+				g.Perm = append(g.Perm, rows[lo:hi]...)
+				g.Offsets = append(g.Offsets, len(g.Perm))
+				starts = append(starts, start.UnixNano())
+				k++
+				continue
+			}
+			// Skip the empty windows before the next row when windows have
+			// a fixed length; the first window that can hold it follows
+			// the last one ending at or before it.
+			next := k + 1
+			if fixedEvery && fixedPeriod && lo < len(rows) {
+				gap := data[rows[lo]] - origin.UnixNano() - periodLen
+				if skip := int(floorDiv(gap, everyLen)) + 1; skip > next {
+					next = skip
+				}
+			}
+			k = next
+		}
+	}
+	g.Keys = []*Series{NewSeries(times.Name, starts)}
+	return g, nil
+}
@@ -0,0 +1,138 @@
+package types
+
+import "fmt"
+
+// InterpolateMethod selects how Interpolate fills the nulls between two
+// values, named as in Polars.
+type InterpolateMethod int
+
+const (
+	InterpolateLinear  InterpolateMethod = iota // on the line through the two values
+	InterpolateNearest                          // the closer of the two values, the later one at mid-distance
+)
+
+func (m InterpolateMethod) String() string {
+	switch m {
+	case InterpolateLinear:
+		return "linear"
+	case InterpolateNearest:
+		return "nearest"
+	default:
+		return fmt.Sprintf("InterpolateMethod(%d)", int(m))
+	}
+}
+
+// FillNullForward replaces every null by the last valid value before it,
+// filling at most limit consecutive nulls, or all of them when limit is 0.
+// Nulls before the first valid value stay null. Any type can be filled.
+func (s *Series) FillNullForward(limit int) (*Series, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("fill limit %d must not be negative", limit)
+	}
+	prev, _ := s.validBounds()
+	src := make([]int, s.Length)
+	for i, p := range prev {
+		src[i] = p
+		if p >= 0 && limit > 0 && i-p > limit {
+			src[i] = -1
+		}
+	}
+	return s.Take(src), nil
+}
+
+// FillNullBackward replaces every null by the first valid value after it,
+// see FillNullForward
+func (s *Series) FillNullBackward(limit int) (*Series, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("fill limit %d must not be negative", limit)
+	}
+	_, next := s.validBounds()
+	src := make([]int, s.Length)
+	for i, n := range next {
+		src[i] = n
+		if n >= 0 && limit > 0 && n-i > limit {
+			src[i] = -1
+		}
+	}
+	return s.Take(src), nil
+}
+
+// Interpolate fills every run of nulls between two valid values of a
+// numeric Series by method; nulls before the first or after the last
+// valid value stay null. Linear interpolation gives Float32 for a Float32
+// Series and Float64 otherwise, while nearest keeps the type.
+func (s *Series) Interpolate(method InterpolateMethod) (*Series, error) {
+	if !IsNumeric(s.DataType) {
+		return nil, fmt.Errorf("cannot interpolate %s", s.DataType)
+	}
+	prev, next := s.validBounds()
+	switch method {
+	case InterpolateNearest:
+		src := make([]int, s.Length)
+		for i := range src {
+			switch p, n := prev[i], next[i]; {
+			case p < 0 || n < 0:
+				src[i] = -1
+			case i-p < n-i:
+				src[i] = p
+			default:
+				src[i] = n
+			}
+		}
+		return s.Take(src), nil
+	case InterpolateLinear:
+		in := s
+		if _, ok := s.Data.([]float32); !ok {
+			var err error
+			if in, err = s.Cast(Float64Type{}, false); err != nil {
+				return nil, err
+			}
+		}
+		switch data := in.Data.(type) {
+		case []float32:
+			return interpolateLinear(s.Name, data, prev, next), nil
+		case []float64:
+			return interpolateLinear(s.Name, data, prev, next), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown interpolation method %s", method)
+}
+
+func interpolateLinear[T float32 | float64](name string, data []T, prev, next []int) *Series {
+	out := make([]T, len(data))
+	valid := make([]bool, len(data))
+	for i := range out {
+		p, n := prev[i], next[i]
+		switch {
+		case p == i:
+			out[i], valid[i] = data[i], true
+		case p >= 0 && n >= 0:
+			frac := T(i-p) / T(n-p)
+			out[i], valid[i] = data[p]+(data[n]-data[p])*frac, true
+		}
+	}
+	return NewNullableSeries(name, out, valid)
+}
+
+// validBounds returns, for every position, the closest position at or
+// before it and at or after it holding a valid value, -1 when there is
+// none; both are the position itself when it is valid.
+func (s *Series) validBounds() (prev, next []int) {
+	prev = make([]int, s.Length)
+	next = make([]int, s.Length)
+	last := -1
+	for i := range prev {
+		if s.IsValid(i) {
+			last = i
+		}
+		prev[i] = last
+	}
+	last = -1
+	for i := s.Length - 1; i >= 0; i-- {
+		if s.IsValid(i) {
+			last = i
+		}
+		next[i] = last
+	}
+	return prev, next
+}
@@ -0,0 +1,93 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Groups is the result of one grouping pass, kept so that several
+// aggregations, partitions or transforms over the same keys need not hash
+// the key columns again. The rows of every group are stored back to back
+// in Perm, each group's rows in their original order. Groups of GroupBy
+// partition the rows; the time windows of DynamicGroups may overlap, so a
+// row can then belong to several groups or to none.
+type Groups struct {
+	// Keys holds the group columns, with one element per group.
+	Keys []*Series
+	// Perm lists row positions of the grouped frame, group after group.
+	Perm []int
+	// Offsets has one entry more than there are groups: group i holds
+	// Perm[Offsets[i]:Offsets[i+1]].
+	Offsets []int
+}
+
+// NewGroups builds Groups from a dense group ID per row, with n groups,
+// by a counting sort of the rows on their ID. Keys is left to the caller.
+func NewGroups(ids []int, n int) (*Groups, error) {
+	offsets := make([]int, n+1)
+	for row, id := range ids {
+		if id < 0 || id >= n {
+			return nil, fmt.Errorf("group id %d of row %d out of range for %d groups", id, row, n)
+		}
+		offsets[id+1]++
+	}
+	for g := 0; g < n; g++ {
+		offsets[g+1] += offsets[g]
+	}
+	perm := make([]int, len(ids))
+	next := append([]int(nil), offsets[:n]...)
+	for row, id := range ids {
+		perm[next[id]] = row
+		next[id]++
+	}
+	return &Groups{Perm: perm, Offsets: offsets}, nil
+}
+
+// Len returns the number of groups
+func (g *Groups) Len() int {
+	if len(g.Offsets) == 0 {
+		return 0
+	}
+	return len(g.Offsets) - 1
+}
+
+// Rows returns the row positions of group i, a view into Perm that must not
+// be modified.
+func (g *Groups) Rows(i int) []int {
+	return g.Perm[g.Offsets[i]:g.Offsets[i+1]:g.Offsets[i+1]]
+}
+
+// Groups returns the grouping of a DataFrame returned by GroupBy, in the
+// order of its group rows, with the group columns of the frame as keys.
+// Perm and Offsets are shared with the frame and must not be modified.
+func (df *DataFrame) Groups() (*Groups, error) {
+	if df == nil || df.GroupIndices == nil {
+		return nil, fmt.Errorf("DataFrame is not grouped")
+	}
+	g := &Groups{Keys: make([]*Series, len(df.GroupColumns))}
+	for i, col := range df.GroupColumns {
+		s, ok := df.Series[col]
+		if !ok {
+			return nil, fmt.Errorf("group column %s not found", col)
+		}
+		g.Keys[i] = s
+	}
+	if df.groups != nil {
+		g.Perm, g.Offsets = df.groups.Perm, df.groups.Offsets
+		return g, nil
+	}
+
+	// Frames grouped by hand carry only GroupIndices; fall back to the
+	// order of its keys.
+	keys := make([]string, 0, len(df.GroupIndices))
+	for k := range df.GroupIndices {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	g.Offsets = make([]int, len(keys)+1)
+	for i, k := range keys {
+		g.Perm = append(g.Perm, df.GroupIndices[k]...)
+		g.Offsets[i+1] = len(g.Perm)
+	}
+	return g, nil
+}
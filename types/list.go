@@ -0,0 +1,136 @@
+package types
+
+import "fmt"
+
+// ListType describes a Series whose elements are variable-length lists of a
+// primitive type. The backing slice is [][]int64, [][]float64, [][]string or
+// [][]bool.
+type ListType struct {
+	Inner DataType
+}
+
+func (t ListType) String() string { return "List(" + t.Inner.String() + ")" }
+
+// newListSeries wraps list data, returning nil when data is not a list slice.
+func newListSeries(name string, data interface{}) *Series {
+	var inner DataType
+	var n int
+	switch d := data.(type) {
+	case [][]int64:
+		inner, n = Int64Type{}, len(d)
+	case [][]float64:
+		inner, n = Float64Type{}, len(d)
+	case [][]string:
+		inner, n = StringType{}, len(d)
+	case [][]bool:
+		inner, n = BooleanType{}, len(d)
+	default:
+		return nil
+	}
+	return &Series{
+		Name:     name,
+		DataType: ListType{Inner: inner},
+		Data:     data,
+		Length:   n,
+	}
+}
+
+// IsList reports whether the Series holds list elements
+func (s *Series) IsList() bool {
+	_, ok := s.DataType.(ListType)
+	return ok
+}
+
+// ListLengths returns the number of elements of every list as an Int64
+// Series. Null lists have a null length.
+func (s *Series) ListLengths() (*Series, error) {
+	var lens []int64
+	switch data := s.Data.(type) {
+	case [][]int64:
+		lens = listLengths(data)
+	case [][]float64:
+		lens = listLengths(data)
+	case [][]string:
+		lens = listLengths(data)
+	case [][]bool:
+		lens = listLengths(data)
+	default:
+		return nil, fmt.Errorf("column %s is not a list column", s.Name)
+	}
+	out := NewSeries(s.Name, lens)
+	out.Validity = s.Validity
+	return out, nil
+}
+
+// ListSum returns the sum of every numeric list. Empty lists sum to 0 and
+// null lists stay null.
+func (s *Series) ListSum() (*Series, error) {
+	var out *Series
+	switch data := s.Data.(type) {
+	case [][]int64:
+		out = NewSeries(s.Name, listSums(data))
+	case [][]float64:
+		out = NewSeries(s.Name, listSums(data))
+	default:
+		return nil, fmt.Errorf("column %s is not a numeric list column", s.Name)
+	}
+	out.Validity = s.Validity
+	return out, nil
+}
+
+// Explode flattens a list Series into one element per row. A null or empty
+// list yields a single null element.
+func (s *Series) Explode() (*Series, error) {
+	switch data := s.Data.(type) {
+	case [][]int64:
+		values, valid := explodeLists(s, data)
+		return NewNullableSeries(s.Name, values, valid), nil
+	case [][]float64:
+		values, valid := explodeLists(s, data)
+		return NewNullableSeries(s.Name, values, valid), nil
+	case [][]string:
+		values, valid := explodeLists(s, data)
+		return NewNullableSeries(s.Name, values, valid), nil
+	case [][]bool:
+		values, valid := explodeLists(s, data)
+		return NewNullableSeries(s.Name, values, valid), nil
+	default:
+		return nil, fmt.Errorf("column %s is not a list column", s.Name)
+	}
+}
+
+func listLengths[T any](data [][]T) []int64 {
+	lens := make([]int64, len(data))
+	for i, l := range data {
+		lens[i] = int64(len(l))
+	}
+	return lens
+}
+
+func listSums[T int64 | float64](data [][]T) []T {
+	sums := make([]T, len(data))
+	for i, l := range data {
+		for _, v := range l {
+			sums[i] += v
+		}
+	}
+	return sums
+}
+
+func explodeLists[T any](s *Series, data [][]T) ([]T, []bool) {
+	values := make([]T, 0, len(data))
+	valid := make([]bool, 0, len(data))
+	for i, l := range data {
+		if len(l) == 0 || !s.IsValid(i) {
+			var zero T
+			values = append(values, zero)
+			valid = append(valid, false)
+			continue
+		}
+		values = append(values, l...)
+		for range l {
+			valid = append(valid, true)
+		}
+	}
+	return values, valid
+}
@@ -0,0 +1,66 @@
+package types
+
+import "fmt"
+
+// And combines two Boolean Series of the same length element-wise. Nulls
+// follow three-valued logic: false AND null is false, true AND null is
+// null.
+func (s *Series) And(other *Series) (*Series, error) {
+	return s.logical("and", other, false)
+}
+
+// Or combines two Boolean Series of the same length element-wise. Nulls
+// follow three-valued logic: true OR null is true, false OR null is null.
+func (s *Series) Or(other *Series) (*Series, error) {
+	return s.logical("or", other, true)
+}
+
+// Not negates a Boolean Series, keeping its nulls
+func (s *Series) Not() (*Series, error) {
+	data, ok := s.Data.([]bool)
+	if !ok {
+		return nil, fmt.Errorf("cannot negate %s", s.DataType)
+	}
+	out := make([]bool, len(data))
+	for i, v := range data {
+		out[i] = !v
+	}
+	res := NewSeries(s.Name, out)
+	res.Validity = s.Validity
+	return res, nil
+}
+
+// logical implements And and Or. The dominant value decides the result even
+// when the other side is null.
+func (s *Series) logical(op string, other *Series, dominant bool) (*Series, error) {
+	l, lok := s.Data.([]bool)
+	r, rok := other.Data.([]bool)
+	if !lok || !rok {
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", op, s.DataType, other.DataType)
+	}
+	if len(l) != len(r) {
+		return nil, fmt.Errorf("cannot apply %s to lengths %d and %d", op, len(l), len(r))
+	}
+	out := make([]bool, len(l))
+	if s.Validity == nil && other.Validity == nil {
+		for i := range out {
+			if dominant {
+				out[i] = l[i] || r[i]
+			} else {
+				out[i] = l[i] && r[i]
+			}
+		}
+		return NewSeries(s.Name, out), nil
+	}
+	valid := make([]bool, len(l))
+	for i := range out {
+		lv, rv := s.IsValid(i), other.IsValid(i)
+		switch {
+		case (lv && l[i] == dominant) || (rv && r[i] == dominant):
+			out[i], valid[i] = dominant, true
+		case lv && rv:
+			out[i], valid[i] = !dominant, true
+		}
+	}
+	return NewNullableSeries(s.Name, out, valid), nil
+}
@@ -0,0 +1,266 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// mathChunk is the number of elements an elementwise function handles per
+// goroutine; shorter columns are processed on the calling goroutine.
+const mathChunk = 1 << 16
+
+// Number is the set of element types of numeric Series.
+type Number interface {
+	Integer | ~float32 | ~float64
+}
+
+// Abs returns the absolute value of every element of a numeric Series,
+// keeping its type and nulls. The most negative value of a signed type
+// wraps around to itself.
+func (s *Series) Abs() (*Series, error) {
+	return s.mapNumeric("abs", elementwise{
+		ints: func(v int64) int64 {
+			if v < 0 {
+				return -v
+			}
+			return v
+		},
+		floats: math.Abs,
+	})
+}
+
+// Log returns the natural logarithm of every element of a numeric Series.
+// Like the other functions producing fractions, it gives Float32 for a
+// Float32 Series and Float64 otherwise, keeping nulls; the logarithm of
+// zero is -Inf and that of a negative value NaN.
+func (s *Series) Log() (*Series, error) {
+	return s.mapFloat("log", math.Log)
+}
+
+// Log10 returns the base 10 logarithm of every element, see Log
+func (s *Series) Log10() (*Series, error) {
+	return s.mapFloat("log10", math.Log10)
+}
+
+// Exp returns e raised to every element, see Log
+func (s *Series) Exp() (*Series, error) {
+	return s.mapFloat("exp", math.Exp)
+}
+
+// Sqrt returns the square root of every element, NaN for negative values,
+// see Log
+func (s *Series) Sqrt() (*Series, error) {
+	return s.mapFloat("sqrt", math.Sqrt)
+}
+
+// Pow raises every element to exponent, see Log
+func (s *Series) Pow(exponent float64) (*Series, error) {
+	return s.mapFloat("pow", func(v float64) float64 { return math.Pow(v, exponent) })
+}
+
+// Round rounds every element of a numeric Series to decimals digits after
+// the decimal point, halves away from zero; a negative decimals rounds to
+// tens, hundreds and so on. The type is kept, and integer Series are
+// returned unchanged.
+func (s *Series) Round(decimals int) (*Series, error) {
+	scale := math.Pow(10, float64(decimals))
+	return s.mapNumeric("round", elementwise{
+		floats: func(v float64) float64 {
+			if r := math.Round(v*scale) / scale; !math.IsInf(r, 0) && !math.IsNaN(r) {
+				return r
+			}
+			return v
+		},
+	})
+}
+
+// Floor rounds every element down to an integral value, see Round
+func (s *Series) Floor() (*Series, error) {
+	return s.mapNumeric("floor", elementwise{floats: math.Floor})
+}
+
+// Ceil rounds every element up to an integral value, see Round
+func (s *Series) Ceil() (*Series, error) {
+	return s.mapNumeric("ceil", elementwise{floats: math.Ceil})
+}
+
+// Clip limits every element of a numeric Series to [lower, upper], keeping
+// its type and nulls; pass math.Inf(-1) or math.Inf(1) to leave a side
+// open. An integer Series is clipped to the integers within the bounds and
+// the range of its type. NaN elements stay NaN.
+func (s *Series) Clip(lower, upper float64) (*Series, error) {
+	if math.IsNaN(lower) || math.IsNaN(upper) || lower > upper {
+		return nil, fmt.Errorf("invalid clip bounds [%v, %v]", lower, upper)
+	}
+	fn := elementwise{
+		floats: func(v float64) float64 {
+			switch {
+			case v < lower:
+				return lower
+			case v > upper:
+				return upper
+			default:
+				return v
+			}
+		},
+	}
+	if info, ok := integerInfo(s.DataType); ok && info.signed {
+		min := -int64(1) << (info.bits - 1)
+		max := int64(1)<<(info.bits-1) - 1
+		if info.bits == 64 {
+			min, max = math.MinInt64, math.MaxInt64
+		}
+		lo, hi := intBound(math.Ceil(lower), min, max), intBound(math.Floor(upper), min, max)
+		fn.ints = func(v int64) int64 { return clamp(v, lo, hi) }
+	} else if ok {
+		max := uint64(1)<<info.bits - 1
+		if info.bits == 64 {
+			max = math.MaxUint64
+		}
+		lo, hi := uintBound(math.Ceil(lower), max), uintBound(math.Floor(upper), max)
+		fn.uints = func(v uint64) uint64 { return clamp(v, lo, hi) }
+	}
+	return s.mapNumeric("clip", fn)
+}
+
+func clamp[T Number](v, lo, hi T) T {
+	switch {
+	case v < lo:
+		return lo
+	case v > hi:
+		return hi
+	default:
+		return v
+	}
+}
+
+// intBound converts an integral float bound to int64, limited to [min, max]
+func intBound(x float64, min, max int64) int64 {
+	switch {
+	case x <= float64(min):
+		return min
+	case x >= float64(max):
+		return max
+	default:
+		return int64(x)
+	}
+}
+
+// uintBound converts an integral float bound to uint64, limited to [0, max]
+func uintBound(x float64, max uint64) uint64 {
+	switch {
+	case x <= 0:
+		return 0
+	case x >= float64(max):
+		return max
+	default:
+		return uint64(x)
+	}
+}
+
+// elementwise holds a function for each family of numeric types: signed
+// integers are computed as int64, unsigned ones as uint64 and floats as
+// float64, then converted back to the Series type. A nil function leaves
+// Series of that family unchanged.
+type elementwise struct {
+	ints   func(int64) int64
+	uints  func(uint64) uint64
+	floats func(float64) float64
+}
+
+func (s *Series) mapNumeric(op string, fn elementwise) (*Series, error) {
+	var out interface{}
+	switch d := s.Data.(type) {
+	case []int8:
+		out = mapConvert(d, fn.ints)
+	case []int16:
+		out = mapConvert(d, fn.ints)
+	case []int32:
+		out = mapConvert(d, fn.ints)
+	case []int64:
+		out = mapConvert(d, fn.ints)
+	case []uint8:
+		out = mapConvert(d, fn.uints)
+	case []uint16:
+		out = mapConvert(d, fn.uints)
+	case []uint32:
+		out = mapConvert(d, fn.uints)
+	case []uint64:
+		out = mapConvert(d, fn.uints)
+	case []float32:
+		out = mapConvert(d, fn.floats)
+	case []float64:
+		out = mapConvert(d, fn.floats)
+	default:
+		return nil, fmt.Errorf("cannot apply %s to %s", op, s.DataType)
+	}
+	if out == nil {
+		return s, nil
+	}
+	res := NewSeries(s.Name, out)
+	res.Validity = s.Validity
+	return res, nil
+}
+
+// mapFloat applies fn to a numeric Series as Float64, or as Float32 when
+// the Series is Float32.
+func (s *Series) mapFloat(op string, fn func(float64) float64) (*Series, error) {
+	if !IsNumeric(s.DataType) {
+		return nil, fmt.Errorf("cannot apply %s to %s", op, s.DataType)
+	}
+	in := s
+	if _, ok := s.Data.([]float32); !ok {
+		var err error
+		if in, err = s.Cast(Float64Type{}, false); err != nil {
+			return nil, err
+		}
+	}
+	return in.mapNumeric(op, elementwise{floats: fn})
+}
+
+// mapConvert applies fn to every element of data computed as W, or returns
+// nil when fn is nil. Long slices are split into chunks processed
+// concurrently.
+func mapConvert[T, W Number](data []T, fn func(W) W) interface{} {
+	if fn == nil {
+		return nil
+	}
+	out := make([]T, len(data))
+	parallelChunks(len(data), func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			out[i] = T(fn(W(data[i])))
+		}
+	})
+	return out
+}
+
+// parallelChunks calls fn on consecutive ranges of mathChunk of the n
+// elements with up to MaxThreads goroutines, returning once all are done.
+func parallelChunks(n int, fn func(lo, hi int)) {
+	workers := MaxThreads()
+	chunks := (n + mathChunk - 1) / mathChunk
+	if chunks < 2 || workers < 2 {
+		fn(0, n)
+		return
+	}
+	if workers > chunks {
+		workers = chunks
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range next {
+				fn(c*mathChunk, min((c+1)*mathChunk, n))
+			}
+		}()
+	}
+	for c := 0; c < chunks; c++ {
+		next <- c
+	}
+	close(next)
+	wg.Wait()
+}
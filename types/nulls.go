@@ -0,0 +1,117 @@
+package types
+
+import "fmt"
+
+// NewNullableSeries creates a Series whose element i is null when valid[i]
+// is false. The data value stored at a null position is ignored.
+func NewNullableSeries(name string, data interface{}, valid []bool) *Series {
+	s := NewSeries(name, data)
+	if len(valid) != s.Length {
+		panic(fmt.Sprintf("validity has length %d, expected %d", len(valid), s.Length))
+	}
+	for _, v := range valid {
+		if !v {
+			s.Validity = BitmapFromBools(valid)
+			break
+		}
+	}
+	return s
+}
+
+// IsValid reports whether element i holds a value
+func (s *Series) IsValid(i int) bool {
+	return s.Validity == nil || s.Validity.Get(i)
+}
+
+// NullCount returns the number of null elements
+func (s *Series) NullCount() int {
+	if s.Validity == nil {
+		return 0
+	}
+	return s.Validity.NullCount()
+}
+
+// HasNulls reports whether any element is null
+func (s *Series) HasNulls() bool {
+	return s.NullCount() > 0
+}
+
+// IsNull returns a Boolean Series that is true where the element is null
+func (s *Series) IsNull() *Series {
+	mask := make([]bool, s.Length)
+	for i := range mask {
+		mask[i] = !s.IsValid(i)
+	}
+	return NewSeries(s.Name, mask)
+}
+
+// IsNotNull returns a Boolean Series that is true where the element is valid
+func (s *Series) IsNotNull() *Series {
+	mask := make([]bool, s.Length)
+	for i := range mask {
+		mask[i] = s.IsValid(i)
+	}
+	return NewSeries(s.Name, mask)
+}
+
+// FillNull returns a copy of the Series with every null replaced by value,
+// which must have the Series' element type.
+func (s *Series) FillNull(value interface{}) (*Series, error) {
+	switch data := s.Data.(type) {
+	case []int64:
+		out, err := fillNulls(s, data, value)
+		if err != nil {
+			return nil, err
+		}
+		return NewSeries(s.Name, out), nil
+	case []float64:
+		out, err := fillNulls(s, data, value)
+		if err != nil {
+			return nil, err
+		}
+		return NewSeries(s.Name, out), nil
+	case []string:
+		out, err := fillNulls(s, data, value)
+		if err != nil {
+			return nil, err
+		}
+		return NewSeries(s.Name, out), nil
+	case []bool:
+		out, err := fillNulls(s, data, value)
+		if err != nil {
+			return nil, err
+		}
+		return NewSeries(s.Name, out), nil
+	default:
+		return nil, fmt.Errorf("unsupported data type for column %s", s.Name)
+	}
+}
+
+func fillNulls[T any](s *Series, data []T, value interface{}) ([]T, error) {
+	fill, ok := value.(T)
+	if !ok {
+		return nil, fmt.Errorf("fill value %v (%T) does not match column %s of type %s", value, value, s.Name, s.DataType)
+	}
+	out := make([]T, len(data))
+	copy(out, data)
+	for i := range out {
+		if !s.IsValid(i) {
+			out[i] = fill
+		}
+	}
+	return out, nil
+}
+
+// DropNulls returns a new Series containing only the valid elements
+func (s *Series) DropNulls() *Series {
+	if s.Validity == nil {
+		return s
+	}
+	idx := make([]int, 0, s.Length-s.NullCount())
+	for i := 0; i < s.Length; i++ {
+		if s.Validity.Get(i) {
+			idx = append(idx, i)
+		}
+	}
+	return s.Take(idx)
+}
@@ -0,0 +1,213 @@
+package types
+
+import (
+	"fmt"
+	"math"
+)
+
+// Narrow and unsigned integer data types
+type (
+	Int8Type   struct{}
+	Int16Type  struct{}
+	Int32Type  struct{}
+	UInt8Type  struct{}
+	UInt16Type struct{}
+	UInt32Type struct{}
+	UInt64Type struct{}
+)
+
+func (Int8Type) String() string   { return "Int8" }
+func (Int16Type) String() string  { return "Int16" }
+func (Int32Type) String() string  { return "Int32" }
+func (UInt8Type) String() string  { return "UInt8" }
+func (UInt16Type) String() string { return "UInt16" }
+func (UInt32Type) String() string { return "UInt32" }
+func (UInt64Type) String() string { return "UInt64" }
+
+// Integer is the set of integer element types a Series can hold.
+type Integer interface {
+	~int8 | ~int16 | ~int32 | ~int64 | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// newIntegerSeries wraps narrow or unsigned integer data, returning nil for
+// any other backing slice.
+func newIntegerSeries(name string, data interface{}) *Series {
+	var dt DataType
+	var n int
+	switch d := data.(type) {
+	case []int8:
+		dt, n = Int8Type{}, len(d)
+	case []int16:
+		dt, n = Int16Type{}, len(d)
+	case []int32:
+		dt, n = Int32Type{}, len(d)
+	case []uint8:
+		dt, n = UInt8Type{}, len(d)
+	case []uint16:
+		dt, n = UInt16Type{}, len(d)
+	case []uint32:
+		dt, n = UInt32Type{}, len(d)
+	case []uint64:
+		dt, n = UInt64Type{}, len(d)
+	default:
+		return nil
+	}
+	return &Series{Name: name, DataType: dt, Data: data, Length: n}
+}
+
+// integer type ranks: width in bits and signedness.
+type intInfo struct {
+	bits   int
+	signed bool
+}
+
+func integerInfo(dt DataType) (intInfo, bool) {
+	switch dt.(type) {
+	case Int8Type:
+		return intInfo{8, true}, true
+	case Int16Type:
+		return intInfo{16, true}, true
+	case Int32Type:
+		return intInfo{32, true}, true
+	case Int64Type:
+		return intInfo{64, true}, true
+	case UInt8Type:
+		return intInfo{8, false}, true
+	case UInt16Type:
+		return intInfo{16, false}, true
+	case UInt32Type:
+		return intInfo{32, false}, true
+	case UInt64Type:
+		return intInfo{64, false}, true
+	}
+	return intInfo{}, false
+}
+
+func integerType(info intInfo) DataType {
+	switch {
+	case info.signed && info.bits == 8:
+		return Int8Type{}
+	case info.signed && info.bits == 16:
+		return Int16Type{}
+	case info.signed && info.bits == 32:
+		return Int32Type{}
+	case info.signed:
+		return Int64Type{}
+	case info.bits == 8:
+		return UInt8Type{}
+	case info.bits == 16:
+		return UInt16Type{}
+	case info.bits == 32:
+		return UInt32Type{}
+	default:
+		return UInt64Type{}
+	}
+}
+
+// IsInteger reports whether dt is one of the integer types
+func IsInteger(dt DataType) bool {
+	_, ok := integerInfo(dt)
+	return ok
+}
+
+// IsNumeric reports whether dt is an integer or floating point type
+func IsNumeric(dt DataType) bool {
+	switch dt.(type) {
+	case Float64Type, Float32Type:
+		return true
+	}
+	return IsInteger(dt)
+}
+
+// Supertype returns the smallest type both a and b can be losslessly
+// represented in, following the usual promotion rules: integers of the same
+// signedness widen to the larger width, mixing signed and unsigned widens to
+// a signed type twice the unsigned width, and anything combined with a float
+// (or UInt64 with a signed type) becomes Float64.
+func Supertype(a, b DataType) (DataType, error) {
+	if a.String() == b.String() {
+		return a, nil
+	}
+	ai, aInt := integerInfo(a)
+	bi, bInt := integerInfo(b)
+	switch {
+	case aInt && bInt:
+		if ai.signed == bi.signed {
+			if bi.bits > ai.bits {
+				return b, nil
+			}
+			return a, nil
+		}
+		signed, unsigned := ai, bi
+		if !ai.signed {
+			signed, unsigned = bi, ai
+		}
+		if unsigned.bits == 64 {
+			return Float64Type{}, nil
+		}
+		bits := unsigned.bits * 2
+		if signed.bits > bits {
+			bits = signed.bits
+		}
+		return integerType(intInfo{bits, true}), nil
+	case IsNumeric(a) && IsNumeric(b):
+		return Float64Type{}, nil
+	default:
+		return nil, fmt.Errorf("no common supertype for %s and %s", a, b)
+	}
+}
+
+// Widen upcasts an integer Series narrower than 64 bits, or a UInt64 Series,
+// to Int64, and a Float32 Series to Float64, so it can be sorted, hashed and
+// aggregated without overflowing the element type. Other Series are returned
+// unchanged. UInt64 values above math.MaxInt64 cannot be represented and
+// produce an error.
+func (s *Series) Widen() (*Series, error) {
+	var out []int64
+	switch d := s.Data.(type) {
+	case []float32:
+		w := NewSeries(s.Name, widenFloats(d))
+		w.Validity = s.Validity
+		return w, nil
+	case []int8:
+		out = widenInts(d)
+	case []int16:
+		out = widenInts(d)
+	case []int32:
+		out = widenInts(d)
+	case []uint8:
+		out = widenInts(d)
+	case []uint16:
+		out = widenInts(d)
+	case []uint32:
+		out = widenInts(d)
+	case []uint64:
+		for i, v := range d {
+			if v > math.MaxInt64 && s.IsValid(i) {
+				return nil, fmt.Errorf("column %s: value %d at row %d overflows Int64", s.Name, v, i)
+			}
+		}
+		out = widenInts(d)
+	default:
+		return s, nil
+	}
+	w := NewSeries(s.Name, out)
+	w.Validity = s.Validity
+	return w, nil
+}
+
+func widenFloats(data []float32) []float64 {
+	out := make([]float64, len(data))
+	for i, v := range data {
+		out[i] = float64(v)
+	}
+	return out
+}
+
+func widenInts[T Integer](data []T) []int64 {
+	out := make([]int64, len(data))
+	for i, v := range data {
+		out[i] = int64(v)
+	}
+	return out
+}
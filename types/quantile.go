@@ -0,0 +1,128 @@
+package types
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+)
+
+// QuantileInterpolation selects the value a quantile takes when it falls
+// between two values, named as in Polars. The quantile q of n sorted values
+// sits at position q*(n-1).
+type QuantileInterpolation int
+
+const (
+	QuantileLinear   QuantileInterpolation = iota // interpolate linearly between the two values
+	QuantileNearest                               // the value at the nearest position, rounding halves up
+	QuantileLower                                 // the value below
+	QuantileHigher                                // the value above
+	QuantileMidpoint                              // the mean of the two values
+)
+
+func (m QuantileInterpolation) String() string {
+	switch m {
+	case QuantileLinear:
+		return "linear"
+	case QuantileNearest:
+		return "nearest"
+	case QuantileLower:
+		return "lower"
+	case QuantileHigher:
+		return "higher"
+	case QuantileMidpoint:
+		return "midpoint"
+	default:
+		return fmt.Sprintf("QuantileInterpolation(%d)", int(m))
+	}
+}
+
+// Valid reports whether m is one of the defined interpolation methods
+func (m QuantileInterpolation) Valid() bool {
+	return m >= QuantileLinear && m <= QuantileMidpoint
+}
+
+// QuantileOf computes the q-th quantile, 0 <= q <= 1, of values, which it
+// reorders. The values around the quantile are found by selection rather
+// than by sorting, so the cost grows linearly with the number of values.
+// NaN orders below every number. It reports false when values is empty.
+func QuantileOf[T int64 | float64](values []T, q float64, method QuantileInterpolation) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+	pos := q * float64(len(values)-1)
+	lo, hi := int(math.Floor(pos)), int(math.Ceil(pos))
+	switch method {
+	case QuantileNearest:
+		lo = int(math.Round(pos))
+		hi = lo
+	case QuantileLower:
+		hi = lo
+	case QuantileHigher:
+		lo = hi
+	}
+	selectKth(values, lo)
+	a := float64(values[lo])
+	if hi == lo {
+		return a, true
+	}
+	// Selection leaves the larger values after lo; the next is their minimum.
+	next := values[lo+1]
+	for _, v := range values[lo+2:] {
+		if cmp.Less(v, next) {
+			next = v
+		}
+	}
+	b := float64(next)
+	if method == QuantileMidpoint {
+		return (a + b) / 2, true
+	}
+	return a + (b-a)*(pos-float64(lo)), true
+}
+
+// selectKth reorders v so that v[k] holds the value it would hold if v were
+// sorted, with no greater value before it and no smaller one after it.
+// Ranges of a few values are finished by insertion sort.
+func selectKth[T int64 | float64](v []T, k int) {
+	lo, hi := 0, len(v)-1
+	for hi-lo > 16 {
+		// Median of three guards against sorted input.
+		mid := lo + (hi-lo)/2
+		if cmp.Less(v[mid], v[lo]) {
+			v[mid], v[lo] = v[lo], v[mid]
+		}
+		if cmp.Less(v[hi], v[lo]) {
+			v[hi], v[lo] = v[lo], v[hi]
+		}
+		if cmp.Less(v[hi], v[mid]) {
+			v[hi], v[mid] = v[mid], v[hi]
+		}
+		pivot := v[mid]
+		i, j := lo, hi
+		for i <= j {
+			for cmp.Less(v[i], pivot) {
+				i++
+			}
+			for cmp.Less(pivot, v[j]) {
+				j--
+			}
+			if i <= j {
+				v[i], v[j] = v[j], v[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case k <= j:
+			hi = j
+		case k >= i:
+			lo = i
+		default:
+			return // v[j+1:i] all equal the pivot
+		}
+	}
+	for i := lo + 1; i <= hi; i++ {
+		for j := i; j > lo && cmp.Less(v[j], v[j-1]); j-- {
+			v[j], v[j-1] = v[j-1], v[j]
+		}
+	}
+}
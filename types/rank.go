@@ -0,0 +1,136 @@
+package types
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// RankMethod selects how Rank numbers tied values
+type RankMethod int
+
+const (
+	RankAverage RankMethod = iota // mean of the positions the ties span
+	RankMin                       // lowest position the ties span
+	RankMax                       // highest position the ties span
+	RankDense                     // like RankMin, without gaps after ties
+	RankOrdinal                   // distinct positions in order of appearance
+)
+
+func (m RankMethod) String() string {
+	switch m {
+	case RankAverage:
+		return "average"
+	case RankMin:
+		return "min"
+	case RankMax:
+		return "max"
+	case RankDense:
+		return "dense"
+	case RankOrdinal:
+		return "ordinal"
+	default:
+		return fmt.Sprintf("RankMethod(%d)", int(m))
+	}
+}
+
+// Rank returns the 1-based rank of every value, smallest first or, when
+// descending, largest first. RankAverage gives Float64 ranks, the other
+// methods Int64. Nulls are not ranked and stay null; NaN ranks above every
+// number.
+func (s *Series) Rank(method RankMethod, descending bool) (*Series, error) {
+	if method < RankAverage || method > RankOrdinal {
+		return nil, fmt.Errorf("unknown rank method %d", int(method))
+	}
+	w, err := s.Widen()
+	if err != nil {
+		return nil, err
+	}
+	switch data := w.Data.(type) {
+	case []int64:
+		return rank(w, data, cmp.Compare[int64], method, descending), nil
+	case []float64:
+		return rank(w, data, compareFloats, method, descending), nil
+	case []string:
+		return rank(w, data, strings.Compare, method, descending), nil
+	case []bool:
+		return rank(w, data, compareBools, method, descending), nil
+	case *Categorical:
+		return rank(w, data.Decode(), strings.Compare, method, descending), nil
+	default:
+		return nil, fmt.Errorf("unsupported data type for rank: %s", s.DataType)
+	}
+}
+
+// compareFloats orders NaN after every number.
+func compareFloats(a, b float64) int {
+	switch an, bn := math.IsNaN(a), math.IsNaN(b); {
+	case an && bn:
+		return 0
+	case an:
+		return 1
+	case bn:
+		return -1
+	}
+	return cmp.Compare(a, b)
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case b:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func rank[T any](s *Series, data []T, compare func(a, b T) int, method RankMethod, descending bool) *Series {
+	order := make([]int, 0, len(data))
+	for i := range data {
+		if s.IsValid(i) {
+			order = append(order, i)
+		}
+	}
+	if descending {
+		inner := compare
+		compare = func(a, b T) int { return inner(b, a) }
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return compare(data[a], data[b]) })
+
+	valid := make([]bool, len(data))
+	ints := make([]int64, len(data))
+	floats := make([]float64, len(data))
+	dense := int64(0)
+	for lo := 0; lo < len(order); {
+		hi := lo + 1
+		for hi < len(order) && compare(data[order[lo]], data[order[hi]]) == 0 {
+			hi++
+		}
+		dense++
+		// The tied values occupy positions lo+1 through hi.
+		for k, i := range order[lo:hi] {
+			valid[i] = true
+			switch method {
+			case RankAverage:
+				floats[i] = float64(lo+1+hi) / 2
+			case RankMin:
+				ints[i] = int64(lo + 1)
+			case RankMax:
+				ints[i] = int64(hi)
+			case RankDense:
+				ints[i] = dense
+			case RankOrdinal:
+				ints[i] = int64(lo + 1 + k)
+			}
+		}
+		lo = hi
+	}
+	if method == RankAverage {
+		return NewNullableSeries(s.Name, floats, valid)
+	}
+	return NewNullableSeries(s.Name, ints, valid)
+}
@@ -0,0 +1,28 @@
+package types
+
+import "fmt"
+
+// Reshape lays the Series out row-major into nCols columns named column_0,
+// column_1, ...: element i lands in row i/nCols of column i%nCols. The length
+// must be a multiple of nCols.
+func (s *Series) Reshape(nCols int) ([]*Series, error) {
+	if nCols <= 0 {
+		return nil, fmt.Errorf("number of columns must be positive, got %d", nCols)
+	}
+	if s.Length%nCols != 0 {
+		return nil, fmt.Errorf("cannot reshape %d values into %d columns", s.Length, nCols)
+	}
+
+	rows := s.Length / nCols
+	columns := make([]*Series, nCols)
+	idx := make([]int, rows)
+	for c := range columns {
+		for r := range idx {
+			idx[r] = r*nCols + c
+		}
+		col := s.Take(idx)
+		col.Name = fmt.Sprintf("column_%d", c)
+		columns[c] = col
+	}
+	return columns, nil
+}
@@ -0,0 +1,91 @@
+package types
+
+import "fmt"
+
+// RLE run-length encodes the Series. It returns the value of each run and a
+// parallel Int64 Series named "lengths" holding the run lengths. Nulls form
+// runs of their own, with a null value.
+func (s *Series) RLE() (*Series, *Series, error) {
+	starts, err := s.runStarts()
+	if err != nil {
+		return nil, nil, err
+	}
+	lengths := make([]int64, len(starts))
+	for i, start := range starts {
+		end := s.Length
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		lengths[i] = int64(end - start)
+	}
+	return s.Take(starts), NewSeries("lengths", lengths), nil
+}
+
+// RunID labels every element with the index of the run it belongs to. The
+// ID increments each time the value differs from the previous element, a
+// null differing from any valid value but not from another null.
+func (s *Series) RunID() (*Series, error) {
+	starts, err := s.runStarts()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, s.Length)
+	id := -1
+	for i := range ids {
+		if id+1 < len(starts) && starts[id+1] == i {
+			id++
+		}
+		ids[i] = int64(id)
+	}
+	return NewSeries(s.Name, ids), nil
+}
+
+// runStarts returns the position of the first element of every run
+func (s *Series) runStarts() ([]int, error) {
+	var same func(i, j int) bool
+	switch data := s.Data.(type) {
+	case []int64:
+		same = equalAt(data)
+	case []float64:
+		same = equalAt(data)
+	case []float32:
+		same = equalAt(data)
+	case []string:
+		same = equalAt(data)
+	case []bool:
+		same = equalAt(data)
+	case []int8:
+		same = equalAt(data)
+	case []int16:
+		same = equalAt(data)
+	case []int32:
+		same = equalAt(data)
+	case []uint8:
+		same = equalAt(data)
+	case []uint16:
+		same = equalAt(data)
+	case []uint32:
+		same = equalAt(data)
+	case []uint64:
+		same = equalAt(data)
+	case *Categorical:
+		same = equalAt(data.Codes)
+	default:
+		return nil, fmt.Errorf("unsupported data type for run lengths of column %s", s.Name)
+	}
+	starts := make([]int, 0)
+	for i := 0; i < s.Length; i++ {
+		if i > 0 {
+			pv, v := s.IsValid(i-1), s.IsValid(i)
+			if pv == v && (!v || same(i-1, i)) {
+				continue
+			}
+		}
+		starts = append(starts, i)
+	}
+	return starts, nil
+}
+
+func equalAt[T comparable](data []T) func(i, j int) bool {
+	return func(i, j int) bool { return data[i] == data[j] }
+}
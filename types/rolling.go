@@ -0,0 +1,259 @@
+package types
+
+import (
+	"fmt"
+	"math"
+)
+
+// Rolling computes statistics over a sliding window of a numeric Series. It
+// is created by Series.Rolling or Series.RollingBy. Every statistic runs in
+// O(n): sums and moments are updated as values enter and leave the window,
+// and minimum and maximum are tracked with monotonic deques.
+type Rolling struct {
+	s          *Series // widened to Int64 or Float64
+	starts     []int   // first element of the window ending at each element
+	minPeriods int
+}
+
+// Rolling returns a sliding window of window elements ending at every
+// element. Nulls are skipped but take up room in the window; a result is
+// null when the window holds fewer than minPeriods valid values, with
+// minPeriods <= 0 meaning the full window size.
+func (s *Series) Rolling(window, minPeriods int) (*Rolling, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("rolling window must be positive, got %d", window)
+	}
+	if minPeriods <= 0 {
+		minPeriods = window
+	}
+	starts := make([]int, s.Length)
+	for i := range starts {
+		starts[i] = max(0, i-window+1)
+	}
+	return newRolling(s, starts, minPeriods)
+}
+
+// RollingBy returns a window over the elements whose value in by lies within
+// period of the current element's, i.e. in (by[i]-period, by[i]]. by must be
+// sorted ascending and free of nulls. minPeriods <= 0 means 1.
+func (s *Series) RollingBy(by *Series, period int64, minPeriods int) (*Rolling, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("rolling period must be positive, got %d", period)
+	}
+	if by.Length != s.Length {
+		return nil, fmt.Errorf("rolling key %s has length %d, expected %d", by.Name, by.Length, s.Length)
+	}
+	if by.HasNulls() {
+		return nil, fmt.Errorf("rolling key %s contains nulls", by.Name)
+	}
+	by, err := by.Widen()
+	if err != nil {
+		return nil, err
+	}
+	if minPeriods <= 0 {
+		minPeriods = 1
+	}
+
+	var starts []int
+	switch keys := by.Data.(type) {
+	case []int64:
+		starts, err = periodStarts(keys, period)
+	case []float64:
+		starts, err = periodStarts(keys, float64(period))
+	default:
+		return nil, fmt.Errorf("unsupported data type for rolling key %s", by.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rolling key %s: %w", by.Name, err)
+	}
+	return newRolling(s, starts, minPeriods)
+}
+
+// periodStarts finds, for every key, the first key greater than key-period.
+func periodStarts[T int64 | float64](keys []T, period T) ([]int, error) {
+	starts := make([]int, len(keys))
+	lo := 0
+	for i, k := range keys {
+		if i > 0 && k < keys[i-1] {
+			return nil, fmt.Errorf("not sorted ascending at row %d", i)
+		}
+		for keys[lo] <= k-period {
+			lo++
+		}
+		starts[i] = lo
+	}
+	return starts, nil
+}
+
+func newRolling(s *Series, starts []int, minPeriods int) (*Rolling, error) {
+	w, err := s.Widen()
+	if err != nil {
+		return nil, err
+	}
+	switch w.Data.(type) {
+	case []int64, []float64:
+	default:
+		return nil, fmt.Errorf("unsupported data type for rolling: %s", s.DataType)
+	}
+	return &Rolling{s: w, starts: starts, minPeriods: minPeriods}, nil
+}
+
+// Sum returns the sum over each window, in the type of the Series
+func (r *Rolling) Sum() *Series {
+	switch data := r.s.Data.(type) {
+	case []int64:
+		return rollingSum(r, data)
+	default:
+		return rollingSum(r, data.([]float64))
+	}
+}
+
+// Mean returns the mean over each window as Float64
+func (r *Rolling) Mean() *Series {
+	switch data := r.s.Data.(type) {
+	case []int64:
+		return rollingMoments(r, data, false)
+	default:
+		return rollingMoments(r, data.([]float64), false)
+	}
+}
+
+// Std returns the sample standard deviation over each window as Float64,
+// null for windows with fewer than two valid values.
+func (r *Rolling) Std() *Series {
+	switch data := r.s.Data.(type) {
+	case []int64:
+		return rollingMoments(r, data, true)
+	default:
+		return rollingMoments(r, data.([]float64), true)
+	}
+}
+
+// Min returns the minimum over each window, in the type of the Series
+func (r *Rolling) Min() *Series {
+	switch data := r.s.Data.(type) {
+	case []int64:
+		return rollingExtreme(r, data, func(a, b int64) bool { return a <= b })
+	default:
+		return rollingExtreme(r, data.([]float64), func(a, b float64) bool { return a <= b })
+	}
+}
+
+// Max returns the maximum over each window, in the type of the Series
+func (r *Rolling) Max() *Series {
+	switch data := r.s.Data.(type) {
+	case []int64:
+		return rollingExtreme(r, data, func(a, b int64) bool { return a >= b })
+	default:
+		return rollingExtreme(r, data.([]float64), func(a, b float64) bool { return a >= b })
+	}
+}
+
+// slide walks the windows in order, calling add for every valid element
+// entering a window and drop for every valid element leaving it, then emit
+// with the number of valid elements in the window ending at i.
+func (r *Rolling) slide(add, drop func(i int), emit func(i, count int)) {
+	lo, count := 0, 0
+	for i := 0; i < r.s.Length; i++ {
+		if r.s.IsValid(i) {
+			add(i)
+			count++
+		}
+		for ; lo < r.starts[i]; lo++ {
+			if r.s.IsValid(lo) {
+				drop(lo)
+				count--
+			}
+		}
+		emit(i, count)
+	}
+}
+
+func rollingSum[T int64 | float64](r *Rolling, data []T) *Series {
+	out := make([]T, len(data))
+	valid := make([]bool, len(data))
+	var sum T
+	r.slide(
+		func(i int) { sum += data[i] },
+		func(i int) { sum -= data[i] },
+		func(i, count int) { out[i], valid[i] = sum, count >= r.minPeriods },
+	)
+	return NewNullableSeries(r.s.Name, out, valid)
+}
+
+// rollingMoments computes the mean, or the standard deviation, with
+// Welford's update extended to removals. Removals leave rounding residue in
+// m2, so a window whose values are all equal is recognised by the length of
+// the trailing run of equal values and given a deviation of exactly zero.
+func rollingMoments[T int64 | float64](r *Rolling, data []T, std bool) *Series {
+	out := make([]float64, len(data))
+	valid := make([]bool, len(data))
+	var n, mean, m2 float64
+	var last T
+	run := 0
+	r.slide(
+		func(i int) {
+			if run > 0 && data[i] == last {
+				run++
+			} else {
+				last, run = data[i], 1
+			}
+			x := float64(data[i])
+			n++
+			delta := x - mean
+			mean += delta / n
+			m2 += delta * (x - mean)
+		},
+		func(i int) {
+			x := float64(data[i])
+			n--
+			if n == 0 {
+				mean, m2 = 0, 0
+				return
+			}
+			delta := x - mean
+			mean -= delta / n
+			m2 -= delta * (x - mean)
+		},
+		func(i, count int) {
+			valid[i] = count >= r.minPeriods
+			if !std {
+				out[i] = mean
+				return
+			}
+			valid[i] = valid[i] && count >= 2
+			if valid[i] && run < count {
+				out[i] = math.Sqrt(math.Max(m2, 0) / float64(count-1))
+			}
+		},
+	)
+	return NewNullableSeries(r.s.Name, out, valid)
+}
+
+// rollingExtreme keeps a deque of element indices whose values are ordered
+// by keep, so the front is always the extreme of the current window.
+func rollingExtreme[T int64 | float64](r *Rolling, data []T, keep func(a, b T) bool) *Series {
+	out := make([]T, len(data))
+	valid := make([]bool, len(data))
+	deque := make([]int, 0)
+	r.slide(
+		func(i int) {
+			for len(deque) > 0 && !keep(data[deque[len(deque)-1]], data[i]) {
+				deque = deque[:len(deque)-1]
+			}
+			deque = append(deque, i)
+		},
+		func(i int) {
+			if len(deque) > 0 && deque[0] == i {
+				deque = deque[1:]
+			}
+		},
+		func(i, count int) {
+			if len(deque) > 0 {
+				out[i] = data[deque[0]]
+			}
+			valid[i] = count >= r.minPeriods && len(deque) > 0
+		},
+	)
+	return NewNullableSeries(r.s.Name, out, valid)
+}
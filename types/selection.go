@@ -0,0 +1,165 @@
+package types
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// sparseRatio is the fraction of rows, one in sparseRatio, below which a
+// Selection keeps row positions instead of a mask: a position takes 64
+// bits where the mask takes one bit per row, selected or not.
+const sparseRatio = 64
+
+// Selection is the set of rows a filter keeps out of a column of a given
+// length. Highly selective filters hold the positions of the kept rows,
+// the others a packed mask, and Series.Filter gathers from either form.
+type Selection struct {
+	length int
+	count  int
+	rows   []int   // ascending positions of the kept rows, when sparse
+	mask   *Bitmap // bit i set when row i is kept, when dense
+}
+
+// NewSelection builds the Selection of the rows where mask, a Boolean
+// Series, is true. Rows where the mask is null are not selected.
+func NewSelection(mask *Series) (*Selection, error) {
+	data, ok := mask.Data.([]bool)
+	if !ok {
+		return nil, fmt.Errorf("selection mask is %s, not Boolean", mask.DataType)
+	}
+	sel := &Selection{length: len(data)}
+	for i, v := range data {
+		if v && mask.IsValid(i) {
+			sel.count++
+		}
+	}
+	if sel.count*sparseRatio < sel.length {
+		sel.rows = make([]int, 0, sel.count)
+		for i, v := range data {
+			if v && mask.IsValid(i) {
+				sel.rows = append(sel.rows, i)
+			}
+		}
+		return sel, nil
+	}
+	sel.mask = BitmapFromBools(data)
+	if mask.Validity != nil {
+		for i := range data {
+			if !mask.Validity.Get(i) {
+				sel.mask.Set(i, false)
+			}
+		}
+	}
+	return sel, nil
+}
+
+// SelectRows builds the sparse Selection of the given ascending positions
+// out of length rows.
+func SelectRows(rows []int, length int) *Selection {
+	return &Selection{length: length, count: len(rows), rows: rows}
+}
+
+// Len returns the number of rows the Selection selects from
+func (sel *Selection) Len() int { return sel.length }
+
+// Count returns the number of selected rows
+func (sel *Selection) Count() int { return sel.count }
+
+// IsSparse reports whether the Selection holds row positions
+func (sel *Selection) IsSparse() bool { return sel.mask == nil }
+
+// Rows returns the positions of the selected rows in ascending order
+func (sel *Selection) Rows() []int {
+	if sel.mask == nil {
+		return sel.rows
+	}
+	rows := make([]int, 0, sel.count)
+	sel.mask.eachSet(func(i int) { rows = append(rows, i) })
+	return rows
+}
+
+// Filter gathers the selected elements into a new Series. Sparse
+// selections are taken by position; dense ones are copied a mask word at
+// a time without materialising the positions.
+func (s *Series) Filter(sel *Selection) *Series {
+	if sel.length != s.Length {
+		panic(fmt.Sprintf("selection of %d rows applied to %d", sel.length, s.Length))
+	}
+	if sel.mask == nil {
+		return s.Take(sel.rows)
+	}
+	var out *Series
+	switch data := s.Data.(type) {
+	case []int64:
+		out = NewSeries(s.Name, compress(data, sel))
+	case []float64:
+		out = NewSeries(s.Name, compress(data, sel))
+	case []float32:
+		out = NewSeries(s.Name, compress(data, sel))
+	case []string:
+		out = NewSeries(s.Name, compress(data, sel))
+	case []bool:
+		out = NewSeries(s.Name, compress(data, sel))
+	case []int8:
+		out = NewSeries(s.Name, compress(data, sel))
+	case []int16:
+		out = NewSeries(s.Name, compress(data, sel))
+	case []int32:
+		out = NewSeries(s.Name, compress(data, sel))
+	case []uint8:
+		out = NewSeries(s.Name, compress(data, sel))
+	case []uint16:
+		out = NewSeries(s.Name, compress(data, sel))
+	case []uint32:
+		out = NewSeries(s.Name, compress(data, sel))
+	case []uint64:
+		out = NewSeries(s.Name, compress(data, sel))
+	case *Categorical:
+		out = NewSeries(s.Name, &Categorical{Codes: compress(data.Codes, sel), Categories: data.Categories})
+	case [][]int64:
+		out = NewSeries(s.Name, compress(data, sel))
+	case [][]float64:
+		out = NewSeries(s.Name, compress(data, sel))
+	case [][]string:
+		out = NewSeries(s.Name, compress(data, sel))
+	case [][]bool:
+		out = NewSeries(s.Name, compress(data, sel))
+	default:
+		panic("unsupported data type")
+	}
+	if s.Validity != nil {
+		valid := NewBitmap(sel.count, false)
+		j := 0
+		sel.mask.eachSet(func(i int) {
+			if s.Validity.Get(i) {
+				valid.Set(j, true)
+			}
+			j++
+		})
+		if valid.NullCount() > 0 {
+			out.Validity = valid
+		}
+	}
+	return out
+}
+
+// compress copies the elements of data whose bit is set in the mask of a
+// dense Selection, skipping whole words with nothing selected and copying
+// whole runs of selected rows at once.
+func compress[T any](data []T, sel *Selection) []T {
+	out := make([]T, 0, sel.count)
+	for w, word := range sel.mask.words {
+		base := w << 6
+		switch word {
+		case 0:
+		case ^uint64(0):
+			out = append(out, data[base:base+64]...)
+		default:
+			for word != 0 {
+				out = append(out, data[base+bits.TrailingZeros64(word)])
+				word &= word - 1
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package types
+
+import "fmt"
+
+// Shift moves the values of the Series n positions down, or up for a
+// negative n, keeping its length. Positions left without a value are null.
+func (s *Series) Shift(n int) *Series {
+	src := make([]int, s.Length)
+	for i := range src {
+		src[i] = lagIndex(i, n, s.Length)
+	}
+	return s.Take(src)
+}
+
+// Diff returns the difference between every value and the one n positions
+// before it (after it for a negative n). The first n values, and those where
+// either operand is null, are null. Narrow integers are widened to Int64 and
+// Float32 to Float64.
+func (s *Series) Diff(n int) (*Series, error) {
+	w, err := s.Widen()
+	if err != nil {
+		return nil, err
+	}
+	switch data := w.Data.(type) {
+	case []int64:
+		return lagged(w, data, n, func(cur, prev int64) int64 { return cur - prev }), nil
+	case []float64:
+		return lagged(w, data, n, func(cur, prev float64) float64 { return cur - prev }), nil
+	default:
+		return nil, fmt.Errorf("unsupported data type for diff: %s", s.DataType)
+	}
+}
+
+// PctChange returns the relative change from the previous value as Float64,
+// (cur - prev) / prev. The first value, and those next to a null, are null;
+// a change from zero follows float division and gives ±Inf or NaN.
+func (s *Series) PctChange() (*Series, error) {
+	w, err := s.Widen()
+	if err != nil {
+		return nil, err
+	}
+	pct := func(cur, prev float64) float64 { return (cur - prev) / prev }
+	switch data := w.Data.(type) {
+	case []int64:
+		floats := make([]float64, len(data))
+		for i, v := range data {
+			floats[i] = float64(v)
+		}
+		return lagged(w, floats, 1, pct), nil
+	case []float64:
+		return lagged(w, data, 1, pct), nil
+	default:
+		return nil, fmt.Errorf("unsupported data type for pct_change: %s", s.DataType)
+	}
+}
+
+// lagIndex returns the position n places before i, or -1 outside [0, length).
+func lagIndex(i, n, length int) int {
+	j := i - n
+	if j < 0 || j >= length {
+		return -1
+	}
+	return j
+}
+
+// lagged combines every element of data with the one n positions before it.
+// s supplies the validity of data.
+func lagged[T int64 | float64](s *Series, data []T, n int, fn func(cur, prev T) T) *Series {
+	out := make([]T, len(data))
+	valid := make([]bool, len(data))
+	for i := range data {
+		j := lagIndex(i, n, len(data))
+		if j < 0 || !s.IsValid(i) || !s.IsValid(j) {
+			continue
+		}
+		out[i], valid[i] = fn(data[i], data[j]), true
+	}
+	return NewNullableSeries(s.Name, out, valid)
+}
@@ -0,0 +1,115 @@
+package types
+
+import (
+	"cmp"
+	"sync/atomic"
+)
+
+// Stats summarises the values of a Series. Min and Max hold the element type
+// of the Series (string for Categorical) and are nil when there is no valid
+// value or the type is unordered, such as lists. Sortedness ignores trailing
+// nulls; nulls anywhere else make a Series unsorted.
+type Stats struct {
+	Min              interface{}
+	Max              interface{}
+	NullCount        int
+	NDV              int // number of distinct valid values
+	SortedAscending  bool
+	SortedDescending bool
+}
+
+// statsCache is embedded in Series to hold statistics computed on first use.
+type statsCache struct {
+	stats atomic.Pointer[Stats]
+}
+
+// Stats returns statistics for the Series, computing them on the first call
+// and caching the result. Series are treated as immutable: code that
+// modifies Data or Validity in place must build a new Series instead.
+func (s *Series) Stats() *Stats {
+	if st := s.cache.stats.Load(); st != nil {
+		return st
+	}
+	st := computeStats(s)
+	s.cache.stats.Store(st)
+	return st
+}
+
+// CachedStats returns the statistics if they have already been computed,
+// letting callers use them opportunistically without paying for a scan.
+func (s *Series) CachedStats() (*Stats, bool) {
+	st := s.cache.stats.Load()
+	return st, st != nil
+}
+
+func computeStats(s *Series) *Stats {
+	st := &Stats{NullCount: s.NullCount()}
+	switch d := s.Data.(type) {
+	case []int64:
+		orderedStats(s, d, st)
+	case []int32:
+		orderedStats(s, d, st)
+	case []int16:
+		orderedStats(s, d, st)
+	case []int8:
+		orderedStats(s, d, st)
+	case []uint64:
+		orderedStats(s, d, st)
+	case []uint32:
+		orderedStats(s, d, st)
+	case []uint16:
+		orderedStats(s, d, st)
+	case []uint8:
+		orderedStats(s, d, st)
+	case []float64:
+		orderedStats(s, d, st)
+	case []float32:
+		orderedStats(s, d, st)
+	case []string:
+		orderedStats(s, d, st)
+	case *Categorical:
+		orderedStats(s, d.Decode(), st)
+	case []bool:
+		ints := make([]uint8, len(d))
+		for i, b := range d {
+			if b {
+				ints[i] = 1
+			}
+		}
+		orderedStats(s, ints, st)
+		if st.Min != nil {
+			st.Min, st.Max = st.Min.(uint8) == 1, st.Max.(uint8) == 1
+		}
+	}
+	return st
+}
+
+func orderedStats[T cmp.Ordered](s *Series, data []T, st *Stats) {
+	distinct := make(map[T]struct{})
+	var lo, hi T
+	asc, desc := true, true
+	prev, seenNull := -1, false
+	for i, v := range data {
+		if !s.IsValid(i) {
+			seenNull = true
+			continue
+		}
+		if seenNull {
+			asc, desc = false, false
+		}
+		if prev < 0 {
+			lo, hi = v, v
+		} else {
+			lo, hi = min(lo, v), max(hi, v)
+			asc = asc && data[prev] <= v
+			desc = desc && data[prev] >= v
+		}
+		distinct[v] = struct{}{}
+		prev = i
+	}
+	st.NDV = len(distinct)
+	st.SortedAscending, st.SortedDescending = asc, desc
+	if prev >= 0 {
+		st.Min, st.Max = lo, hi
+	}
+}
@@ -0,0 +1,384 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// StringNamespace holds the string operations of a Series, returned by
+// Series.Str. Every operation keeps the nulls of the Series and fails
+// unless it is a String or Categorical Series; Categorical elements are
+// handled as their text.
+type StringNamespace struct {
+	s *Series
+}
+
+// Str returns the string operations of s
+func (s *Series) Str() StringNamespace {
+	return StringNamespace{s: s}
+}
+
+// Contains returns a Boolean Series, true where the element contains
+// pattern. pattern is a regular expression in the syntax of package regexp
+// unless literal is set.
+func (n StringNamespace) Contains(pattern string, literal bool) (*Series, error) {
+	if literal {
+		return n.mask("contains", func(v string) bool { return strings.Contains(v, pattern) })
+	}
+	re, err := compilePattern("contains", pattern)
+	if err != nil {
+		return nil, err
+	}
+	return n.mask("contains", re.MatchString)
+}
+
+// StartsWith returns a Boolean Series, true where the element begins with
+// prefix.
+func (n StringNamespace) StartsWith(prefix string) (*Series, error) {
+	return n.mask("starts_with", func(v string) bool { return strings.HasPrefix(v, prefix) })
+}
+
+// EndsWith returns a Boolean Series, true where the element ends with suffix
+func (n StringNamespace) EndsWith(suffix string) (*Series, error) {
+	return n.mask("ends_with", func(v string) bool { return strings.HasSuffix(v, suffix) })
+}
+
+// Replace replaces the first match of pattern in every element with value,
+// giving a String Series. pattern is a regular expression unless literal is
+// set, in which case value is inserted as is; otherwise value may refer to
+// submatches as $1 or ${name}, see regexp.Regexp.Expand.
+func (n StringNamespace) Replace(pattern, value string, literal bool) (*Series, error) {
+	if literal {
+		return n.transform("replace", func(v string) string { return strings.Replace(v, pattern, value, 1) })
+	}
+	re, err := compilePattern("replace", pattern)
+	if err != nil {
+		return nil, err
+	}
+	return n.transform("replace", func(v string) string {
+		loc := re.FindStringSubmatchIndex(v)
+		if loc == nil {
+			return v
+		}
+		dst := re.ExpandString([]byte(v[:loc[0]]), value, v, loc)
+		return string(dst) + v[loc[1]:]
+	})
+}
+
+// ReplaceAll replaces every match of pattern in every element with value,
+// see Replace
+func (n StringNamespace) ReplaceAll(pattern, value string, literal bool) (*Series, error) {
+	if literal {
+		return n.transform("replace_all", func(v string) string { return strings.ReplaceAll(v, pattern, value) })
+	}
+	re, err := compilePattern("replace_all", pattern)
+	if err != nil {
+		return nil, err
+	}
+	return n.transform("replace_all", func(v string) string { return re.ReplaceAllString(v, value) })
+}
+
+// Extract returns the text captured by group groupIndex of the first match
+// of the regular expression pattern in every element, 0 meaning the whole
+// match, as a String Series. An element is null when the pattern does not
+// match or the group takes no part in the match.
+func (n StringNamespace) Extract(pattern string, groupIndex int) (*Series, error) {
+	re, err := compileGroup("extract", pattern, groupIndex)
+	if err != nil {
+		return nil, err
+	}
+	data, err := n.values("extract")
+	if err != nil {
+		return nil, err
+	}
+	return extractGroup(n.s, data, re, groupIndex, n.s.Name), nil
+}
+
+// ExtractAll returns the text captured by group groupIndex of every
+// non-overlapping match of pattern in every element as a List(String)
+// Series, see Extract. An element without matches gives an empty list.
+func (n StringNamespace) ExtractAll(pattern string, groupIndex int) (*Series, error) {
+	re, err := compileGroup("extract_all", pattern, groupIndex)
+	if err != nil {
+		return nil, err
+	}
+	data, err := n.values("extract_all")
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]string, len(data))
+	parallelChunks(len(data), func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			if !n.s.IsValid(i) {
+				continue
+			}
+			out[i] = []string{}
+			for _, loc := range re.FindAllStringSubmatchIndex(data[i], -1) {
+				if start := loc[2*groupIndex]; start >= 0 {
+					out[i] = append(out[i], data[i][start:loc[2*groupIndex+1]])
+				}
+			}
+		}
+	})
+	res := NewSeries(n.s.Name, out)
+	res.Validity = n.s.Validity
+	return res, nil
+}
+
+// ExtractGroups returns one String Series per named capture group of
+// pattern, named after the group and in the order of the pattern, holding
+// what the group captured in the first match of every element as Extract
+// does. Unnamed groups are skipped; a pattern without named groups, or
+// naming two groups alike, is an error.
+func (n StringNamespace) ExtractGroups(pattern string) ([]*Series, error) {
+	re, err := compilePattern("extract_groups", pattern)
+	if err != nil {
+		return nil, err
+	}
+	data, err := n.values("extract_groups")
+	if err != nil {
+		return nil, err
+	}
+	var out []*Series
+	seen := make(map[string]bool)
+	for i, name := range re.SubexpNames() {
+		if name == "" {
+			continue
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("str.extract_groups: group %s is named twice", name)
+		}
+		seen[name] = true
+		out = append(out, extractGroup(n.s, data, re, i, name))
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("str.extract_groups: pattern %q has no named groups", pattern)
+	}
+	return out, nil
+}
+
+// extractGroup captures group of the first match of re in every element of
+// data, the text of s, into a String Series called name.
+func extractGroup(s *Series, data []string, re *regexp.Regexp, group int, name string) *Series {
+	out := make([]string, len(data))
+	valid := make([]bool, len(data))
+	parallelChunks(len(data), func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			if !s.IsValid(i) {
+				continue
+			}
+			loc := re.FindStringSubmatchIndex(data[i])
+			if loc == nil || loc[2*group] < 0 {
+				continue
+			}
+			out[i], valid[i] = data[i][loc[2*group]:loc[2*group+1]], true
+		}
+	})
+	return NewNullableSeries(name, out, valid)
+}
+
+// Lower converts every element to lower case
+func (n StringNamespace) Lower() (*Series, error) {
+	return n.transform("lower", strings.ToLower)
+}
+
+// Upper converts every element to upper case
+func (n StringNamespace) Upper() (*Series, error) {
+	return n.transform("upper", strings.ToUpper)
+}
+
+// Strip removes the leading and trailing characters found in chars from
+// every element, or white space when chars is empty.
+func (n StringNamespace) Strip(chars string) (*Series, error) {
+	if chars == "" {
+		return n.transform("strip", strings.TrimSpace)
+	}
+	return n.transform("strip", func(v string) string { return strings.Trim(v, chars) })
+}
+
+// StripChars removes every occurrence of the characters in chars, wherever
+// they are in the element; use Strip to trim only the ends. With
+// ParseFloat it cleans formatted numbers:
+//
+//	clean, err := s.Str().StripChars("$,% ")
+//	amounts, err := clean.Str().ParseFloat(false)
+func (n StringNamespace) StripChars(chars string) (*Series, error) {
+	return n.transform("strip_chars", func(v string) string {
+		if !strings.ContainsAny(v, chars) {
+			return v
+		}
+		return strings.Map(func(r rune) rune {
+			if strings.ContainsRune(chars, r) {
+				return -1
+			}
+			return r
+		}, v)
+	})
+}
+
+// ParseInt parses every element as a base 10 integer into an Int64 Series.
+// An element that does not parse is an error when strict is set and null
+// otherwise.
+func (n StringNamespace) ParseInt(strict bool) (*Series, error) {
+	return parseEach(n, "parse_int", strict, func(v string) (int64, error) {
+		return strconv.ParseInt(v, 10, 64)
+	})
+}
+
+// ParseFloat parses every element as a float into a Float64 Series, see
+// ParseInt
+func (n StringNamespace) ParseFloat(strict bool) (*Series, error) {
+	return parseEach(n, "parse_float", strict, func(v string) (float64, error) {
+		return strconv.ParseFloat(v, 64)
+	})
+}
+
+// ParseNumericLoose parses formatted numbers such as "$1,234.50", "12%",
+// "(42.00)" or "-€ 3 000" into a Float64 Series in one go. Surrounding
+// white space, currency symbols and thousands separators (",", "_", "'"
+// and spaces) are dropped, a trailing "%" divides by 100 and parentheses
+// mark a negative amount, as in accounting. "." is the decimal mark; read
+// decimal commas with the CSV Locale options or Replace them first.
+// Elements that still do not parse are null.
+func (n StringNamespace) ParseNumericLoose() (*Series, error) {
+	return parseEach(n, "parse_numeric_loose", false, parseLoose)
+}
+
+func parseLoose(v string) (float64, error) {
+	v = strings.TrimSpace(v)
+	negative := false
+	if len(v) >= 2 && v[0] == '(' && v[len(v)-1] == ')' {
+		negative, v = true, v[1:len(v)-1]
+	}
+	scale := 1.0
+	if trimmed, ok := strings.CutSuffix(strings.TrimSpace(v), "%"); ok {
+		scale, v = 0.01, trimmed
+	}
+	v = strings.Map(func(r rune) rune {
+		switch {
+		case r == ',' || r == '_' || r == '\'' || unicode.IsSpace(r), unicode.Is(unicode.Sc, r):
+			return -1
+		}
+		return r
+	}, v)
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		f = -f
+	}
+	return f * scale, nil
+}
+
+// Split splits every element around each occurrence of sep into a
+// List(String) Series, as strings.Split does; a null element gives a null
+// list.
+func (n StringNamespace) Split(sep string) (*Series, error) {
+	data, err := n.values("split")
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]string, len(data))
+	parallelChunks(len(data), func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			if n.s.IsValid(i) {
+				out[i] = strings.Split(data[i], sep)
+			}
+		}
+	})
+	res := NewSeries(n.s.Name, out)
+	res.Validity = n.s.Validity
+	return res, nil
+}
+
+// values returns the elements of the Series as text
+func (n StringNamespace) values(op string) ([]string, error) {
+	switch d := n.s.Data.(type) {
+	case []string:
+		return d, nil
+	case *Categorical:
+		return d.Decode(), nil
+	default:
+		return nil, fmt.Errorf("cannot apply str.%s to %s", op, n.s.DataType)
+	}
+}
+
+func (n StringNamespace) mask(op string, fn func(string) bool) (*Series, error) {
+	data, err := n.values(op)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]bool, len(data))
+	parallelChunks(len(data), func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			out[i] = fn(data[i])
+		}
+	})
+	res := NewSeries(n.s.Name, out)
+	res.Validity = n.s.Validity
+	return res, nil
+}
+
+func (n StringNamespace) transform(op string, fn func(string) string) (*Series, error) {
+	data, err := n.values(op)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, len(data))
+	parallelChunks(len(data), func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			out[i] = fn(data[i])
+		}
+	})
+	res := NewSeries(n.s.Name, out)
+	res.Validity = n.s.Validity
+	return res, nil
+}
+
+// parseEach parses every valid element with parse, see ParseInt
+func parseEach[T any](n StringNamespace, op string, strict bool, parse func(string) (T, error)) (*Series, error) {
+	data, err := n.values(op)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, len(data))
+	valid := make([]bool, len(data))
+	for i, v := range data {
+		if !n.s.IsValid(i) {
+			continue
+		}
+		x, err := parse(v)
+		if err != nil {
+			if strict {
+				return nil, fmt.Errorf("str.%s: row %d: %w", op, i, err)
+			}
+			continue
+		}
+		out[i], valid[i] = x, true
+	}
+	return NewNullableSeries(n.s.Name, out, valid), nil
+}
+
+func compilePattern(op, pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("str.%s: invalid pattern: %w", op, err)
+	}
+	return re, nil
+}
+
+// compileGroup compiles pattern and checks that it has group groupIndex
+func compileGroup(op, pattern string, groupIndex int) (*regexp.Regexp, error) {
+	re, err := compilePattern(op, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if groupIndex < 0 || groupIndex > re.NumSubexp() {
+		return nil, fmt.Errorf("str.%s: pattern %q has no group %d", op, pattern, groupIndex)
+	}
+	return re, nil
+}
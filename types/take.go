@@ -0,0 +1,161 @@
+package types
+
+import "fmt"
+
+// Take gathers the elements at the given positions into a new Series. A
+// negative position produces a null element.
+func (s *Series) Take(idx []int) *Series {
+	out, err := s.take(idx)
+	if err != nil {
+		panic("unsupported data type")
+	}
+	return out
+}
+
+// take is Take returning an error for a Series of an unknown type
+func (s *Series) take(idx []int) (*Series, error) {
+	var out *Series
+	switch data := s.Data.(type) {
+	case []int64:
+		out = NewSeries(s.Name, gather(data, idx))
+	case []float64:
+		out = NewSeries(s.Name, gather(data, idx))
+	case []float32:
+		out = NewSeries(s.Name, gather(data, idx))
+	case []string:
+		out = NewSeries(s.Name, gather(data, idx))
+	case []bool:
+		out = NewSeries(s.Name, gather(data, idx))
+	case []int8:
+		out = NewSeries(s.Name, gather(data, idx))
+	case []int16:
+		out = NewSeries(s.Name, gather(data, idx))
+	case []int32:
+		out = NewSeries(s.Name, gather(data, idx))
+	case []uint8:
+		out = NewSeries(s.Name, gather(data, idx))
+	case []uint16:
+		out = NewSeries(s.Name, gather(data, idx))
+	case []uint32:
+		out = NewSeries(s.Name, gather(data, idx))
+	case []uint64:
+		out = NewSeries(s.Name, gather(data, idx))
+	case *Categorical:
+		out = NewSeries(s.Name, data.take(idx))
+	case [][]int64:
+		out = NewSeries(s.Name, gather(data, idx))
+	case [][]float64:
+		out = NewSeries(s.Name, gather(data, idx))
+	case [][]string:
+		out = NewSeries(s.Name, gather(data, idx))
+	case [][]bool:
+		out = NewSeries(s.Name, gather(data, idx))
+	default:
+		return nil, fmt.Errorf("unsupported data type for column %s", s.Name)
+	}
+
+	missing := false
+	for _, j := range idx {
+		if j < 0 {
+			missing = true
+			break
+		}
+	}
+	if s.Validity == nil && !missing {
+		return out, nil
+	}
+
+	valid := NewBitmap(len(idx), true)
+	for i, j := range idx {
+		if j < 0 || (s.Validity != nil && !s.Validity.Get(j)) {
+			valid.Set(i, false)
+		}
+	}
+	if valid.NullCount() > 0 {
+		out.Validity = valid
+	}
+	return out, nil
+}
+
+// Slice returns the elements [offset, offset+length) as a new Series sharing
+// the underlying data.
+func (s *Series) Slice(offset, length int) *Series {
+	out, err := s.slice(offset, length)
+	if err != nil {
+		panic("unsupported data type")
+	}
+	return out
+}
+
+// slice is Slice returning an error for a Series of an unknown type
+func (s *Series) slice(offset, length int) (*Series, error) {
+	var out *Series
+	switch data := s.Data.(type) {
+	case []int64:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case []float64:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case []float32:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case []string:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case []bool:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case []int8:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case []int16:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case []int32:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case []uint8:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case []uint16:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case []uint32:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case []uint64:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case *Categorical:
+		out = NewSeries(s.Name, data.slice(offset, offset+length))
+	case [][]int64:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case [][]float64:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case [][]string:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	case [][]bool:
+		out = NewSeries(s.Name, data[offset:offset+length])
+	default:
+		return nil, fmt.Errorf("unsupported data type for column %s", s.Name)
+	}
+	if s.Validity != nil {
+		out.Validity = s.Validity.Slice(offset, offset+length)
+	}
+	return out, nil
+}
+
+// Rename returns a Series with the given name sharing the data, validity and
+// cached statistics of s.
+func (s *Series) Rename(name string) *Series {
+	out := &Series{
+		Name:     name,
+		DataType: s.DataType,
+		Data:     s.Data,
+		Length:   s.Length,
+		Validity: s.Validity,
+	}
+	if st, ok := s.CachedStats(); ok {
+		out.cache.stats.Store(st)
+	}
+	return out
+}
+
+func gather[T any](data []T, idx []int) []T {
+	out := make([]T, len(idx))
+	for i, j := range idx {
+		if j >= 0 {
+			out[i] = data[j]
+		}
+	}
+	return out
+}
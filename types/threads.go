@@ -0,0 +1,49 @@
+package types
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// maxThreads caps the goroutines of a parallel operation, 0 meaning
+// GOMAXPROCS. It starts from the GOPOLARS_MAX_THREADS environment variable,
+// the counterpart of POLARS_MAX_THREADS, so deployed binaries can be tuned
+// without code changes.
+var maxThreads atomic.Int64
+
+func init() {
+	v, ok := os.LookupEnv("GOPOLARS_MAX_THREADS")
+	if !ok || v == "" {
+		return
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		fmt.Fprintf(os.Stderr, "gopolars: ignoring GOPOLARS_MAX_THREADS=%q, want a positive integer\n", v)
+		return
+	}
+	maxThreads.Store(int64(n))
+}
+
+// MaxThreads returns the number of goroutines a parallel operation of the
+// types and dataframe packages uses at most: the value of SetMaxThreads or
+// GOPOLARS_MAX_THREADS, or GOMAXPROCS when neither is set.
+func MaxThreads() int {
+	if n := maxThreads.Load(); n > 0 {
+		return int(n)
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// SetMaxThreads caps the goroutines of every later parallel operation at n,
+// overriding GOPOLARS_MAX_THREADS; n of 0 restores GOMAXPROCS and 1 runs
+// everything on the calling goroutine. It is safe to call at any time.
+func SetMaxThreads(n int) error {
+	if n < 0 {
+		return fmt.Errorf("max threads %d must not be negative", n)
+	}
+	maxThreads.Store(int64(n))
+	return nil
+}
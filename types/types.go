@@ -1,7 +1,10 @@
 package types
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"math/bits"
 	"sort"
 	"strconv"
 	"strings"
@@ -17,12 +20,14 @@ type DataType interface {
 type (
 	Int64Type   struct{}
 	Float64Type struct{}
+	Float32Type struct{}
 	StringType  struct{}
 	BooleanType struct{}
 )
 
 func (Int64Type) String() string   { return "Int64" }
 func (Float64Type) String() string { return "Float64" }
+func (Float32Type) String() string { return "Float32" }
 func (StringType) String() string  { return "String" }
 func (BooleanType) String() string { return "Boolean" }
 
@@ -30,8 +35,10 @@ func (BooleanType) String() string { return "Boolean" }
 type Series struct {
 	Name     string
 	DataType DataType
-	Data     interface{} // Will hold []int64, []float64, []string, or []bool
+	Data     interface{} // Will hold []int64, []float64, []float32, []string, or []bool
 	Length   int
+	Validity *Bitmap // nil when no element is null
+	cache    statsCache
 }
 
 // NewSeries creates a new Series with the given name and data
@@ -51,6 +58,13 @@ func NewSeries(name string, data interface{}) *Series {
 			Data:     d,
 			Length:   len(d),
 		}
+	case []float32:
+		return &Series{
+			Name:     name,
+			DataType: Float32Type{},
+			Data:     d,
+			Length:   len(d),
+		}
 	case []string:
 		return &Series{
 			Name:     name,
@@ -65,17 +79,35 @@ func NewSeries(name string, data interface{}) *Series {
 			Data:     d,
 			Length:   len(d),
 		}
+	case *Categorical:
+		return &Series{
+			Name:     name,
+			DataType: CategoricalType{},
+			Data:     d,
+			Length:   len(d.Codes),
+		}
 	default:
+		if s := newIntegerSeries(name, data); s != nil {
+			return s
+		}
+		if s := newListSeries(name, data); s != nil {
+			return s
+		}
 		panic("unsupported data type")
 	}
 }
 
 // DataFrame represents a collection of Series with the same length
 type DataFrame struct {
-	Series       map[string]*Series
-	Length       int
+	Series map[string]*Series
+	Length int
+	// GroupIndices maps the text key of every group of a frame returned by
+	// GroupBy to its rows, a view into the permutation held by Groups.
 	GroupIndices map[string][]int
 	GroupColumns []string
+
+	order  []string // insertion order of the columns, see Columns
+	groups *Groups  // rows of each group, in group column order
 }
 
 // New creates a new DataFrame from a map of Series
@@ -111,6 +143,22 @@ func New(series map[string]*Series) (*DataFrame, error) {
 	}, nil
 }
 
+// NewOrdered creates a DataFrame like New whose Columns follow order
+func NewOrdered(series map[string]*Series, order []string) (*DataFrame, error) {
+	df, err := New(series)
+	if err != nil {
+		return nil, err
+	}
+	df.order = append([]string(nil), order...)
+	return df, nil
+}
+
+// withOrder puts the names in first ahead of rest; Columns skips the
+// repeats.
+func withOrder(first []string, rest []string) []string {
+	return append(append([]string(nil), first...), rest...)
+}
+
 // Shape returns the dimensions of the DataFrame (rows, columns)
 func (df *DataFrame) Shape() (int, int) {
 	if df == nil || df.Series == nil {
@@ -119,19 +167,36 @@ func (df *DataFrame) Shape() (int, int) {
 	return df.Length, len(df.Series)
 }
 
-// Columns returns the column names of the DataFrame
+// Columns returns the column names of the DataFrame in insertion order.
+// Frames built with New, and columns added to Series directly, are ordered
+// by name.
 func (df *DataFrame) Columns() []string {
 	if df == nil || df.Series == nil {
 		return []string{}
 	}
 	cols := make([]string, 0, len(df.Series))
-	for name := range df.Series {
-		cols = append(cols, name)
+	seen := make(map[string]bool, len(df.Series))
+	for _, name := range df.order {
+		if _, ok := df.Series[name]; ok && !seen[name] {
+			seen[name] = true
+			cols = append(cols, name)
+		}
+	}
+	if len(cols) < len(df.Series) {
+		var rest []string
+		for name := range df.Series {
+			if !seen[name] {
+				rest = append(rest, name)
+			}
+		}
+		sort.Strings(rest)
+		cols = append(cols, rest...)
 	}
 	return cols
 }
 
-// Head returns a new DataFrame with the first n rows
+// Head returns a new DataFrame with the first n rows, sharing the column
+// data
 func (df *DataFrame) Head(n int) (*DataFrame, error) {
 	if df == nil || df.Series == nil {
 		return nil, fmt.Errorf("DataFrame is nil or empty")
@@ -139,22 +204,30 @@ func (df *DataFrame) Head(n int) (*DataFrame, error) {
 	if n > df.Length {
 		n = df.Length
 	}
+	return df.Slice(0, n)
+}
 
-	head := make(map[string]*Series)
+// Slice returns the rows [offset, offset+length) as a new DataFrame sharing
+// the column data. A range reaching past the last row is truncated.
+func (df *DataFrame) Slice(offset, length int) (*DataFrame, error) {
+	if df == nil || df.Series == nil {
+		return nil, fmt.Errorf("DataFrame is nil or empty")
+	}
+	if offset < 0 || length < 0 || offset > df.Length {
+		return nil, fmt.Errorf("slice [%d, %d+%d) out of range for %d rows", offset, offset, length, df.Length)
+	}
+	if offset+length > df.Length {
+		length = df.Length - offset
+	}
+
+	sliced := make(map[string]*Series, len(df.Series))
 	for name, s := range df.Series {
-		switch data := s.Data.(type) {
-		case []int64:
-			head[name] = NewSeries(name, data[:n])
-		case []float64:
-			head[name] = NewSeries(name, data[:n])
-		case []string:
-			head[name] = NewSeries(name, data[:n])
-		case []bool:
-			head[name] = NewSeries(name, data[:n])
+		var err error
+		if sliced[name], err = s.slice(offset, length); err != nil {
+			return nil, err
 		}
 	}
-
-	return New(head)
+	return NewOrdered(sliced, df.Columns())
 }
 
 // AggregationType represents the type of aggregation to perform
@@ -166,10 +239,58 @@ const (
 	Count
 	Min
 	Max
+	Var
+	Std
+	Median
+	Quantile
+	First
+	Last
+	NUnique
 )
 
-// GroupBy groups the DataFrame by one or more columns
+func (a AggregationType) String() string {
+	switch a {
+	case Sum:
+		return "sum"
+	case Mean:
+		return "mean"
+	case Count:
+		return "count"
+	case Min:
+		return "min"
+	case Max:
+		return "max"
+	case Var:
+		return "var"
+	case Std:
+		return "std"
+	case Median:
+		return "median"
+	case Quantile:
+		return "quantile"
+	case First:
+		return "first"
+	case Last:
+		return "last"
+	case NUnique:
+		return "n_unique"
+	default:
+		return fmt.Sprintf("AggregationType(%d)", int(a))
+	}
+}
+
+// GroupBy groups the DataFrame by one or more columns. Groups are ordered by
+// key, and their rows are kept as one permutation of the frame's rows cut
+// into runs by offsets, see Groups.
 func (df *DataFrame) GroupBy(columns []string) (*DataFrame, error) {
+	return df.GroupByContext(context.Background(), columns)
+}
+
+// GroupByContext is GroupBy giving up with the error of ctx as soon as it
+// is done.
+func (df *DataFrame) GroupByContext(ctx context.Context, columns []string) (out *DataFrame, err error) {
+	defer recoverStopped(&err)
+	p := newPoller(ctx)
 	if df == nil || df.Series == nil {
 		return nil, fmt.Errorf("DataFrame is nil or empty")
 	}
@@ -183,46 +304,40 @@ func (df *DataFrame) GroupBy(columns []string) (*DataFrame, error) {
 
 	// === Fast path: single-column groupby ==================================
 	if len(columns) == 1 {
-		col := columns[0]
-		s := df.Series[col]
-
-		switch data := s.Data.(type) {
+		switch data := df.Series[columns[0]].Data.(type) {
 		case []int64:
-			groups := make(map[int64][]int, df.Length)
-			for i, v := range data {
-				groups[v] = append(groups[v], i)
-			}
-			return buildGroupedDataFrameSingleInt64(df, col, groups)
+			ids, keys := sortedGroupIDs(data, p, func(a, b int64) bool { return a < b })
+			return df.buildGroupedDataFrame(columns, ids, keyNames(keys, func(k int64) string { return strconv.FormatInt(k, 10) }))
 		case []float64:
-			groups := make(map[float64][]int, df.Length)
-			for i, v := range data {
-				groups[v] = append(groups[v], i)
-			}
-			return buildGroupedDataFrameSingleFloat64(df, col, groups)
+			// NaN sorts first, as with sort.Float64s; every NaN is a group
+			// of its own since it never equals itself.
+			ids, keys := sortedGroupIDs(data, p, func(a, b float64) bool { return a < b || a != a && b == b })
+			return df.buildGroupedDataFrame(columns, ids, keyNames(keys, func(k float64) string { return strconv.FormatFloat(k, 'f', -1, 64) }))
 		case []string:
-			groups := make(map[string][]int, df.Length)
-			for i, v := range data {
-				groups[v] = append(groups[v], i)
-			}
-			return buildGroupedDataFrameSingleString(df, col, groups)
+			ids, keys := sortedGroupIDs(data, p, func(a, b string) bool { return a < b })
+			return df.buildGroupedDataFrame(columns, ids, keys)
 		case []bool:
-			groups := make(map[bool][]int, 2)
-			for i, v := range data {
-				groups[v] = append(groups[v], i)
-			}
-			return buildGroupedDataFrameSingleBool(df, col, groups)
+			ids, keys := sortedGroupIDs(data, p, func(a, b bool) bool { return !a && b })
+			return df.buildGroupedDataFrame(columns, ids, keyNames(keys, strconv.FormatBool))
 		default:
 			// Fallback to generic implementation below
 		}
 	}
 
+	// Without group columns the whole frame is one group, even when empty,
+	// so aggregating it always yields a single row.
+	if len(columns) == 0 {
+		return df.buildGroupedDataFrame(columns, make([]int, df.Length), []string{""})
+	}
+
 	// === Generic (multi-column) implementation =============================
 
-	// Create a map of group keys to row indices
-	groups := make(map[string][]int)
+	// Encode every row's key as text and group on that
+	keys := make([]string, df.Length)
 	var builder strings.Builder
 
 	for i := 0; i < df.Length; i++ {
+		p.poll()
 		builder.Reset()
 		for _, col := range columns {
 			series := df.Series[col]
@@ -231,6 +346,8 @@ func (df *DataFrame) GroupBy(columns []string) (*DataFrame, error) {
 				builder.WriteString(strconv.FormatInt(data[i], 10))
 			case []float64:
 				builder.WriteString(strconv.FormatFloat(data[i], 'f', -1, 64))
+			case []float32:
+				builder.WriteString(strconv.FormatFloat(float64(data[i]), 'f', -1, 32))
 			case []string:
 				builder.WriteString(data[i])
 			case []bool:
@@ -242,189 +359,131 @@ func (df *DataFrame) GroupBy(columns []string) (*DataFrame, error) {
 			}
 			builder.WriteByte('_')
 		}
-		key := builder.String()
-		groups[key] = append(groups[key], i)
+		keys[i] = builder.String()
 	}
 
-	return df.buildGroupedDataFrameMulti(columns, groups)
+	ids, names := sortedGroupIDs(keys, p, func(a, b string) bool { return a < b })
+	return df.buildGroupedDataFrame(columns, ids, names)
 }
 
-// Helper functions specialised per key type to avoid generics (which are not
-// yet allowed on methods).
-
-func buildGroupedDataFrameSingleInt64(df *DataFrame, column string, groups map[int64][]int) (*DataFrame, error) {
-	// Gather keys deterministically
-	keys := make([]int64, 0, len(groups))
-	for k := range groups {
-		keys = append(keys, k)
-	}
-	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
-
-	// Build result series
-	resultSeries := map[string]*Series{
-		column: NewSeries(column, keys),
-	}
-
-	groupIndices := make(map[string][]int, len(groups))
-	for _, k := range keys {
-		groupIndices[strconv.FormatInt(k, 10)] = groups[k]
-	}
-
-	// Reference other columns
-	for name, s := range df.Series {
-		if name != column {
-			resultSeries[name] = s
-		}
-	}
-
-	return &DataFrame{Series: resultSeries, Length: len(keys), GroupIndices: groupIndices, GroupColumns: []string{column}}, nil
+// sortedGroupIDs numbers the distinct values of data in ascending order by
+// less and returns the group ID of every row with the values in that order.
+// Values that compare equal under less keep the order they first appear in.
+func sortedGroupIDs[K comparable](data []K, p *poller, less func(a, b K) bool) ([]int, []K) {
+	ids := make([]int, len(data))
+	seen := make(map[K]int)
+	var keys []K
+	for i, v := range data {
+		p.poll()
+		id, ok := seen[v]
+		if !ok {
+			id = len(keys)
+			seen[v] = id
+			keys = append(keys, v)
+		}
+		ids[i] = id
+	}
+
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		p.poll()
+		return less(keys[order[a]], keys[order[b]])
+	})
+	rank := make([]int, len(keys))
+	sorted := make([]K, len(keys))
+	for r, id := range order {
+		rank[id] = r
+		sorted[r] = keys[id]
+	}
+	for i, id := range ids {
+		ids[i] = rank[id]
+	}
+	return ids, sorted
 }
 
-func buildGroupedDataFrameSingleFloat64(df *DataFrame, column string, groups map[float64][]int) (*DataFrame, error) {
-	keys := make([]float64, 0, len(groups))
-	for k := range groups {
-		keys = append(keys, k)
-	}
-	sort.Float64s(keys)
-
-	resultSeries := map[string]*Series{
-		column: NewSeries(column, keys),
-	}
-
-	groupIndices := make(map[string][]int, len(groups))
-	for _, k := range keys {
-		groupIndices[strconv.FormatFloat(k, 'f', -1, 64)] = groups[k]
-	}
-
-	for name, s := range df.Series {
-		if name != column {
-			resultSeries[name] = s
-		}
+// keyNames formats group keys as the text keys of GroupIndices
+func keyNames[K any](keys []K, format func(K) string) []string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = format(k)
 	}
-
-	return &DataFrame{Series: resultSeries, Length: len(keys), GroupIndices: groupIndices, GroupColumns: []string{column}}, nil
+	return names
 }
 
-func buildGroupedDataFrameSingleString(df *DataFrame, column string, groups map[string][]int) (*DataFrame, error) {
-	keys := make([]string, 0, len(groups))
-	for k := range groups {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	resultSeries := map[string]*Series{
-		column: NewSeries(column, keys),
+// buildGroupedDataFrame builds the grouped frame of columns from the group
+// ID of every row, with names[i] the text key of group i. The group columns
+// hold each group's key, taken from its first row, and the other columns
+// are shared with df.
+func (df *DataFrame) buildGroupedDataFrame(columns []string, ids []int, names []string) (*DataFrame, error) {
+	groups, err := NewGroups(ids, len(names))
+	if err != nil {
+		return nil, err
 	}
 
-	groupIndices := make(map[string][]int, len(groups))
-	for _, k := range keys {
-		groupIndices[k] = groups[k]
-	}
-
-	for name, s := range df.Series {
-		if name != column {
-			resultSeries[name] = s
-		}
-	}
-
-	return &DataFrame{Series: resultSeries, Length: len(keys), GroupIndices: groupIndices, GroupColumns: []string{column}}, nil
-}
-
-func buildGroupedDataFrameSingleBool(df *DataFrame, column string, groups map[bool][]int) (*DataFrame, error) {
-	// keys order: false, true
-	keys := []bool{false, true}
-	// Filter to existing keys
-	uniqueKeys := make([]bool, 0, 2)
-	for _, k := range keys {
-		if _, ok := groups[k]; ok {
-			uniqueKeys = append(uniqueKeys, k)
-		}
-	}
-
-	resultSeries := map[string]*Series{
-		column: NewSeries(column, uniqueKeys),
-	}
-
-	groupIndices := make(map[string][]int, len(uniqueKeys))
-	for _, k := range uniqueKeys {
-		if k {
-			groupIndices["true"] = groups[k]
-		} else {
-			groupIndices["false"] = groups[k]
+	reps := make([]int, len(names))
+	groupIndices := make(map[string][]int, len(names))
+	for g, name := range names {
+		rows := groups.Rows(g)
+		if len(rows) > 0 {
+			reps[g] = rows[0]
 		}
+		groupIndices[name] = rows
 	}
 
+	resultSeries := make(map[string]*Series, len(df.Series))
 	for name, s := range df.Series {
-		if name != column {
-			resultSeries[name] = s
-		}
+		resultSeries[name] = s
 	}
-
-	return &DataFrame{Series: resultSeries, Length: len(uniqueKeys), GroupIndices: groupIndices, GroupColumns: []string{column}}, nil
-}
-
-// buildGroupedDataFrameMulti handles the generic (multi-column) grouping path.
-func (df *DataFrame) buildGroupedDataFrameMulti(columns []string, groups map[string][]int) (*DataFrame, error) {
-	// Create result series for group columns
-	resultSeries := make(map[string]*Series)
-	length := len(groups)
-
-	// Initialize series for group columns
 	for _, col := range columns {
-		switch df.Series[col].Data.(type) {
-		case []int64:
-			resultSeries[col] = NewSeries(col, make([]int64, length))
-		case []float64:
-			resultSeries[col] = NewSeries(col, make([]float64, length))
-		case []string:
-			resultSeries[col] = NewSeries(col, make([]string, length))
-		case []bool:
-			resultSeries[col] = NewSeries(col, make([]bool, length))
-		}
-	}
-
-	// Set group column values
-	i := 0
-	for _, indices := range groups {
-		if len(indices) == 0 {
-			continue
-		}
-		for _, col := range columns {
-			series := df.Series[col]
-			switch data := series.Data.(type) {
-			case []int64:
-				resultSeries[col].Data.([]int64)[i] = data[indices[0]]
-			case []float64:
-				resultSeries[col].Data.([]float64)[i] = data[indices[0]]
-			case []string:
-				resultSeries[col].Data.([]string)[i] = data[indices[0]]
-			case []bool:
-				resultSeries[col].Data.([]bool)[i] = data[indices[0]]
-			}
-		}
-		i++
+		resultSeries[col] = df.Series[col].Take(reps)
 	}
 
-	// Create new DataFrame with group columns and indices
-	result := &DataFrame{
+	return &DataFrame{
 		Series:       resultSeries,
-		Length:       i,
-		GroupIndices: groups,
+		Length:       len(names),
+		GroupIndices: groupIndices,
 		GroupColumns: columns,
-	}
+		order:        withOrder(columns, df.Columns()),
+		groups:       groups,
+	}, nil
+}
 
-	// Copy all series from original DataFrame
-	for name, s := range df.Series {
-		if _, ok := resultSeries[name]; !ok {
-			result.Series[name] = s
-		}
+// Aggregate performs the specified aggregation on a DataFrame returned by
+// GroupBy. The result holds the group columns and the aggregated column,
+// one row per group. Null values are skipped; Var and Std are sample
+// statistics and, like Median, produce Float64, while NUnique produces Int64.
+// A group without valid values gives 0 for Sum, Count and NUnique and null
+// otherwise; a frame grouped by no columns is a single group even when it
+// has no rows. Use AggregateQuantile for Quantile.
+func (df *DataFrame) Aggregate(column string, aggType AggregationType) (*DataFrame, error) {
+	if aggType == Quantile {
+		return nil, fmt.Errorf("quantile aggregation needs a quantile, use AggregateQuantile")
 	}
+	return df.aggregate(column, aggType, 0.5, QuantileLinear)
+}
 
-	return result, nil
+// AggregateQuantile computes the q-th quantile, 0 <= q <= 1, of column in
+// every group, interpolating linearly between the closest values.
+func (df *DataFrame) AggregateQuantile(column string, q float64) (*DataFrame, error) {
+	return df.AggregateQuantileWith(column, q, QuantileLinear)
 }
 
-// Aggregate performs the specified aggregation on the DataFrame
-func (df *DataFrame) Aggregate(column string, aggType AggregationType) (*DataFrame, error) {
+// AggregateQuantileWith is AggregateQuantile with the value between the
+// closest values chosen by method.
+func (df *DataFrame) AggregateQuantileWith(column string, q float64, method QuantileInterpolation) (*DataFrame, error) {
+	if !(q >= 0 && q <= 1) {
+		return nil, fmt.Errorf("quantile %v out of range [0, 1]", q)
+	}
+	if !method.Valid() {
+		return nil, fmt.Errorf("unknown quantile interpolation %d", int(method))
+	}
+	return df.aggregate(column, Quantile, q, method)
+}
+
+func (df *DataFrame) aggregate(column string, aggType AggregationType, q float64, method QuantileInterpolation) (*DataFrame, error) {
 	if df == nil || df.Series == nil {
 		return nil, fmt.Errorf("DataFrame is nil or empty")
 	}
@@ -438,707 +497,304 @@ func (df *DataFrame) Aggregate(column string, aggType AggregationType) (*DataFra
 		return nil, fmt.Errorf("DataFrame is not grouped")
 	}
 
-	// Fast streaming path: single grouping column, avoid GroupIndices slices
-	if len(df.GroupColumns) == 1 {
-		keyCol := df.GroupColumns[0]
-		keySeries := df.Series[keyCol]
-
-		// Build aggregation map keyed by the grouping column's underlying type.
-		switch keys := keySeries.Data.(type) {
-		case []int64:
-			return aggregateStreamingInt64Key(df, keys, series, column, aggType)
-		case []string:
-			return aggregateStreamingStringKey(df, keys, series, column, aggType)
-		case []float64:
-			// Using float64 as map key is okay here because we originated them; NaN treated as distinct.
-			return aggregateStreamingFloat64Key(df, keys, series, column, aggType)
-		case []bool:
-			return aggregateStreamingBoolKey(df, keys, series, column, aggType)
-		default:
-			// Fallback to existing logic
-		}
+	groups, err := df.Groups()
+	if err != nil {
+		return nil, err
 	}
-
-	// Create result series with group columns only
-	resultSeries := make(map[string]*Series)
-	for name, s := range df.Series {
-		if s.Length == df.Length {
-			resultSeries[name] = s
-		}
+	rows := make([][]int, groups.Len())
+	for g := range rows {
+		rows[g] = groups.Rows(g)
 	}
 
-	// Build deterministic slice of group keys (same order as group column)
-	keys := make([]string, 0, len(df.GroupIndices))
-	for k := range df.GroupIndices {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	switch data := series.Data.(type) {
-	case []int64:
-		newData := make([]int64, len(keys))
-		var wg sync.WaitGroup
-		for idx, key := range keys {
-			indices := df.GroupIndices[key]
-			wg.Add(1)
-			go func(outIdx int, idxs []int) {
-				defer wg.Done()
-				if len(idxs) == 0 {
-					return
-				}
-				var res int64
-				switch aggType {
-				case Sum:
-					for _, id := range idxs {
-						res += data[id]
-					}
-				case Mean:
-					for _, id := range idxs {
-						res += data[id]
-					}
-					res /= int64(len(idxs))
-				case Count:
-					res = int64(len(idxs))
-				case Min:
-					min := data[idxs[0]]
-					for _, id := range idxs {
-						if data[id] < min {
-							min = data[id]
-						}
-					}
-					res = min
-				case Max:
-					max := data[idxs[0]]
-					for _, id := range idxs {
-						if data[id] > max {
-							max = data[id]
-						}
-					}
-					res = max
-				}
-				newData[outIdx] = res
-			}(idx, indices)
+	// Narrow integers and Float32 values are accumulated in 64 bits. UInt64
+	// Sum, Min and Max keep uint64, as Int64 cannot hold every value.
+	if data, ok := series.Data.([]uint64); ok && (aggType == Sum || aggType == Min || aggType == Max) {
+		agg, err := aggregateUint64Rows(column, series, data, rows, aggType)
+		if err != nil {
+			return nil, err
 		}
-		wg.Wait()
-		resultSeries[column] = NewSeries(column, newData)
-	case []float64:
-		newData := make([]float64, len(keys))
-		var wg sync.WaitGroup
-		for idx, key := range keys {
-			indices := df.GroupIndices[key]
-			wg.Add(1)
-			go func(outIdx int, idxs []int) {
-				defer wg.Done()
-				if len(idxs) == 0 {
-					return
-				}
-				var res float64
-				switch aggType {
-				case Sum:
-					for _, id := range idxs {
-						res += data[id]
-					}
-				case Mean:
-					for _, id := range idxs {
-						res += data[id]
-					}
-					res /= float64(len(idxs))
-				case Count:
-					res = float64(len(idxs))
-				case Min:
-					min := data[idxs[0]]
-					for _, id := range idxs {
-						if data[id] < min {
-							min = data[id]
-						}
-					}
-					res = min
-				case Max:
-					max := data[idxs[0]]
-					for _, id := range idxs {
-						if data[id] > max {
-							max = data[id]
-						}
-					}
-					res = max
-				}
-				newData[outIdx] = res
-			}(idx, indices)
-		}
-		wg.Wait()
-		resultSeries[column] = NewSeries(column, newData)
-	default:
-		return nil, fmt.Errorf("unsupported data type for aggregation")
+		return df.aggregateResult(column, agg)
 	}
-
-	return New(resultSeries)
-}
-
-// SortByColumn sorts the DataFrame by the specified column
-func (df *DataFrame) SortByColumn(column string, ascending bool) (*DataFrame, error) {
-	series, ok := df.Series[column]
-	if !ok {
-		return nil, fmt.Errorf("column %s not found", column)
-	}
-
-	// Create index slice to track original positions
-	indices := make([]int, df.Length)
-	for i := range indices {
-		indices[i] = i
+	series, err = series.Widen()
+	if err != nil {
+		return nil, err
 	}
 
-	// Sort indices based on the column values
+	var agg *Series
 	switch data := series.Data.(type) {
 	case []int64:
-		sort.Slice(indices, func(i, j int) bool {
-			if ascending {
-				return data[indices[i]] < data[indices[j]]
-			}
-			return data[indices[i]] > data[indices[j]]
-		})
+		agg = aggregateRows(column, series, data, rows, aggType, q, method)
 	case []float64:
-		sort.Slice(indices, func(i, j int) bool {
-			if ascending {
-				return data[indices[i]] < data[indices[j]]
-			}
-			return data[indices[i]] > data[indices[j]]
-		})
-	case []string:
-		sort.Slice(indices, func(i, j int) bool {
-			if ascending {
-				return data[indices[i]] < data[indices[j]]
-			}
-			return data[indices[i]] > data[indices[j]]
-		})
-	case []bool:
-		sort.Slice(indices, func(i, j int) bool {
-			if ascending {
-				return !data[indices[i]] && data[indices[j]]
-			}
-			return data[indices[i]] && !data[indices[j]]
-		})
+		agg = aggregateRows(column, series, data, rows, aggType, q, method)
 	default:
-		return nil, fmt.Errorf("unsupported data type for column %s", column)
-	}
-
-	// Create new sorted series concurrently for better throughput on wide DataFrames
-	sorted := make(map[string]*Series, len(df.Series))
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-
-	for name, s := range df.Series {
-		wg.Add(1)
-		go func(name string, s *Series) {
-			defer wg.Done()
-			switch data := s.Data.(type) {
-			case []int64:
-				newData := make([]int64, df.Length)
-				for newIdx, oldIdx := range indices {
-					newData[newIdx] = data[oldIdx]
-				}
-				series := NewSeries(name, newData)
-				mu.Lock()
-				sorted[name] = series
-				mu.Unlock()
-			case []float64:
-				newData := make([]float64, df.Length)
-				for newIdx, oldIdx := range indices {
-					newData[newIdx] = data[oldIdx]
-				}
-				series := NewSeries(name, newData)
-				mu.Lock()
-				sorted[name] = series
-				mu.Unlock()
-			case []string:
-				newData := make([]string, df.Length)
-				for newIdx, oldIdx := range indices {
-					newData[newIdx] = data[oldIdx]
-				}
-				series := NewSeries(name, newData)
-				mu.Lock()
-				sorted[name] = series
-				mu.Unlock()
-			case []bool:
-				newData := make([]bool, df.Length)
-				for newIdx, oldIdx := range indices {
-					newData[newIdx] = data[oldIdx]
-				}
-				series := NewSeries(name, newData)
-				mu.Lock()
-				sorted[name] = series
-				mu.Unlock()
-			}
-		}(name, s)
+		return nil, fmt.Errorf("unsupported data type for aggregation")
 	}
-
-	wg.Wait()
-
-	return New(sorted)
+	return df.aggregateResult(column, agg)
 }
 
-// SortByIndex sorts the DataFrame by the row index
-func (df *DataFrame) SortByIndex(ascending bool) (*DataFrame, error) {
-	// Create index slice
-	indices := make([]int, df.Length)
-	for i := range indices {
-		indices[i] = i
-	}
-
-	// Sort indices
-	if ascending {
-		sort.Ints(indices)
-	} else {
-		sort.Sort(sort.Reverse(sort.IntSlice(indices)))
+func (df *DataFrame) aggregateResult(column string, agg *Series) (*DataFrame, error) {
+	resultSeries := map[string]*Series{column: agg}
+	for _, col := range df.GroupColumns {
+		resultSeries[col] = df.Series[col]
 	}
-
-	// Create new sorted series concurrently
-	sorted := make(map[string]*Series, len(df.Series))
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-
-	for name, s := range df.Series {
-		wg.Add(1)
-		go func(name string, s *Series) {
-			defer wg.Done()
-			switch data := s.Data.(type) {
-			case []int64:
-				newData := make([]int64, df.Length)
-				for newIdx, oldIdx := range indices {
-					newData[newIdx] = data[oldIdx]
-				}
-				mu.Lock()
-				sorted[name] = NewSeries(name, newData)
-				mu.Unlock()
-			case []float64:
-				newData := make([]float64, df.Length)
-				for newIdx, oldIdx := range indices {
-					newData[newIdx] = data[oldIdx]
-				}
-				mu.Lock()
-				sorted[name] = NewSeries(name, newData)
-				mu.Unlock()
-			case []string:
-				newData := make([]string, df.Length)
-				for newIdx, oldIdx := range indices {
-					newData[newIdx] = data[oldIdx]
-				}
-				mu.Lock()
-				sorted[name] = NewSeries(name, newData)
-				mu.Unlock()
-			case []bool:
-				newData := make([]bool, df.Length)
-				for newIdx, oldIdx := range indices {
-					newData[newIdx] = data[oldIdx]
-				}
-				mu.Lock()
-				sorted[name] = NewSeries(name, newData)
-				mu.Unlock()
-			}
-		}(name, s)
-	}
-
-	wg.Wait()
-
-	return New(sorted)
+	return NewOrdered(resultSeries, append(append([]string(nil), df.GroupColumns...), column))
 }
 
-// --- streaming aggregation helpers -------------------------------------------------
-
-type aggStateInt64 struct {
-	sum, min, max int64
-	count         int64
-}
-type aggStateFloat64 struct {
-	sum, min, max float64
-	count         int64
-}
-
-func aggregateStreamingInt64Key(df *DataFrame, keys []int64, valSeries *Series, column string, aggType AggregationType) (*DataFrame, error) {
-	switch values := valSeries.Data.(type) {
-	case []int64:
-		state := make(map[int64]*aggStateInt64, len(df.GroupIndices))
-		for i, k := range keys {
-			v := values[i]
-			s, ok := state[k]
-			if !ok {
-				state[k] = &aggStateInt64{sum: v, min: v, max: v, count: 1}
-			} else {
-				s.sum += v
-				s.count++
-				if v < s.min {
-					s.min = v
-				}
-				if v > s.max {
-					s.max = v
-				}
-			}
-		}
-
-		// Build result slices in deterministic order (sort keys)
-		uniq := make([]int64, 0, len(state))
-		for k := range state {
-			uniq = append(uniq, k)
-		}
-		sort.Slice(uniq, func(i, j int) bool { return uniq[i] < uniq[j] })
-
-		resultVals := make([]int64, len(uniq))
-		for i, k := range uniq {
-			st := state[k]
+// aggregateUint64Rows is aggregateRows for the Sum, Min and Max of a UInt64
+// column. A Sum beyond math.MaxUint64 is an error rather than wrapping.
+func aggregateUint64Rows(name string, s *Series, data []uint64, rows [][]int, aggType AggregationType) (*Series, error) {
+	out := make([]uint64, len(rows))
+	valid := make([]bool, len(rows))
+	for g, idx := range rows {
+		var acc uint64
+		count := 0
+		for _, i := range idx {
+			if !s.IsValid(i) {
+				continue
+			}
+			v := data[i]
 			switch aggType {
 			case Sum:
-				resultVals[i] = st.sum
-			case Mean:
-				if st.count > 0 {
-					resultVals[i] = st.sum / st.count
+				var carry uint64
+				if acc, carry = bits.Add64(acc, v, 0); carry != 0 {
+					return nil, fmt.Errorf("sum of column %s overflows UInt64", name)
 				}
-			case Count:
-				resultVals[i] = st.count
 			case Min:
-				resultVals[i] = st.min
-			case Max:
-				resultVals[i] = st.max
-			}
-		}
-
-		// Build group column data slice (keys)
-		keySeries := NewSeries(df.GroupColumns[0], uniq)
-		resSeries := map[string]*Series{
-			df.GroupColumns[0]: keySeries,
-			column:             NewSeries(column, resultVals),
-		}
-
-		// Attach other original series by reference
-		for name, s := range df.Series {
-			if name != df.GroupColumns[0] && name != column {
-				resSeries[name] = s
-			}
-		}
-
-		return &DataFrame{Series: resSeries, Length: len(uniq), GroupIndices: nil, GroupColumns: df.GroupColumns}, nil
-
-	case []float64:
-		state := make(map[int64]*aggStateFloat64, len(df.GroupIndices))
-		for i, k := range keys {
-			v := values[i]
-			s, ok := state[k]
-			if !ok {
-				state[k] = &aggStateFloat64{sum: v, min: v, max: v, count: 1}
-			} else {
-				s.sum += v
-				s.count++
-				if v < s.min {
-					s.min = v
+				if count == 0 || v < acc {
+					acc = v
 				}
-				if v > s.max {
-					s.max = v
+			case Max:
+				if count == 0 || v > acc {
+					acc = v
 				}
 			}
+			count++
 		}
-		uniq := make([]int64, 0, len(state))
-		for k := range state {
-			uniq = append(uniq, k)
-		}
-		sort.Slice(uniq, func(i, j int) bool { return uniq[i] < uniq[j] })
+		out[g], valid[g] = acc, aggType == Sum || count > 0
+	}
+	return NewNullableSeries(name, out, valid), nil
+}
 
-		resultVals := make([]float64, len(uniq))
-		for i, k := range uniq {
-			st := state[k]
-			switch aggType {
-			case Sum:
-				resultVals[i] = st.sum
-			case Mean:
-				if st.count > 0 {
-					resultVals[i] = st.sum / float64(st.count)
+// aggregateRows reduces the values of s at each group's rows, skipping
+// nulls, into a Series with one element per group.
+func aggregateRows[T int64 | float64](name string, s *Series, data []T, rows [][]int, aggType AggregationType, q float64, method QuantileInterpolation) *Series {
+	n := len(rows)
+	valid := make([]bool, n)
+	switch aggType {
+	case Var, Std, Median, Quantile:
+		out := make([]float64, n)
+		for g, idx := range rows {
+			var vals []float64
+			for _, i := range idx {
+				if s.IsValid(i) {
+					vals = append(vals, float64(data[i]))
+				}
+			}
+			out[g], valid[g] = reduceFloat(vals, aggType, q, method)
+		}
+		return NewNullableSeries(name, out, valid)
+	case NUnique:
+		out := make([]int64, n)
+		for g, idx := range rows {
+			distinct := make(map[T]struct{})
+			nan := false
+			for _, i := range idx {
+				if !s.IsValid(i) {
+					continue
+				}
+				if v := data[i]; v != v {
+					nan = true
+				} else {
+					distinct[v] = struct{}{}
 				}
-			case Count:
-				resultVals[i] = float64(st.count)
-			case Min:
-				resultVals[i] = st.min
-			case Max:
-				resultVals[i] = st.max
 			}
-		}
-
-		keySeries := NewSeries(df.GroupColumns[0], uniq)
-		resSeries := map[string]*Series{
-			df.GroupColumns[0]: keySeries,
-			column:             NewSeries(column, resultVals),
-		}
-		for name, s := range df.Series {
-			if name != df.GroupColumns[0] && name != column {
-				resSeries[name] = s
+			out[g], valid[g] = int64(len(distinct)), true
+			if nan {
+				out[g]++
 			}
 		}
-		return &DataFrame{Series: resSeries, Length: len(uniq), GroupIndices: nil, GroupColumns: df.GroupColumns}, nil
-
-	default:
-		return nil, fmt.Errorf("unsupported aggregation data type for streaming path")
+		return NewNullableSeries(name, out, valid)
 	}
-}
 
-func aggregateStreamingStringKey(df *DataFrame, keys []string, valSeries *Series, column string, aggType AggregationType) (*DataFrame, error) {
-	// Similar logic but keys are strings.
-	switch values := valSeries.Data.(type) {
-	case []int64:
-		state := make(map[string]*aggStateInt64)
-		for i, k := range keys {
-			v := values[i]
-			s, ok := state[k]
-			if !ok {
-				state[k] = &aggStateInt64{sum: v, min: v, max: v, count: 1}
-			} else {
-				s.sum += v
-				s.count++
-				if v < s.min {
-					s.min = v
-				}
-				if v > s.max {
-					s.max = v
-				}
+	out := make([]T, n)
+	for g, idx := range rows {
+		switch aggType {
+		case First, Last:
+			// Groups set up by hand may be empty; their first row is null.
+			if len(idx) == 0 {
+				continue
 			}
-		}
-		uniq := make([]string, 0, len(state))
-		for k := range state {
-			uniq = append(uniq, k)
-		}
-		sort.Strings(uniq)
-		resultVals := make([]int64, len(uniq))
-		for i, k := range uniq {
-			st := state[k]
-			switch aggType {
-			case Sum:
-				resultVals[i] = st.sum
-			case Mean:
-				if st.count > 0 {
-					resultVals[i] = st.sum / st.count
-				}
-			case Count:
-				resultVals[i] = st.count
-			case Min:
-				resultVals[i] = st.min
-			case Max:
-				resultVals[i] = st.max
+			row := idx[0]
+			if aggType == Last {
+				row = idx[len(idx)-1]
 			}
+			out[g], valid[g] = data[row], s.IsValid(row)
+			continue
 		}
-		resSeries := map[string]*Series{
-			df.GroupColumns[0]: NewSeries(df.GroupColumns[0], uniq),
-			column:             NewSeries(column, resultVals),
-		}
-		for name, s := range df.Series {
-			if name != df.GroupColumns[0] && name != column {
-				resSeries[name] = s
+		var sum, lo, hi T
+		count := 0
+		for _, i := range idx {
+			if !s.IsValid(i) {
+				continue
 			}
-		}
-		return &DataFrame{Series: resSeries, Length: len(uniq), GroupIndices: nil, GroupColumns: df.GroupColumns}, nil
-	case []float64:
-		state := make(map[string]*aggStateFloat64)
-		for i, k := range keys {
-			v := values[i]
-			s, ok := state[k]
-			if !ok {
-				state[k] = &aggStateFloat64{sum: v, min: v, max: v, count: 1}
-			} else {
-				s.sum += v
-				s.count++
-				if v < s.min {
-					s.min = v
-				}
-				if v > s.max {
-					s.max = v
-				}
+			v := data[i]
+			if count == 0 || v < lo {
+				lo = v
 			}
-		}
-		uniq := make([]string, 0, len(state))
-		for k := range state {
-			uniq = append(uniq, k)
-		}
-		sort.Strings(uniq)
-		resultVals := make([]float64, len(uniq))
-		for i, k := range uniq {
-			st := state[k]
-			switch aggType {
-			case Sum:
-				resultVals[i] = st.sum
-			case Mean:
-				if st.count > 0 {
-					resultVals[i] = st.sum / float64(st.count)
-				}
-			case Count:
-				resultVals[i] = float64(st.count)
-			case Min:
-				resultVals[i] = st.min
-			case Max:
-				resultVals[i] = st.max
+			if count == 0 || v > hi {
+				hi = v
 			}
+			sum += v
+			count++
 		}
-		resSeries := map[string]*Series{df.GroupColumns[0]: NewSeries(df.GroupColumns[0], uniq), column: NewSeries(column, resultVals)}
-		for name, s := range df.Series {
-			if name != df.GroupColumns[0] && name != column {
-				resSeries[name] = s
+		switch aggType {
+		case Sum:
+			out[g], valid[g] = sum, true
+		case Mean:
+			if count > 0 {
+				out[g], valid[g] = sum/T(count), true
 			}
+		case Count:
+			out[g], valid[g] = T(count), true
+		case Min:
+			out[g], valid[g] = lo, count > 0
+		case Max:
+			out[g], valid[g] = hi, count > 0
 		}
-		return &DataFrame{Series: resSeries, Length: len(uniq), GroupIndices: nil, GroupColumns: df.GroupColumns}, nil
-	default:
-		return nil, fmt.Errorf("unsupported data type for streaming path")
 	}
+	return NewNullableSeries(name, out, valid)
 }
 
-func aggregateStreamingFloat64Key(df *DataFrame, keys []float64, valSeries *Series, column string, aggType AggregationType) (*DataFrame, error) {
-	// Convert float64 key to string for sorting stability
-	stateInt := make(map[float64]*aggStateFloat64)
-	switch values := valSeries.Data.(type) {
-	case []float64:
-		for i, k := range keys {
-			v := values[i]
-			s, ok := stateInt[k]
-			if !ok {
-				stateInt[k] = &aggStateFloat64{sum: v, min: v, max: v, count: 1}
-			} else {
-				s.sum += v
-				s.count++
-				if v < s.min {
-					s.min = v
-				}
-				if v > s.max {
-					s.max = v
-				}
-			}
-		}
-		uniq := make([]float64, 0, len(stateInt))
-		for k := range stateInt {
-			uniq = append(uniq, k)
-		}
-		sort.Float64s(uniq)
-		resultVals := make([]float64, len(uniq))
-		for i, k := range uniq {
-			st := stateInt[k]
-			switch aggType {
-			case Sum:
-				resultVals[i] = st.sum
-			case Mean:
-				if st.count > 0 {
-					resultVals[i] = st.sum / float64(st.count)
-				}
-			case Count:
-				resultVals[i] = float64(st.count)
-			case Min:
-				resultVals[i] = st.min
-			case Max:
-				resultVals[i] = st.max
-			}
-		}
-		resSeries := map[string]*Series{df.GroupColumns[0]: NewSeries(df.GroupColumns[0], uniq), column: NewSeries(column, resultVals)}
-		for name, s := range df.Series {
-			if name != df.GroupColumns[0] && name != column {
-				resSeries[name] = s
-			}
-		}
-		return &DataFrame{Series: resSeries, Length: len(uniq), GroupIndices: nil, GroupColumns: df.GroupColumns}, nil
+// reduceFloat computes the Float64-valued aggregates over vals, which it may
+// reorder. Var and Std use Welford's update and are null for fewer than two
+// values; quantiles pick or interpolate between the closest ranks.
+func reduceFloat(vals []float64, aggType AggregationType, q float64, method QuantileInterpolation) (float64, bool) {
+	switch aggType {
+	case Var, Std:
+		if len(vals) < 2 {
+			return 0, false
+		}
+		var mean, m2 float64
+		for i, x := range vals {
+			delta := x - mean
+			mean += delta / float64(i+1)
+			m2 += delta * (x - mean)
+		}
+		v := m2 / float64(len(vals)-1)
+		if aggType == Std {
+			v = math.Sqrt(v)
+		}
+		return v, true
 	default:
-		return nil, fmt.Errorf("unsupported data type for streaming float64 key path")
+		return QuantileOf(vals, q, method)
 	}
 }
 
-func aggregateStreamingBoolKey(df *DataFrame, keys []bool, valSeries *Series, column string, aggType AggregationType) (*DataFrame, error) {
-	// keys are bool -> map[bool]
-	switch values := valSeries.Data.(type) {
-	case []int64:
-		state := map[bool]*aggStateInt64{}
-		for i, k := range keys {
-			v := values[i]
-			s, ok := state[k]
-			if !ok {
-				state[k] = &aggStateInt64{sum: v, min: v, max: v, count: 1}
-			} else {
-				s.sum += v
-				s.count++
-				if v < s.min {
-					s.min = v
-				}
-				if v > s.max {
-					s.max = v
-				}
-			}
-		}
-		uniq := []bool{}
-		if _, ok := state[false]; ok {
-			uniq = append(uniq, false)
-		}
-		if _, ok := state[true]; ok {
-			uniq = append(uniq, true)
-		}
-		resultVals := make([]int64, len(uniq))
-		for i, k := range uniq {
-			st := state[k]
-			switch aggType {
-			case Sum:
-				resultVals[i] = st.sum
-			case Mean:
-				resultVals[i] = st.sum / st.count
-			case Count:
-				resultVals[i] = st.count
-			case Min:
-				resultVals[i] = st.min
-			case Max:
-				resultVals[i] = st.max
-			}
+// SortByColumn sorts the DataFrame by the specified column, nulls last in
+// either direction. Rows with equal values keep their order.
+func (df *DataFrame) SortByColumn(column string, ascending bool) (*DataFrame, error) {
+	return df.SortByColumnContext(context.Background(), column, ascending)
+}
+
+// SortByColumnContext is SortByColumn giving up with the error of ctx as
+// soon as it is done.
+func (df *DataFrame) SortByColumnContext(ctx context.Context, column string, ascending bool) (out *DataFrame, err error) {
+	defer recoverStopped(&err)
+	series, ok := df.Series[column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	indices, err := sortedIndices(series, ascending, newPoller(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return df.takeRows(indices)
+}
+
+// sortedIndices returns the positions of the valid elements of s in sorted
+// order followed by those of the nulls.
+func sortedIndices(s *Series, ascending bool, p *poller) ([]int, error) {
+	if _, unsigned := s.Data.([]uint64); !unsigned {
+		var err error
+		if s, err = s.Widen(); err != nil {
+			return nil, err
 		}
-		resSeries := map[string]*Series{df.GroupColumns[0]: NewSeries(df.GroupColumns[0], uniq), column: NewSeries(column, resultVals)}
-		for name, s := range df.Series {
-			if name != df.GroupColumns[0] && name != column {
-				resSeries[name] = s
-			}
+	}
+	indices := make([]int, 0, s.Length)
+	var nulls []int
+	for i := 0; i < s.Length; i++ {
+		if s.IsValid(i) {
+			indices = append(indices, i)
+		} else {
+			nulls = append(nulls, i)
 		}
-		return &DataFrame{Series: resSeries, Length: len(uniq), GroupIndices: nil, GroupColumns: df.GroupColumns}, nil
+	}
+
+	var less func(a, b int) bool
+	switch data := s.Data.(type) {
+	case []int64:
+		less = func(a, b int) bool { return data[a] < data[b] }
+	case []uint64:
+		less = func(a, b int) bool { return data[a] < data[b] }
 	case []float64:
-		state := map[bool]*aggStateFloat64{}
-		for i, k := range keys {
-			v := values[i]
-			s, ok := state[k]
-			if !ok {
-				state[k] = &aggStateFloat64{sum: v, min: v, max: v, count: 1}
-			} else {
-				s.sum += v
-				s.count++
-				if v < s.min {
-					s.min = v
-				}
-				if v > s.max {
-					s.max = v
-				}
-			}
-		}
-		uniq := []bool{}
-		if _, ok := state[false]; ok {
-			uniq = append(uniq, false)
-		}
-		if _, ok := state[true]; ok {
-			uniq = append(uniq, true)
+		less = func(a, b int) bool { return data[a] < data[b] }
+	case []string:
+		less = func(a, b int) bool { return data[a] < data[b] }
+	case []bool:
+		less = func(a, b int) bool { return !data[a] && data[b] }
+	case *Categorical:
+		less = func(a, b int) bool { return data.Value(a) < data.Value(b) }
+	default:
+		return nil, fmt.Errorf("unsupported data type for column %s", s.Name)
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		p.poll()
+		if ascending {
+			return less(indices[i], indices[j])
 		}
-		resultVals := make([]float64, len(uniq))
-		for i, k := range uniq {
-			st := state[k]
-			switch aggType {
-			case Sum:
-				resultVals[i] = st.sum
-			case Mean:
-				resultVals[i] = st.sum / float64(st.count)
-			case Count:
-				resultVals[i] = float64(st.count)
-			case Min:
-				resultVals[i] = st.min
-			case Max:
-				resultVals[i] = st.max
-			}
+		return less(indices[j], indices[i])
+	})
+	return append(indices, nulls...), nil
+}
+
+// SortByIndex sorts the DataFrame by the row index
+func (df *DataFrame) SortByIndex(ascending bool) (*DataFrame, error) {
+	indices := make([]int, df.Length)
+	for i := range indices {
+		indices[i] = i
+		if !ascending {
+			indices[i] = df.Length - 1 - i
 		}
-		resSeries := map[string]*Series{df.GroupColumns[0]: NewSeries(df.GroupColumns[0], uniq), column: NewSeries(column, resultVals)}
-		for name, s := range df.Series {
-			if name != df.GroupColumns[0] && name != column {
-				resSeries[name] = s
-			}
+	}
+	return df.takeRows(indices)
+}
+
+// takeRows gathers the rows at indices from every column, one goroutine per
+// column for better throughput on wide DataFrames.
+func (df *DataFrame) takeRows(indices []int) (*DataFrame, error) {
+	names := df.Columns()
+	columns := make([]*Series, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, s *Series) {
+			defer wg.Done()
+			columns[i], errs[i] = s.take(indices)
+		}(i, df.Series[name])
+	}
+	wg.Wait()
+
+	sorted := make(map[string]*Series, len(names))
+	for i, name := range names {
+		if errs[i] != nil {
+			return nil, errs[i]
 		}
-		return &DataFrame{Series: resSeries, Length: len(uniq), GroupIndices: nil, GroupColumns: df.GroupColumns}, nil
-	default:
-		return nil, fmt.Errorf("unsupported data type for streaming bool path")
+		columns[i].Name = name
+		sorted[name] = columns[i]
 	}
+	return NewOrdered(sorted, names)
 }
@@ -0,0 +1,125 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Unique returns the distinct values of the Series in order of first
+// appearance. Nulls are kept as a single null, and so are NaNs as a single
+// NaN. Categorical Series stay Categorical.
+func (s *Series) Unique() (*Series, error) {
+	rows, _, err := s.firstRows()
+	if err != nil {
+		return nil, err
+	}
+	return s.Take(rows), nil
+}
+
+// NUnique counts the distinct non-null values of the Series, NaN counting
+// as one value, like the NUnique aggregation.
+func (s *Series) NUnique() (int, error) {
+	rows, _, err := s.firstRows()
+	if err != nil {
+		return 0, err
+	}
+	n := len(rows)
+	if s.HasNulls() {
+		n--
+	}
+	return n, nil
+}
+
+// ValueCounts returns the distinct values of the Series, nulls included as
+// one value, and how often each occurs as an Int64 Series named "count".
+// Values are in order of first appearance, or by descending count when
+// sortByCount is set, ties keeping that order.
+func (s *Series) ValueCounts(sortByCount bool) (values, counts *Series, err error) {
+	rows, n, err := s.firstRows()
+	if err != nil {
+		return nil, nil, err
+	}
+	if sortByCount {
+		order := make([]int, len(rows))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool { return n[order[i]] > n[order[j]] })
+		sortedRows := make([]int, len(rows))
+		sortedCounts := make([]int64, len(rows))
+		for i, o := range order {
+			sortedRows[i], sortedCounts[i] = rows[o], n[o]
+		}
+		rows, n = sortedRows, sortedCounts
+	}
+	return s.Take(rows), NewSeries("count", n), nil
+}
+
+// firstRows returns the position of the first occurrence of every distinct
+// value, null included, and the number of occurrences of each.
+func (s *Series) firstRows() (rows []int, counts []int64, err error) {
+	switch data := s.Data.(type) {
+	case []int64:
+		rows, counts = firstOccurrences(s, data)
+	case []float64:
+		rows, counts = firstOccurrences(s, data)
+	case []float32:
+		rows, counts = firstOccurrences(s, data)
+	case []string:
+		rows, counts = firstOccurrences(s, data)
+	case []bool:
+		rows, counts = firstOccurrences(s, data)
+	case []int8:
+		rows, counts = firstOccurrences(s, data)
+	case []int16:
+		rows, counts = firstOccurrences(s, data)
+	case []int32:
+		rows, counts = firstOccurrences(s, data)
+	case []uint8:
+		rows, counts = firstOccurrences(s, data)
+	case []uint16:
+		rows, counts = firstOccurrences(s, data)
+	case []uint32:
+		rows, counts = firstOccurrences(s, data)
+	case []uint64:
+		rows, counts = firstOccurrences(s, data)
+	case *Categorical:
+		rows, counts = firstOccurrences(s, data.Codes)
+	default:
+		return nil, nil, fmt.Errorf("cannot find unique values of %s", s.DataType)
+	}
+	return rows, counts, nil
+}
+
+func firstOccurrences[T comparable](s *Series, data []T) ([]int, []int64) {
+	seen := make(map[T]int)
+	var rows []int
+	var counts []int64
+	null, nan := -1, -1 // the positions in rows of the null and NaN values
+	for i, v := range data {
+		var g int
+		switch {
+		case !s.IsValid(i):
+			if null < 0 {
+				null = len(rows)
+				rows, counts = append(rows, i), append(counts, 0)
+			}
+			g = null
+		case v != v: // NaN never equals itself, so it cannot be a map key
+			if nan < 0 {
+				nan = len(rows)
+				rows, counts = append(rows, i), append(counts, 0)
+			}
+			g = nan
+		default:
+			var ok bool
+			if g, ok = seen[v]; !ok {
+				g = len(rows)
+				seen[v] = g
+				rows, counts = append(rows, i), append(counts, 0)
+			}
+		}
+		counts[g]++
+	}
+	return rows, counts
+}